@@ -0,0 +1,171 @@
+package dbkit
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// TryAdvisoryLock attempts to acquire a session-level advisory lock without blocking.
+// Returns true if the lock was acquired.
+//
+// Session-level locks belong to the connection that acquired them. When db is a
+// connection pool, unlock must happen on the same connection, so prefer
+// WithAdvisoryLock or pass a *Tx or bun.Conn. Not supported in pgBouncer mode.
+//
+// Usage:
+//
+//	acquired, err := dbkit.TryAdvisoryLock(ctx, conn, dbkit.StringAdvisoryKey("jobs:cleanup"))
+func TryAdvisoryLock(ctx context.Context, db IDB, key int64) (bool, error) {
+	if pgBouncerMode(db) {
+		return false, pgBouncerError("TryAdvisoryLock", "session-level advisory locks are not supported in pgBouncer mode, use TryAdvisoryXactLock")
+	}
+	var acquired bool
+	if err := db.NewRaw("SELECT pg_try_advisory_lock(?)", key).Scan(ctx, &acquired); err != nil {
+		return false, wrapError(err, "TryAdvisoryLock")
+	}
+	return acquired, nil
+}
+
+// AdvisoryLock acquires a session-level advisory lock, blocking until it is available.
+// Not supported in pgBouncer mode.
+//
+// Usage:
+//
+//	if err := dbkit.AdvisoryLock(ctx, conn, key); err != nil {
+//	    return err
+//	}
+//	defer dbkit.AdvisoryUnlock(ctx, conn, key)
+func AdvisoryLock(ctx context.Context, db IDB, key int64) error {
+	if pgBouncerMode(db) {
+		return pgBouncerError("AdvisoryLock", "session-level advisory locks are not supported in pgBouncer mode, use AdvisoryXactLock")
+	}
+	if _, err := db.ExecContext(ctx, "SELECT pg_advisory_lock(?)", key); err != nil {
+		return wrapError(err, "AdvisoryLock")
+	}
+	return nil
+}
+
+// AdvisoryUnlock releases a session-level advisory lock.
+// Returns an error if the lock was not held by the current session.
+//
+// Usage:
+//
+//	err := dbkit.AdvisoryUnlock(ctx, conn, key)
+func AdvisoryUnlock(ctx context.Context, db IDB, key int64) error {
+	if pgBouncerMode(db) {
+		return pgBouncerError("AdvisoryUnlock", "session-level advisory locks are not supported in pgBouncer mode")
+	}
+	var released bool
+	if err := db.NewRaw("SELECT pg_advisory_unlock(?)", key).Scan(ctx, &released); err != nil {
+		return wrapError(err, "AdvisoryUnlock")
+	}
+	if !released {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "advisory lock was not held by this session",
+			Op:      "AdvisoryUnlock",
+		}
+	}
+	return nil
+}
+
+// WithAdvisoryLock acquires a session-level advisory lock, runs fn, and releases the lock.
+// The lock is released even if fn panics.
+// When db is a *DBKit, a dedicated connection is held for the lifetime of the lock.
+//
+// In pgBouncer mode a transaction-level lock is taken instead: with a *DBKit, fn runs while a
+// transaction holding the lock is open; with a *Tx, the lock is held until tx ends.
+//
+// Usage:
+//
+//	err := dbkit.WithAdvisoryLock(ctx, db, dbkit.StringAdvisoryKey("jobs:cleanup"), func() error {
+//	    return runCleanup(ctx)
+//	})
+func WithAdvisoryLock(ctx context.Context, db IDB, key int64, fn func() error) error {
+	if pgBouncerMode(db) {
+		return withAdvisoryXactLock(ctx, db, key, fn)
+	}
+
+	if kit, ok := db.(*DBKit); ok {
+		conn, err := kit.Conn(ctx)
+		if err != nil {
+			return wrapError(err, "WithAdvisoryLock.Conn")
+		}
+		defer conn.Close()
+		db = conn
+	}
+
+	if err := AdvisoryLock(ctx, db, key); err != nil {
+		return err
+	}
+
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled
+		_ = AdvisoryUnlock(context.WithoutCancel(ctx), db, key)
+	}()
+
+	return fn()
+}
+
+// withAdvisoryXactLock runs fn holding a transaction-level lock, for pgBouncer mode
+func withAdvisoryXactLock(ctx context.Context, db IDB, key int64, fn func() error) error {
+	if tx, ok := db.(*Tx); ok {
+		if err := AdvisoryXactLock(ctx, tx, key); err != nil {
+			return err
+		}
+		return fn()
+	}
+
+	return db.(*DBKit).Transaction(ctx, func(tx *Tx) error {
+		if err := AdvisoryXactLock(ctx, tx, key); err != nil {
+			return err
+		}
+		return fn()
+	})
+}
+
+// TryAdvisoryXactLock attempts to acquire a transaction-level advisory lock without blocking.
+// The lock is released automatically when the transaction ends.
+//
+// Usage:
+//
+//	err := db.Transaction(ctx, func(tx *dbkit.Tx) error {
+//	    acquired, err := dbkit.TryAdvisoryXactLock(ctx, tx, key)
+//	    ...
+//	})
+func TryAdvisoryXactLock(ctx context.Context, db IDB, key int64) (bool, error) {
+	var acquired bool
+	if err := db.NewRaw("SELECT pg_try_advisory_xact_lock(?)", key).Scan(ctx, &acquired); err != nil {
+		return false, wrapError(err, "TryAdvisoryXactLock")
+	}
+	return acquired, nil
+}
+
+// AdvisoryXactLock acquires a transaction-level advisory lock, blocking until it is available.
+// The lock is released automatically when the transaction ends.
+//
+// Usage:
+//
+//	err := db.Transaction(ctx, func(tx *dbkit.Tx) error {
+//	    if err := dbkit.AdvisoryXactLock(ctx, tx, key); err != nil {
+//	        return err
+//	    }
+//	    ...
+//	})
+func AdvisoryXactLock(ctx context.Context, db IDB, key int64) error {
+	if _, err := db.ExecContext(ctx, "SELECT pg_advisory_xact_lock(?)", key); err != nil {
+		return wrapError(err, "AdvisoryXactLock")
+	}
+	return nil
+}
+
+// StringAdvisoryKey hashes a string to a stable int64 advisory lock key using FNV-1a.
+//
+// Usage:
+//
+//	key := dbkit.StringAdvisoryKey("jobs:cleanup")
+func StringAdvisoryKey(s string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,101 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStringAdvisoryKey(t *testing.T) {
+	key := StringAdvisoryKey("jobs:cleanup")
+
+	if StringAdvisoryKey("jobs:cleanup") != key {
+		t.Error("same string should produce same key")
+	}
+
+	if StringAdvisoryKey("jobs:reindex") == key {
+		t.Error("different strings should produce different keys")
+	}
+}
+
+func TestAdvisoryLock_MutualExclusion(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	key := StringAdvisoryKey("test:mutual-exclusion")
+
+	var holders int32
+	var maxHolders int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithAdvisoryLock(ctx, db, key, func() error {
+				n := atomic.AddInt32(&holders, 1)
+				if n > atomic.LoadInt32(&maxHolders) {
+					atomic.StoreInt32(&maxHolders, n)
+				}
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt32(&holders, -1)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("WithAdvisoryLock failed: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if maxHolders != 1 {
+		t.Errorf("Expected at most 1 concurrent lock holder, got %d", maxHolders)
+	}
+}
+
+func TestTryAdvisoryLock_HeldByOtherSession(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	key := StringAdvisoryKey("test:try-lock")
+
+	conn1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn1.Close()
+
+	conn2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn2.Close()
+
+	acquired, err := TryAdvisoryLock(ctx, conn1, key)
+	if err != nil || !acquired {
+		t.Fatalf("Expected first session to acquire lock, got acquired=%v err=%v", acquired, err)
+	}
+
+	acquired, err = TryAdvisoryLock(ctx, conn2, key)
+	if err != nil {
+		t.Fatalf("TryAdvisoryLock failed: %v", err)
+	}
+	if acquired {
+		t.Error("Second session should not acquire a held lock")
+	}
+
+	if err := AdvisoryUnlock(ctx, conn1, key); err != nil {
+		t.Fatalf("AdvisoryUnlock failed: %v", err)
+	}
+
+	acquired, err = TryAdvisoryLock(ctx, conn2, key)
+	if err != nil || !acquired {
+		t.Errorf("Expected second session to acquire released lock, got acquired=%v err=%v", acquired, err)
+	}
+	_ = AdvisoryUnlock(ctx, conn2, key)
+}
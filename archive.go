@@ -0,0 +1,96 @@
+package dbkit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ArchiveConfig configures ArchiveDeleted
+type ArchiveConfig struct {
+	ArchiveTable string // Table receiving the rows, with the schema of the source (required, see CreateArchiveTable)
+	ChunkSize    int    // Rows moved per transaction (default: 1000)
+}
+
+// CreateArchiveTable creates archiveTableName, if it does not exist, with the columns, defaults,
+// constraints and indexes of the table of T. Foreign keys are not copied, so archived rows
+// don't depend on the live tables.
+//
+// Usage:
+//
+//	err := dbkit.CreateArchiveTable[User](ctx, db, "users_archive")
+func CreateArchiveTable[T any](ctx context.Context, db IDB, archiveTableName string) error {
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	_, err := db.NewRaw("CREATE TABLE IF NOT EXISTS ? (LIKE ? INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING INDEXES)",
+		bun.Ident(archiveTableName), table.SQLName).Exec(ctx)
+	if err != nil {
+		return wrapError(err, "CreateArchiveTable")
+	}
+	return nil
+}
+
+// ArchiveDeleted moves the rows of T soft-deleted more than olderThan ago to cfg.ArchiveTable.
+// Each chunk is selected, inserted into the archive and deleted from the source in one
+// statement, so a failure leaves every row in exactly one table. Rows locked by other
+// transactions are skipped. Returns the number of rows moved.
+//
+// Usage:
+//
+//	moved, err := dbkit.ArchiveDeleted[User](ctx, db, 90*24*time.Hour, dbkit.ArchiveConfig{
+//	    ArchiveTable: "users_archive",
+//	})
+func ArchiveDeleted[T any](ctx context.Context, db bun.IDB, olderThan time.Duration, cfg ArchiveConfig) (int64, error) {
+	if cfg.ArchiveTable == "" {
+		return 0, &Error{
+			Code:    CodeValidation,
+			Message: "archive table is required",
+			Op:      "ArchiveDeleted",
+		}
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = 1000
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	if len(table.PKs) == 0 {
+		return 0, &Error{
+			Code:    CodeValidation,
+			Message: "archiving requires a primary key",
+			Op:      "ArchiveDeleted",
+			Table:   table.Name,
+		}
+	}
+	pkNames := make([]string, len(table.PKs))
+	for i, pk := range table.PKs {
+		pkNames[i] = string(pk.SQLName)
+	}
+	pks := bun.Safe(strings.Join(pkNames, ", "))
+	cutoff := time.Now().Add(-olderThan)
+
+	var total int64
+	for {
+		result, err := db.NewRaw(`
+            WITH chunk AS (
+                SELECT ? FROM ? WHERE deleted_at IS NOT NULL AND deleted_at < ?
+                ORDER BY deleted_at LIMIT ? FOR UPDATE SKIP LOCKED
+            ), moved AS (
+                DELETE FROM ? WHERE (?) IN (SELECT ? FROM chunk) RETURNING ?.*
+            )
+            INSERT INTO ? SELECT * FROM moved
+        `, pks, table.SQLName, cutoff, cfg.ChunkSize,
+			table.SQLName, pks, pks, table.SQLName,
+			bun.Ident(cfg.ArchiveTable)).Exec(ctx)
+		if err != nil {
+			return total, wrapError(err, "ArchiveDeleted")
+		}
+
+		rows, _ := result.RowsAffected()
+		total += rows
+		if rows < int64(cfg.ChunkSize) {
+			return total, nil
+		}
+	}
+}
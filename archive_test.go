@@ -0,0 +1,86 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveDeletedRequiresTable(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	_, err := ArchiveDeleted[TestSoftModel](context.Background(), db, time.Hour, ArchiveConfig{})
+	if !IsValidation(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestArchiveDeleted_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	if _, err := ArchiveDeleted[TestSoftModel](context.Background(), db, time.Hour, ArchiveConfig{ArchiveTable: "archive.soft"}); err != nil {
+		t.Fatalf("ArchiveDeleted failed: %v", err)
+	}
+	for _, want := range []string{
+		`FOR UPDATE SKIP LOCKED`,
+		`DELETE FROM "test_soft_models" WHERE ("id") IN (SELECT "id" FROM chunk) RETURNING "test_soft_models".*`,
+		`INSERT INTO "archive"."soft" SELECT * FROM moved`,
+	} {
+		if !strings.Contains(hook.last, want) {
+			t.Errorf("Expected SQL to contain %q, got %s", want, hook.last)
+		}
+	}
+}
+
+func TestArchiveDeleted(t *testing.T) {
+	db := getTestDB(t)
+	ctx, models := createSoftModels(t, db, 4)
+
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS test_soft_models_archive"); err != nil {
+		t.Fatalf("Failed to drop archive table: %v", err)
+	}
+	if err := CreateArchiveTable[TestSoftModel](ctx, db, "test_soft_models_archive"); err != nil {
+		t.Fatalf("CreateArchiveTable failed: %v", err)
+	}
+
+	// Three deleted records of different ages and one live record
+	for i, age := range []time.Duration{3 * time.Hour, 2 * time.Hour, 10 * time.Minute} {
+		_, err := db.NewUpdate().Model((*TestSoftModel)(nil)).
+			Set("deleted_at = ?", time.Now().Add(-age)).
+			Where("id = ?", models[i].ID).
+			Exec(ctx)
+		if err != nil {
+			t.Fatalf("Failed to soft delete: %v", err)
+		}
+	}
+
+	moved, err := ArchiveDeleted[TestSoftModel](ctx, db, time.Hour, ArchiveConfig{
+		ArchiveTable: "test_soft_models_archive",
+		ChunkSize:    1,
+	})
+	if err != nil {
+		t.Fatalf("ArchiveDeleted failed: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("Expected 2 archived records, got %d", moved)
+	}
+
+	var archived []string
+	if err := db.NewRaw("SELECT id FROM test_soft_models_archive ORDER BY deleted_at").Scan(ctx, &archived); err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	if len(archived) != 2 || archived[0] != models[0].ID || archived[1] != models[1].ID {
+		t.Errorf("Expected records 0 and 1 archived, got %v", archived)
+	}
+
+	remaining, err := db.NewSelect().Model((*TestSoftModel)(nil)).WhereAllWithDeleted().Count(ctx)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if remaining != 2 {
+		t.Errorf("Expected 2 records left in the source table, got %d", remaining)
+	}
+}
@@ -3,6 +3,7 @@ package dbkit
 import (
 	"context"
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -30,6 +31,7 @@ type AuditEntry struct {
 	UserAgent string          `json:"user_agent,omitempty"`
 	Metadata  json.RawMessage `json:"metadata,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
+	Signature string          `json:"signature,omitempty"` // Set by SignedAuditHandler
 }
 
 // AuditHandler is a function that handles audit entries.
@@ -271,6 +273,27 @@ type AuditLog struct {
 	CreatedAt time.Time       `bun:"created_at,notnull,default:current_timestamp"`
 }
 
+// SignedAuditLog is an AuditLog with the signature column written by SignedAuditHandler.
+// Only signed entries and VerifyAuditLog use it, so unsigned setups keep the AuditLog schema.
+// Add the column with AuditLogSignatureMigration before signing.
+type SignedAuditLog struct {
+	AuditLog `bun:",extend"`
+
+	Signature string `bun:"signature,nullzero"`
+}
+
+// AuditLogSignatureMigration adds the signature column to audit_logs. It is only needed for
+// SignedAuditHandler; unsigned entries are stored without it.
+//
+// Usage:
+//
+//	_, err := db.Migrate(ctx, append([]dbkit.Migration{dbkit.AuditLogSignatureMigration}, migrations...))
+var AuditLogSignatureMigration = Migration{
+	ID:          "dbkit_audit_logs_signature",
+	Description: "Add signature to audit_logs for signed entries",
+	SQL:         "ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS signature VARCHAR(64)",
+}
+
 // NewDatabaseAuditHandler creates an AuditHandler that stores entries in the database.
 //
 // Usage:
@@ -279,24 +302,69 @@ type AuditLog struct {
 //	dbkit.AuditCreate(ctx, handler, "users", user.ID, &user)
 func NewDatabaseAuditHandler(db bun.IDB) AuditHandler {
 	return func(ctx context.Context, entry *AuditEntry) error {
-		log := &AuditLog{
-			Action:    entry.Action,
-			TableName: entry.TableName,
-			RecordID:  entry.RecordID,
-			OldData:   entry.OldData,
-			NewData:   entry.NewData,
-			UserID:    entry.UserID,
-			IPAddress: entry.IPAddress,
-			UserAgent: entry.UserAgent,
-			Metadata:  entry.Metadata,
-			CreatedAt: entry.CreatedAt,
+		if entry.Signature != "" {
+			log := signedAuditLogFromEntry(entry)
+			_, err := db.NewInsert().Model(&log).Exec(ctx)
+			return err
 		}
+		log := auditLogFromEntry(entry)
+		_, err := db.NewInsert().Model(&log).Exec(ctx)
+		return err
+	}
+}
+
+// AuditBatchHandler handles several audit entries at once, see BulkAuditHandler.
+type AuditBatchHandler func(ctx context.Context, entries []*AuditEntry) error
 
-		_, err := db.NewInsert().Model(log).Exec(ctx)
+// NewDatabaseAuditBatchHandler creates an AuditBatchHandler that stores entries in the database
+// with one INSERT per batch.
+//
+// Usage:
+//
+//	handler, shutdown := dbkit.BulkAuditHandler(dbkit.NewDatabaseAuditBatchHandler(db), 100, time.Second)
+func NewDatabaseAuditBatchHandler(db bun.IDB) AuditBatchHandler {
+	return func(ctx context.Context, entries []*AuditEntry) error {
+		if len(entries) == 0 {
+			return nil
+		}
+		if slices.ContainsFunc(entries, func(e *AuditEntry) bool { return e.Signature != "" }) {
+			batch := make([]SignedAuditLog, len(entries))
+			for i, entry := range entries {
+				batch[i] = signedAuditLogFromEntry(entry)
+			}
+			_, err := db.NewInsert().Model(&batch).Exec(ctx)
+			return err
+		}
+		batch := make([]AuditLog, len(entries))
+		for i, entry := range entries {
+			batch[i] = auditLogFromEntry(entry)
+		}
+		_, err := db.NewInsert().Model(&batch).Exec(ctx)
 		return err
 	}
 }
 
+// auditLogFromEntry converts an entry to its database model
+func auditLogFromEntry(entry *AuditEntry) AuditLog {
+	return AuditLog{
+		Action:    entry.Action,
+		TableName: entry.TableName,
+		RecordID:  entry.RecordID,
+		OldData:   entry.OldData,
+		NewData:   entry.NewData,
+		UserID:    entry.UserID,
+		IPAddress: entry.IPAddress,
+		UserAgent: entry.UserAgent,
+		Metadata:  entry.Metadata,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+// signedAuditLogFromEntry converts an entry to its database model, signature included
+func signedAuditLogFromEntry(entry *AuditEntry) SignedAuditLog {
+	return SignedAuditLog{AuditLog: auditLogFromEntry(entry), Signature: entry.Signature}
+}
+
 // ContextKey is a type for context keys used by the audit system.
 type ContextKey string
 
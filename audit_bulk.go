@@ -0,0 +1,171 @@
+package dbkit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BulkAuditor buffers audit entries and hands them to a batch handler when BatchSize entries are
+// buffered or FlushInterval has elapsed since the first buffered entry.
+// Interval flushes have no caller to report to: a failed batch is logged with slog.Default and
+// its entries are dropped, wrap the batch handler to retry or spool them.
+type BulkAuditor struct {
+	inner         AuditBatchHandler
+	batchSize     int
+	flushInterval time.Duration
+
+	mu       sync.Mutex
+	buffer   []*AuditEntry
+	timer    *time.Timer
+	stopped  bool
+	flushing int           // Batches taken but not yet handled
+	idle     chan struct{} // Closed when flushing drops to zero
+	flushMu  sync.Mutex    // serializes calls to inner
+
+	pending atomic.Int64
+}
+
+// BulkAuditHandler returns an AuditHandler buffering entries for inner, and the function flushing the
+// remaining entries on shutdown. batchSize defaults to BatchSize and flushInterval to 1s.
+//
+// Usage:
+//
+//	handler, shutdown := dbkit.BulkAuditHandler(dbkit.NewDatabaseAuditBatchHandler(db), 100, time.Second)
+//	defer shutdown(ctx)
+//	hook := dbkit.NewAuditHook(dbkit.AuditConfig{Handler: handler})
+func BulkAuditHandler(inner AuditBatchHandler, batchSize int, flushInterval time.Duration) (AuditHandler, func(context.Context) error) {
+	b := NewBulkAuditor(inner, batchSize, flushInterval)
+	return b.Handle, b.Shutdown
+}
+
+// NewBulkAuditor creates a BulkAuditor, use it instead of BulkAuditHandler to monitor PendingCount.
+//
+// Usage:
+//
+//	auditor := dbkit.NewBulkAuditor(dbkit.NewDatabaseAuditBatchHandler(db), 100, time.Second)
+//	defer auditor.Shutdown(ctx)
+//	dbkit.AuditCreate(ctx, auditor.Handle, "users", user.ID, &user)
+func NewBulkAuditor(inner AuditBatchHandler, batchSize int, flushInterval time.Duration) *BulkAuditor {
+	if batchSize < 1 {
+		batchSize = BatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	return &BulkAuditor{
+		inner:         inner,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+	}
+}
+
+// Handle buffers entry, it implements AuditHandler. The call that fills a batch flushes it and
+// returns the error of the batch handler. Returns an error once Shutdown was called.
+func (b *BulkAuditor) Handle(ctx context.Context, entry *AuditEntry) error {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return &Error{Code: CodeUnsupported, Message: "bulk auditor is shut down", Op: "BulkAuditor.Handle"}
+	}
+	b.buffer = append(b.buffer, entry)
+	b.pending.Add(1)
+
+	if len(b.buffer) < b.batchSize {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.flushInterval, b.flushBuffered)
+		}
+		b.mu.Unlock()
+		return nil
+	}
+	batch := b.take()
+	b.mu.Unlock()
+
+	return b.flush(ctx, batch)
+}
+
+// PendingCount returns the number of entries not yet handled by the batch handler.
+func (b *BulkAuditor) PendingCount() int {
+	return int(b.pending.Load())
+}
+
+// Shutdown flushes the buffered entries, rejects later ones and waits, until ctx is done, for
+// the batches other calls or the interval flush are still handling.
+func (b *BulkAuditor) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	b.stopped = true
+	batch := b.take()
+	b.mu.Unlock()
+
+	err := b.flush(ctx, batch)
+
+	b.mu.Lock()
+	if b.flushing == 0 {
+		b.mu.Unlock()
+		return err
+	}
+	idle := b.idle
+	b.mu.Unlock()
+
+	select {
+	case <-idle:
+		return err
+	case <-ctx.Done():
+		return wrapError(ctx.Err(), "BulkAuditor.Shutdown")
+	}
+}
+
+// take empties the buffer, counting it as an in-flight batch, and cancels the interval flush.
+// b.mu must be held.
+func (b *BulkAuditor) take() []*AuditEntry {
+	batch := b.buffer
+	b.buffer = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(batch) > 0 {
+		if b.flushing == 0 {
+			b.idle = make(chan struct{})
+		}
+		b.flushing++
+	}
+	return batch
+}
+
+// flushBuffered is the interval flush, logging failures as there is no caller to return them to.
+// The entries of a failed batch are dropped.
+func (b *BulkAuditor) flushBuffered() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+
+	if err := b.flush(context.Background(), batch); err != nil {
+		slog.Default().Error("audit batch flush failed", slog.Int("entries", len(batch)), slog.Any("error", err))
+	}
+}
+
+// flush hands a batch returned by take to the batch handler
+func (b *BulkAuditor) flush(ctx context.Context, batch []*AuditEntry) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	defer b.done()
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	defer b.pending.Add(-int64(len(batch)))
+
+	return b.inner(ctx, batch)
+}
+
+// done ends an in-flight batch, waking Shutdown after the last one
+func (b *BulkAuditor) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushing--
+	if b.flushing == 0 {
+		close(b.idle)
+	}
+}
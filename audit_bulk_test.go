@@ -0,0 +1,173 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecorder is an AuditBatchHandler keeping the size of each batch
+type batchRecorder struct {
+	mu    sync.Mutex
+	sizes []int
+	err   error
+}
+
+func (r *batchRecorder) handle(ctx context.Context, entries []*AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sizes = append(r.sizes, len(entries))
+	return r.err
+}
+
+func (r *batchRecorder) batches() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.sizes...)
+}
+
+func TestBulkAuditHandler_BatchSize(t *testing.T) {
+	ctx := context.Background()
+	recorder := &batchRecorder{}
+	handler, shutdown := BulkAuditHandler(recorder.handle, 100, time.Hour)
+
+	for i := range 500 {
+		if err := AuditCreate(ctx, handler, "users", "id", map[string]int{"i": i}); err != nil {
+			t.Fatalf("AuditCreate failed: %v", err)
+		}
+	}
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	sizes := recorder.batches()
+	if len(sizes) != 5 {
+		t.Fatalf("Expected 5 batches, got %v", sizes)
+	}
+	for _, size := range sizes {
+		if size != 100 {
+			t.Errorf("Expected batches of 100, got %v", sizes)
+			break
+		}
+	}
+
+	if err := handler(ctx, &AuditEntry{}); err == nil {
+		t.Error("Expected entries after shutdown to be rejected")
+	}
+}
+
+func TestBulkAuditor_FlushInterval(t *testing.T) {
+	ctx := context.Background()
+	recorder := &batchRecorder{}
+	auditor := NewBulkAuditor(recorder.handle, 100, 10*time.Millisecond)
+
+	for range 3 {
+		_ = auditor.Handle(ctx, &AuditEntry{Action: AuditActionUpdate})
+	}
+	if n := auditor.PendingCount(); n != 3 {
+		t.Errorf("Expected 3 pending entries, got %d", n)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for auditor.PendingCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sizes := recorder.batches(); len(sizes) != 1 || sizes[0] != 3 {
+		t.Errorf("Expected one interval flush of 3 entries, got %v", sizes)
+	}
+
+	// Shutdown flushes what is left
+	_ = auditor.Handle(ctx, &AuditEntry{Action: AuditActionDelete})
+	if err := auditor.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if sizes := recorder.batches(); len(sizes) != 2 || sizes[1] != 1 || auditor.PendingCount() != 0 {
+		t.Errorf("Expected the last entry to be flushed on shutdown, got %v", sizes)
+	}
+}
+
+func TestBulkAuditor_ShutdownWaitsForFlushes(t *testing.T) {
+	ctx := context.Background()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var handled sync.WaitGroup
+	handled.Add(1)
+	auditor := NewBulkAuditor(func(ctx context.Context, entries []*AuditEntry) error {
+		close(started)
+		<-release
+		handled.Done()
+		return nil
+	}, 1, time.Hour)
+
+	go func() { _ = auditor.Handle(ctx, &AuditEntry{}) }()
+	<-started
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := auditor.Shutdown(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected Shutdown to wait for the in-flight batch until its deadline, got %v", err)
+	}
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- auditor.Shutdown(ctx) }()
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned while a batch was still being handled: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	handled.Wait()
+	if auditor.PendingCount() != 0 {
+		t.Errorf("Expected no pending entries, got %d", auditor.PendingCount())
+	}
+}
+
+func TestBulkAuditor_Error(t *testing.T) {
+	ctx := context.Background()
+	failed := errors.New("insert failed")
+	recorder := &batchRecorder{err: failed}
+	auditor := NewBulkAuditor(recorder.handle, 2, time.Hour)
+
+	if err := auditor.Handle(ctx, &AuditEntry{}); err != nil {
+		t.Errorf("Expected buffering to succeed, got %v", err)
+	}
+	if err := auditor.Handle(ctx, &AuditEntry{}); !errors.Is(err, failed) {
+		t.Errorf("Expected the batch error from the call filling the batch, got %v", err)
+	}
+	if auditor.PendingCount() != 0 {
+		t.Errorf("Expected no pending entries, got %d", auditor.PendingCount())
+	}
+}
+
+func TestNewDatabaseAuditBatchHandler(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*AuditLog)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*AuditLog)(nil)).IfExists().Exec(ctx)
+	_, _ = db.NewDelete().Model((*AuditLog)(nil)).Where("1=1").Exec(ctx)
+
+	handler, shutdown := BulkAuditHandler(NewDatabaseAuditBatchHandler(db), 50, time.Hour)
+	for range 120 {
+		if err := AuditCreate(ctx, handler, "users", "u1", nil); err != nil {
+			t.Fatalf("AuditCreate failed: %v", err)
+		}
+	}
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	count, err := db.NewSelect().Model((*AuditLog)(nil)).Count(ctx)
+	if err != nil || count != 120 {
+		t.Errorf("Expected 120 stored entries, got %d, %v", count, err)
+	}
+}
@@ -0,0 +1,121 @@
+package dbkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AuditFilter selects audit logs by QueryAuditLogs. Nil fields are not filtered on.
+type AuditFilter struct {
+	Action    *AuditAction
+	TableName *string
+	RecordID  *string
+	UserID    *string
+	Since     *time.Time // Inclusive
+	Until     *time.Time // Exclusive
+}
+
+// AuditStats summarizes the audit logs created since a point in time.
+type AuditStats struct {
+	TotalEntries int                 `json:"total_entries"`
+	ByAction     map[AuditAction]int `json:"by_action"`
+	ByTable      map[string]int      `json:"by_table"`
+	UniqueUsers  int                 `json:"unique_users"` // Entries without a user are not counted
+}
+
+// apply adds the conditions of the filter to q
+func (f AuditFilter) apply(q *bun.SelectQuery) *bun.SelectQuery {
+	if f.Action != nil {
+		q = q.Where("al.action = ?", *f.Action)
+	}
+	if f.TableName != nil {
+		q = q.Where("al.table_name = ?", *f.TableName)
+	}
+	if f.RecordID != nil {
+		q = q.Where("al.record_id = ?", *f.RecordID)
+	}
+	if f.UserID != nil {
+		q = q.Where("al.user_id = ?", *f.UserID)
+	}
+	if f.Since != nil {
+		q = q.Where("al.created_at >= ?", *f.Since)
+	}
+	if f.Until != nil {
+		q = q.Where("al.created_at < ?", *f.Until)
+	}
+	return q
+}
+
+// QueryAuditLogs returns a page of the audit logs matching filter, newest first, with the total count.
+//
+// Usage:
+//
+//	action := dbkit.AuditActionDelete
+//	page, err := dbkit.QueryAuditLogs(ctx, db, dbkit.AuditFilter{Action: &action, Since: &lastWeek}, 1, 50)
+func QueryAuditLogs(ctx context.Context, db IDB, filter AuditFilter, page, pageSize int) (*OffsetPage[AuditLog], error) {
+	return PaginateWithCount[AuditLog](ctx, db, page, pageSize, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return filter.apply(q).OrderExpr("al.created_at DESC, al.id DESC")
+	})
+}
+
+// AuditLogTimeline returns the whole history of one record, oldest first.
+//
+// Usage:
+//
+//	history, err := dbkit.AuditLogTimeline(ctx, db, "users", user.ID)
+func AuditLogTimeline(ctx context.Context, db IDB, tableName, recordID string) ([]AuditLog, error) {
+	logs := []AuditLog{}
+	err := db.NewSelect().Model(&logs).
+		Where("al.table_name = ?", tableName).
+		Where("al.record_id = ?", recordID).
+		OrderExpr("al.created_at ASC, al.id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, wrapError(err, "AuditLogTimeline")
+	}
+	return logs, nil
+}
+
+// AuditLogStats counts the audit logs created since the given time by action, table and user.
+//
+// Usage:
+//
+//	stats, err := dbkit.AuditLogStats(ctx, db, time.Now().Add(-24*time.Hour))
+//	fmt.Println(stats.ByAction[dbkit.AuditActionDelete], stats.UniqueUsers)
+func AuditLogStats(ctx context.Context, db IDB, since time.Time) (*AuditStats, error) {
+	var groups []struct {
+		Action    AuditAction `bun:"action"`
+		TableName string      `bun:"table_name"`
+		Count     int         `bun:"count"`
+	}
+	err := db.NewSelect().Model((*AuditLog)(nil)).
+		Column("action", "table_name").
+		ColumnExpr("COUNT(*) AS count").
+		Where("al.created_at >= ?", since).
+		Group("action", "table_name").
+		Scan(ctx, &groups)
+	if err != nil {
+		return nil, wrapError(err, "AuditLogStats")
+	}
+
+	stats := &AuditStats{
+		ByAction: make(map[AuditAction]int),
+		ByTable:  make(map[string]int),
+	}
+	for _, g := range groups {
+		stats.TotalEntries += g.Count
+		stats.ByAction[g.Action] += g.Count
+		stats.ByTable[g.TableName] += g.Count
+	}
+
+	err = db.NewSelect().Model((*AuditLog)(nil)).
+		ColumnExpr("COUNT(DISTINCT NULLIF(al.user_id, ''))").
+		Where("al.created_at >= ?", since).
+		Scan(ctx, &stats.UniqueUsers)
+	if err != nil {
+		return nil, wrapError(err, "AuditLogStats")
+	}
+	return stats, nil
+}
@@ -0,0 +1,166 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAuditFilter_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	action := AuditActionUpdate
+	table := "users"
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 1, 0)
+	sql := AuditFilter{Action: &action, TableName: &table, Since: &since, Until: &until}.
+		apply(db.NewSelect().Model((*AuditLog)(nil)).Column("id")).String()
+
+	want := `SELECT "al"."id" FROM "audit_logs" AS "al" WHERE (al.action = 'UPDATE') AND (al.table_name = 'users')` +
+		` AND (al.created_at >= '2024-01-01 00:00:00+00:00') AND (al.created_at < '2024-02-01 00:00:00+00:00')`
+	if sql != want {
+		t.Errorf("Expected %s, got %s", want, sql)
+	}
+
+	sql = AuditFilter{}.apply(db.NewSelect().Model((*AuditLog)(nil)).Column("id")).String()
+	if sql != `SELECT "al"."id" FROM "audit_logs" AS "al"` {
+		t.Errorf("Expected no conditions for an empty filter, got %s", sql)
+	}
+}
+
+// createAuditLogs inserts 20 entries: actions cycle create/update/delete, tables alternate
+// users/orders, records cycle r0..r3, users cycle u0..u3 and every fifth entry is anonymous,
+// and entry i was created i hours after base.
+func createAuditLogs(t *testing.T, db *DBKit) (context.Context, time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*AuditLog)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = db.NewDropTable().Model((*AuditLog)(nil)).IfExists().Exec(ctx) })
+	_, _ = db.NewDelete().Model((*AuditLog)(nil)).Where("1=1").Exec(ctx)
+
+	base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	actions := []AuditAction{AuditActionCreate, AuditActionUpdate, AuditActionDelete}
+	logs := make([]AuditLog, 20)
+	for i := range logs {
+		logs[i] = AuditLog{
+			Action:    actions[i%3],
+			TableName: []string{"users", "orders"}[i%2],
+			RecordID:  fmt.Sprintf("r%d", i%4),
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+		}
+		if i%5 != 4 {
+			logs[i].UserID = fmt.Sprintf("u%d", i%5)
+		}
+	}
+	if _, err := db.NewInsert().Model(&logs).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	return ctx, base
+}
+
+func TestQueryAuditLogs(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx, base := createAuditLogs(t, db)
+
+	create := AuditActionCreate
+	users := "users"
+	record := "r1"
+	user := "u2"
+	since := base.Add(15 * time.Hour)
+	until := base.Add(5 * time.Hour)
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		total  int
+		check  func(AuditLog) bool
+	}{
+		{"none", AuditFilter{}, 20, func(AuditLog) bool { return true }},
+		{"action", AuditFilter{Action: &create}, 7, func(l AuditLog) bool { return l.Action == create }},
+		{"table", AuditFilter{TableName: &users}, 10, func(l AuditLog) bool { return l.TableName == users }},
+		{"record", AuditFilter{RecordID: &record}, 5, func(l AuditLog) bool { return l.RecordID == record }},
+		{"user", AuditFilter{UserID: &user}, 4, func(l AuditLog) bool { return l.UserID == user }},
+		{"since", AuditFilter{Since: &since}, 5, func(l AuditLog) bool { return !l.CreatedAt.Before(since) }},
+		{"until", AuditFilter{Until: &until}, 5, func(l AuditLog) bool { return l.CreatedAt.Before(until) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := QueryAuditLogs(ctx, db, tt.filter, 1, 3)
+			if err != nil {
+				t.Fatalf("QueryAuditLogs failed: %v", err)
+			}
+			if page.TotalItems != tt.total {
+				t.Errorf("Expected %d entries, got %d", tt.total, page.TotalItems)
+			}
+			if len(page.Items) != min(3, tt.total) {
+				t.Errorf("Expected a page of %d, got %d", min(3, tt.total), len(page.Items))
+			}
+			for i, l := range page.Items {
+				if !tt.check(l) {
+					t.Errorf("Entry %+v does not match the filter", l)
+				}
+				if i > 0 && l.CreatedAt.After(page.Items[i-1].CreatedAt) {
+					t.Errorf("Expected newest entries first")
+				}
+			}
+		})
+	}
+}
+
+func TestAuditLogTimeline(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx, _ := createAuditLogs(t, db)
+
+	// users entries have even indexes, so r0 and r2 only
+	timeline, err := AuditLogTimeline(ctx, db, "users", "r2")
+	if err != nil {
+		t.Fatalf("AuditLogTimeline failed: %v", err)
+	}
+	if len(timeline) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(timeline))
+	}
+	for i, l := range timeline {
+		if l.TableName != "users" || l.RecordID != "r2" {
+			t.Errorf("Unexpected entry %+v", l)
+		}
+		if i > 0 && !l.CreatedAt.After(timeline[i-1].CreatedAt) {
+			t.Errorf("Expected chronological order")
+		}
+	}
+
+	empty, err := AuditLogTimeline(ctx, db, "orders", "r2")
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty slice, got %v, %v", empty, err)
+	}
+}
+
+func TestAuditLogStats(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx, base := createAuditLogs(t, db)
+
+	stats, err := AuditLogStats(ctx, db, base)
+	if err != nil {
+		t.Fatalf("AuditLogStats failed: %v", err)
+	}
+	if stats.TotalEntries != 20 || stats.UniqueUsers != 4 {
+		t.Errorf("Expected 20 entries by 4 users, got %+v", stats)
+	}
+	if stats.ByAction[AuditActionCreate] != 7 || stats.ByAction[AuditActionUpdate] != 7 || stats.ByAction[AuditActionDelete] != 6 {
+		t.Errorf("Unexpected counts by action %v", stats.ByAction)
+	}
+	if stats.ByTable["users"] != 10 || stats.ByTable["orders"] != 10 {
+		t.Errorf("Unexpected counts by table %v", stats.ByTable)
+	}
+
+	stats, err = AuditLogStats(ctx, db, base.Add(18*time.Hour))
+	if err != nil || stats.TotalEntries != 2 {
+		t.Errorf("Expected 2 recent entries, got %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,169 @@
+package dbkit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedAuditHandler(t *testing.T) {
+	ctx := context.Background()
+	key := []byte("audit-secret")
+
+	var signed *AuditEntry
+	handler := SignedAuditHandler(func(ctx context.Context, entry *AuditEntry) error {
+		signed = entry
+		return nil
+	}, key)
+	if err := AuditUpdate(ctx, handler, "users", "u1", map[string]any{"name": "Alice", "age": 30}, map[string]any{"name": "Bob", "age": 31}); err != nil {
+		t.Fatalf("AuditUpdate failed: %v", err)
+	}
+	if len(signed.Signature) != 64 {
+		t.Fatalf("Expected a hex SHA-256 signature, got %q", signed.Signature)
+	}
+	if signed.CreatedAt.Location() != time.UTC || signed.CreatedAt.Nanosecond()%1000 != 0 {
+		t.Errorf("Expected CreatedAt in UTC microseconds, got %v", signed.CreatedAt)
+	}
+
+	log := signedAuditLogFromEntry(signed)
+	if !verifyAuditLog(log, key) {
+		t.Fatal("Expected the signed entry to verify")
+	}
+
+	// JSONB reorders keys and drops whitespace
+	reordered := log
+	reordered.NewData = json.RawMessage(`{ "age": 31,  "name": "Bob" }`)
+	reordered.CreatedAt = log.CreatedAt.In(time.FixedZone("CET", 3600))
+	if !verifyAuditLog(reordered, key) {
+		t.Error("Expected an equivalent JSON encoding to verify")
+	}
+
+	tampered := log
+	tampered.NewData = json.RawMessage(`{"age": 31, "name": "Mallory"}`)
+	if verifyAuditLog(tampered, key) {
+		t.Error("Expected altered data to fail verification")
+	}
+	tampered = log
+	tampered.UserID = "someone-else"
+	if verifyAuditLog(tampered, key) {
+		t.Error("Expected an altered user to fail verification")
+	}
+
+	if verifyAuditLog(log, []byte("other-key")) {
+		t.Error("Expected another key to fail verification")
+	}
+	log.Signature = ""
+	if verifyAuditLog(log, key) {
+		t.Error("Expected unsigned logs to fail verification")
+	}
+}
+
+func TestDatabaseAuditHandler_SignatureOptIn(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+	ctx := context.Background()
+
+	_ = AuditCreate(ctx, NewDatabaseAuditHandler(db), "users", "u1", map[string]any{"id": "u1"})
+	_ = NewDatabaseAuditBatchHandler(db)(ctx, []*AuditEntry{{Action: AuditActionCreate, TableName: "users", RecordID: "u1"}})
+	if len(hook.queries) != 2 {
+		t.Fatalf("Expected 2 inserts, got %v", hook.queries)
+	}
+	for _, query := range hook.queries {
+		if strings.Contains(query, "signature") {
+			t.Errorf("Expected unsigned inserts to leave out the signature column, got %s", query)
+		}
+	}
+
+	hook.queries = nil
+	_ = AuditCreate(ctx, SignedAuditHandler(NewDatabaseAuditHandler(db), []byte("audit-secret")), "users", "u1", map[string]any{"id": "u1"})
+	_ = NewDatabaseAuditBatchHandler(db)(ctx, []*AuditEntry{
+		{Action: AuditActionCreate, TableName: "users", RecordID: "u1"},
+		{Action: AuditActionCreate, TableName: "users", RecordID: "u2", Signature: "abc"},
+	})
+	if len(hook.queries) != 2 {
+		t.Fatalf("Expected 2 inserts, got %v", hook.queries)
+	}
+	for _, query := range hook.queries {
+		if !strings.Contains(query, `INSERT INTO "audit_logs"`) || !strings.Contains(query, `"signature"`) {
+			t.Errorf("Expected signed inserts to write the signature column of audit_logs, got %s", query)
+		}
+	}
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*SignedAuditLog)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*SignedAuditLog)(nil)).IfExists().Exec(ctx)
+	_, _ = db.NewDelete().Model((*SignedAuditLog)(nil)).Where("1=1").Exec(ctx)
+
+	key := []byte("audit-secret")
+	handler := SignedAuditHandler(NewDatabaseAuditHandler(db), key)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		if err := AuditCreate(ctx, handler, "users", id, map[string]any{"id": id, "roles": []string{"admin"}}); err != nil {
+			t.Fatalf("AuditCreate failed: %v", err)
+		}
+	}
+	var ids []string
+	if err := db.NewSelect().Model((*AuditLog)(nil)).Column("id").Order("record_id").Scan(ctx, &ids); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+
+	ok, err := VerifyAuditLog(ctx, db, ids[0], key)
+	if err != nil || !ok {
+		t.Fatalf("Expected the stored entry to verify, got %v, %v", ok, err)
+	}
+
+	// Alter the stored JSON directly
+	if _, err := db.NewRaw(`UPDATE audit_logs SET new_data = jsonb_set(new_data, '{roles}', '["superuser"]') WHERE id = ?`, ids[0]).Exec(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if ok, err := VerifyAuditLog(ctx, db, ids[0], key); err != nil || ok {
+		t.Errorf("Expected the altered entry to fail verification, got %v, %v", ok, err)
+	}
+	if _, err := VerifyAuditLog(ctx, db, "00000000-0000-0000-0000-000000000000", key); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	total, invalid, err := VerifyAuditLogRange(ctx, db, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), key)
+	if err != nil || total != 3 || invalid != 1 {
+		t.Errorf("Expected 1 invalid of 3, got %d of %d, %v", invalid, total, err)
+	}
+}
+
+func TestAuditLogSignatureMigration(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*AuditLog)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*AuditLog)(nil)).IfExists().Exec(ctx)
+
+	// Unsigned entries don't need the signature column
+	if err := AuditCreate(ctx, NewDatabaseAuditHandler(db), "users", "u1", map[string]any{"id": "u1"}); err != nil {
+		t.Fatalf("Expected unsigned audit entries to be stored without the migration, got %v", err)
+	}
+	if err := NewDatabaseAuditBatchHandler(db)(ctx, []*AuditEntry{{Action: AuditActionCreate, TableName: "users", RecordID: "u2"}}); err != nil {
+		t.Fatalf("Expected unsigned batches to be stored without the migration, got %v", err)
+	}
+
+	for range 2 {
+		if _, err := db.ExecContext(ctx, AuditLogSignatureMigration.SQL); err != nil {
+			t.Fatalf("Migration failed: %v", err)
+		}
+	}
+
+	handler := SignedAuditHandler(NewDatabaseAuditHandler(db), []byte("audit-secret"))
+	if err := AuditCreate(ctx, handler, "users", "u3", map[string]any{"id": "u3"}); err != nil {
+		t.Errorf("Expected signed audit entries to be stored after the migration, got %v", err)
+	}
+}
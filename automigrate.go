@@ -0,0 +1,201 @@
+package dbkit
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// autoMigrationsTable records the DDL executed by AutoMigrate
+const autoMigrationsTable = `
+CREATE TABLE IF NOT EXISTS _dbkit_automigrations (
+    id BIGSERIAL PRIMARY KEY,
+    table_name VARCHAR(255) NOT NULL,
+    statement TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+`
+
+// AutoMigrateOptions configures AutoMigrateWithOptions
+type AutoMigrateOptions struct {
+	DropUnknownColumns   bool // Drop columns that have no field in the model
+	DryRun               bool // Return the statements without executing them
+	AddUpdatedAtTriggers bool // Install an updated_at trigger on tables with an updated_at column, see UpdatedAtTriggerSQL
+}
+
+// AutoMigrate creates the tables of models that do not exist and adds the columns missing
+// from existing tables, using the Bun struct tags. It is a development convenience: column
+// types, constraints and indexes of existing columns are never changed, so use Migrate
+// in production.
+//
+// Usage:
+//
+//	if err := db.AutoMigrate(ctx, (*User)(nil), (*Order)(nil)); err != nil {
+//	    log.Fatal(err)
+//	}
+func (db *DBKit) AutoMigrate(ctx context.Context, models ...any) error {
+	_, err := db.AutoMigrateWithOptions(ctx, AutoMigrateOptions{}, models...)
+	return err
+}
+
+// AutoMigrateWithOptions is AutoMigrate with options. It returns the executed statements,
+// or with DryRun the statements that would be executed.
+// Added columns are nullable unless the field has a default, as existing rows need a value.
+//
+// Usage:
+//
+//	stmts, err := db.AutoMigrateWithOptions(ctx, dbkit.AutoMigrateOptions{DryRun: true}, (*User)(nil))
+//	for _, stmt := range stmts {
+//	    fmt.Println(stmt)
+//	}
+func (db *DBKit) AutoMigrateWithOptions(ctx context.Context, opts AutoMigrateOptions, models ...any) ([]string, error) {
+	if !opts.DryRun {
+		if _, err := db.ExecContext(ctx, autoMigrationsTable); err != nil {
+			return nil, &Error{
+				Code:    CodeUnknown,
+				Message: "failed to create auto-migrations table",
+				Op:      "AutoMigrate",
+				Cause:   err,
+			}
+		}
+	}
+
+	var executed []string
+	for _, model := range models {
+		table := db.Table(reflect.TypeOf(model))
+
+		stmts, err := db.autoMigrateStatements(ctx, model, table, opts)
+		if err != nil {
+			return executed, err
+		}
+		if opts.DryRun || len(stmts) == 0 {
+			executed = append(executed, stmts...)
+			continue
+		}
+
+		err = db.Transaction(ctx, func(tx *Tx) error {
+			for _, stmt := range stmts {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return &Error{
+						Code:    CodeUnknown,
+						Message: "auto-migration failed: " + err.Error(),
+						Op:      "AutoMigrate",
+						Table:   table.Name,
+						Query:   truncateSQL(stmt, 200),
+						Cause:   err,
+					}
+				}
+				if _, err := tx.NewRaw("INSERT INTO _dbkit_automigrations (table_name, statement) VALUES (?, ?)",
+					table.Name, stmt).Exec(ctx); err != nil {
+					return wrapError(err, "AutoMigrate.Record")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return executed, err
+		}
+		executed = append(executed, stmts...)
+	}
+	return executed, nil
+}
+
+// autoMigrateStatements returns the DDL that brings the table of model in line with its fields
+func (db *DBKit) autoMigrateStatements(ctx context.Context, model any, table *schema.Table, opts AutoMigrateOptions) ([]string, error) {
+	var columns []string
+	err := db.NewRaw(`
+        SELECT column_name FROM information_schema.columns
+        WHERE table_schema = COALESCE(NULLIF(?, ''), current_schema()) AND table_name = ?
+    `, table.Schema, table.Name).Scan(ctx, &columns)
+	if err != nil {
+		return nil, wrapError(err, "AutoMigrate.Columns")
+	}
+
+	if len(columns) == 0 {
+		stmt, err := db.renderDDL(db.NewCreateTable().Model(model).IfNotExists())
+		if err != nil {
+			return nil, err
+		}
+		return db.withUpdatedAtTrigger(ctx, table, opts, []string{stmt})
+	}
+
+	existing := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		existing[c] = true
+	}
+
+	var stmts []string
+	for _, field := range table.Fields {
+		if existing[field.Name] {
+			continue
+		}
+		stmt, err := db.renderDDL(db.NewAddColumn().Model(model).IfNotExists().
+			ColumnExpr("? ?", field.SQLName, bun.Safe(columnDefinition(field))))
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	if opts.DropUnknownColumns {
+		sort.Strings(columns)
+		for _, c := range columns {
+			if _, ok := table.FieldMap[c]; ok {
+				continue
+			}
+			stmt, err := db.renderDDL(db.NewDropColumn().Model(model).Column(c))
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, stmt)
+		}
+	}
+	return db.withUpdatedAtTrigger(ctx, table, opts, stmts)
+}
+
+// withUpdatedAtTrigger appends the updated_at trigger DDL to stmts when it is enabled and missing
+func (db *DBKit) withUpdatedAtTrigger(ctx context.Context, table *schema.Table, opts AutoMigrateOptions, stmts []string) ([]string, error) {
+	if !opts.AddUpdatedAtTriggers {
+		return stmts, nil
+	}
+	if _, ok := table.FieldMap["updated_at"]; !ok {
+		return stmts, nil
+	}
+
+	tableName := table.Name
+	if table.Schema != "" {
+		tableName = table.Schema + "." + table.Name
+	}
+	exists, err := updatedAtTriggerExists(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		stmts = append(stmts, UpdatedAtTriggerSQL(tableName))
+	}
+	return stmts, nil
+}
+
+// columnDefinition returns the type, default and NOT NULL clause of a column added to an existing table
+func columnDefinition(field *schema.Field) string {
+	def := field.CreateTableSQLType
+	if field.SQLDefault != "" {
+		def += " DEFAULT " + field.SQLDefault
+		if field.NotNull {
+			def += " NOT NULL"
+		}
+	}
+	return def
+}
+
+// renderDDL formats query as SQL
+func (db *DBKit) renderDDL(query schema.QueryAppender) (string, error) {
+	b, err := query.AppendQuery(db.QueryGen(), nil)
+	if err != nil {
+		return "", wrapError(err, "AutoMigrate")
+	}
+	return string(b), nil
+}
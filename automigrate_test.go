@@ -0,0 +1,126 @@
+package dbkit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// autoMigrateModel is TestModel with two added fields
+type autoMigrateModel struct {
+	bun.BaseModel `bun:"table:test_models,alias:tm"`
+	ID            string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Name          string    `bun:"name,notnull"`
+	Email         string    `bun:"email,notnull,unique"`
+	Age           int       `bun:"age"`
+	Active        bool      `bun:"active,notnull"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	Nickname      string    `bun:"nickname,notnull"`
+	Score         int       `bun:"score,notnull,default:0"`
+}
+
+func TestColumnDefinition(t *testing.T) {
+	db, _ := newFakeDB(t)
+	table := db.Table(reflect.TypeOf((*autoMigrateModel)(nil)))
+
+	tests := map[string]string{
+		"nickname":   "VARCHAR",
+		"score":      "BIGINT DEFAULT 0 NOT NULL",
+		"created_at": "TIMESTAMPTZ DEFAULT current_timestamp NOT NULL",
+		"age":        "BIGINT",
+	}
+	for column, want := range tests {
+		if got := columnDefinition(table.FieldMap[column]); got != want {
+			t.Errorf("columnDefinition(%s) = %q, want %q", column, got, want)
+		}
+	}
+}
+
+func TestAutoMigrateAddColumnSQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	table := db.Table(reflect.TypeOf((*autoMigrateModel)(nil)))
+	field := table.FieldMap["score"]
+
+	got, err := db.renderDDL(db.NewAddColumn().Model((*autoMigrateModel)(nil)).IfNotExists().
+		ColumnExpr("? ?", field.SQLName, bun.Safe(columnDefinition(field))))
+	if err != nil {
+		t.Fatalf("renderDDL failed: %v", err)
+	}
+	want := `ALTER TABLE "test_models" ADD IF NOT EXISTS "score" BIGINT DEFAULT 0 NOT NULL`
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestAutoMigrate(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if err := db.AutoMigrate(ctx, (*TestModel)(nil)); err != nil {
+		t.Fatalf("AutoMigrate (create) failed: %v", err)
+	}
+	if _, err := db.NewInsert().Model(&TestModel{Name: "Alice", Email: "alice@example.com"}).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	// DryRun plans the new columns without adding them
+	planned, err := db.AutoMigrateWithOptions(ctx, AutoMigrateOptions{DryRun: true}, (*autoMigrateModel)(nil))
+	if err != nil {
+		t.Fatalf("AutoMigrate (dry run) failed: %v", err)
+	}
+	if len(planned) != 2 || !strings.Contains(planned[0], `"nickname"`) || !strings.Contains(planned[1], `"score"`) {
+		t.Fatalf("unexpected plan: %v", planned)
+	}
+	if columnExists(t, db, "test_models", "nickname") {
+		t.Fatal("expected DryRun not to add columns")
+	}
+
+	if err := db.AutoMigrate(ctx, (*autoMigrateModel)(nil)); err != nil {
+		t.Fatalf("AutoMigrate (add columns) failed: %v", err)
+	}
+	for _, column := range []string{"nickname", "score"} {
+		if !columnExists(t, db, "test_models", column) {
+			t.Errorf("expected column %s to exist", column)
+		}
+	}
+
+	var recorded int
+	if err := db.NewRaw("SELECT count(*) FROM _dbkit_automigrations WHERE table_name = 'test_models' AND statement LIKE '%nickname%'").Scan(ctx, &recorded); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if recorded == 0 {
+		t.Error("expected the added column to be recorded")
+	}
+
+	// Nothing left to do
+	stmts, err := db.AutoMigrateWithOptions(ctx, AutoMigrateOptions{}, (*autoMigrateModel)(nil))
+	if err != nil || len(stmts) != 0 {
+		t.Errorf("expected no statements, got %v (%v)", stmts, err)
+	}
+
+	if _, err := db.AutoMigrateWithOptions(ctx, AutoMigrateOptions{DropUnknownColumns: true}, (*TestModel)(nil)); err != nil {
+		t.Fatalf("AutoMigrate (drop columns) failed: %v", err)
+	}
+	if columnExists(t, db, "test_models", "nickname") || columnExists(t, db, "test_models", "score") {
+		t.Error("expected unknown columns to be dropped")
+	}
+}
+
+func columnExists(t *testing.T, db *DBKit, table, column string) bool {
+	t.Helper()
+	var exists bool
+	err := db.NewRaw(`SELECT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?)`, table, column).
+		Scan(context.Background(), &exists)
+	if err != nil {
+		t.Fatalf("column lookup failed: %v", err)
+	}
+	return exists
+}
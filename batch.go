@@ -3,6 +3,11 @@ package dbkit
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 
 	"github.com/uptrace/bun"
 )
@@ -47,6 +52,90 @@ func BatchInsert[T any](ctx context.Context, db bun.IDB, items []T, batchSize in
 	return totalRows, nil
 }
 
+// BatchError describes a batch that failed in BatchInsertCollectErrors.
+type BatchError[T any] struct {
+	BatchIndex int // Zero-based index of the batch
+	Items      []T // Items of the failing batch
+	Err        error
+}
+
+func (e BatchError[T]) Error() string {
+	return fmt.Sprintf("batch %d (%d items): %v", e.BatchIndex, len(e.Items), e.Err)
+}
+
+func (e BatchError[T]) Unwrap() error {
+	return e.Err
+}
+
+// BatchInsertCollectErrors inserts records in batches, continuing after failing batches.
+// Each batch runs in its own transaction (or savepoint if db is a transaction), so a
+// failing batch does not affect the others.
+// Returns the total number of rows inserted and the failing batches.
+//
+// Usage:
+//
+//	count, errs := dbkit.BatchInsertCollectErrors(ctx, db, users, 100)
+//	for _, e := range errs {
+//	    log.Printf("batch %d failed: %v", e.BatchIndex, e.Err)
+//	}
+func BatchInsertCollectErrors[T any](ctx context.Context, db bun.IDB, items []T, batchSize int) (int64, []BatchError[T]) {
+	if batchSize <= 0 {
+		batchSize = BatchSize
+	}
+
+	var total int64
+	var errs []BatchError[T]
+	for i := 0; i < len(items); i += batchSize {
+		end := min(i+batchSize, len(items))
+		batch := items[i:end]
+
+		var rows int64
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			res, err := tx.NewInsert().Model(&batch).Exec(ctx)
+			if err != nil {
+				return err
+			}
+			rows, _ = res.RowsAffected()
+			return nil
+		})
+		if err != nil {
+			// Rows of a batch whose commit failed were not inserted
+			errs = append(errs, BatchError[T]{
+				BatchIndex: i / batchSize,
+				Items:      batch,
+				Err:        wrapError(err, "BatchInsertCollectErrors"),
+			})
+			continue
+		}
+		total += rows
+	}
+
+	return total, errs
+}
+
+// BatchInsertAllOrNothing inserts records in batches inside a single transaction.
+// If any batch fails, no rows are inserted.
+//
+// Usage:
+//
+//	count, err := dbkit.BatchInsertAllOrNothing(ctx, db, users, 100)
+func BatchInsertAllOrNothing[T any](ctx context.Context, db bun.IDB, items []T, batchSize int) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	var total int64
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		rows, err := BatchInsert(ctx, tx, items, batchSize)
+		total = rows
+		return err
+	})
+	if err != nil {
+		return 0, wrapError(err, "BatchInsertAllOrNothing")
+	}
+	return total, nil
+}
+
 // BatchUpdate updates records in batches.
 // Returns the total number of rows affected.
 //
@@ -126,6 +215,105 @@ func BatchDelete[T any](ctx context.Context, db bun.IDB, ids []string, batchSize
 	return totalRows, nil
 }
 
+// BulkUpdateByIDs sets columns to the same values on every record whose primary key is in ids,
+// in a single UPDATE statement. Returns the number of rows affected.
+//
+// Usage:
+//
+//	count, err := dbkit.BulkUpdateByIDs[User](ctx, db, []any{id1, id2}, map[string]any{"active": false})
+func BulkUpdateByIDs[T any](ctx context.Context, db IDB, ids []any, setClause map[string]any) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if len(setClause) == 0 {
+		return 0, &Error{Code: CodeUnknown, Message: "no columns to update", Op: "BulkUpdateByIDs"}
+	}
+
+	rows, err := bulkUpdateByIDs[T](ctx, db, ids, setClause)
+	if err != nil {
+		return 0, wrapError(err, "BulkUpdateByIDs")
+	}
+	return rows, nil
+}
+
+// BulkUpdateByIDsBatched is like BulkUpdateByIDs but issues one UPDATE per batchSize IDs,
+// keeping each statement below PostgreSQL's parameter limit.
+//
+// Usage:
+//
+//	count, err := dbkit.BulkUpdateByIDsBatched[User](ctx, db, ids, map[string]any{"active": false}, 1000)
+func BulkUpdateByIDsBatched[T any](ctx context.Context, db IDB, ids []any, setClause map[string]any, batchSize int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if len(setClause) == 0 {
+		return 0, &Error{Code: CodeUnknown, Message: "no columns to update", Op: "BulkUpdateByIDsBatched"}
+	}
+
+	if batchSize <= 0 {
+		batchSize = BatchSize
+	}
+
+	var totalRows int64
+
+	for i := 0; i < len(ids); i += batchSize {
+		end := i + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		rows, err := bulkUpdateByIDs[T](ctx, db, ids[i:end], setClause)
+		if err != nil {
+			return totalRows, wrapError(err, "BulkUpdateByIDsBatched")
+		}
+		totalRows += rows
+	}
+
+	return totalRows, nil
+}
+
+// bulkUpdateByIDs executes a single UPDATE ... WHERE pk IN (ids).
+func bulkUpdateByIDs[T any](ctx context.Context, db IDB, ids []any, setClause map[string]any) (int64, error) {
+	q := db.NewUpdate().Model((*T)(nil))
+
+	// Sorted for a stable statement text
+	for _, col := range slices.Sorted(maps.Keys(setClause)) {
+		q = q.Set("? = ?", bun.Ident(col), setClause[col])
+	}
+
+	result, err := q.Where("?PKs IN (?)", bun.In(ids)).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// BulkUpdateModels updates columns of every model to its own values in a single statement,
+// matching rows by primary key. Models without a matching row are ignored.
+//
+// Usage:
+//
+//	users[0].Name, users[1].Name = "A", "B"
+//	count, err := dbkit.BulkUpdateModels(ctx, db, users, []string{"name", "updated_at"})
+func BulkUpdateModels[T any](ctx context.Context, db IDB, models []T, columns []string) (int64, error) {
+	if len(models) == 0 {
+		return 0, nil
+	}
+	if len(columns) == 0 {
+		return 0, &Error{Code: CodeUnknown, Message: "no columns to update", Op: "BulkUpdateModels"}
+	}
+
+	result, err := db.NewUpdate().Model(&models).Column(columns...).Bulk().Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "BulkUpdateModels")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
 // BatchUpsert performs upsert (insert or update) in batches.
 // conflictColumns specifies which columns to check for conflicts.
 // updateColumns specifies which columns to update on conflict.
@@ -151,27 +339,112 @@ func BatchUpsert[T any](ctx context.Context, db bun.IDB, items []T, conflictColu
 			end = len(items)
 		}
 
-		batch := items[i:end]
-		q := db.NewInsert().Model(&batch).On("CONFLICT (" + joinColumns(conflictColumns) + ") DO UPDATE")
-
-		for _, col := range updateColumns {
-			q = q.Set(col + " = EXCLUDED." + col)
-		}
-
-		result, err := q.Exec(ctx)
+		rows, err := upsertBatch(ctx, db, items[i:end], conflictColumns, updateColumns)
 		if err != nil {
 			return totalRows, wrapError(err, "BatchUpsert")
 		}
-
-		rows, _ := result.RowsAffected()
 		totalRows += rows
 	}
 
 	return totalRows, nil
 }
 
+// upsertBatch performs a single multi-row upsert.
+func upsertBatch[T any](ctx context.Context, db bun.IDB, batch []T, conflictColumns, updateColumns []string) (int64, error) {
+	q := db.NewInsert().Model(&batch).On("CONFLICT (" + joinColumns(conflictColumns) + ") DO UPDATE")
+
+	for _, col := range updateColumns {
+		q = q.Set(col + " = EXCLUDED." + col)
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// WasIgnored reports whether an INSERT ... ON CONFLICT DO NOTHING skipped every row.
+//
+// Usage:
+//
+//	res, err := db.NewInsert().Model(&event).On("CONFLICT DO NOTHING").Exec(ctx)
+//	if err == nil && dbkit.WasIgnored(res) {
+//	    // Event already recorded
+//	}
+func WasIgnored(result sql.Result) bool {
+	if result == nil {
+		return true
+	}
+	rows, err := result.RowsAffected()
+	return err == nil && rows == 0
+}
+
+// InsertOrIgnore inserts a record, silently skipping it if it conflicts with an existing row.
+// When the row is skipped no error is returned and the model's primary key is left unchanged.
+//
+// Usage:
+//
+//	err := dbkit.InsertOrIgnore(ctx, db, &Event{ID: eventID, Type: "signup"})
+func InsertOrIgnore[T any](ctx context.Context, db IDB, model *T) error {
+	_, err := db.NewInsert().Model(model).On("CONFLICT DO NOTHING").Exec(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return wrapError(err, "InsertOrIgnore")
+	}
+	return nil
+}
+
+// InsertOrIgnoreMany inserts records in a single statement, skipping conflicting rows.
+// Returns the number of rows actually inserted.
+//
+// Usage:
+//
+//	inserted, err := dbkit.InsertOrIgnoreMany(ctx, db, events)
+func InsertOrIgnoreMany[T any](ctx context.Context, db IDB, models []T) (int64, error) {
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	// Without RETURNING, skipped rows cannot shift generated values onto the wrong models
+	result, err := db.NewInsert().Model(&models).On("CONFLICT DO NOTHING").Returning("NULL").Exec(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, wrapError(err, "InsertOrIgnoreMany")
+	}
+	if result == nil {
+		return 0, nil
+	}
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// BatchInsertOrIgnore inserts records in batches, skipping conflicting rows.
+// Returns the total number of rows actually inserted.
+//
+// Usage:
+//
+//	inserted, err := dbkit.BatchInsertOrIgnore(ctx, db, events, 500)
+func BatchInsertOrIgnore[T any](ctx context.Context, db IDB, items []T, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = BatchSize
+	}
+
+	var totalRows int64
+	for i := 0; i < len(items); i += batchSize {
+		end := min(i+batchSize, len(items))
+		rows, err := InsertOrIgnoreMany(ctx, db, items[i:end])
+		if err != nil {
+			return totalRows, wrapError(err, "BatchInsertOrIgnore")
+		}
+		totalRows += rows
+	}
+	return totalRows, nil
+}
+
 // InTransaction executes a function within a transaction.
 // This is an alias for DBKit.Transaction for use with plain bun.IDB.
+// The optional opts set the isolation level and retry policy, see TxOptions.
 //
 // Usage:
 //
@@ -179,8 +452,20 @@ func BatchUpsert[T any](ctx context.Context, db bun.IDB, items []T, conflictColu
 //	    // do work
 //	    return nil
 //	})
-func InTransaction(ctx context.Context, db *bun.DB, fn func(ctx context.Context, tx bun.Tx) error) error {
-	return db.RunInTx(ctx, nil, fn)
+func InTransaction(ctx context.Context, db *bun.DB, fn func(ctx context.Context, tx bun.Tx) error, opts ...TxOptions) error {
+	var txOpts TxOptions
+	var sqlOpts *sql.TxOptions
+	if len(opts) > 0 {
+		txOpts = opts[0]
+		sqlOpts = &sql.TxOptions{Isolation: txOpts.Isolation, ReadOnly: txOpts.ReadOnly}
+	}
+
+	if policy, ok := txRetryPolicy(ctx, txOpts); ok {
+		return RetryableOperation(ctx, policy, func(ctx context.Context) error {
+			return db.RunInTx(ctx, sqlOpts, fn)
+		})
+	}
+	return db.RunInTx(ctx, sqlOpts, fn)
 }
 
 // BulkInsertReturning inserts records and returns the inserted rows with generated values.
@@ -203,6 +488,133 @@ func BulkInsertReturning[T any](ctx context.Context, db bun.IDB, items []T) ([]T
 	return items, nil
 }
 
+// FindByID returns the record with the given primary key, a UUID string or an integer.
+// Returns ErrNotFound if there is none.
+//
+// Usage:
+//
+//	user, err := dbkit.FindByID[User](ctx, db, userID)
+//	invoice, err := dbkit.FindByID[Invoice](ctx, db, int64(42))
+func FindByID[T any](ctx context.Context, db IDB, id any) (*T, error) {
+	var model T
+	if err := db.NewSelect().Model(&model).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindByID")
+	}
+	return &model, nil
+}
+
+// Create inserts a record, filling in generated columns.
+// Models implementing Validatable are validated first.
+//
+// Usage:
+//
+//	err := dbkit.Create(ctx, db, &user)
+func Create[T any](ctx context.Context, db IDB, model *T) error {
+	if err := validateModel(ctx, model, "Create"); err != nil {
+		return err
+	}
+	if _, err := db.NewInsert().Model(model).Returning("*").Exec(ctx); err != nil {
+		return wrapError(err, "Create")
+	}
+	return nil
+}
+
+// CreateMany inserts records in a single statement, filling in generated columns.
+// Models implementing Validatable are validated first, nothing is inserted if one is invalid.
+//
+// Usage:
+//
+//	err := dbkit.CreateMany(ctx, db, users)
+func CreateMany[T any](ctx context.Context, db IDB, models []T) error {
+	if len(models) == 0 {
+		return nil
+	}
+	for i := range models {
+		if err := validateModel(ctx, &models[i], "CreateMany"); err != nil {
+			return err
+		}
+	}
+	if _, err := db.NewInsert().Model(&models).Returning("*").Exec(ctx); err != nil {
+		return wrapError(err, "CreateMany")
+	}
+	return nil
+}
+
+// Update updates all columns of a record by primary key.
+// Models implementing Validatable are validated first.
+//
+// Usage:
+//
+//	err := dbkit.Update(ctx, db, &user)
+func Update[T any](ctx context.Context, db IDB, model *T) error {
+	if err := validateModel(ctx, model, "Update"); err != nil {
+		return err
+	}
+	if _, err := db.NewUpdate().Model(model).WherePK().Exec(ctx); err != nil {
+		return wrapError(err, "Update")
+	}
+	return nil
+}
+
+// UpdateColumns updates the given columns of a record by primary key.
+// Models implementing Validatable are validated first.
+//
+// Usage:
+//
+//	err := dbkit.UpdateColumns(ctx, db, &user, "name", "updated_at")
+func UpdateColumns[T any](ctx context.Context, db IDB, model *T, columns ...string) error {
+	if err := validateModel(ctx, model, "UpdateColumns"); err != nil {
+		return err
+	}
+	if _, err := db.NewUpdate().Model(model).Column(columns...).WherePK().Exec(ctx); err != nil {
+		return wrapError(err, "UpdateColumns")
+	}
+	return nil
+}
+
+// ExistsByID checks if a record with the given primary key exists.
+//
+// Usage:
+//
+//	exists, err := dbkit.ExistsByID[User](ctx, db, userID)
+func ExistsByID[T any](ctx context.Context, db IDB, id any) (bool, error) {
+	exists, err := db.NewSelect().Model((*T)(nil)).Where("id = ?", id).Exists(ctx)
+	if err != nil {
+		return false, wrapError(err, "ExistsByID")
+	}
+	return exists, nil
+}
+
+// ExistsByField checks if a record with column set to value exists.
+//
+// Usage:
+//
+//	taken, err := dbkit.ExistsByField[User](ctx, db, "email", email)
+func ExistsByField[T any](ctx context.Context, db IDB, column string, value any) (bool, error) {
+	exists, err := db.NewSelect().Model((*T)(nil)).Where("?TableAlias.? = ?", bun.Ident(column), value).Exists(ctx)
+	if err != nil {
+		return false, wrapError(err, "ExistsByField")
+	}
+	return exists, nil
+}
+
+// DeleteByID deletes the record with the given primary key (soft delete for soft-deletable models).
+// Returns ErrNotFound if there is none.
+//
+// Usage:
+//
+//	err := dbkit.DeleteByID[User](ctx, db, userID)
+func DeleteByID[T any](ctx context.Context, db IDB, id any) error {
+	result, err := db.NewDelete().Model((*T)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return wrapError(err, "DeleteByID")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "DeleteByID"}
+	}
+	return nil
+}
+
 // Exists checks if any record matches the query.
 //
 // Usage:
@@ -271,6 +683,199 @@ func Pluck[T any, V any](ctx context.Context, db bun.IDB, column string, queryFn
 	return values, nil
 }
 
+// PluckPaginated extracts a single column from matching records in pages of pageSize (default: BatchSize),
+// calling fn for each page. Pages are fetched with keyset pagination ordered by column, so column must be
+// unique (e.g. the primary key) and queryFn must not add its own ordering. Stops at the first error of fn.
+//
+// Usage:
+//
+//	err := dbkit.PluckPaginated[User, string](ctx, db, "email", 500, nil, func(emails []string) error {
+//	    return mailer.Enqueue(ctx, emails)
+//	})
+func PluckPaginated[T any, V any](ctx context.Context, db IDB, column string, pageSize int, queryFn func(*bun.SelectQuery) *bun.SelectQuery, fn func(page []V) error) error {
+	if pageSize < 1 {
+		pageSize = BatchSize
+	}
+
+	var last *V
+	for {
+		var page []V
+
+		q := db.NewSelect().Model((*T)(nil)).Column(column)
+		if queryFn != nil {
+			q = queryFn(q)
+		}
+		if last != nil {
+			q = q.Where("?TableAlias.? > ?", bun.Ident(column), *last)
+		}
+		q = q.OrderExpr("?TableAlias.? ASC", bun.Ident(column)).Limit(pageSize)
+
+		if err := q.Scan(ctx, &page); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return wrapError(err, "PluckPaginated")
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		last = &page[len(page)-1]
+	}
+}
+
+// PluckIDs extracts the UUID primary keys of matching records in pages of pageSize, see PluckPaginated.
+//
+// Usage:
+//
+//	err := dbkit.PluckIDs[User](ctx, db, nil, func(ids []string) error {
+//	    return jobs.Enqueue(ctx, "reindex", ids)
+//	}, 1000)
+func PluckIDs[T any](ctx context.Context, db IDB, queryFn func(*bun.SelectQuery) *bun.SelectQuery, fn func([]string) error, pageSize int) error {
+	return PluckPaginated[T, string](ctx, db, "id", pageSize, queryFn, fn)
+}
+
+// PluckStruct selects several columns of T into a slice of small structs R.
+// R's bun tags must match the selected columns. Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	type userEmail struct {
+//	    ID    string `bun:"id"`
+//	    Email string `bun:"email"`
+//	}
+//	rows, err := dbkit.PluckStruct[User, userEmail](ctx, db, []string{"id", "email"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Where("active = ?", true)
+//	})
+func PluckStruct[T any, R any](ctx context.Context, db IDB, columns []string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]R, error) {
+	results := []R{}
+
+	q := db.NewSelect().Model((*T)(nil)).Column(columns...)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx, &results); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, wrapError(err, "PluckStruct")
+	}
+	return results, nil
+}
+
+// PluckMap selects several columns of T into maps keyed by column name.
+// Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	rows, err := dbkit.PluckMap[User](ctx, db, []string{"id", "email"}, nil)
+//	for _, row := range rows {
+//	    fmt.Println(row["email"])
+//	}
+func PluckMap[T any](ctx context.Context, db IDB, columns []string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]map[string]any, error) {
+	results := []map[string]any{}
+
+	q := db.NewSelect().Model((*T)(nil)).Column(columns...)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx, &results); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, wrapError(err, "PluckMap")
+	}
+	return results, nil
+}
+
+// PluckFirst returns a single column of the first matching record and whether one was found.
+// Use a pointer or sql.Null* type for V when the column may be NULL.
+//
+// Usage:
+//
+//	email, ok, err := dbkit.PluckFirst[User, string](ctx, db, "email", func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Where("id = ?", id)
+//	})
+func PluckFirst[T any, V any](ctx context.Context, db IDB, column string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (V, bool, error) {
+	var values []V
+
+	q := db.NewSelect().Model((*T)(nil)).Column(column)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	var zero V
+	if err := q.Limit(1).Scan(ctx, &values); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return zero, false, wrapError(err, "PluckFirst")
+	}
+	if len(values) == 0 {
+		return zero, false, nil
+	}
+	return values[0], true, nil
+}
+
+// FindDistinct returns T's records with distinct values of distinctColumns.
+// Only distinctColumns are selected, other fields are left zero. Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	users, err := dbkit.FindDistinct[User](ctx, db, []string{"country", "city"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Order("country", "city")
+//	})
+func FindDistinct[T any](ctx context.Context, db IDB, distinctColumns []string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]T, error) {
+	models := []T{}
+
+	q := db.NewSelect().Model(&models).Distinct().Column(distinctColumns...)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindDistinct")
+	}
+
+	return models, nil
+}
+
+// CountDistinct returns the number of distinct non-NULL values of column in matching records.
+//
+// Usage:
+//
+//	countries, err := dbkit.CountDistinct[User](ctx, db, "country", nil)
+func CountDistinct[T any](ctx context.Context, db IDB, column string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (int, error) {
+	var count int
+
+	q := db.NewSelect().Model((*T)(nil)).ColumnExpr("COUNT(DISTINCT ?)", bun.Ident(column))
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx, &count); err != nil {
+		return 0, wrapError(err, "CountDistinct")
+	}
+
+	return count, nil
+}
+
+// PluckDistinct extracts the distinct values of a single column from matching records.
+// Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	domains, err := dbkit.PluckDistinct[User, string](ctx, db, "email_domain", nil)
+func PluckDistinct[T any, V any](ctx context.Context, db IDB, column string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]V, error) {
+	values := []V{}
+
+	q := db.NewSelect().Model((*T)(nil)).Distinct().Column(column)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx, &values); err != nil {
+		return nil, wrapError(err, "PluckDistinct")
+	}
+
+	return values, nil
+}
+
 // UpdateReturning updates a record and returns the updated row.
 //
 // Usage:
@@ -298,50 +903,129 @@ func DeleteReturning[T any](ctx context.Context, db bun.IDB, model *T) (*T, erro
 	return model, nil
 }
 
-// FindOrCreate finds a record or creates it if it doesn't exist.
-// Returns the record and a boolean indicating if it was created.
+// FindOrCreate atomically finds a record or creates it if it doesn't exist.
+// It runs INSERT ... ON CONFLICT (conflictColumns) DO NOTHING RETURNING * and, if the
+// row already existed, selects it with queryFn (or by the model's conflict column values
+// when queryFn is nil). Concurrent callers never create duplicates.
+// The model is populated from the stored row in both cases; the boolean reports whether it was created.
 //
 // Usage:
 //
-//	user, created, err := dbkit.FindOrCreate(ctx, db, &User{Email: "test@example.com"},
+//	user, created, err := dbkit.FindOrCreate(ctx, db, &User{Email: "test@example.com"}, []string{"email"},
 //	    func(q *bun.SelectQuery) *bun.SelectQuery {
 //	        return q.Where("email = ?", "test@example.com")
 //	    })
-func FindOrCreate[T any](ctx context.Context, db bun.IDB, model *T, findFn func(*bun.SelectQuery) *bun.SelectQuery) (*T, bool, error) {
-	// Try to find first
-	var found T
-	q := db.NewSelect().Model(&found)
-	if findFn != nil {
-		q = findFn(q)
+func FindOrCreate[T any](ctx context.Context, db IDB, model *T, conflictColumns []string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (*T, bool, error) {
+	if len(conflictColumns) == 0 {
+		return nil, false, &Error{
+			Code:    CodeUnknown,
+			Message: "conflict columns are required",
+			Op:      "FindOrCreate",
+		}
 	}
 
-	err := q.Scan(ctx)
-	if err == nil {
-		return &found, false, nil
+	result, err := db.NewInsert().
+		Model(model).
+		On("CONFLICT (" + joinColumns(conflictColumns) + ") DO NOTHING").
+		Returning("*").
+		Exec(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, wrapError(err, "FindOrCreate.Create")
+	}
+	if err == nil && !WasIgnored(result) {
+		return model, true, nil
+	}
+
+	q := db.NewSelect().Model(model)
+	if queryFn != nil {
+		q = queryFn(q)
+	} else {
+		table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+		strct := reflect.ValueOf(model).Elem()
+		for _, col := range conflictColumns {
+			field, ok := table.FieldMap[col]
+			if !ok {
+				return nil, false, &Error{
+					Code:    CodeUnknown,
+					Message: fmt.Sprintf("unknown conflict column %q", col),
+					Op:      "FindOrCreate",
+					Table:   table.Name,
+					Column:  col,
+				}
+			}
+			q = q.Where("? = ?", bun.Ident(col), field.Value(strct).Interface())
+		}
 	}
 
-	if !IsNotFound(err) {
+	if err := q.Scan(ctx); err != nil {
 		return nil, false, wrapError(err, "FindOrCreate.Find")
 	}
+	return model, false, nil
+}
 
-	// Not found, create it
-	_, err = db.NewInsert().Model(model).Exec(ctx)
-	if err != nil {
-		// Check if it was created by another process (race condition)
-		if IsDuplicate(err) {
-			var retry T
-			retryQ := db.NewSelect().Model(&retry)
-			if findFn != nil {
-				retryQ = findFn(retryQ)
-			}
-			if retryErr := retryQ.Scan(ctx); retryErr == nil {
-				return &retry, false, nil
-			}
+// touchQuery returns an UPDATE that sets updated_at = NOW() on T's table.
+// It fails if T has no updated_at column.
+func touchQuery[T any](db IDB, op string) (*bun.UpdateQuery, error) {
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	if _, ok := table.FieldMap["updated_at"]; !ok {
+		return nil, &Error{
+			Code:    CodeValidation,
+			Message: fmt.Sprintf("%s has no updated_at column (embed dbkit.BaseModel or dbkit.TimestampedModel)", table.TypeName),
+			Op:      op,
+			Table:   table.Name,
+			Column:  "updated_at",
 		}
-		return nil, false, wrapError(err, "FindOrCreate.Create")
 	}
+	return db.NewUpdate().Model((*T)(nil)).Set("updated_at = NOW()"), nil
+}
 
-	return model, true, nil
+// Touch refreshes a record's updated_at without changing any other column.
+// Returns ErrNotFound if no record has the ID.
+//
+// Usage:
+//
+//	err := dbkit.Touch[User](ctx, db, userID)
+func Touch[T any](ctx context.Context, db IDB, id any) error {
+	q, err := touchQuery[T](db, "Touch")
+	if err != nil {
+		return err
+	}
+
+	result, err := q.Where("?PKs = ?", id).Exec(ctx)
+	if err != nil {
+		return wrapError(err, "Touch")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "Touch"}
+	}
+	return nil
+}
+
+// TouchWhere refreshes updated_at on all records matching the query, or on all records if
+// queryFn is nil. Returns the number of rows touched.
+//
+// Usage:
+//
+//	count, err := dbkit.TouchWhere[Session](ctx, db, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+//	    return q.Where("user_id = ?", userID)
+//	})
+func TouchWhere[T any](ctx context.Context, db IDB, queryFn func(*bun.UpdateQuery) *bun.UpdateQuery) (int64, error) {
+	q, err := touchQuery[T](db, "TouchWhere")
+	if err != nil {
+		return 0, err
+	}
+	if queryFn != nil {
+		q = queryFn(q)
+	} else {
+		q = q.Where("1=1")
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "TouchWhere")
+	}
+	rows, _ := result.RowsAffected()
+	return rows, nil
 }
 
 // RawQuery executes a raw SQL query and scans results into the destination.
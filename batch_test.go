@@ -2,7 +2,16 @@ package dbkit
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
 )
 
 func TestBatchInsert_Empty(t *testing.T) {
@@ -61,3 +70,622 @@ func TestBatchSize_Default(t *testing.T) {
 		t.Errorf("Expected default BatchSize to be 100, got %d", BatchSize)
 	}
 }
+
+func TestBatchInsertCollectErrors_Empty(t *testing.T) {
+	count, errs := BatchInsertCollectErrors[TestModel](context.Background(), nil, nil, 100)
+	if count != 0 || len(errs) != 0 {
+		t.Errorf("Expected no rows and no errors, got %d, %v", count, errs)
+	}
+}
+
+func TestBatchInsertCollectErrors(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	items := make([]TestModel, 30)
+	for i := range items {
+		items[i] = TestModel{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	// Batch 1 (items 10-19) contains a duplicate email
+	items[15].Email = items[12].Email
+
+	count, errs := BatchInsertCollectErrors(ctx, db, items, 10)
+	if count != 20 {
+		t.Errorf("Expected 20 rows from the healthy batches, got %d", count)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 failing batch, got %v", errs)
+	}
+	if errs[0].BatchIndex != 1 || len(errs[0].Items) != 10 || !IsDuplicate(errs[0].Err) {
+		t.Errorf("Unexpected batch error %+v", errs[0])
+	}
+
+	stored, err := Count[TestModel](ctx, db, nil)
+	if err != nil || stored != 20 {
+		t.Errorf("Expected 20 stored rows, got %d, %v", stored, err)
+	}
+}
+
+func TestBatchInsertAllOrNothing(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	items := make([]TestModel, 30)
+	for i := range items {
+		items[i] = TestModel{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	items[25].Email = items[0].Email
+
+	if _, err := BatchInsertAllOrNothing(ctx, db, items, 10); !IsDuplicate(err) {
+		t.Errorf("Expected duplicate error, got %v", err)
+	}
+
+	stored, err := Count[TestModel](ctx, db, nil)
+	if err != nil || stored != 0 {
+		t.Errorf("Expected no stored rows, got %d, %v", stored, err)
+	}
+}
+
+func TestWasIgnored(t *testing.T) {
+	if !WasIgnored(driver.RowsAffected(0)) {
+		t.Error("Expected 0 rows affected to be ignored")
+	}
+	if WasIgnored(driver.RowsAffected(1)) {
+		t.Error("Expected 1 row affected not to be ignored")
+	}
+}
+
+func TestInsertOrIgnore(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	first := TestModel{Name: "Event", Email: "event@example.com"}
+	if err := InsertOrIgnore(ctx, db, &first); err != nil {
+		t.Fatalf("First InsertOrIgnore failed: %v", err)
+	}
+	if first.ID == "" {
+		t.Error("Expected ID to be set on insert")
+	}
+
+	second := TestModel{Name: "Event again", Email: "event@example.com"}
+	if err := InsertOrIgnore(ctx, db, &second); err != nil {
+		t.Fatalf("Second InsertOrIgnore should not error: %v", err)
+	}
+	if second.ID != "" {
+		t.Errorf("Expected ID to stay empty when ignored, got %s", second.ID)
+	}
+
+	count, err := Count[TestModel](ctx, db, nil)
+	if err != nil || count != 1 {
+		t.Errorf("Expected exactly 1 row, got %d, %v", count, err)
+	}
+}
+
+func TestBatchInsertOrIgnore(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	items := make([]TestModel, 25)
+	for i := range items {
+		items[i] = TestModel{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i%20)}
+	}
+
+	inserted, err := BatchInsertOrIgnore(ctx, db, items, 10)
+	if err != nil {
+		t.Fatalf("BatchInsertOrIgnore failed: %v", err)
+	}
+	if inserted != 20 {
+		t.Errorf("Expected 20 inserted rows, got %d", inserted)
+	}
+
+	again, err := InsertOrIgnoreMany(ctx, db, items[:5])
+	if err != nil || again != 0 {
+		t.Errorf("Expected all duplicates to be ignored, got %d, %v", again, err)
+	}
+}
+
+func TestFindOrCreate_Concurrent(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	const workers = 50
+	var (
+		wg      sync.WaitGroup
+		created atomic.Int64
+		ids     sync.Map
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			model := &TestModel{Name: fmt.Sprintf("Worker %d", i), Email: "race@example.com"}
+			user, isNew, err := FindOrCreate(ctx, db, model, []string{"email"}, nil)
+			if err != nil {
+				t.Errorf("FindOrCreate failed: %v", err)
+				return
+			}
+			if isNew {
+				created.Add(1)
+			}
+			if user.ID == "" {
+				t.Error("Expected model to be populated with the stored ID")
+			}
+			ids.Store(user.ID, true)
+		}(i)
+	}
+	wg.Wait()
+
+	if created.Load() != 1 {
+		t.Errorf("Expected exactly one goroutine to create the record, got %d", created.Load())
+	}
+	count, err := Count[TestModel](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("email = ?", "race@example.com")
+	})
+	if err != nil || count != 1 {
+		t.Errorf("Expected exactly one record, got %d, %v", count, err)
+	}
+
+	distinct := 0
+	ids.Range(func(_, _ any) bool { distinct++; return true })
+	if distinct != 1 {
+		t.Errorf("Expected all goroutines to see the same record, got %d IDs", distinct)
+	}
+}
+
+func TestFindOrCreate_QueryFn(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	byEmail := func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("email = ?", "find@example.com")
+	}
+
+	first, created, err := FindOrCreate(ctx, db, &TestModel{Name: "First", Email: "find@example.com"}, []string{"email"}, byEmail)
+	if err != nil || !created {
+		t.Fatalf("Expected record to be created, got %v, %v", created, err)
+	}
+
+	second, created, err := FindOrCreate(ctx, db, &TestModel{Name: "Second", Email: "find@example.com"}, []string{"email"}, byEmail)
+	if err != nil || created {
+		t.Fatalf("Expected existing record, got %v, %v", created, err)
+	}
+	if second.ID != first.ID || second.Name != "First" {
+		t.Errorf("Expected the stored record, got %+v", second)
+	}
+}
+
+func TestFindOrCreate_NoConflictColumns(t *testing.T) {
+	if _, _, err := FindOrCreate[TestModel](context.Background(), nil, &TestModel{}, nil, nil); err == nil {
+		t.Error("Expected an error without conflict columns")
+	}
+}
+
+func TestTouch_NoUpdatedAt(t *testing.T) {
+	type counter struct {
+		bun.BaseModel `bun:"table:counters"`
+		ID            int64 `bun:"id,pk"`
+		Hits          int   `bun:"hits"`
+	}
+
+	db, _ := newFakeDB(t)
+	err := Touch[counter](context.Background(), db, 1)
+	if !IsValidation(err) || !strings.Contains(err.Error(), "updated_at") {
+		t.Errorf("Expected a missing updated_at validation error, got %v", err)
+	}
+	if _, err := TouchWhere[counter](context.Background(), db, nil); !IsValidation(err) {
+		t.Errorf("Expected TouchWhere to fail without updated_at, got %v", err)
+	}
+}
+
+func TestTouchWhere_NilQueryFn(t *testing.T) {
+	type session struct {
+		bun.BaseModel `bun:"table:sessions"`
+		ID            int64     `bun:"id,pk"`
+		UpdatedAt     time.Time `bun:"updated_at"`
+	}
+
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	if _, err := TouchWhere[session](context.Background(), db, nil); err != nil {
+		t.Fatalf("Expected TouchWhere to touch every row without a queryFn, got %v", err)
+	}
+	if !strings.Contains(hook.last, "WHERE (1=1)") {
+		t.Errorf("Expected an explicit WHERE, got %s", hook.last)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	original := TestModel{Name: "Toucher", Email: "touch@example.com", Age: 30, Active: true}
+	if _, err := db.NewInsert().Model(&original).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	var before TestModel
+	if err := db.NewSelect().Model(&before).Where("id = ?", original.ID).Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := Touch[TestModel](ctx, db, original.ID); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+
+	var after TestModel
+	if err := db.NewSelect().Model(&after).Where("id = ?", original.ID).Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if !after.UpdatedAt.After(before.UpdatedAt) {
+		t.Errorf("Expected updated_at to advance, before %v after %v", before.UpdatedAt, after.UpdatedAt)
+	}
+	if after.Name != before.Name || after.Email != before.Email || after.Age != before.Age ||
+		after.Active != before.Active || !after.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("Expected other columns to stay intact, before %+v after %+v", before, after)
+	}
+
+	if err := Touch[TestModel](ctx, db, "00000000-0000-0000-0000-000000000000"); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound for unknown ID, got %v", err)
+	}
+
+	touched, err := TouchWhere[TestModel](ctx, db, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("active = ?", true)
+	})
+	if err != nil || touched != 1 {
+		t.Errorf("Expected 1 touched row, got %d, %v", touched, err)
+	}
+}
+
+func TestPluckPaginated_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	calls := 0
+	err := PluckIDs[TestModel](context.Background(), db, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("active")
+	}, func(ids []string) error {
+		calls++
+		return nil
+	}, 50)
+	if err != nil {
+		t.Fatalf("PluckIDs failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no pages without rows, got %d", calls)
+	}
+	want := `SELECT "tm"."id" FROM "test_models" AS "tm" WHERE (active) ORDER BY "tm"."id" ASC LIMIT 50`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+}
+
+func TestPluckPaginated(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	models := make([]TestModel, 500)
+	for i := range models {
+		models[i] = TestModel{Name: "User", Email: fmt.Sprintf("user%d@example.com", i), Age: i}
+	}
+	if _, err := db.NewInsert().Model(&models).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	pages := 0
+	err := PluckIDs[TestModel](ctx, db, nil, func(ids []string) error {
+		pages++
+		if len(ids) != 50 {
+			t.Errorf("Expected pages of 50, got %d", len(ids))
+		}
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("ID %s received twice", id)
+			}
+			seen[id] = true
+		}
+		return nil
+	}, 50)
+	if err != nil {
+		t.Fatalf("PluckIDs failed: %v", err)
+	}
+	if len(seen) != 500 || pages != 10 {
+		t.Errorf("Expected 500 IDs in 10 pages, got %d in %d", len(seen), pages)
+	}
+
+	stop := errors.New("stop")
+	pages = 0
+	err = PluckPaginated[TestModel, int](ctx, db, "age", 100, nil, func(ages []int) error {
+		pages++
+		return stop
+	})
+	if !errors.Is(err, stop) || pages != 1 {
+		t.Errorf("Expected to stop at the first error, got %v after %d pages", err, pages)
+	}
+}
+
+func TestPluckStruct(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 4)
+
+	type userEmail struct {
+		ID    string `bun:"id"`
+		Email string `bun:"email"`
+	}
+	rows, err := PluckStruct[TestModel, userEmail](ctx, db, []string{"id", "email"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("active = ?", true).Order("email ASC")
+	})
+	if err != nil {
+		t.Fatalf("PluckStruct failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Email != "user0@example.com" || rows[0].ID == "" {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+
+	empty, err := PluckStruct[TestModel, userEmail](ctx, db, []string{"id", "email"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("email = ?", "nobody@example.com")
+	})
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty slice, got %v, %v", empty, err)
+	}
+}
+
+func TestPluckMap(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 3)
+	if _, err := db.NewUpdate().Model((*TestModel)(nil)).Set("age = NULL").Where("email = ?", "user1@example.com").Exec(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	rows, err := PluckMap[TestModel](ctx, db, []string{"email", "age"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("email ASC")
+	})
+	if err != nil {
+		t.Fatalf("PluckMap failed: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	if rows[1]["email"] != "user1@example.com" || rows[1]["age"] != nil {
+		t.Errorf("Expected NULL age for user1, got %v", rows[1])
+	}
+
+	empty, err := PluckMap[TestModel](ctx, db, []string{"email"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("1 = 0")
+	})
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty slice, got %v, %v", empty, err)
+	}
+}
+
+func TestPluckFirst(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 3)
+	if _, err := db.NewUpdate().Model((*TestModel)(nil)).Set("age = NULL").Where("email = ?", "user2@example.com").Exec(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	byEmail := func(email string) func(*bun.SelectQuery) *bun.SelectQuery {
+		return func(q *bun.SelectQuery) *bun.SelectQuery { return q.Where("email = ?", email) }
+	}
+
+	age, ok, err := PluckFirst[TestModel, *int](ctx, db, "age", byEmail("user1@example.com"))
+	if err != nil || !ok || age == nil || *age != 1 {
+		t.Errorf("Expected age 1, got %v, %v, %v", age, ok, err)
+	}
+
+	age, ok, err = PluckFirst[TestModel, *int](ctx, db, "age", byEmail("user2@example.com"))
+	if err != nil || !ok || age != nil {
+		t.Errorf("Expected NULL age to be found as nil, got %v, %v, %v", age, ok, err)
+	}
+
+	_, ok, err = PluckFirst[TestModel, string](ctx, db, "email", byEmail("nobody@example.com"))
+	if err != nil || ok {
+		t.Errorf("Expected no match without error, got %v, %v", ok, err)
+	}
+}
+
+func TestBulkUpdateByIDs_Empty(t *testing.T) {
+	count, err := BulkUpdateByIDs[TestModel](context.Background(), nil, nil, map[string]any{"age": 1})
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0, nil for no IDs, got %d, %v", count, err)
+	}
+
+	db, _ := newFakeDB(t)
+	if _, err := BulkUpdateByIDs[TestModel](context.Background(), db, []any{"id"}, nil); err == nil {
+		t.Error("Expected an error for an empty set clause")
+	}
+	if _, err := BulkUpdateModels(context.Background(), db, []TestModel{{}}, nil); err == nil {
+		t.Error("Expected an error for no columns")
+	}
+}
+
+func TestBulkUpdateByIDsBatched_QueryCount(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+
+	ids := make([]any, 25)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id-%d", i)
+	}
+	if _, err := BulkUpdateByIDsBatched[TestModel](context.Background(), db, ids, map[string]any{"active": false, "age": 0}, 10); err != nil {
+		t.Fatalf("BulkUpdateByIDsBatched failed: %v", err)
+	}
+	if hook.Count() != 3 {
+		t.Errorf("Expected 3 UPDATE statements for 25 IDs in batches of 10, got %d", hook.Count())
+	}
+}
+
+func TestBulkUpdateByIDs(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	models := insertTestModels(t, db, 60)
+
+	ids := make([]any, 50)
+	for i := range ids {
+		ids[i] = models[i].ID
+	}
+	count, err := BulkUpdateByIDs[TestModel](ctx, db, ids, map[string]any{"age": 99, "active": false})
+	if err != nil {
+		t.Fatalf("BulkUpdateByIDs failed: %v", err)
+	}
+	if count != 50 {
+		t.Errorf("Expected 50 rows affected, got %d", count)
+	}
+
+	updated, _ := Count[TestModel](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("age = 99 AND active = FALSE")
+	})
+	if updated != 50 {
+		t.Errorf("Expected 50 updated rows, got %d", updated)
+	}
+
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+	count, err = BulkUpdateByIDsBatched[TestModel](ctx, db, ids, map[string]any{"age": 1}, 20)
+	if err != nil {
+		t.Fatalf("BulkUpdateByIDsBatched failed: %v", err)
+	}
+	if count != 50 || hook.Count() != 3 {
+		t.Errorf("Expected 50 rows in 3 queries, got %d rows in %d queries", count, hook.Count())
+	}
+}
+
+func TestBulkUpdateModels(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	models := insertTestModels(t, db, 50)
+
+	for i := range models {
+		models[i].Name = fmt.Sprintf("Renamed %d", i)
+		models[i].Age = 50 + i
+		models[i].Email = "ignored@example.com" // not in columns, must stay unchanged
+	}
+	count, err := BulkUpdateModels(ctx, db, models, []string{"name", "age"})
+	if err != nil {
+		t.Fatalf("BulkUpdateModels failed: %v", err)
+	}
+	if count != 50 {
+		t.Errorf("Expected 50 rows affected, got %d", count)
+	}
+
+	var found []TestModel
+	if err := db.NewSelect().Model(&found).Order("age ASC").Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	for i, m := range found {
+		if m.Name != fmt.Sprintf("Renamed %d", i) || m.Age != 50+i || m.Email != fmt.Sprintf("user%d@example.com", i) {
+			t.Errorf("Unexpected row %d: %+v", i, m)
+		}
+	}
+}
+
+func insertDistinctModels(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := createTable(t, db)
+
+	// email is unique on test_models, so duplicates use name
+	models := []TestModel{
+		{Name: "Alice", Email: "alice1@example.com", Age: 30},
+		{Name: "Alice", Email: "alice2@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 40},
+	}
+	if _, err := db.NewInsert().Model(&models).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	return ctx
+}
+
+func TestPluckDistinct(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := insertDistinctModels(t, db)
+
+	names, err := PluckDistinct[TestModel, string](ctx, db, "name", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("name ASC")
+	})
+	if err != nil {
+		t.Fatalf("PluckDistinct failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Alice" || names[1] != "Bob" {
+		t.Errorf("Expected [Alice Bob], got %v", names)
+	}
+
+	empty, err := PluckDistinct[TestModel, string](ctx, db, "name", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("age > 100")
+	})
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty slice, got %v, %v", empty, err)
+	}
+}
+
+func TestFindDistinct(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := insertDistinctModels(t, db)
+
+	rows, err := FindDistinct[TestModel](ctx, db, []string{"name", "age"}, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("name ASC")
+	})
+	if err != nil {
+		t.Fatalf("FindDistinct failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Name != "Alice" || rows[0].Age != 30 || rows[1].Name != "Bob" {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+	if rows[0].Email != "" {
+		t.Errorf("Expected only distinct columns to be selected, got %+v", rows[0])
+	}
+}
+
+func TestCountDistinct(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := insertDistinctModels(t, db)
+
+	count, err := CountDistinct[TestModel](ctx, db, "name", nil)
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2 distinct names, got %d, %v", count, err)
+	}
+
+	count, err = CountDistinct[TestModel](ctx, db, "name", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("age > 100")
+	})
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0 with no matches, got %d, %v", count, err)
+	}
+}
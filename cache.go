@@ -0,0 +1,255 @@
+package dbkit
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// QueryCache is an in-memory LRU cache for query results.
+// It is intended for lookup tables that rarely change (countries, currencies, configs).
+// QueryCache is safe for concurrent use.
+type QueryCache struct {
+	mu       sync.RWMutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// QueryCacheStats contains cache hit/miss/eviction counters.
+type QueryCacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Size      int   `json:"size"`
+}
+
+// cacheEntry is a single cached value.
+type cacheEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// DefaultCacheCapacity is the default number of entries held by a QueryCache.
+const DefaultCacheCapacity = 1000
+
+// NewQueryCache creates a new LRU query cache.
+// A ttl of zero means entries never expire (they are still evicted by LRU).
+//
+// Usage:
+//
+//	cache := dbkit.NewQueryCache(500, 10*time.Minute)
+func NewQueryCache(capacity int, ttl time.Duration) *QueryCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &QueryCache{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached value for key, if present and not expired.
+func (c *QueryCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		c.misses++
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	c.hits++
+	return entry.value, true
+}
+
+// Set stores value under key, evicting the least recently used entry if the cache is full.
+func (c *QueryCache) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&cacheEntry{key: key, value: value, expiresAt: expiresAt})
+	c.items[key] = el
+
+	for c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+		c.evictions++
+	}
+}
+
+// Invalidate removes all entries whose key starts with keyPrefix.
+// Use CacheKeyPrefix to invalidate all entries for a model type.
+//
+// Usage:
+//
+//	cache.Invalidate(dbkit.CacheKeyPrefix[Country]())
+func (c *QueryCache) Invalidate(keyPrefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		if strings.HasPrefix(key, keyPrefix) {
+			c.removeElement(el)
+		}
+	}
+}
+
+// InvalidateAll removes all entries from the cache.
+func (c *QueryCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// Len returns the number of entries in the cache.
+func (c *QueryCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ll.Len()
+}
+
+// CacheStats returns hit/miss/eviction counters.
+func (c *QueryCache) CacheStats() QueryCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return QueryCacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.ll.Len(),
+	}
+}
+
+// removeElement removes el from the cache. The caller must hold the lock.
+func (c *QueryCache) removeElement(el *list.Element) {
+	entry := el.Value.(*cacheEntry)
+	c.ll.Remove(el)
+	delete(c.items, entry.key)
+}
+
+// CacheKeyPrefix returns the cache key prefix for a model type.
+// All cached queries for T have keys starting with this prefix.
+func CacheKeyPrefix[T any]() string {
+	return reflect.TypeFor[T]().String() + ":"
+}
+
+// cacheKey builds a stable cache key from the model type, operation and query parameters.
+func cacheKey[T any](op string, params string) string {
+	hash := sha256.Sum256([]byte(params))
+	return CacheKeyPrefix[T]() + op + ":" + hex.EncodeToString(hash[:8])
+}
+
+// CachedFindByID finds a record by ID, returning a cached copy when available.
+//
+// Usage:
+//
+//	country, err := dbkit.CachedFindByID[Country](ctx, cache, db, "ES")
+func CachedFindByID[T any](ctx context.Context, cache *QueryCache, db IDB, id any) (*T, error) {
+	key := cacheKey[T]("FindByID", fmt.Sprintf("%T:%v", id, id))
+	if v, ok := cache.Get(key); ok {
+		model := v.(T)
+		return &model, nil
+	}
+
+	var model T
+	if err := db.NewSelect().Model(&model).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, wrapError(err, "CachedFindByID")
+	}
+
+	cache.Set(key, model)
+	return &model, nil
+}
+
+// CachedFindAll finds all records matching the query, returning a cached copy when available.
+// The cache key is derived from the generated SQL, so different filters are cached separately.
+//
+// Usage:
+//
+//	countries, err := dbkit.CachedFindAll[Country](ctx, cache, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Order("name ASC")
+//	})
+func CachedFindAll[T any](ctx context.Context, cache *QueryCache, db IDB, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]T, error) {
+	var items []T
+	q := db.NewSelect().Model(&items)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	key := cacheKey[T]("FindAll", q.String())
+	if v, ok := cache.Get(key); ok {
+		return slices.Clone(v.([]T)), nil
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, wrapError(err, "CachedFindAll")
+	}
+
+	cache.Set(key, slices.Clone(items))
+	return items, nil
+}
+
+// CachedCount counts records matching the query, returning a cached value when available.
+//
+// Usage:
+//
+//	count, err := dbkit.CachedCount[Country](ctx, cache, db, nil)
+func CachedCount[T any](ctx context.Context, cache *QueryCache, db IDB, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (int, error) {
+	q := db.NewSelect().Model((*T)(nil))
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	key := cacheKey[T]("Count", q.String())
+	if v, ok := cache.Get(key); ok {
+		return v.(int), nil
+	}
+
+	count, err := q.Count(ctx)
+	if err != nil {
+		return 0, wrapError(err, "CachedCount")
+	}
+
+	cache.Set(key, count)
+	return count, nil
+}
@@ -0,0 +1,145 @@
+package dbkit
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// queryCountHook counts executed queries.
+type queryCountHook struct {
+	count int64
+}
+
+func (h *queryCountHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *queryCountHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	atomic.AddInt64(&h.count, 1)
+}
+
+func (h *queryCountHook) Count() int64 {
+	return atomic.LoadInt64(&h.count)
+}
+
+func TestQueryCache_GetSet(t *testing.T) {
+	cache := NewQueryCache(10, 0)
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Expected miss for unknown key")
+	}
+
+	cache.Set("key", 42)
+	v, ok := cache.Get("key")
+	if !ok || v.(int) != 42 {
+		t.Errorf("Expected hit with 42, got %v, %v", v, ok)
+	}
+
+	stats := cache.CacheStats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestQueryCache_LRUEviction(t *testing.T) {
+	cache := NewQueryCache(2, 0)
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a") // a is now most recently used
+	cache.Set("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected a to remain cached")
+	}
+	if cache.CacheStats().Evictions != 1 {
+		t.Errorf("Expected 1 eviction, got %d", cache.CacheStats().Evictions)
+	}
+}
+
+func TestQueryCache_TTL(t *testing.T) {
+	cache := NewQueryCache(10, 10*time.Millisecond)
+
+	cache.Set("key", "value")
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected entry to expire")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected expired entry to be removed, got %d entries", cache.Len())
+	}
+}
+
+func TestQueryCache_Invalidate(t *testing.T) {
+	cache := NewQueryCache(10, 0)
+
+	cache.Set(cacheKey[TestModel]("FindByID", "1"), TestModel{})
+	cache.Set(cacheKey[TestModel]("Count", ""), 1)
+	cache.Set(cacheKey[Tenant]("Count", ""), 1)
+
+	cache.Invalidate(CacheKeyPrefix[TestModel]())
+	if cache.Len() != 1 {
+		t.Errorf("Expected 1 entry after invalidation, got %d", cache.Len())
+	}
+
+	cache.InvalidateAll()
+	if cache.Len() != 0 {
+		t.Errorf("Expected empty cache, got %d entries", cache.Len())
+	}
+}
+
+func TestCacheKey_Stable(t *testing.T) {
+	if cacheKey[TestModel]("FindAll", "SELECT 1") != cacheKey[TestModel]("FindAll", "SELECT 1") {
+		t.Error("same parameters should produce same key")
+	}
+	if cacheKey[TestModel]("FindAll", "SELECT 1") == cacheKey[TestModel]("FindAll", "SELECT 2") {
+		t.Error("different parameters should produce different keys")
+	}
+}
+
+func TestCachedFindByID(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+
+	model := &TestModel{Name: "Cached", Email: "cached@example.com"}
+	if _, err := db.NewInsert().Model(model).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	cache := NewQueryCache(10, 50*time.Millisecond)
+
+	before := hook.Count()
+	if _, err := CachedFindByID[TestModel](ctx, cache, db, model.ID); err != nil {
+		t.Fatalf("CachedFindByID failed: %v", err)
+	}
+	found, err := CachedFindByID[TestModel](ctx, cache, db, model.ID)
+	if err != nil {
+		t.Fatalf("CachedFindByID failed: %v", err)
+	}
+	if found.Name != "Cached" {
+		t.Errorf("Expected name Cached, got %s", found.Name)
+	}
+	if hook.Count()-before != 1 {
+		t.Errorf("Expected 1 query, got %d", hook.Count()-before)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := CachedFindByID[TestModel](ctx, cache, db, model.ID); err != nil {
+		t.Fatalf("CachedFindByID failed: %v", err)
+	}
+	if hook.Count()-before != 2 {
+		t.Errorf("Expected TTL expiry to trigger a second query, got %d", hook.Count()-before)
+	}
+}
@@ -0,0 +1,324 @@
+// Package cdc streams row changes from PostgreSQL logical replication using the pgoutput plugin.
+//
+// The server must run with wal_level = logical, and the user needs the REPLICATION attribute.
+// Changes are read from a publication:
+//
+//	CREATE PUBLICATION app_changes FOR TABLE orders, customers;
+package cdc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+// standbyTimeout is how often the consumer reports its position to the server
+const standbyTimeout = 10 * time.Second
+
+// namePattern matches slot and publication names; they are interpolated into replication commands.
+var namePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// postgresEpoch is the origin of timestamps in the replication protocol.
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// CDCChange is a row change read from the replication stream.
+// OldRow is only set for updates and deletes of tables whose replica identity includes the old values.
+type CDCChange struct {
+	Operation string // OpInsert, OpUpdate, OpDelete or OpTruncate
+	Schema    string
+	Table     string
+	OldRow    map[string]any
+	NewRow    map[string]any
+	LSN       uint64
+}
+
+// CDCConsumer reads changes of a publication through a logical replication slot
+type CDCConsumer struct {
+	config          *pgconn.Config
+	slotName        string
+	publicationName string
+
+	conn    *pgconn.PgConn
+	changes chan CDCChange
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu        sync.Mutex
+	err       error
+	confirmed uint64 // Last LSN acknowledged with Ack
+}
+
+// NewCDCConsumer creates a consumer of publicationName through the slot slotName.
+// Nothing is dialed until Start.
+//
+// Usage:
+//
+//	consumer, err := cdc.NewCDCConsumer(dsn, "app_slot", "app_changes")
+//	if err != nil {
+//	    return err
+//	}
+//	if err := consumer.Start(ctx); err != nil {
+//	    return err
+//	}
+//	defer consumer.Close()
+//
+//	for change := range consumer.Changes() {
+//	    log.Printf("%s on %s: %v", change.Operation, change.Table, change.NewRow)
+//	    consumer.Ack(change.LSN)
+//	}
+func NewCDCConsumer(dsn, slotName, publicationName string) (*CDCConsumer, error) {
+	if !namePattern.MatchString(slotName) || !namePattern.MatchString(publicationName) {
+		return nil, fmt.Errorf("cdc: slot and publication names must match %s", namePattern)
+	}
+
+	config, err := pgconn.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("cdc: invalid dsn: %w", err)
+	}
+	config.RuntimeParams["replication"] = "database"
+
+	return &CDCConsumer{
+		config:          config,
+		slotName:        slotName,
+		publicationName: publicationName,
+		changes:         make(chan CDCChange, 64),
+		done:            make(chan struct{}),
+	}, nil
+}
+
+// Changes returns the channel changes are delivered on. It is closed when the consumer stops;
+// Err then reports why.
+//
+// Delivery is at least once: the slot only moves past changes confirmed with Ack, so changes
+// that were not acknowledged when the consumer stopped are delivered again on the next Start.
+func (c *CDCConsumer) Changes() <-chan CDCChange {
+	return c.changes
+}
+
+// Ack confirms that every change up to lsn has been processed, so the server may discard
+// them. Call it with change.LSN once a change has been handled, e.g. after its effects
+// are committed. Acknowledging an older LSN has no effect.
+func (c *CDCConsumer) Ack(lsn uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.confirmed = max(c.confirmed, lsn)
+}
+
+// Err returns the error that stopped the consumer, or nil.
+func (c *CDCConsumer) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Start connects, creates the replication slot if it does not exist and starts streaming.
+// It returns once the server streams changes; they are read in the background until ctx is
+// done or Close is called. Streaming resumes at the position last confirmed to the slot with Ack.
+func (c *CDCConsumer) Start(ctx context.Context) error {
+	conn, err := pgconn.ConnectConfig(ctx, c.config)
+	if err != nil {
+		return fmt.Errorf("cdc: failed to connect: %w", err)
+	}
+	c.conn = conn
+
+	exists, err := c.slotExists(ctx)
+	if err == nil && !exists {
+		err = c.CreateSlot(ctx)
+	}
+	if err == nil {
+		err = c.startReplication(ctx)
+	}
+	if err != nil {
+		_ = conn.Close(context.Background())
+		return err
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	stop := context.AfterFunc(ctx, cancel)
+	go func() {
+		defer stop()
+		c.run(loopCtx)
+	}()
+	return nil
+}
+
+// Close stops streaming, closes the connection and waits for Changes to be closed.
+func (c *CDCConsumer) Close() error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// CreateSlot creates the logical replication slot using the pgoutput plugin.
+// Start calls it when the slot does not exist.
+func (c *CDCConsumer) CreateSlot(ctx context.Context) error {
+	_, err := c.conn.Exec(ctx, fmt.Sprintf("CREATE_REPLICATION_SLOT %s LOGICAL pgoutput NOEXPORT_SNAPSHOT", c.slotName)).ReadAll()
+	if err != nil {
+		return fmt.Errorf("cdc: failed to create slot %s: %w", c.slotName, err)
+	}
+	return nil
+}
+
+// DropSlot drops a replication slot, discarding the changes it retains.
+// Slots keep WAL on the server until dropped, so drop the slots of consumers that are retired.
+// The slot must not be in use by a running consumer.
+func DropSlot(ctx context.Context, dsn, slotName string) error {
+	if !namePattern.MatchString(slotName) {
+		return fmt.Errorf("cdc: slot names must match %s", namePattern)
+	}
+	config, err := pgconn.ParseConfig(dsn)
+	if err != nil {
+		return fmt.Errorf("cdc: invalid dsn: %w", err)
+	}
+	config.RuntimeParams["replication"] = "database"
+
+	conn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return fmt.Errorf("cdc: failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "DROP_REPLICATION_SLOT "+slotName+" WAIT").ReadAll(); err != nil {
+		return fmt.Errorf("cdc: failed to drop slot %s: %w", slotName, err)
+	}
+	return nil
+}
+
+// slotExists reports whether the replication slot exists.
+func (c *CDCConsumer) slotExists(ctx context.Context) (bool, error) {
+	results, err := c.conn.Exec(ctx, fmt.Sprintf("SELECT 1 FROM pg_replication_slots WHERE slot_name = '%s'", c.slotName)).ReadAll()
+	if err != nil {
+		return false, fmt.Errorf("cdc: failed to look up slot %s: %w", c.slotName, err)
+	}
+	return len(results) > 0 && len(results[0].Rows) > 0, nil
+}
+
+// startReplication sends START_REPLICATION and waits for the server to start streaming.
+func (c *CDCConsumer) startReplication(ctx context.Context) error {
+	query := fmt.Sprintf("START_REPLICATION SLOT %s LOGICAL 0/0 (proto_version '1', publication_names '%s')",
+		c.slotName, c.publicationName)
+	c.conn.Frontend().Send(&pgproto3.Query{String: query})
+	if err := c.conn.Frontend().Flush(); err != nil {
+		return fmt.Errorf("cdc: failed to start replication: %w", err)
+	}
+
+	for {
+		msg, err := c.conn.ReceiveMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("cdc: failed to start replication: %w", err)
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.CopyBothResponse:
+			return nil
+		case *pgproto3.ErrorResponse:
+			return fmt.Errorf("cdc: failed to start replication: %w", pgconn.ErrorResponseToPgError(msg))
+		}
+	}
+}
+
+// run reads the replication stream until ctx is done, then closes Changes.
+func (c *CDCConsumer) run(ctx context.Context) {
+	defer close(c.done)
+	defer close(c.changes)
+	defer c.conn.Close(context.Background())
+
+	if err := c.stream(ctx); err != nil && ctx.Err() == nil {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+	}
+}
+
+// stream receives messages, delivering changes and answering keepalives.
+func (c *CDCConsumer) stream(ctx context.Context) error {
+	dec := newDecoder()
+	nextStatus := time.Now().Add(standbyTimeout)
+
+	for {
+		if time.Now().After(nextStatus) {
+			if err := c.sendStandbyStatus(); err != nil {
+				return err
+			}
+			nextStatus = time.Now().Add(standbyTimeout)
+		}
+
+		recvCtx, cancel := context.WithDeadline(ctx, nextStatus)
+		msg, err := c.conn.ReceiveMessage(recvCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if pgconn.Timeout(err) {
+				continue
+			}
+			return fmt.Errorf("cdc: failed to receive: %w", err)
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		case *pgproto3.CopyData:
+			if len(msg.Data) == 0 {
+				continue
+			}
+			switch msg.Data[0] {
+			case 'k': // Primary keepalive: wal end, server time, reply requested
+				if len(msg.Data) >= 18 && msg.Data[17] == 1 {
+					nextStatus = time.Time{}
+				}
+			case 'w': // XLogData: wal start, wal end, server time, message
+				if len(msg.Data) < 25 {
+					return errShortMessage
+				}
+				lsn := binary.BigEndian.Uint64(msg.Data[1:9])
+				changes, err := dec.decode(msg.Data[25:], lsn)
+				if err != nil {
+					return err
+				}
+				for _, change := range changes {
+					select {
+					case c.changes <- change:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+		}
+	}
+}
+
+// sendStandbyStatus reports the last acknowledged LSN as written, flushed and applied.
+func (c *CDCConsumer) sendStandbyStatus() error {
+	c.mu.Lock()
+	lsn := c.confirmed
+	c.mu.Unlock()
+
+	data := make([]byte, 34)
+	data[0] = 'r'
+	binary.BigEndian.PutUint64(data[1:], lsn)
+	binary.BigEndian.PutUint64(data[9:], lsn)
+	binary.BigEndian.PutUint64(data[17:], lsn)
+	binary.BigEndian.PutUint64(data[25:], uint64(time.Since(postgresEpoch).Microseconds()))
+
+	c.conn.Frontend().Send(&pgproto3.CopyData{Data: data})
+	if err := c.conn.Frontend().Flush(); err != nil {
+		return fmt.Errorf("cdc: failed to send standby status: %w", err)
+	}
+	return nil
+}
+
+// FormatLSN formats a log sequence number in PostgreSQL's notation.
+func FormatLSN(lsn uint64) string {
+	return fmt.Sprintf("%X/%X", lsn>>32, uint32(lsn))
+}
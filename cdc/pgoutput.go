@@ -0,0 +1,240 @@
+package cdc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Operations reported in CDCChange.Operation
+const (
+	OpInsert   = "INSERT"
+	OpUpdate   = "UPDATE"
+	OpDelete   = "DELETE"
+	OpTruncate = "TRUNCATE"
+)
+
+var errShortMessage = errors.New("cdc: truncated pgoutput message")
+
+// relation describes a table as announced by a pgoutput Relation message.
+type relation struct {
+	schema  string
+	name    string
+	columns []column
+}
+
+type column struct {
+	name string
+	oid  uint32
+}
+
+// decoder turns pgoutput (protocol version 1) messages into changes.
+// Relation messages always precede the first change of a table in a session.
+type decoder struct {
+	relations map[uint32]*relation
+	types     *pgtype.Map
+}
+
+func newDecoder() *decoder {
+	return &decoder{relations: make(map[uint32]*relation), types: pgtype.NewMap()}
+}
+
+// reader reads big-endian values from a pgoutput message.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errShortMessage
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	for i, c := range r.data {
+		if c == 0 {
+			s := string(r.data[:i])
+			r.data = r.data[i+1:]
+			return s
+		}
+	}
+	r.err = errShortMessage
+	return ""
+}
+
+// decode returns the changes in a pgoutput message, or none for messages that carry no rows.
+func (d *decoder) decode(data []byte, lsn uint64) ([]CDCChange, error) {
+	if len(data) == 0 {
+		return nil, errShortMessage
+	}
+	r := &reader{data: data[1:]}
+
+	switch data[0] {
+	case 'R':
+		d.decodeRelation(r)
+		return nil, r.err
+	case 'I':
+		rel, err := d.relation(r)
+		if err != nil {
+			return nil, err
+		}
+		if r.byte() != 'N' {
+			return nil, fmt.Errorf("cdc: unexpected insert tuple in %s", rel.name)
+		}
+		row, err := d.decodeTuple(r, rel)
+		if err != nil {
+			return nil, err
+		}
+		return []CDCChange{{Operation: OpInsert, Schema: rel.schema, Table: rel.name, NewRow: row, LSN: lsn}}, nil
+	case 'U':
+		rel, err := d.relation(r)
+		if err != nil {
+			return nil, err
+		}
+		change := CDCChange{Operation: OpUpdate, Schema: rel.schema, Table: rel.name, LSN: lsn}
+		kind := r.byte()
+		if kind == 'K' || kind == 'O' {
+			if change.OldRow, err = d.decodeTuple(r, rel); err != nil {
+				return nil, err
+			}
+			kind = r.byte()
+		}
+		if kind != 'N' {
+			return nil, fmt.Errorf("cdc: unexpected update tuple in %s", rel.name)
+		}
+		if change.NewRow, err = d.decodeTuple(r, rel); err != nil {
+			return nil, err
+		}
+		return []CDCChange{change}, nil
+	case 'D':
+		rel, err := d.relation(r)
+		if err != nil {
+			return nil, err
+		}
+		if kind := r.byte(); kind != 'K' && kind != 'O' {
+			return nil, fmt.Errorf("cdc: unexpected delete tuple in %s", rel.name)
+		}
+		row, err := d.decodeTuple(r, rel)
+		if err != nil {
+			return nil, err
+		}
+		return []CDCChange{{Operation: OpDelete, Schema: rel.schema, Table: rel.name, OldRow: row, LSN: lsn}}, nil
+	case 'T':
+		count := int(r.uint32())
+		r.byte() // Options (CASCADE, RESTART IDENTITY)
+		var changes []CDCChange
+		for i := 0; i < count; i++ {
+			rel, err := d.relation(r)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, CDCChange{Operation: OpTruncate, Schema: rel.schema, Table: rel.name, LSN: lsn})
+		}
+		return changes, nil
+	}
+	// Begin, Commit, Origin and Type messages carry no rows
+	return nil, nil
+}
+
+// decodeRelation records the table described by a Relation message.
+func (d *decoder) decodeRelation(r *reader) {
+	id := r.uint32()
+	rel := &relation{schema: r.string(), name: r.string()}
+	r.byte() // Replica identity
+	count := int(r.uint16())
+	for i := 0; i < count && r.err == nil; i++ {
+		r.byte() // Flags
+		col := column{name: r.string(), oid: r.uint32()}
+		r.uint32() // Type modifier
+		rel.columns = append(rel.columns, col)
+	}
+	if r.err == nil {
+		d.relations[id] = rel
+	}
+}
+
+// relation reads a relation id and returns the table it refers to.
+func (d *decoder) relation(r *reader) (*relation, error) {
+	id := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	rel, ok := d.relations[id]
+	if !ok {
+		return nil, fmt.Errorf("cdc: change for unknown relation %d", id)
+	}
+	return rel, nil
+}
+
+// decodeTuple reads a TupleData into a column name to value map.
+// Unchanged TOASTed values are left out; values of unknown types are returned as strings.
+func (d *decoder) decodeTuple(r *reader, rel *relation) (map[string]any, error) {
+	count := int(r.uint16())
+	row := make(map[string]any, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		kind := r.byte()
+		if i >= len(rel.columns) {
+			return nil, fmt.Errorf("cdc: tuple has more columns than %s", rel.name)
+		}
+		col := rel.columns[i]
+
+		switch kind {
+		case 'n':
+			row[col.name] = nil
+		case 'u':
+		case 't':
+			value := r.take(int(r.uint32()))
+			if r.err != nil {
+				break
+			}
+			row[col.name] = d.decodeValue(col.oid, value)
+		default:
+			return nil, fmt.Errorf("cdc: unsupported tuple column kind %q", kind)
+		}
+	}
+	return row, r.err
+}
+
+// decodeValue converts a value in text format to its Go type.
+func (d *decoder) decodeValue(oid uint32, value []byte) any {
+	if t, ok := d.types.TypeForOID(oid); ok {
+		if v, err := t.Codec.DecodeValue(d.types, oid, pgtype.TextFormatCode, value); err == nil {
+			return v
+		}
+	}
+	return string(value)
+}
@@ -0,0 +1,112 @@
+package cdc
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// message builds pgoutput messages for tests.
+type message []byte
+
+func (m message) byte(b byte) message { return append(m, b) }
+
+func (m message) uint16(v uint16) message { return binary.BigEndian.AppendUint16(m, v) }
+
+func (m message) uint32(v uint32) message { return binary.BigEndian.AppendUint32(m, v) }
+
+func (m message) string(s string) message { return append(append(m, s...), 0) }
+
+func (m message) text(s string) message { return m.byte('t').uint32(uint32(len(s))).append(s) }
+
+func (m message) append(s string) message { return append(m, s...) }
+
+// usersRelation describes public.users (id int8, name text, bio text).
+func usersRelation() message {
+	return message{'R'}.uint32(16384).string("public").string("users").byte('d').uint16(3).
+		byte(1).string("id").uint32(20).uint32(0xFFFFFFFF).
+		byte(0).string("name").uint32(25).uint32(0xFFFFFFFF).
+		byte(0).string("bio").uint32(25).uint32(0xFFFFFFFF)
+}
+
+func TestDecoder_Insert(t *testing.T) {
+	dec := newDecoder()
+	if changes, err := dec.decode(usersRelation(), 1); err != nil || len(changes) != 0 {
+		t.Fatalf("Expected relation to be recorded silently, got %v (%v)", changes, err)
+	}
+
+	insert := message{'I'}.uint32(16384).byte('N').uint16(3).text("42").text("Ada").byte('n')
+	changes, err := dec.decode(insert, 100)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+
+	c := changes[0]
+	if c.Operation != OpInsert || c.Schema != "public" || c.Table != "users" || c.LSN != 100 {
+		t.Errorf("Unexpected change: %+v", c)
+	}
+	if c.NewRow["id"] != int64(42) || c.NewRow["name"] != "Ada" {
+		t.Errorf("Expected decoded values, got %v", c.NewRow)
+	}
+	if v, ok := c.NewRow["bio"]; !ok || v != nil {
+		t.Errorf("Expected NULL bio, got %v", c.NewRow)
+	}
+}
+
+func TestDecoder_UpdateAndDelete(t *testing.T) {
+	dec := newDecoder()
+	if _, err := dec.decode(usersRelation(), 1); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	update := message{'U'}.uint32(16384).
+		byte('O').uint16(3).text("42").text("Ada").byte('n').
+		byte('N').uint16(3).text("42").text("Grace").byte('u')
+	changes, err := dec.decode(update, 2)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %v (%v)", changes, err)
+	}
+	if changes[0].Operation != OpUpdate || changes[0].OldRow["name"] != "Ada" || changes[0].NewRow["name"] != "Grace" {
+		t.Errorf("Unexpected update: %+v", changes[0])
+	}
+	if _, ok := changes[0].NewRow["bio"]; ok {
+		t.Error("Expected unchanged TOAST values to be left out")
+	}
+
+	del := message{'D'}.uint32(16384).byte('K').uint16(3).text("42").byte('n').byte('n')
+	changes, err = dec.decode(del, 3)
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %v (%v)", changes, err)
+	}
+	if changes[0].Operation != OpDelete || changes[0].OldRow["id"] != int64(42) || changes[0].NewRow != nil {
+		t.Errorf("Unexpected delete: %+v", changes[0])
+	}
+}
+
+func TestDecoder_Truncate(t *testing.T) {
+	dec := newDecoder()
+	if _, err := dec.decode(usersRelation(), 1); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	changes, err := dec.decode(message{'T'}.uint32(1).byte(0).uint32(16384), 4)
+	if err != nil || len(changes) != 1 || changes[0].Operation != OpTruncate || changes[0].Table != "users" {
+		t.Errorf("Unexpected truncate: %v (%v)", changes, err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	dec := newDecoder()
+
+	if _, err := dec.decode(message{'I'}.uint32(1).byte('N').uint16(0), 1); err == nil {
+		t.Error("Expected an error for an unknown relation")
+	}
+	if _, err := dec.decode(usersRelation()[:10], 1); err == nil {
+		t.Error("Expected an error for a truncated relation")
+	}
+	if changes, err := dec.decode(message{'B'}.append("ignored"), 1); err != nil || changes != nil {
+		t.Errorf("Expected begin messages to be ignored, got %v (%v)", changes, err)
+	}
+}
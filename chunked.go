@@ -0,0 +1,213 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/uptrace/bun"
+)
+
+// cursorSeq generates unique cursor names within the process.
+var cursorSeq int64
+
+// nextCursorName returns a unique server-side cursor name.
+func nextCursorName() string {
+	return fmt.Sprintf("dbkit_cursor_%d", atomic.AddInt64(&cursorSeq, 1))
+}
+
+// declareCursor declares a server-side cursor for the model query.
+func declareCursor[T any](ctx context.Context, tx bun.Tx, name string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) error {
+	q := tx.NewSelect().Model((*T)(nil))
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	_, err := tx.ExecContext(ctx, "DECLARE "+name+" NO SCROLL CURSOR FOR "+q.String())
+	return err
+}
+
+// fetchCursor fetches the next chunk of rows from a server-side cursor.
+func fetchCursor[T any](ctx context.Context, tx bun.Tx, name string, chunkSize int) ([]T, error) {
+	var chunk []T
+	err := tx.NewRaw("FETCH ? FROM "+name, chunkSize).Scan(ctx, &chunk)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// ScanChunked streams query results in chunks using a PostgreSQL server-side cursor.
+// Only chunkSize rows are held in memory at a time. The cursor runs inside a
+// transaction (or a savepoint if db is already a transaction).
+//
+// Usage:
+//
+//	err := dbkit.ScanChunked[User](ctx, db, 500, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Where("active = ?", true).Order("id ASC")
+//	}, func(chunk []User) error {
+//	    return process(chunk)
+//	})
+func ScanChunked[T any](ctx context.Context, db IDB, chunkSize int, queryFn func(*bun.SelectQuery) *bun.SelectQuery, fn func(chunk []T) error) error {
+	if chunkSize <= 0 {
+		chunkSize = BatchSize
+	}
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		name := nextCursorName()
+		if err := declareCursor[T](ctx, tx, name, queryFn); err != nil {
+			return wrapError(err, "ScanChunked.Declare")
+		}
+
+		for {
+			chunk, err := fetchCursor[T](ctx, tx, name, chunkSize)
+			if err != nil {
+				return wrapError(err, "ScanChunked.Fetch")
+			}
+			if len(chunk) == 0 {
+				break
+			}
+
+			if err := fn(chunk); err != nil {
+				return err
+			}
+
+			if len(chunk) < chunkSize {
+				break
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "CLOSE "+name); err != nil {
+			return wrapError(err, "ScanChunked.Close")
+		}
+		return nil
+	})
+}
+
+// ChunkedScanner iterates over query results in chunks using a server-side cursor.
+// The scanner holds a transaction open until iteration finishes or Close is called.
+//
+// Usage:
+//
+//	scanner := dbkit.NewChunkedScanner[User](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Order("id ASC")
+//	}).ChunkSize(500)
+//	defer scanner.Close()
+//
+//	for scanner.Next() {
+//	    process(scanner.Chunk())
+//	}
+//	if err := scanner.Err(); err != nil {
+//	    return err
+//	}
+type ChunkedScanner[T any] struct {
+	ctx       context.Context
+	db        IDB
+	queryFn   func(*bun.SelectQuery) *bun.SelectQuery
+	chunkSize int
+
+	tx      *bun.Tx
+	cursor  string
+	chunk   []T
+	err     error
+	done    bool
+	started bool
+}
+
+// NewChunkedScanner creates a new chunked scanner. The query is not executed until Next is called.
+func NewChunkedScanner[T any](ctx context.Context, db IDB, queryFn func(*bun.SelectQuery) *bun.SelectQuery) *ChunkedScanner[T] {
+	return &ChunkedScanner[T]{
+		ctx:       ctx,
+		db:        db,
+		queryFn:   queryFn,
+		chunkSize: BatchSize,
+	}
+}
+
+// ChunkSize sets the number of rows fetched per chunk.
+func (s *ChunkedScanner[T]) ChunkSize(n int) *ChunkedScanner[T] {
+	if n > 0 {
+		s.chunkSize = n
+	}
+	return s
+}
+
+// Next fetches the next chunk. It returns false when there are no more rows or an error occurred.
+func (s *ChunkedScanner[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if !s.started {
+		s.started = true
+		tx, err := s.db.BeginTx(s.ctx, nil)
+		if err != nil {
+			s.fail(wrapError(err, "ChunkedScanner.Begin"))
+			return false
+		}
+		s.tx = &tx
+		s.cursor = nextCursorName()
+
+		if err := declareCursor[T](s.ctx, tx, s.cursor, s.queryFn); err != nil {
+			s.fail(wrapError(err, "ChunkedScanner.Declare"))
+			return false
+		}
+	}
+
+	chunk, err := fetchCursor[T](s.ctx, *s.tx, s.cursor, s.chunkSize)
+	if err != nil {
+		s.fail(wrapError(err, "ChunkedScanner.Fetch"))
+		return false
+	}
+
+	if len(chunk) == 0 {
+		s.chunk = nil
+		s.err = s.Close()
+		return false
+	}
+
+	s.chunk = chunk
+	return true
+}
+
+// Chunk returns the current chunk.
+func (s *ChunkedScanner[T]) Chunk() []T {
+	return s.chunk
+}
+
+// Err returns the first error encountered during iteration.
+func (s *ChunkedScanner[T]) Err() error {
+	return s.err
+}
+
+// Close closes the cursor and ends the transaction.
+// It is safe to call Close multiple times.
+func (s *ChunkedScanner[T]) Close() error {
+	if s.done {
+		return nil
+	}
+	s.done = true
+
+	if s.tx == nil {
+		return nil
+	}
+
+	if _, err := s.tx.ExecContext(s.ctx, "CLOSE "+s.cursor); err != nil {
+		_ = s.tx.Rollback()
+		return wrapError(err, "ChunkedScanner.Close")
+	}
+	if err := s.tx.Commit(); err != nil {
+		return wrapError(err, "ChunkedScanner.Commit")
+	}
+	return nil
+}
+
+// fail records err and rolls back the transaction.
+func (s *ChunkedScanner[T]) fail(err error) {
+	s.err = err
+	s.done = true
+	s.chunk = nil
+	if s.tx != nil {
+		_ = s.tx.Rollback()
+	}
+}
@@ -0,0 +1,101 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+func insertTestModels(t *testing.T, db *DBKit, n int) []TestModel {
+	t.Helper()
+
+	models := make([]TestModel, n)
+	for i := range models {
+		models[i] = TestModel{
+			Name:   fmt.Sprintf("User %d", i),
+			Email:  fmt.Sprintf("user%d@example.com", i),
+			Age:    i % 100,
+			Active: i%2 == 0,
+		}
+	}
+
+	if _, err := BatchInsert(context.Background(), db, models, 1000); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+	return models
+}
+
+func TestNextCursorName_Unique(t *testing.T) {
+	if nextCursorName() == nextCursorName() {
+		t.Error("cursor names should be unique")
+	}
+}
+
+func TestScanChunked(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 10000)
+
+	seen := make(map[string]bool)
+	chunks := 0
+
+	err := ScanChunked[TestModel](ctx, db, 100, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("email ASC")
+	}, func(chunk []TestModel) error {
+		chunks++
+		if len(chunk) > 100 {
+			t.Errorf("Chunk larger than chunk size: %d", len(chunk))
+		}
+		for _, m := range chunk {
+			if seen[m.ID] {
+				t.Errorf("Duplicate row received: %s", m.ID)
+			}
+			seen[m.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanChunked failed: %v", err)
+	}
+
+	if len(seen) != 10000 {
+		t.Errorf("Expected 10000 rows, got %d", len(seen))
+	}
+	if chunks != 100 {
+		t.Errorf("Expected 100 chunks, got %d", chunks)
+	}
+}
+
+func TestChunkedScanner(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 1000)
+
+	scanner := NewChunkedScanner[TestModel](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("active = ?", true)
+	}).ChunkSize(100)
+	defer scanner.Close()
+
+	total := 0
+	for scanner.Next() {
+		for _, m := range scanner.Chunk() {
+			if !m.Active {
+				t.Errorf("Expected only active rows, got %+v", m)
+			}
+		}
+		total += len(scanner.Chunk())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("ChunkedScanner failed: %v", err)
+	}
+
+	if total != 500 {
+		t.Errorf("Expected 500 rows, got %d", total)
+	}
+}
@@ -0,0 +1,445 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fernandezvara/dbkit/hooks"
+)
+
+// ErrCircuitOpen is returned while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("dbkit: circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Queries flow normally
+	CircuitOpen                         // Queries fail immediately with ErrCircuitOpen
+	CircuitHalfOpen                     // A probe query is allowed through
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerOptions configures the circuit breaker
+type CircuitBreakerOptions struct {
+	FailureThreshold int           // Consecutive failures that open the circuit (default: 5)
+	WindowDuration   time.Duration // Failures older than this are forgotten (default: 10s)
+	HalfOpenTimeout  time.Duration // Time the circuit stays open before probing (default: 30s)
+	SuccessThreshold int           // Successful probes needed to close the circuit (default: 1)
+}
+
+// applyDefaults fills in zero values with defaults
+func (o *CircuitBreakerOptions) applyDefaults() {
+	if o.FailureThreshold <= 0 {
+		o.FailureThreshold = 5
+	}
+	if o.WindowDuration <= 0 {
+		o.WindowDuration = 10 * time.Second
+	}
+	if o.HalfOpenTimeout <= 0 {
+		o.HalfOpenTimeout = 30 * time.Second
+	}
+	if o.SuccessThreshold <= 0 {
+		o.SuccessThreshold = 1
+	}
+}
+
+// WithCircuitBreaker installs a circuit breaker on db and returns it.
+// Only connection-level failures (dial errors, broken connections, timeouts) count
+// as failures; SQL errors returned by PostgreSQL do not.
+//
+// Usage:
+//
+//	db, err := dbkit.New(cfg)
+//	db = dbkit.WithCircuitBreaker(db, dbkit.CircuitBreakerOptions{
+//	    FailureThreshold: 5,
+//	    HalfOpenTimeout:  10 * time.Second,
+//	})
+//
+//	if errors.Is(err, dbkit.ErrCircuitOpen) {
+//	    // Fail fast
+//	}
+func WithCircuitBreaker(db *DBKit, opts CircuitBreakerOptions) *DBKit {
+	opts.applyDefaults()
+	db.connector.cb.Store(&circuitBreaker{opts: opts})
+	return db
+}
+
+// BreakerState returns the circuit breaker state, or CircuitClosed if no breaker is installed.
+func (db *DBKit) BreakerState() CircuitState {
+	cb := db.circuitBreaker()
+	if cb == nil {
+		return CircuitClosed
+	}
+	return cb.State()
+}
+
+// circuitBreaker returns the installed circuit breaker, if any.
+func (db *DBKit) circuitBreaker() *circuitBreaker {
+	if db.connector == nil {
+		return nil
+	}
+	return db.connector.cb.Load()
+}
+
+// IsCircuitOpen checks if error was caused by an open circuit breaker
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
+
+// circuitBreaker tracks connection failures and decides whether queries may run.
+type circuitBreaker struct {
+	opts CircuitBreakerOptions
+
+	mu           sync.Mutex
+	state        CircuitState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	successes    int
+	probing      bool
+	generation   uint64 // bumped on every state change, see breakerCall
+}
+
+// breakerCall identifies a call permitted by allow. Outcomes of calls allowed before the
+// last state change are stale and ignored, so a slow call started while closed cannot
+// count as a half-open probe.
+type breakerCall struct {
+	generation uint64
+	probe      bool
+}
+
+// State returns the current state, moving from open to half-open once the timeout elapsed.
+func (cb *circuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.opts.HalfOpenTimeout {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+// allow reports whether a call may proceed. In half-open state one probe runs at a time.
+func (cb *circuitBreaker) allow() (breakerCall, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.opts.HalfOpenTimeout {
+			return breakerCall{}, ErrCircuitOpen
+		}
+		cb.state = CircuitHalfOpen
+		cb.successes = 0
+		cb.generation++
+		fallthrough
+	case CircuitHalfOpen:
+		if cb.probing {
+			return breakerCall{}, ErrCircuitOpen
+		}
+		cb.probing = true
+		return breakerCall{generation: cb.generation, probe: true}, nil
+	}
+	return breakerCall{generation: cb.generation}, nil
+}
+
+// done records the outcome of a call permitted by allow.
+func (cb *circuitBreaker) done(call breakerCall, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if call.generation != cb.generation {
+		return
+	}
+	failed := isConnectionFailure(err)
+
+	switch cb.state {
+	case CircuitHalfOpen:
+		cb.probing = false
+		if failed {
+			cb.trip()
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.opts.SuccessThreshold {
+			cb.state = CircuitClosed
+			cb.failures = 0
+			cb.generation++
+		}
+	case CircuitClosed:
+		if !failed {
+			cb.failures = 0
+			return
+		}
+		now := time.Now()
+		if cb.failures == 0 || now.Sub(cb.firstFailure) > cb.opts.WindowDuration {
+			cb.failures = 0
+			cb.firstFailure = now
+		}
+		cb.failures++
+		if cb.failures >= cb.opts.FailureThreshold {
+			cb.trip()
+		}
+	}
+}
+
+// trip opens the circuit. The caller must hold the lock.
+func (cb *circuitBreaker) trip() {
+	cb.state = CircuitOpen
+	cb.openedAt = time.Now()
+	cb.failures = 0
+	cb.successes = 0
+	cb.generation++
+}
+
+// call runs fn if the breaker allows it and records the outcome.
+// Errors after ctx is done are the caller giving up, not the database failing, so they are not recorded.
+func (cb *circuitBreaker) call(ctx context.Context, fn func() error) error {
+	call, err := cb.allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	cb.finish(ctx, call, err)
+	return err
+}
+
+// finish records the outcome of call, or only releases it if ctx is done.
+func (cb *circuitBreaker) finish(ctx context.Context, call breakerCall, err error) {
+	if err != nil && ctx.Err() != nil {
+		cb.release(call)
+		return
+	}
+	cb.done(call, err)
+}
+
+// release ends a call permitted by allow without recording an outcome.
+func (cb *circuitBreaker) release(call breakerCall) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if call.probe && call.generation == cb.generation {
+		cb.probing = false
+	}
+}
+
+// isConnectionFailure reports whether err indicates the database is unreachable or overloaded.
+func isConnectionFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// breakerConnector wraps a driver.Connector so every connection reports to the breaker, if any.
+type breakerConnector struct {
+	driver.Connector
+	cb        atomic.Pointer[circuitBreaker]
+	reconnect *reconnector
+	pgBouncer bool // Reject statements that are unsafe behind pgBouncer
+}
+
+// call runs fn through the installed breaker, or directly if there is none.
+func (c *breakerConnector) call(ctx context.Context, fn func() error) error {
+	if cb := c.cb.Load(); cb != nil {
+		return cb.call(ctx, fn)
+	}
+	return fn()
+}
+
+func (c *breakerConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.reconnect.dial(ctx, c.connect)
+	if err != nil {
+		return nil, err
+	}
+	return &breakerConn{Conn: conn, connector: c}, nil
+}
+
+// connect dials a single connection through the breaker.
+func (c *breakerConnector) connect(ctx context.Context) (driver.Conn, error) {
+	var conn driver.Conn
+	err := c.call(ctx, func() error {
+		var err error
+		conn, err = c.Connector.Connect(ctx)
+		return err
+	})
+	return conn, err
+}
+
+// breakerConn forwards to the wrapped connection, consulting the breaker on every call.
+type breakerConn struct {
+	driver.Conn
+	connector *breakerConnector
+}
+
+var (
+	_ driver.QueryerContext     = (*breakerConn)(nil)
+	_ driver.ExecerContext      = (*breakerConn)(nil)
+	_ driver.ConnBeginTx        = (*breakerConn)(nil)
+	_ driver.ConnPrepareContext = (*breakerConn)(nil)
+	_ driver.Pinger             = (*breakerConn)(nil)
+	_ driver.SessionResetter    = (*breakerConn)(nil)
+	_ driver.Validator          = (*breakerConn)(nil)
+	_ driver.NamedValueChecker  = (*breakerConn)(nil)
+)
+
+func (c *breakerConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	hooks.MarkConnAcquired(ctx)
+	if c.connector.pgBouncer {
+		if reason := pgBouncerViolation(query); reason != "" {
+			return nil, pgBouncerError("Query", reason)
+		}
+	}
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	cb := c.connector.cb.Load()
+	if cb == nil {
+		return queryer.QueryContext(ctx, query, args)
+	}
+
+	// The call lasts until the rows are read, so errors from Next count too
+	call, err := cb.allow()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		cb.finish(ctx, call, err)
+		return nil, err
+	}
+	return &breakerRows{Rows: rows, ctx: ctx, cb: cb, call: call}, nil
+}
+
+func (c *breakerConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	hooks.MarkConnAcquired(ctx)
+	if c.connector.pgBouncer {
+		if reason := pgBouncerViolation(query); reason != "" {
+			return nil, pgBouncerError("Query", reason)
+		}
+	}
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var result driver.Result
+	err := c.connector.call(ctx, func() error {
+		var err error
+		result, err = execer.ExecContext(ctx, query, args)
+		return err
+	})
+	return result, err
+}
+
+func (c *breakerConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	hooks.MarkConnAcquired(ctx)
+	if c.connector.pgBouncer {
+		return nil, pgBouncerError("Prepare", "prepared statements are not supported in pgBouncer mode")
+	}
+	var stmt driver.Stmt
+	err := c.connector.call(ctx, func() error {
+		var err error
+		if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+			stmt, err = preparer.PrepareContext(ctx, query)
+		} else {
+			stmt, err = c.Conn.Prepare(query)
+		}
+		return err
+	})
+	return stmt, err
+}
+
+func (c *breakerConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	hooks.MarkConnAcquired(ctx)
+	var tx driver.Tx
+	err := c.connector.call(ctx, func() error {
+		var err error
+		if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+			tx, err = beginner.BeginTx(ctx, opts)
+		} else {
+			tx, err = c.Conn.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+		}
+		return err
+	})
+	return tx, err
+}
+
+func (c *breakerConn) Ping(ctx context.Context) error {
+	return c.connector.call(ctx, func() error {
+		if pinger, ok := c.Conn.(driver.Pinger); ok {
+			return pinger.Ping(ctx)
+		}
+		return nil
+	})
+}
+
+func (c *breakerConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *breakerConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *breakerConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// breakerRows records the outcome of a query once its rows fail or are closed.
+type breakerRows struct {
+	driver.Rows
+	ctx  context.Context
+	cb   *circuitBreaker
+	call breakerCall
+	once sync.Once
+}
+
+func (r *breakerRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err != nil && err != io.EOF {
+		r.finish(err)
+	}
+	return err
+}
+
+func (r *breakerRows) Close() error {
+	err := r.Rows.Close()
+	r.finish(err)
+	return err
+}
+
+func (r *breakerRows) finish(err error) {
+	r.once.Do(func() { r.cb.finish(r.ctx, r.call, err) })
+}
@@ -0,0 +1,301 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeConnector dials fake connections, failing while failing is set.
+type fakeConnector struct {
+	failing    atomic.Bool
+	dials      atomic.Int64
+	queryDelay time.Duration
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.dials.Add(1)
+	if c.failing.Load() {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	return fakeConn{delay: c.queryDelay}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver { return nil }
+
+// fakeConn answers every statement with an empty result after delay.
+type fakeConn struct {
+	delay time.Duration
+}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+func (fakeConn) Ping(ctx context.Context) error            { return nil }
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(c.delay)
+	return driver.RowsAffected(0), nil
+}
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	time.Sleep(c.delay)
+	return fakeRows{}, nil
+}
+
+// fakeTx commits and rolls back without doing anything.
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string              { return nil }
+func (fakeRows) Close() error                   { return nil }
+func (fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+func newFakeDB(t *testing.T) (*DBKit, *fakeConnector) {
+	t.Helper()
+	return newFakeDBWithConnector(t, &fakeConnector{})
+}
+
+func newFakeDBWithConnector(t *testing.T, connector *fakeConnector) (*DBKit, *fakeConnector) {
+	t.Helper()
+
+	cfg := DefaultConfig("fake")
+	db, err := newDBKit(cfg, connector)
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	// Force a dial for every call
+	db.DB.DB.SetMaxIdleConns(-1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, connector
+}
+
+func TestCircuitBreaker_OpensAndCloses(t *testing.T) {
+	ctx := context.Background()
+	db, connector := newFakeDB(t)
+	db = WithCircuitBreaker(db, CircuitBreakerOptions{
+		FailureThreshold: 3,
+		HalfOpenTimeout:  50 * time.Millisecond,
+	})
+
+	if db.BreakerState() != CircuitClosed {
+		t.Fatalf("Expected closed breaker, got %s", db.BreakerState())
+	}
+
+	connector.failing.Store(true)
+	for i := 0; i < 3; i++ {
+		if err := db.Ping(ctx); err == nil || IsCircuitOpen(err) {
+			t.Fatalf("Expected dial error, got %v", err)
+		}
+	}
+
+	if db.BreakerState() != CircuitOpen {
+		t.Fatalf("Expected open breaker, got %s", db.BreakerState())
+	}
+
+	dials := connector.dials.Load()
+	if err := db.Ping(ctx); !IsCircuitOpen(err) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if _, err := db.NewSelect().ColumnExpr("1").Exec(ctx); !IsCircuitOpen(err) {
+		t.Errorf("Expected ErrCircuitOpen for query, got %v", err)
+	}
+	if connector.dials.Load() != dials {
+		t.Error("Expected no dial attempts while the breaker is open")
+	}
+
+	status := db.Health(ctx)
+	if status.Healthy || status.Circuit != "open" {
+		t.Errorf("Expected unhealthy status with open circuit, got %+v", status)
+	}
+
+	connector.failing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+
+	if db.BreakerState() != CircuitHalfOpen {
+		t.Fatalf("Expected half-open breaker, got %s", db.BreakerState())
+	}
+	if err := db.Ping(ctx); err != nil {
+		t.Fatalf("Expected probe to succeed, got %v", err)
+	}
+	if db.BreakerState() != CircuitClosed {
+		t.Errorf("Expected breaker to close after successful probe, got %s", db.BreakerState())
+	}
+	if !db.Health(ctx).Healthy {
+		t.Error("Expected healthy status after breaker closed")
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	cb := &circuitBreaker{opts: CircuitBreakerOptions{
+		FailureThreshold: 1,
+		HalfOpenTimeout:  10 * time.Millisecond,
+		SuccessThreshold: 2,
+	}}
+	cb.opts.applyDefaults()
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("refused")}
+
+	_ = cb.call(context.Background(), func() error { return dialErr })
+	if cb.State() != CircuitOpen {
+		t.Fatalf("Expected open, got %s", cb.State())
+	}
+
+	// A failed probe reopens the circuit
+	time.Sleep(15 * time.Millisecond)
+	_ = cb.call(context.Background(), func() error { return dialErr })
+	if cb.State() != CircuitOpen {
+		t.Fatalf("Expected reopened circuit, got %s", cb.State())
+	}
+
+	// Only one probe at a time
+	time.Sleep(15 * time.Millisecond)
+	probe, err := cb.allow()
+	if err != nil {
+		t.Fatalf("Expected probe to be allowed, got %v", err)
+	}
+	if _, err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected concurrent probe to be rejected, got %v", err)
+	}
+	cb.done(probe, nil)
+
+	// SuccessThreshold probes are needed to close
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("Expected half-open after first success, got %s", cb.State())
+	}
+	_ = cb.call(context.Background(), func() error { return nil })
+	if cb.State() != CircuitClosed {
+		t.Errorf("Expected closed, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_IgnoresSQLErrors(t *testing.T) {
+	cb := &circuitBreaker{opts: CircuitBreakerOptions{FailureThreshold: 2}}
+	cb.opts.applyDefaults()
+
+	for i := 0; i < 5; i++ {
+		_ = cb.call(context.Background(), func() error { return errors.New("duplicate key value violates unique constraint") })
+	}
+	if cb.State() != CircuitClosed {
+		t.Errorf("SQL errors should not open the circuit, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_IgnoresCallerDeadline(t *testing.T) {
+	cb := &circuitBreaker{opts: CircuitBreakerOptions{FailureThreshold: 2, HalfOpenTimeout: 10 * time.Millisecond}}
+	cb.opts.applyDefaults()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	for i := 0; i < 5; i++ {
+		_ = cb.call(ctx, func() error { return ctx.Err() })
+	}
+	if cb.State() != CircuitClosed {
+		t.Errorf("The caller's own deadline should not open the circuit, got %s", cb.State())
+	}
+
+	// A probe cut short by its caller lets the next probe run
+	cb.mu.Lock()
+	cb.trip()
+	cb.openedAt = time.Now().Add(-time.Second)
+	cb.mu.Unlock()
+	_ = cb.call(ctx, func() error { return ctx.Err() })
+	if _, err := cb.allow(); err != nil {
+		t.Errorf("Expected another probe to be allowed, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_IgnoresStaleOutcomes(t *testing.T) {
+	cb := &circuitBreaker{opts: CircuitBreakerOptions{FailureThreshold: 1, HalfOpenTimeout: 10 * time.Millisecond}}
+	cb.opts.applyDefaults()
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("refused")}
+
+	// A slow call allowed while closed outlives a trip and the move to half-open
+	slow, err := cb.allow()
+	if err != nil {
+		t.Fatalf("Expected call to be allowed, got %v", err)
+	}
+	_ = cb.call(context.Background(), func() error { return dialErr })
+	time.Sleep(15 * time.Millisecond)
+	probe, err := cb.allow()
+	if err != nil || !probe.probe {
+		t.Fatalf("Expected a probe, got %+v, %v", probe, err)
+	}
+
+	cb.done(slow, nil)
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("Expected the stale success not to close the circuit, got %s", cb.State())
+	}
+	if _, err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected the probe to still be running, got %v", err)
+	}
+	cb.release(slow)
+	if _, err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a stale release to keep the probe running, got %v", err)
+	}
+
+	cb.done(probe, nil)
+	if cb.State() != CircuitClosed {
+		t.Errorf("Expected the probe to close the circuit, got %s", cb.State())
+	}
+}
+
+// errRows fails on the first Next with err.
+type errRows struct {
+	fakeRows
+	err error
+}
+
+func (r errRows) Next(dest []driver.Value) error { return r.err }
+
+func TestBreakerRows_CountsNextErrors(t *testing.T) {
+	cb := &circuitBreaker{opts: CircuitBreakerOptions{FailureThreshold: 2}}
+	cb.opts.applyDefaults()
+	ctx := context.Background()
+
+	for range 2 {
+		call, err := cb.allow()
+		if err != nil {
+			t.Fatalf("Expected call to be allowed, got %v", err)
+		}
+		rows := &breakerRows{Rows: errRows{err: io.ErrUnexpectedEOF}, ctx: ctx, cb: cb, call: call}
+		_ = rows.Next(nil)
+		_ = rows.Close()
+	}
+	if cb.State() != CircuitOpen {
+		t.Errorf("Expected broken rows to open the circuit, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_Window(t *testing.T) {
+	cb := &circuitBreaker{opts: CircuitBreakerOptions{FailureThreshold: 2, WindowDuration: 10 * time.Millisecond}}
+	cb.opts.applyDefaults()
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("refused")}
+
+	_ = cb.call(context.Background(), func() error { return dialErr })
+	time.Sleep(15 * time.Millisecond)
+	_ = cb.call(context.Background(), func() error { return dialErr })
+
+	if cb.State() != CircuitClosed {
+		t.Errorf("Failures outside the window should not open the circuit, got %s", cb.State())
+	}
+}
+
+func TestBreakerState_NoBreaker(t *testing.T) {
+	db, _ := newFakeDB(t)
+	if db.BreakerState() != CircuitClosed {
+		t.Errorf("Expected closed without breaker, got %s", db.BreakerState())
+	}
+	if status := db.Health(context.Background()); !status.Healthy || status.Circuit != "" {
+		t.Errorf("Unexpected health status: %+v", status)
+	}
+}
@@ -0,0 +1,142 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/uptrace/bun"
+)
+
+// PKMap builds the column to value map of a composite primary key from column/value pairs.
+// It panics when the arguments are not pairs of a string column and a value.
+//
+// Usage:
+//
+//	member, err := dbkit.FindByCompositePK[Membership](ctx, db, dbkit.PKMap("user_id", userID, "org_id", orgID))
+func PKMap(fields ...any) map[string]any {
+	if len(fields)%2 != 0 {
+		panic("dbkit: PKMap needs column/value pairs")
+	}
+	pks := make(map[string]any, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		column, ok := fields[i].(string)
+		if !ok {
+			panic(fmt.Sprintf("dbkit: PKMap column %v is not a string", fields[i]))
+		}
+		pks[column] = fields[i+1]
+	}
+	return pks
+}
+
+// FindByCompositePK returns the record matching every column of pks.
+// Returns ErrNotFound if there is none.
+//
+// Usage:
+//
+//	member, err := dbkit.FindByCompositePK[Membership](ctx, db, dbkit.PKMap("user_id", userID, "org_id", orgID))
+func FindByCompositePK[T any](ctx context.Context, db IDB, pks map[string]any) (*T, error) {
+	if err := validateCompositePK(pks, "FindByCompositePK"); err != nil {
+		return nil, err
+	}
+	var model T
+	q := db.NewSelect().Model(&model)
+	for _, column := range compositePKColumns(pks) {
+		q = q.Where("?TableAlias.? = ?", bun.Ident(column), pks[column])
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindByCompositePK")
+	}
+	return &model, nil
+}
+
+// ExistsByCompositePK checks if a record matching every column of pks exists.
+//
+// Usage:
+//
+//	exists, err := dbkit.ExistsByCompositePK[Membership](ctx, db, dbkit.PKMap("user_id", userID, "org_id", orgID))
+func ExistsByCompositePK[T any](ctx context.Context, db IDB, pks map[string]any) (bool, error) {
+	if err := validateCompositePK(pks, "ExistsByCompositePK"); err != nil {
+		return false, err
+	}
+	q := db.NewSelect().Model((*T)(nil))
+	for _, column := range compositePKColumns(pks) {
+		q = q.Where("?TableAlias.? = ?", bun.Ident(column), pks[column])
+	}
+	exists, err := q.Exists(ctx)
+	if err != nil {
+		return false, wrapError(err, "ExistsByCompositePK")
+	}
+	return exists, nil
+}
+
+// UpdateByCompositePK updates the given columns of model (all columns when none are given) on the
+// record matching every column of pks. Models implementing Validatable are validated first.
+// Returns ErrNotFound if there is none.
+//
+// Usage:
+//
+//	member.Role = "admin"
+//	err := dbkit.UpdateByCompositePK(ctx, db, dbkit.PKMap("user_id", userID, "org_id", orgID), &member, "role")
+func UpdateByCompositePK[T any](ctx context.Context, db IDB, pks map[string]any, model *T, columns ...string) error {
+	if err := validateCompositePK(pks, "UpdateByCompositePK"); err != nil {
+		return err
+	}
+	if err := validateModel(ctx, model, "UpdateByCompositePK"); err != nil {
+		return err
+	}
+	q := db.NewUpdate().Model(model).Column(columns...)
+	for _, column := range compositePKColumns(pks) {
+		q = q.Where("?TableAlias.? = ?", bun.Ident(column), pks[column])
+	}
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return wrapError(err, "UpdateByCompositePK")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "UpdateByCompositePK"}
+	}
+	return nil
+}
+
+// DeleteByCompositePK deletes the record matching every column of pks (soft delete for soft-deletable models).
+// Returns ErrNotFound if there is none.
+//
+// Usage:
+//
+//	err := dbkit.DeleteByCompositePK[Membership](ctx, db, dbkit.PKMap("user_id", userID, "org_id", orgID))
+func DeleteByCompositePK[T any](ctx context.Context, db IDB, pks map[string]any) error {
+	if err := validateCompositePK(pks, "DeleteByCompositePK"); err != nil {
+		return err
+	}
+	q := db.NewDelete().Model((*T)(nil))
+	for _, column := range compositePKColumns(pks) {
+		q = q.Where("?TableAlias.? = ?", bun.Ident(column), pks[column])
+	}
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return wrapError(err, "DeleteByCompositePK")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "DeleteByCompositePK"}
+	}
+	return nil
+}
+
+// validateCompositePK rejects an empty key, which would match every record
+func validateCompositePK(pks map[string]any, op string) error {
+	if len(pks) == 0 {
+		return &Error{Code: CodeValidation, Message: "composite primary key needs at least one column", Op: op}
+	}
+	return nil
+}
+
+// compositePKColumns returns the columns of pks sorted, so the generated SQL is stable
+func compositePKColumns(pks map[string]any) []string {
+	columns := make([]string, 0, len(pks))
+	for column := range pks {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -0,0 +1,132 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+// membership is a junction table with a two-column primary key
+type membership struct {
+	bun.BaseModel `bun:"table:test_memberships,alias:tms"`
+	UserID        string `bun:"user_id,pk"`
+	OrgID         string `bun:"org_id,pk"`
+	Role          string `bun:"role,notnull"`
+}
+
+func TestPKMap(t *testing.T) {
+	pks := PKMap("user_id", "u1", "org_id", 7)
+	if len(pks) != 2 || pks["user_id"] != "u1" || pks["org_id"] != 7 {
+		t.Errorf("Unexpected map %v", pks)
+	}
+
+	for _, fields := range [][]any{{"user_id"}, {1, "u1"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected PKMap(%v) to panic", fields)
+				}
+			}()
+			PKMap(fields...)
+		}()
+	}
+}
+
+func TestCompositePK_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+	ctx := context.Background()
+	pks := PKMap("user_id", "u1", "org_id", "o1")
+
+	_, _ = ExistsByCompositePK[membership](ctx, db, pks)
+	want := `SELECT EXISTS (SELECT "tms"."user_id", "tms"."org_id", "tms"."role" FROM "test_memberships" AS "tms" WHERE ("tms"."org_id" = 'o1') AND ("tms"."user_id" = 'u1'))`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+
+	if err := DeleteByCompositePK[membership](ctx, db, pks); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	want = `DELETE FROM "test_memberships" AS "tms" WHERE ("tms"."org_id" = 'o1') AND ("tms"."user_id" = 'u1')`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+}
+
+func TestCompositePK_Empty(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	if _, err := FindByCompositePK[membership](ctx, db, nil); !IsValidation(err) {
+		t.Errorf("FindByCompositePK: expected validation error, got %v", err)
+	}
+	if _, err := ExistsByCompositePK[membership](ctx, db, map[string]any{}); !IsValidation(err) {
+		t.Errorf("ExistsByCompositePK: expected validation error, got %v", err)
+	}
+	if err := UpdateByCompositePK(ctx, db, nil, &membership{}); !IsValidation(err) {
+		t.Errorf("UpdateByCompositePK: expected validation error, got %v", err)
+	}
+	if err := DeleteByCompositePK[membership](ctx, db, nil); !IsValidation(err) {
+		t.Errorf("DeleteByCompositePK: expected validation error, got %v", err)
+	}
+}
+
+func TestCompositePK(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*membership)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*membership)(nil)).IfExists().Exec(ctx)
+	_, _ = db.NewDelete().Model((*membership)(nil)).Where("1=1").Exec(ctx)
+
+	// Rows sharing one of the key columns with the target
+	rows := []membership{
+		{UserID: "u1", OrgID: "o1", Role: "member"},
+		{UserID: "u1", OrgID: "o2", Role: "member"},
+		{UserID: "u2", OrgID: "o1", Role: "member"},
+	}
+	if _, err := db.NewInsert().Model(&rows).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	pks := PKMap("user_id", "u1", "org_id", "o1")
+
+	found, err := FindByCompositePK[membership](ctx, db, pks)
+	if err != nil || found.UserID != "u1" || found.OrgID != "o1" {
+		t.Fatalf("Expected u1/o1, got %+v, %v", found, err)
+	}
+	if _, err := FindByCompositePK[membership](ctx, db, PKMap("user_id", "u2", "org_id", "o2")); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	found.Role = "admin"
+	if err := UpdateByCompositePK(ctx, db, pks, found, "role"); err != nil {
+		t.Fatalf("UpdateByCompositePK failed: %v", err)
+	}
+	var admins []membership
+	if err := db.NewSelect().Model(&admins).Where("role = 'admin'").Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(admins) != 1 || admins[0].UserID != "u1" || admins[0].OrgID != "o1" {
+		t.Errorf("Expected only u1/o1 to be updated, got %+v", admins)
+	}
+
+	if err := DeleteByCompositePK[membership](ctx, db, pks); err != nil {
+		t.Fatalf("DeleteByCompositePK failed: %v", err)
+	}
+	if exists, err := ExistsByCompositePK[membership](ctx, db, pks); err != nil || exists {
+		t.Errorf("Expected u1/o1 to be deleted, got %v, %v", exists, err)
+	}
+	for _, other := range [][2]string{{"u1", "o2"}, {"u2", "o1"}} {
+		if exists, err := ExistsByCompositePK[membership](ctx, db, PKMap("user_id", other[0], "org_id", other[1])); err != nil || !exists {
+			t.Errorf("Expected %s/%s to remain, got %v, %v", other[0], other[1], exists, err)
+		}
+	}
+	if err := DeleteByCompositePK[membership](ctx, db, pks); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package dbkit
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// ComputedExpr is a SQL expression selected under Alias alongside a model's columns
+type ComputedExpr struct {
+	Expression string // e.g. "price * quantity", must not contain user input
+	Alias      string // Matches the bun tag of a scanonly field on the model
+}
+
+// ComputedField returns a query modifier that appends expression to the SELECT list.
+// Bun then selects only the listed columns, so add the model's own with ?TableColumns.
+//
+// Usage:
+//
+//	db.NewSelect().Model(&lines).
+//	    ColumnExpr("?TableColumns").
+//	    Apply(dbkit.ComputedField("price * quantity AS total")).
+//	    Scan(ctx)
+func ComputedField(expression string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.ColumnExpr(expression)
+	}
+}
+
+// computedQuery selects T's columns followed by extras.
+func computedQuery(db IDB, dest any, extras []ComputedExpr, queryFn func(*bun.SelectQuery) *bun.SelectQuery) *bun.SelectQuery {
+	q := db.NewSelect().Model(dest).ColumnExpr("?TableColumns")
+	for _, extra := range extras {
+		q = q.ColumnExpr(extra.Expression+" AS ?", bun.Ident(extra.Alias))
+	}
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+	return q
+}
+
+// FindAllWithComputed finds T's records together with computed expressions.
+// T must have a field tagged `bun:"<alias>,scanonly"` for every extra, e.g. by
+// embedding the stored model with `bun:",extend"`. Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	type OrderLineWithTotal struct {
+//	    OrderLine `bun:",extend"`
+//	    Total     int64 `bun:"total,scanonly"`
+//	}
+//
+//	lines, err := dbkit.FindAllWithComputed[OrderLineWithTotal](ctx, db,
+//	    []dbkit.ComputedExpr{{Expression: "price * quantity", Alias: "total"}}, nil)
+func FindAllWithComputed[T any](ctx context.Context, db IDB, extras []ComputedExpr, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]T, error) {
+	models := []T{}
+	if err := computedQuery(db, &models, extras, queryFn).Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindAllWithComputed")
+	}
+	return models, nil
+}
+
+// FindOneWithComputed finds the first matching record together with computed expressions.
+// See FindAllWithComputed for the requirements on T. Returns ErrNotFound if nothing matches.
+//
+// Usage:
+//
+//	line, err := dbkit.FindOneWithComputed[OrderLineWithTotal](ctx, db,
+//	    []dbkit.ComputedExpr{{Expression: "price * quantity", Alias: "total"}},
+//	    func(q *bun.SelectQuery) *bun.SelectQuery {
+//	        return q.Where("id = ?", id)
+//	    })
+func FindOneWithComputed[T any](ctx context.Context, db IDB, extras []ComputedExpr, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (*T, error) {
+	var model T
+	if err := computedQuery(db, &model, extras, queryFn).Limit(1).Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindOneWithComputed")
+	}
+	return &model, nil
+}
@@ -0,0 +1,73 @@
+package dbkit
+
+import (
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type TestModelWithComputed struct {
+	TestModel `bun:",extend"`
+	DoubleAge int    `bun:"double_age,scanonly"`
+	Label     string `bun:"label,scanonly"`
+}
+
+func TestComputedQuery_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	var models []TestModelWithComputed
+	sql := computedQuery(db, &models, []ComputedExpr{{Expression: "age * 2", Alias: "double_age"}}, nil).String()
+	want := `SELECT "tm"."id", "tm"."name", "tm"."email", "tm"."age", "tm"."active", "tm"."created_at", "tm"."updated_at", age * 2 AS "double_age" FROM "test_models" AS "tm"`
+	if sql != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", sql, want)
+	}
+
+	sql = db.NewSelect().Model(&models).ColumnExpr("?TableColumns").Apply(ComputedField("age * 2 AS double_age")).String()
+	want = `SELECT "tm"."id", "tm"."name", "tm"."email", "tm"."age", "tm"."active", "tm"."created_at", "tm"."updated_at", age * 2 AS double_age FROM "test_models" AS "tm"`
+	if sql != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", sql, want)
+	}
+}
+
+func TestFindAllWithComputed(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 3)
+
+	extras := []ComputedExpr{
+		{Expression: "age * 2", Alias: "double_age"},
+		{Expression: "upper(name)", Alias: "label"},
+	}
+	rows, err := FindAllWithComputed[TestModelWithComputed](ctx, db, extras, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("age ASC")
+	})
+	if err != nil {
+		t.Fatalf("FindAllWithComputed failed: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	for _, r := range rows {
+		if r.DoubleAge != r.Age*2 || r.ID == "" {
+			t.Errorf("Unexpected row %+v", r)
+		}
+	}
+	if rows[1].Label != "USER 1" {
+		t.Errorf("Expected label USER 1, got %q", rows[1].Label)
+	}
+
+	one, err := FindOneWithComputed[TestModelWithComputed](ctx, db, extras, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("age = ?", 2)
+	})
+	if err != nil || one.DoubleAge != 4 || one.Email != "user2@example.com" {
+		t.Errorf("Unexpected FindOneWithComputed result %+v, %v", one, err)
+	}
+
+	if _, err := FindOneWithComputed[TestModelWithComputed](ctx, db, extras, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("age > 100")
+	}); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -4,17 +4,37 @@
 package dbkit
 
 import (
+	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fernandezvara/dbkit/hooks"
 )
 
 // Config holds database configuration
 type Config struct {
 	// Connection
-	URL string // PostgreSQL connection string (required)
+	URL             string // PostgreSQL connection string (required)
+	ApplicationName string // Reported to PostgreSQL as application_name (default: from URL)
+
+	// AWS RDS IAM authentication (optional, replaces the URL user and password with a signed token)
+	RDSIAM *RDSIAMConfig
+
+	// Failover
+	FallbackURLs     []string      // Standby connection strings tried in order when URL is unreachable
+	FailoverInterval time.Duration // Minimum time between failovers to another URL (default: 5s)
+
+	// pgBouncer transaction pooling (see PgBouncerDisabledFeatures)
+	PgBouncerMode bool // Reject session-level statements that break behind pgBouncer in transaction mode
+
+	// Reconnect
+	AutoReconnect       bool          // Retry dialing with exponential backoff while the database is unreachable
+	ReconnectMaxElapsed time.Duration // Max time spent reconnecting before giving up (default: 1m)
 
 	// Pool settings
 	MaxOpenConns    int           // Max open connections (default: 25)
@@ -27,12 +47,35 @@ type Config struct {
 	ReadTimeout  time.Duration // Read timeout (default: 30s)
 	WriteTimeout time.Duration // Write timeout (default: 30s)
 
+	// Shutdown
+	DrainTimeout time.Duration // Max time Shutdown waits for in-flight queries (default: 30s)
+
 	// Observability (all optional)
-	Logger          *slog.Logger          // Structured logger
-	LogQueries      bool                  // Log all queries
-	LogSlowQueries  time.Duration         // Log queries slower than this (0 = disabled)
-	MetricsRegistry prometheus.Registerer // Prometheus registry for metrics
-	Tracer          trace.Tracer          // OpenTelemetry tracer
+	Logger          *slog.Logger                 // Structured logger
+	LogQueries      bool                         // Log all queries
+	LogSlowQueries  time.Duration                // Log queries slower than this (0 = disabled)
+	LogSampleRate   float64                      // Fraction of queries logged, failed queries are always logged (0 = all)
+	MetricsRegistry prometheus.Registerer        // Prometheus registry for metrics
+	MetricsOptions  *hooks.MetricsHookOptions    // Label limits and query duration buckets (nil = defaults)
+	TxMetrics       hooks.TransactionHookOptions // Buckets and labels of the transaction duration histogram
+	PoolWait        hooks.PoolWaitHookOptions    // Exhaustion threshold and buckets of the connection wait histogram
+	Tracer          trace.Tracer                 // OpenTelemetry tracer
+	TracingOptions  hooks.TracingHookOptions     // Span naming, baggage and statement options for tracing
+
+	// Query parameter logging (opt-in, parameter values may contain personal data)
+	LogParameters      bool  // Add query parameters to logged queries
+	LogSensitiveParams []int // Zero-based parameter positions logged as "[REDACTED]", per row for multi-row models
+	LogMaxParamLength  int   // Length long string parameters are truncated to (default: 100)
+
+	// Slow query plans (plain SELECTs only, explaining takes a spare connection for up to 500ms)
+	ExplainSlowQueries bool          // Attach EXPLAIN (FORMAT JSON) output to slow query logs
+	ExplainThreshold   time.Duration // Explain queries slower than this (default: 1s)
+
+	// Development
+	DetectN1    bool                            // Report repeated query patterns (N+1 queries)
+	N1Threshold int                             // Repetitions within the window that are reported (default: 5)
+	N1Window    time.Duration                   // Window in which repetitions are counted (default: 1s), see DBKit.FlushN1
+	N1Handler   func(pattern string, count int) // Called once a pattern exceeds the threshold (default: log a warning)
 }
 
 // DefaultConfig returns sensible defaults
@@ -46,7 +89,135 @@ func DefaultConfig(url string) Config {
 		DialTimeout:     5 * time.Second,
 		ReadTimeout:     30 * time.Second,
 		WriteTimeout:    30 * time.Second,
+		DrainTimeout:    30 * time.Second,
+
+		FailoverInterval:    5 * time.Second,
+		ReconnectMaxElapsed: time.Minute,
+		ExplainThreshold:    time.Second,
+	}
+}
+
+// ConfigError describes an invalid configuration field or environment variable
+type ConfigError struct {
+	Field   string
+	Message string
+	Warning bool // Suspicious but allowed value
+}
+
+func (e ConfigError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ConfigValidationError lists every configuration error, excluding warnings
+type ConfigValidationError struct {
+	Errors []ConfigError
+}
+
+func (e *ConfigValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Validate returns every problem found in the configuration, or nil.
+// Entries with Warning set are suspicious values that New accepts.
+//
+// Usage:
+//
+//	for _, e := range cfg.Validate() {
+//	    log.Printf("%s (warning: %v)", e, e.Warning)
+//	}
+func (c Config) Validate() []ConfigError {
+	var errs []ConfigError
+	fail := func(field, msg string) {
+		errs = append(errs, ConfigError{Field: field, Message: msg})
+	}
+	warn := func(field, msg string) {
+		errs = append(errs, ConfigError{Field: field, Message: msg, Warning: true})
+	}
+
+	if c.URL == "" {
+		fail("URL", "is required")
+	} else if !isPostgresDSN(c.URL) {
+		fail("URL", "must be a postgres:// or postgresql:// DSN")
+	}
+	for i, u := range c.FallbackURLs {
+		if !isPostgresDSN(u) {
+			fail(fmt.Sprintf("FallbackURLs[%d]", i), "must be a postgres:// or postgresql:// DSN")
+		}
+	}
+
+	if c.RDSIAM != nil {
+		if c.RDSIAM.Region == "" {
+			fail("RDSIAM.Region", "is required")
+		}
+		if c.RDSIAM.DBUser == "" {
+			fail("RDSIAM.DBUser", "is required")
+		}
+	}
+
+	if c.MaxOpenConns <= 0 {
+		fail("MaxOpenConns", "must be positive")
+	} else if c.MaxOpenConns > 100 {
+		warn("MaxOpenConns", "above 100 may exhaust PostgreSQL max_connections")
 	}
+	if c.MaxIdleConns < 0 {
+		fail("MaxIdleConns", "must not be negative")
+	} else if c.MaxIdleConns > c.MaxOpenConns {
+		fail("MaxIdleConns", "must not exceed MaxOpenConns")
+	}
+
+	for _, d := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"ConnMaxLifetime", c.ConnMaxLifetime},
+		{"ConnMaxIdleTime", c.ConnMaxIdleTime},
+		{"DialTimeout", c.DialTimeout},
+		{"ReadTimeout", c.ReadTimeout},
+		{"WriteTimeout", c.WriteTimeout},
+	} {
+		if d.value <= 0 {
+			fail(d.field, "must be positive")
+		}
+	}
+	if c.ConnMaxLifetime > 0 && c.ConnMaxLifetime <= c.ConnMaxIdleTime {
+		fail("ConnMaxLifetime", "must be greater than ConnMaxIdleTime")
+	}
+
+	if c.PgBouncerMode {
+		for _, feature := range PgBouncerDisabledFeatures {
+			warn("PgBouncerMode", "disables "+feature)
+		}
+	}
+
+	if c.LogSlowQueries > time.Second {
+		warn("LogSlowQueries", "above 1s may hide slow queries")
+	}
+
+	return errs
+}
+
+// isPostgresDSN reports whether dsn parses as a postgres URL.
+func isPostgresDSN(dsn string) bool {
+	u, err := url.Parse(dsn)
+	return err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql")
+}
+
+// validationError returns the non-warning entries of errs as a ConfigValidationError, or nil.
+func validationError(errs []ConfigError) error {
+	var failed []ConfigError
+	for _, e := range errs {
+		if !e.Warning {
+			failed = append(failed, e)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &ConfigValidationError{Errors: failed}
 }
 
 // applyDefaults fills in zero values with defaults
@@ -72,6 +243,24 @@ func (c *Config) applyDefaults() {
 	if c.WriteTimeout == 0 {
 		c.WriteTimeout = 30 * time.Second
 	}
+	if c.FailoverInterval == 0 {
+		c.FailoverInterval = 5 * time.Second
+	}
+	if c.DrainTimeout == 0 {
+		c.DrainTimeout = 30 * time.Second
+	}
+	if c.ReconnectMaxElapsed == 0 {
+		c.ReconnectMaxElapsed = time.Minute
+	}
+	if c.ExplainThreshold == 0 {
+		c.ExplainThreshold = time.Second
+	}
+	if c.N1Threshold == 0 {
+		c.N1Threshold = 5
+	}
+	if c.N1Window == 0 {
+		c.N1Window = hooks.DefaultN1WindowMs * time.Millisecond
+	}
 }
 
 // WithLogger enables query logging
@@ -87,12 +276,40 @@ func (c Config) WithSlowQueryLog(threshold time.Duration) Config {
 	return c
 }
 
+// WithExplainSlowQueries attaches EXPLAIN plans to logs of SELECT queries slower than threshold.
+// Requires a logger. Plans are estimates: the query is not executed again.
+func (c Config) WithExplainSlowQueries(threshold time.Duration) Config {
+	c.ExplainSlowQueries = true
+	c.ExplainThreshold = threshold
+	return c
+}
+
+// NewSamplingConfig returns an option that logs only a rate (0.0 to 1.0) of queries.
+//
+// Usage:
+//
+//	cfg := dbkit.DefaultConfig(url).WithLogger(logger)
+//	dbkit.NewSamplingConfig(0.1)(&cfg)
+func NewSamplingConfig(rate float64) func(*Config) {
+	return func(c *Config) {
+		c.LogSampleRate = rate
+	}
+}
+
 // WithMetrics enables Prometheus metrics
 func (c Config) WithMetrics(registry prometheus.Registerer) Config {
 	c.MetricsRegistry = registry
 	return c
 }
 
+// WithN1Detection enables N+1 query detection with the given handler
+func (c Config) WithN1Detection(threshold int, handler func(pattern string, count int)) Config {
+	c.DetectN1 = true
+	c.N1Threshold = threshold
+	c.N1Handler = handler
+	return c
+}
+
 // WithTracing enables OpenTelemetry tracing
 func (c Config) WithTracing(tracer trace.Tracer) Config {
 	c.Tracer = tracer
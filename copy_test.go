@@ -0,0 +1,137 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendCopyValue(t *testing.T) {
+	tests := []struct {
+		literal  string
+		expected string
+	}{
+		{"NULL", `\N`},
+		{"42", "42"},
+		{"TRUE", "TRUE"},
+		{"'hello'", "hello"},
+		{"'it''s'", "it's"},
+		{"'tab\there'", `tab\there`},
+		{"'line\nbreak'", `line\nbreak`},
+		{`'\x0102'`, `\\x0102`},
+	}
+
+	for _, tt := range tests {
+		result := string(appendCopyValue(nil, []byte(tt.literal)))
+		if result != tt.expected {
+			t.Errorf("appendCopyValue(%q) = %q, expected %q", tt.literal, result, tt.expected)
+		}
+	}
+}
+
+func TestCopyInsert_Empty(t *testing.T) {
+	count, err := CopyInsert[TestModel](context.Background(), nil, nil)
+	if err != nil {
+		t.Errorf("CopyInsert with empty slice should not error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 count, got %d", count)
+	}
+}
+
+func TestCopyInsert_Large(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	rows := make([]TestModel, 100000)
+	for i := range rows {
+		rows[i] = TestModel{
+			Name:   fmt.Sprintf("User %d", i),
+			Email:  fmt.Sprintf("copy%d@example.com", i),
+			Age:    i % 100,
+			Active: i%2 == 0,
+		}
+	}
+
+	start := time.Now()
+	count, err := CopyInsert(ctx, db, rows)
+	if err != nil {
+		t.Fatalf("CopyInsert failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if count != 100000 {
+		t.Errorf("Expected 100000 rows copied, got %d", count)
+	}
+
+	if elapsed > 2*time.Second {
+		t.Errorf("CopyInsert took too long: %v", elapsed)
+	}
+
+	total, err := Count[TestModel](ctx, db, nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if total != 100000 {
+		t.Errorf("Expected 100000 rows in table, got %d", total)
+	}
+}
+
+func TestCopyInsertWithMapping(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	rows := []TestModel{
+		{Name: "a", Email: "mapped1@example.com", Age: 10},
+		{Name: "b", Email: "mapped2@example.com", Age: 20},
+	}
+
+	// Non-default columns in model order: name, email, age, active
+	count, err := CopyInsertWithMapping(ctx, db, rows, func(m TestModel) []any {
+		return []any{"Mapped " + m.Name, m.Email, m.Age, true}
+	})
+	if err != nil {
+		t.Fatalf("CopyInsertWithMapping failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 rows copied, got %d", count)
+	}
+
+	var found TestModel
+	err = db.NewSelect().Model(&found).Where("email = ?", "mapped1@example.com").Scan(ctx)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if found.Name != "Mapped a" || !found.Active {
+		t.Errorf("Unexpected row: %+v", found)
+	}
+}
+
+func TestCopyInsertWithMapping_WrongColumnCount(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	rows := []TestModel{{Name: "a", Email: "short1@example.com"}, {Name: "b", Email: "short2@example.com"}}
+	_, err := CopyInsertWithMapping(ctx, db, rows, func(m TestModel) []any {
+		if m.Name == "b" {
+			return []any{m.Name, m.Email}
+		}
+		return []any{m.Name, m.Email, m.Age, true}
+	})
+	if !IsValidation(err) || !strings.Contains(err.Error(), "row 1") {
+		t.Fatalf("Expected a validation error for row 1, got %v", err)
+	}
+
+	count, err := db.NewSelect().Model((*TestModel)(nil)).Count(ctx)
+	if err != nil || count != 0 {
+		t.Errorf("Expected the COPY to be aborted, got %d rows (%v)", count, err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package crdb runs dbkit transactions on CockroachDB with its client-side retry protocol.
+//
+// CockroachDB aborts contended transactions with SQLSTATE 40001 and expects clients to retry
+// them. The retry loop uses the cockroach_restart savepoint, so a retried transaction keeps its
+// priority and the same connection.
+package crdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// DefaultMaxRetries is the number of retries when Options.MaxRetries is zero
+const DefaultMaxRetries = 10
+
+// restartSavepoint is the savepoint name CockroachDB reserves for client-side retries
+const restartSavepoint = "cockroach_restart"
+
+// retryMessages are the messages of CockroachDB retry errors. Depending on the driver and the
+// node version they are not always reported with SQLSTATE 40001.
+var retryMessages = []string{
+	"restart transaction",
+	"TransactionRetryWithProtoRefreshError",
+	"TransactionRetryError",
+	"TransactionAbortedError",
+	"ReadWithinUncertaintyIntervalError",
+	"WriteTooOldError",
+}
+
+// Options configures CockroachRetryableTransactionWithOptions
+type Options struct {
+	MaxRetries int             // Retries after the first attempt (default: 10)
+	TxOptions  dbkit.TxOptions // Isolation and read-only mode of the transaction
+}
+
+// CockroachRetryableTransaction executes fn in a transaction, retrying it when CockroachDB
+// reports a retryable error. fn may run several times and must not have side effects outside
+// the transaction. Retries use the retry budget of ctx, if any (see dbkit.WithRetryBudget).
+//
+// Usage:
+//
+//	err := crdb.CockroachRetryableTransaction(ctx, db, func(tx *dbkit.Tx) error {
+//	    _, err := tx.NewUpdate().Model(&account).WherePK().Exec(ctx)
+//	    return err
+//	})
+func CockroachRetryableTransaction(ctx context.Context, db *dbkit.DBKit, fn dbkit.TxFunc) error {
+	return CockroachRetryableTransactionWithOptions(ctx, db, Options{}, fn)
+}
+
+// CockroachRetryableTransactionWithOptions is CockroachRetryableTransaction with custom options
+func CockroachRetryableTransactionWithOptions(ctx context.Context, db *dbkit.DBKit, opts Options, fn dbkit.TxFunc) error {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+
+	tx, err := db.BeginWithOptions(ctx, opts.TxOptions)
+	if err != nil {
+		return WrapError(err, "crdb.Transaction.Begin")
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := retry(ctx, tx, opts.MaxRetries, func() error { return fn(tx) }); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("dbkit: rollback failed: %v (original error: %w)", rbErr, err)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return WrapError(err, "crdb.Transaction.Commit")
+	}
+	return nil
+}
+
+// savepointExecer runs the savepoint statements; *dbkit.Tx implements it
+type savepointExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// retry runs fn between SAVEPOINT and RELEASE SAVEPOINT cockroach_restart, rolling back to the
+// savepoint and running fn again on retryable errors
+func retry(ctx context.Context, tx savepointExecer, maxRetries int, fn func() error) error {
+	if err := exec(ctx, tx, "SAVEPOINT "+restartSavepoint); err != nil {
+		return WrapError(err, "crdb.Transaction.Savepoint")
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			// RELEASE commits the transaction in CockroachDB and can fail with a retry error
+			if err = exec(ctx, tx, "RELEASE SAVEPOINT "+restartSavepoint); err == nil {
+				return nil
+			}
+			err = WrapError(err, "crdb.Transaction.Release")
+		}
+		if !IsRetryable(err) {
+			return err
+		}
+		if attempt >= maxRetries {
+			return &dbkit.Error{
+				Code:    dbkit.CodeSerialization,
+				Message: fmt.Sprintf("transaction retry limit of %d exceeded", maxRetries),
+				Op:      "crdb.Transaction",
+				Cause:   err,
+			}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return dbkit.WrapError(ctxErr, "crdb.Transaction")
+		}
+		if err := dbkit.RetryBudgetFromContext(ctx).Take("crdb.Transaction"); err != nil {
+			return err
+		}
+		if rbErr := exec(ctx, tx, "ROLLBACK TO SAVEPOINT "+restartSavepoint); rbErr != nil {
+			return WrapError(rbErr, "crdb.Transaction.Retry")
+		}
+	}
+}
+
+func exec(ctx context.Context, tx savepointExecer, query string) error {
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+// IsRetryable reports whether err asks the client to retry the transaction, either with
+// SQLSTATE 40001 or with one of CockroachDB's retry error messages
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	// Wrapping maps SQLSTATE 40001 from pgx and pgdriver errors
+	if dbkit.IsRetryable(dbkit.WrapError(err, "")) {
+		return true
+	}
+	msg := err.Error()
+	for _, m := range retryMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// WrapError converts err like dbkit.WrapError, mapping CockroachDB retry errors to CodeSerialization
+func WrapError(err error, op string) error {
+	if err == nil {
+		return nil
+	}
+	wrapped := dbkit.WrapError(err, op)
+	if code, _ := dbkit.GetErrorCode(wrapped); code != dbkit.CodeSerialization && IsRetryable(err) {
+		return &dbkit.Error{
+			Code:    dbkit.CodeSerialization,
+			Message: "serialization failure, retry transaction",
+			Op:      op,
+			Cause:   err,
+		}
+	}
+	return wrapped
+}
@@ -0,0 +1,82 @@
+//go:build crdb
+
+package crdb
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// Run with: TEST_COCKROACH_URL=... go test -tags crdb ./crdb
+func getTestDB(t *testing.T) *dbkit.DBKit {
+	t.Helper()
+
+	url := os.Getenv("TEST_COCKROACH_URL")
+	if url == "" {
+		url = "postgres://root@localhost:26257/defaultdb?sslmode=disable"
+	}
+	db, err := dbkit.New(dbkit.DefaultConfig(url))
+	if err != nil {
+		t.Fatalf("Failed to connect to CockroachDB: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestCockroachRetryableTransaction_Retries(t *testing.T) {
+	db := getTestDB(t)
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, `
+		DROP TABLE IF EXISTS crdb_counters;
+		CREATE TABLE crdb_counters (id INT PRIMARY KEY, value INT NOT NULL);
+		INSERT INTO crdb_counters VALUES (1, 0);`); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	attempts := 0
+	err := CockroachRetryableTransaction(ctx, db, func(tx *dbkit.Tx) error {
+		attempts++
+		if _, err := tx.ExecContext(ctx, "UPDATE crdb_counters SET value = value + 1 WHERE id = 1"); err != nil {
+			return err
+		}
+		// Fails with a retry error until the transaction is 50ms old
+		_, err := tx.ExecContext(ctx, "SELECT crdb_internal.force_retry('50ms')")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("CockroachRetryableTransaction failed: %v", err)
+	}
+	if attempts < 2 {
+		t.Errorf("expected the transaction to be retried, got %d attempts", attempts)
+	}
+
+	var value int
+	if err := db.QueryRowContext(ctx, "SELECT value FROM crdb_counters WHERE id = 1").Scan(&value); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if value != 1 {
+		t.Errorf("expected retried updates to be rolled back to the savepoint, got value %d", value)
+	}
+}
+
+func TestCockroachRetryableTransaction_RetryLimit(t *testing.T) {
+	db := getTestDB(t)
+	ctx := context.Background()
+
+	attempts := 0
+	err := CockroachRetryableTransactionWithOptions(ctx, db, Options{MaxRetries: 2}, func(tx *dbkit.Tx) error {
+		attempts++
+		_, err := tx.ExecContext(ctx, "SELECT crdb_internal.force_retry('1h')")
+		return err
+	})
+	if code, _ := dbkit.GetErrorCode(err); code != dbkit.CodeSerialization {
+		t.Fatalf("expected CodeSerialization, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
@@ -0,0 +1,153 @@
+package crdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// recordingTx records savepoint statements and fails the ones listed in errs
+type recordingTx struct {
+	queries []string
+	errs    map[string]error
+}
+
+func (r *recordingTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	r.queries = append(r.queries, query)
+	if err, ok := r.errs[query]; ok {
+		delete(r.errs, query)
+		return nil, err
+	}
+	return nil, nil
+}
+
+var retryErr = &pgconn.PgError{Code: "40001", Message: "restart transaction: TransactionRetryWithProtoRefreshError: WriteTooOldError"}
+
+func TestRetryRetriesSerializationFailures(t *testing.T) {
+	tx := &recordingTx{}
+	attempts := 0
+	err := retry(context.Background(), tx, 3, func() error {
+		attempts++
+		if attempts < 3 {
+			return retryErr
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+
+	want := []string{
+		"SAVEPOINT cockroach_restart",
+		"ROLLBACK TO SAVEPOINT cockroach_restart",
+		"ROLLBACK TO SAVEPOINT cockroach_restart",
+		"RELEASE SAVEPOINT cockroach_restart",
+	}
+	if !reflect.DeepEqual(tx.queries, want) {
+		t.Errorf("expected %v, got %v", want, tx.queries)
+	}
+}
+
+func TestRetryReleaseFailure(t *testing.T) {
+	tx := &recordingTx{errs: map[string]error{"RELEASE SAVEPOINT cockroach_restart": retryErr}}
+	attempts := 0
+	if err := retry(context.Background(), tx, 3, func() error { attempts++; return nil }); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected a retry after RELEASE failed, got %d attempts", attempts)
+	}
+}
+
+func TestRetryLimit(t *testing.T) {
+	tx := &recordingTx{}
+	attempts := 0
+	err := retry(context.Background(), tx, 2, func() error { attempts++; return retryErr })
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if code, _ := dbkit.GetErrorCode(err); code != dbkit.CodeSerialization {
+		t.Errorf("expected CodeSerialization, got %v", err)
+	}
+	if !errors.Is(err, retryErr) {
+		t.Error("expected the last error as cause")
+	}
+}
+
+func TestRetryStopsOnOtherErrors(t *testing.T) {
+	tx := &recordingTx{}
+	boom := errors.New("boom")
+	attempts := 0
+	err := retry(context.Background(), tx, 3, func() error { attempts++; return boom })
+	if !errors.Is(err, boom) || attempts != 1 {
+		t.Errorf("expected boom after 1 attempt, got %v after %d", err, attempts)
+	}
+}
+
+func TestRetryStopsOnCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tx := &recordingTx{}
+	attempts := 0
+	err := retry(ctx, tx, 3, func() error { attempts++; cancel(); return retryErr })
+	if attempts != 1 || err == nil {
+		t.Errorf("expected to stop after 1 attempt, got %v after %d", err, attempts)
+	}
+}
+
+func TestRetryUsesRetryBudget(t *testing.T) {
+	ctx := dbkit.WithRetryBudget(context.Background(), 1)
+	attempts := 0
+	err := retry(ctx, &recordingTx{}, 5, func() error { attempts++; return retryErr })
+	if !errors.Is(err, dbkit.ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"pgx 40001", &pgconn.PgError{Code: "40001"}, true},
+		{"wrapped 40001", dbkit.WrapError(&pgconn.PgError{Code: "40001"}, "op"), true},
+		{"message only", errors.New("ERROR: restart transaction: TransactionRetryWithProtoRefreshError: ReadWithinUncertaintyIntervalError"), true},
+		{"aborted", fmt.Errorf("exec: %w", errors.New("TransactionAbortedError(ABORT_REASON_ABORTED_RECORD_FOUND)")), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	err := WrapError(errors.New("XX000: restart transaction: TransactionRetryError: retry txn"), "op")
+	if !dbkit.IsRetryable(err) {
+		t.Errorf("expected CodeSerialization, got %v", err)
+	}
+	if code, _ := dbkit.GetErrorCode(WrapError(&pgconn.PgError{Code: "23505"}, "op")); code != dbkit.CodeDuplicate {
+		t.Errorf("expected other errors to be wrapped as usual, got %s", code)
+	}
+	if WrapError(nil, "op") != nil {
+		t.Error("expected nil")
+	}
+}
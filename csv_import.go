@@ -0,0 +1,379 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// ConflictStrategy selects what ImportCSV does with rows that violate a unique constraint
+type ConflictStrategy int
+
+const (
+	ConflictFail   ConflictStrategy = iota // Report the row in ImportResult.RowErrors
+	ConflictSkip                           // Skip the row, counted in ImportResult.Skipped
+	ConflictUpdate                         // Update the existing row with the imported columns
+)
+
+// CSVImportOptions configures ImportCSV
+type CSVImportOptions struct {
+	// HasHeader reads column names from the first line. Without a header, columns are named
+	// by their 1-based position ("1", "2", ...), or map to the model columns in field order
+	// when ColumnMap is empty.
+	HasHeader bool
+
+	// ColumnMap maps CSV column names to model columns or Go field names. Columns missing
+	// from the map must be named like a model column; map a column to "" to ignore it.
+	ColumnMap map[string]string
+
+	ConflictColumns []string         // Unique columns of ConflictSkip and ConflictUpdate (empty: any conflict with ConflictSkip)
+	OnConflict      ConflictStrategy // Default: ConflictFail
+	BatchSize       int              // Rows inserted per statement (default: BatchSize)
+}
+
+// ImportResult reports the outcome of ImportCSV
+type ImportResult struct {
+	Inserted  int64 // Rows inserted, or updated with ConflictUpdate
+	Skipped   int64 // Conflicting rows skipped with ConflictSkip
+	Errors    int64
+	RowErrors []RowError
+}
+
+// RowError describes a CSV row that could not be imported
+type RowError struct {
+	Row    int    // 1-based data row, the header excluded
+	Column string // CSV column of a value that could not be parsed, empty for database errors
+	Err    error
+}
+
+func (e RowError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("row %d, column %s: %v", e.Row, e.Column, e.Err)
+	}
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// ImportCSV reads records of T from r and inserts them in batches. Values are parsed according to
+// the field types: numbers, booleans (strconv.ParseBool), time.Time (RFC 3339 or "2006-01-02"),
+// sql.Scanner and encoding.TextUnmarshaler types, and JSON for other types. Empty values are
+// zero, or nil for pointers.
+// Rows that fail to parse or insert are reported in RowErrors and the import continues: when a
+// batch fails, its rows are inserted one by one to find the failing ones. Each statement runs in
+// its own transaction (or savepoint if db is a transaction).
+//
+// Usage:
+//
+//	result, err := dbkit.ImportCSV[Customer](ctx, db, file, dbkit.CSVImportOptions{
+//	    HasHeader:       true,
+//	    ColumnMap:       map[string]string{"E-mail": "email", "Full name": "name"},
+//	    ConflictColumns: []string{"email"},
+//	    OnConflict:      dbkit.ConflictUpdate,
+//	})
+//	for _, e := range result.RowErrors {
+//	    log.Printf("skipped %v", e)
+//	}
+func ImportCSV[T any](ctx context.Context, db IDB, r io.Reader, opts CSVImportOptions) (*ImportResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = BatchSize
+	}
+	if opts.OnConflict == ConflictUpdate && len(opts.ConflictColumns) == 0 {
+		return nil, &Error{Code: CodeValidation, Message: "ConflictUpdate needs ConflictColumns", Op: "ImportCSV"}
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	var names []string
+	if opts.HasHeader {
+		header, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return &ImportResult{}, nil
+		}
+		if err != nil {
+			return nil, wrapError(err, "ImportCSV.Header")
+		}
+		names = slices.Clone(header)
+	}
+
+	var (
+		fields  []*schema.Field
+		result  = &ImportResult{}
+		batch   []T
+		rowNums []int
+		row     int
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := importCSVBatch(ctx, db, table, fields, batch, rowNums, opts, result); err != nil {
+			return err
+		}
+		batch, rowNums = batch[:0], rowNums[:0]
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row++
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			result.addRowError(RowError{Row: row, Err: err})
+			continue
+		}
+		if err != nil {
+			return result, wrapError(err, "ImportCSV.Read")
+		}
+
+		if fields == nil {
+			if names == nil {
+				for i := range record {
+					names = append(names, strconv.Itoa(i+1))
+				}
+			}
+			positional := !opts.HasHeader && len(opts.ColumnMap) == 0
+			if fields, err = csvImportFields(table, names, opts.ColumnMap, positional); err != nil {
+				return nil, err
+			}
+		}
+
+		var model T
+		if e, ok := parseCSVRecord(reflect.ValueOf(&model).Elem(), fields, names, record); !ok {
+			e.Row = row
+			result.addRowError(e)
+			continue
+		}
+		batch = append(batch, model)
+		rowNums = append(rowNums, row)
+
+		if len(batch) >= opts.BatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, flush()
+}
+
+func (r *ImportResult) addRowError(e RowError) {
+	r.Errors++
+	r.RowErrors = append(r.RowErrors, e)
+}
+
+// csvImportFields resolves the model field of each CSV column, nil for ignored columns
+func csvImportFields(table *schema.Table, names []string, columnMap map[string]string, positional bool) ([]*schema.Field, error) {
+	fields := make([]*schema.Field, len(names))
+	for i, name := range names {
+		target, mapped := columnMap[name]
+		switch {
+		case mapped && target == "":
+			continue
+		case !mapped && positional:
+			if i >= len(table.Fields) {
+				return nil, &Error{Code: CodeValidation, Message: fmt.Sprintf("CSV has more columns than %s", table.TypeName), Op: "ImportCSV", Table: table.Name}
+			}
+			fields[i] = table.Fields[i]
+			continue
+		case !mapped:
+			target = name
+		}
+
+		field := table.FieldMap[target]
+		if field == nil {
+			for _, f := range table.Fields {
+				if f.GoName == target {
+					field = f
+					break
+				}
+			}
+		}
+		if field == nil {
+			return nil, &Error{
+				Code:    CodeValidation,
+				Message: fmt.Sprintf("CSV column %q matches no column of %s", name, table.TypeName),
+				Op:      "ImportCSV",
+				Table:   table.Name,
+				Column:  target,
+			}
+		}
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+// parseCSVRecord sets the fields of model from record, reporting the first value that does not parse
+func parseCSVRecord(model reflect.Value, fields []*schema.Field, names, record []string) (RowError, bool) {
+	for i, value := range record {
+		if i >= len(fields) || fields[i] == nil {
+			continue
+		}
+		if err := setCSVValue(fields[i].Value(model), value); err != nil {
+			return RowError{Column: names[i], Err: err}, false
+		}
+	}
+	return RowError{}, true
+}
+
+// setCSVValue parses s into v according to its type
+func setCSVValue(v reflect.Value, s string) error {
+	if s == "" {
+		v.SetZero()
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := setCSVValue(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch dest := v.Addr().Interface().(type) {
+	case *time.Time:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", time.DateOnly} {
+			if t, err := time.Parse(layout, s); err == nil {
+				*dest = t
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid time %q", s)
+	case sql.Scanner:
+		return dest.Scan(s)
+	case encoding.TextUnmarshaler:
+		return dest.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return nil
+}
+
+// importCSVBatch inserts batch, falling back to one row at a time to report the failing rows
+func importCSVBatch[T any](ctx context.Context, db IDB, table *schema.Table, fields []*schema.Field, batch []T, rowNums []int, opts CSVImportOptions, result *ImportResult) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError(err, "ImportCSV")
+	}
+	updateColumns := csvUpdateColumns(table, fields, opts.ConflictColumns)
+
+	insert := func(items []T) (int64, error) {
+		var rows int64
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			var err error
+			switch opts.OnConflict {
+			case ConflictSkip:
+				rows, err = insertCSVIgnoring(ctx, tx, items, opts.ConflictColumns)
+			case ConflictUpdate:
+				rows, err = BatchUpsert(ctx, tx, items, opts.ConflictColumns, updateColumns, len(items))
+			default:
+				rows, err = BatchInsert(ctx, tx, items, len(items))
+			}
+			return err
+		})
+		return rows, err
+	}
+
+	record := func(items []T, rows int64) {
+		result.Inserted += rows
+		if opts.OnConflict == ConflictSkip {
+			result.Skipped += int64(len(items)) - rows
+		}
+	}
+
+	if rows, err := insert(batch); err == nil {
+		record(batch, rows)
+		return nil
+	}
+	for i := range batch {
+		rows, err := insert(batch[i : i+1])
+		if err != nil {
+			result.addRowError(RowError{Row: rowNums[i], Err: wrapError(err, "ImportCSV")})
+			continue
+		}
+		record(batch[i:i+1], rows)
+	}
+	return nil
+}
+
+// insertCSVIgnoring inserts items, skipping rows that conflict on columns (any constraint when empty)
+func insertCSVIgnoring[T any](ctx context.Context, db IDB, items []T, columns []string) (int64, error) {
+	if len(columns) == 0 {
+		return InsertOrIgnoreMany(ctx, db, items)
+	}
+	result, err := db.NewInsert().Model(&items).
+		On("CONFLICT (" + joinColumns(columns) + ") DO NOTHING").
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, wrapError(err, "ImportCSV")
+	}
+	if result == nil {
+		return 0, nil
+	}
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// csvUpdateColumns returns the imported columns that ConflictUpdate sets, plus updated_at
+func csvUpdateColumns(table *schema.Table, fields []*schema.Field, conflictColumns []string) []string {
+	var columns []string
+	for _, f := range fields {
+		if f != nil && !f.IsPK && !slices.Contains(conflictColumns, f.Name) && !slices.Contains(columns, f.Name) {
+			columns = append(columns, f.Name)
+		}
+	}
+	if _, ok := table.FieldMap["updated_at"]; ok && !slices.Contains(columns, "updated_at") {
+		columns = append(columns, "updated_at")
+	}
+	return columns
+}
@@ -0,0 +1,200 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetCSVValue(t *testing.T) {
+	var v struct {
+		S string
+		I int16
+		U uint
+		F float64
+		B bool
+		T time.Time
+		D time.Duration
+		P *int
+		M map[string]int
+	}
+	rv := reflect.ValueOf(&v).Elem()
+
+	for _, c := range []struct {
+		field, value string
+	}{
+		{"S", "text"}, {"I", "-12"}, {"U", "7"}, {"F", "1.5"}, {"B", "true"},
+		{"T", "2024-01-15"}, {"D", "90"}, {"P", "3"}, {"M", `{"a":1}`},
+	} {
+		if err := setCSVValue(rv.FieldByName(c.field), c.value); err != nil {
+			t.Errorf("%s: %v", c.field, err)
+		}
+	}
+	if v.S != "text" || v.I != -12 || v.U != 7 || v.F != 1.5 || !v.B || v.D != 90 || *v.P != 3 || v.M["a"] != 1 {
+		t.Errorf("Unexpected values %+v", v)
+	}
+	if !v.T.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-01-15, got %v", v.T)
+	}
+
+	if err := setCSVValue(rv.FieldByName("P"), ""); err != nil || v.P != nil {
+		t.Errorf("Expected an empty value to reset the pointer, got %v (%v)", v.P, err)
+	}
+	for field, value := range map[string]string{"I": "100000", "B": "maybe", "T": "yesterday", "F": "x"} {
+		if err := setCSVValue(rv.FieldByName(field), value); err == nil {
+			t.Errorf("%s: expected %q to fail", field, value)
+		}
+	}
+}
+
+func TestCSVImportFields(t *testing.T) {
+	db, _ := newFakeDB(t)
+	table := db.Dialect().Tables().Get(reflect.TypeFor[TestModel]())
+
+	fields, err := csvImportFields(table, []string{"E-mail", "Name", "notes", "age"}, map[string]string{"E-mail": "email", "notes": ""}, false)
+	if err != nil {
+		t.Fatalf("csvImportFields failed: %v", err)
+	}
+	if fields[0].Name != "email" || fields[1].Name != "name" || fields[2] != nil || fields[3].Name != "age" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+
+	fields, err = csvImportFields(table, []string{"1", "2"}, nil, true)
+	if err != nil || fields[0].Name != "id" || fields[1].Name != "name" {
+		t.Errorf("Expected positional fields, got %v (%v)", fields, err)
+	}
+
+	if _, err := csvImportFields(table, []string{"phone"}, nil, false); !IsValidation(err) {
+		t.Errorf("Expected a validation error for an unknown column, got %v", err)
+	}
+}
+
+func TestImportCSV_Validation(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	_, err := ImportCSV[TestModel](ctx, db, strings.NewReader("email\na@example.com\n"), CSVImportOptions{HasHeader: true, OnConflict: ConflictUpdate})
+	if !IsValidation(err) {
+		t.Errorf("Expected ConflictUpdate without ConflictColumns to fail validation, got %v", err)
+	}
+
+	_, err = ImportCSV[TestModel](ctx, db, strings.NewReader("phone\n555\n"), CSVImportOptions{HasHeader: true})
+	if !IsValidation(err) {
+		t.Errorf("Expected an unknown column to fail validation, got %v", err)
+	}
+
+	result, err := ImportCSV[TestModel](ctx, db, strings.NewReader(""), CSVImportOptions{HasHeader: true})
+	if err != nil || result.Inserted != 0 || result.Errors != 0 {
+		t.Errorf("Expected an empty import, got %+v (%v)", result, err)
+	}
+}
+
+// testCSV returns n rows of TestModel, row dup (1-based) repeating the email of row 1
+func testCSV(n, dup int) string {
+	var b strings.Builder
+	b.WriteString("Full name,E-mail,age,active\n")
+	for i := 1; i <= n; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if i == dup {
+			email = "user1@example.com"
+		}
+		fmt.Fprintf(&b, "User %d,%s,%d,%t\n", i, email, i%100, i%2 == 0)
+	}
+	return b.String()
+}
+
+var testCSVOptions = CSVImportOptions{
+	HasHeader: true,
+	ColumnMap: map[string]string{"Full name": "Name", "E-mail": "email"},
+	BatchSize: 30,
+}
+
+func TestImportCSV(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTable(t, db)
+
+	result, err := ImportCSV[TestModel](ctx, db, strings.NewReader(testCSV(100, 50)), testCSVOptions)
+	if err != nil {
+		t.Fatalf("ImportCSV failed: %v", err)
+	}
+	if result.Inserted != 99 || result.Errors != 1 || len(result.RowErrors) != 1 {
+		t.Fatalf("Expected 99 inserted and 1 error, got %+v", result)
+	}
+	if e := result.RowErrors[0]; e.Row != 50 || !IsDuplicate(e.Err) {
+		t.Errorf("Expected a duplicate on row 50, got %v", e)
+	}
+
+	count, err := db.NewSelect().Model((*TestModel)(nil)).Count(ctx)
+	if err != nil || count != 99 {
+		t.Errorf("Expected 99 rows, got %d (%v)", count, err)
+	}
+
+	var m TestModel
+	if err := db.NewSelect().Model(&m).Where("email = ?", "user42@example.com").Scan(ctx); err != nil {
+		t.Fatalf("Failed to load imported row: %v", err)
+	}
+	if m.Name != "User 42" || m.Age != 42 || !m.Active {
+		t.Errorf("Unexpected imported row %+v", m)
+	}
+}
+
+func TestImportCSV_ParseErrors(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTable(t, db)
+
+	input := "Full name,E-mail,age,active\nAda,ada@example.com,36,true\nBob,bob@example.com,old,true\nEve,eve@example.com\n"
+	result, err := ImportCSV[TestModel](ctx, db, strings.NewReader(input), testCSVOptions)
+	if err != nil {
+		t.Fatalf("ImportCSV failed: %v", err)
+	}
+	if result.Inserted != 1 || result.Errors != 2 {
+		t.Fatalf("Expected 1 inserted and 2 errors, got %+v", result)
+	}
+	if e := result.RowErrors[0]; e.Row != 2 || e.Column != "age" {
+		t.Errorf("Expected an age error on row 2, got %v", e)
+	}
+	if e := result.RowErrors[1]; e.Row != 3 {
+		t.Errorf("Expected a field count error on row 3, got %v", e)
+	}
+}
+
+func TestImportCSV_OnConflict(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTable(t, db)
+
+	if _, err := ImportCSV[TestModel](ctx, db, strings.NewReader(testCSV(10, 0)), testCSVOptions); err != nil {
+		t.Fatalf("ImportCSV failed: %v", err)
+	}
+
+	opts := testCSVOptions
+	opts.ConflictColumns = []string{"email"}
+	opts.OnConflict = ConflictSkip
+	result, err := ImportCSV[TestModel](ctx, db, strings.NewReader(testCSV(20, 0)), opts)
+	if err != nil {
+		t.Fatalf("ImportCSV with ConflictSkip failed: %v", err)
+	}
+	if result.Inserted != 10 || result.Skipped != 10 || result.Errors != 0 {
+		t.Errorf("Expected 10 inserted and 10 skipped, got %+v", result)
+	}
+
+	opts.OnConflict = ConflictUpdate
+	input := strings.ReplaceAll(testCSV(20, 0), "User ", "Updated ")
+	result, err = ImportCSV[TestModel](ctx, db, strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatalf("ImportCSV with ConflictUpdate failed: %v", err)
+	}
+	if result.Inserted != 20 || result.Errors != 0 {
+		t.Errorf("Expected 20 upserted, got %+v", result)
+	}
+
+	count, err := db.NewSelect().Model((*TestModel)(nil)).Where("name LIKE 'Updated %'").Count(ctx)
+	if err != nil || count != 20 {
+		t.Errorf("Expected 20 updated rows, got %d (%v)", count, err)
+	}
+}
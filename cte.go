@@ -0,0 +1,97 @@
+package dbkit
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// CTEDef is a named common table expression for FindWithCTE
+type CTEDef struct {
+	Name      string
+	Query     string // SQL with ? placeholders
+	Args      []any
+	Recursive bool // Query references Name (base UNION ALL recursive part)
+}
+
+// WithCTE returns a query modifier that prepends WITH name AS (query).
+// The main query can then select from name, e.g. with ModelTableExpr or Join.
+//
+// Usage:
+//
+//	db.NewSelect().Model(&users).
+//	    Apply(dbkit.WithCTE("big_spenders", "SELECT user_id FROM orders GROUP BY user_id HAVING SUM(total) > ?", 1000)).
+//	    Where("id IN (SELECT user_id FROM big_spenders)").
+//	    Scan(ctx)
+func WithCTE(name, query string, args ...any) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.With(name, q.DB().NewRaw(query, args...))
+	}
+}
+
+// WithRecursiveCTE returns a query modifier that prepends
+// WITH RECURSIVE name AS (baseQuery UNION ALL recursiveQuery).
+// args are shared by both parts in order. Apply it before any other CTE,
+// PostgreSQL only accepts RECURSIVE right after WITH.
+//
+// Usage:
+//
+//	db.NewSelect().Model(&categories).
+//	    Apply(dbkit.WithRecursiveCTE("tree",
+//	        "SELECT * FROM categories WHERE id = ?",
+//	        "SELECT c.* FROM categories c JOIN tree t ON c.parent_id = t.id",
+//	        rootID)).
+//	    ModelTableExpr("tree AS c").
+//	    Scan(ctx)
+func WithRecursiveCTE(name, baseQuery, recursiveQuery string, args ...any) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WithRecursive(name, q.DB().NewRaw(recursiveUnion(baseQuery, recursiveQuery), args...))
+	}
+}
+
+// recursiveUnion joins the parts of a recursive CTE.
+func recursiveUnion(baseQuery, recursiveQuery string) string {
+	return baseQuery + " UNION ALL " + recursiveQuery
+}
+
+// FindWithCTE runs mainQueryFn on T's records with the given CTEs in scope.
+// CTEs are emitted in order, a recursive CTE makes the whole WITH clause RECURSIVE.
+// Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	categories, err := dbkit.FindWithCTE[Category](ctx, db, []dbkit.CTEDef{{
+//	    Name:      "tree",
+//	    Query:     "SELECT * FROM categories WHERE id = ? UNION ALL SELECT c.* FROM categories c JOIN tree t ON c.parent_id = t.id",
+//	    Args:      []any{rootID},
+//	    Recursive: true,
+//	}}, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.ModelTableExpr("tree AS c")
+//	})
+func FindWithCTE[T any](ctx context.Context, db IDB, ctes []CTEDef, mainQueryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]T, error) {
+	models := []T{}
+	q := db.NewSelect().Model(&models)
+
+	recursive := false
+	for _, cte := range ctes {
+		recursive = recursive || cte.Recursive
+	}
+	for i, cte := range ctes {
+		raw := db.NewRaw(cte.Query, cte.Args...)
+		// Bun writes RECURSIVE per CTE, PostgreSQL expects it once after WITH
+		if recursive && i == 0 {
+			q = q.WithRecursive(cte.Name, raw)
+		} else {
+			q = q.With(cte.Name, raw)
+		}
+	}
+
+	if mainQueryFn != nil {
+		q = mainQueryFn(q)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindWithCTE")
+	}
+	return models, nil
+}
@@ -0,0 +1,130 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type TestCategory struct {
+	bun.BaseModel `bun:"table:test_categories,alias:tc"`
+	ID            int64  `bun:"id,pk,autoincrement"`
+	ParentID      *int64 `bun:"parent_id"`
+	Name          string `bun:"name,notnull"`
+}
+
+// queryRecorderHook keeps the last executed query.
+type queryRecorderHook struct {
+	last    string
+	queries []string
+}
+
+func (h *queryRecorderHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *queryRecorderHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	h.last = event.Query
+	h.queries = append(h.queries, event.Query)
+}
+
+func TestWithCTE_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	var models []TestModel
+	sql := db.NewSelect().Model(&models).
+		Apply(WithRecursiveCTE("tree", "SELECT * FROM test_models WHERE id = ?", "SELECT m.* FROM test_models m JOIN tree t ON m.id = t.id", "root")).
+		Apply(WithCTE("adults", "SELECT id FROM test_models WHERE age >= ?", 18)).
+		ModelTableExpr("tree AS tm").
+		String()
+
+	want := `WITH RECURSIVE "tree" AS (SELECT * FROM test_models WHERE id = 'root' UNION ALL SELECT m.* FROM test_models m JOIN tree t ON m.id = t.id), "adults" AS (SELECT id FROM test_models WHERE age >= 18) SELECT`
+	if !strings.HasPrefix(sql, want) {
+		t.Errorf("Unexpected SQL:\n got %s\nwant prefix %s", sql, want)
+	}
+}
+
+func TestFindWithCTE_RecursiveOnce(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	rows, err := FindWithCTE[TestModel](context.Background(), db, []CTEDef{
+		{Name: "a", Query: "SELECT 1"},
+		{Name: "b", Query: "SELECT 1 UNION ALL SELECT 1 FROM b", Recursive: true},
+	}, nil)
+	if err != nil {
+		t.Fatalf("FindWithCTE failed: %v", err)
+	}
+	if rows == nil || len(rows) != 0 {
+		t.Errorf("Expected an empty slice, got %v", rows)
+	}
+	if !strings.HasPrefix(hook.last, `WITH RECURSIVE "a" AS (SELECT 1), "b" AS (`) {
+		t.Errorf("Expected a single RECURSIVE after WITH, got %s", hook.last)
+	}
+}
+
+func TestFindWithCTE_CategoryTree(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.NewDropTable().Model((*TestCategory)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop categories table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestCategory)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create categories table: %v", err)
+	}
+
+	// electronics > computers > laptops, electronics > phones, books (separate tree)
+	insert := func(name string, parent *int64) int64 {
+		c := &TestCategory{Name: name, ParentID: parent}
+		if _, err := db.NewInsert().Model(c).Exec(ctx); err != nil {
+			t.Fatalf("Insert %s failed: %v", name, err)
+		}
+		return c.ID
+	}
+	electronics := insert("electronics", nil)
+	computers := insert("computers", &electronics)
+	insert("laptops", &computers)
+	insert("phones", &electronics)
+	insert("books", nil)
+
+	tree, err := FindWithCTE[TestCategory](ctx, db, []CTEDef{{
+		Name:      "tree",
+		Query:     "SELECT * FROM test_categories WHERE id = ? UNION ALL SELECT c.* FROM test_categories c JOIN tree t ON c.parent_id = t.id",
+		Args:      []any{electronics},
+		Recursive: true,
+	}}, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.ModelTableExpr("tree AS tc").Order("id ASC")
+	})
+	if err != nil {
+		t.Fatalf("FindWithCTE failed: %v", err)
+	}
+
+	var names []string
+	for _, c := range tree {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "electronics,computers,laptops,phones" {
+		t.Errorf("Expected all levels below electronics, got %v", names)
+	}
+
+	var subtree []TestCategory
+	err = db.NewSelect().Model(&subtree).
+		Apply(WithRecursiveCTE("tree",
+			"SELECT * FROM test_categories WHERE id = ?",
+			"SELECT c.* FROM test_categories c JOIN tree t ON c.parent_id = t.id",
+			computers)).
+		ModelTableExpr("tree AS tc").
+		Order("id ASC").
+		Scan(ctx)
+	if err != nil {
+		t.Fatalf("WithRecursiveCTE query failed: %v", err)
+	}
+	if len(subtree) != 2 || subtree[0].Name != "computers" || subtree[1].Name != "laptops" {
+		t.Errorf("Unexpected subtree %+v", subtree)
+	}
+}
@@ -0,0 +1,232 @@
+// Package dataloader batches and deduplicates lookups by key, so GraphQL resolvers that load
+// related entities one at a time run a single query per batch instead of one per entity.
+//
+// Usage:
+//
+//	loader := dataloader.NewDataLoader(ctx, func(ctx context.Context, ids []int64) (map[int64]*User, error) {
+//	    var users []User
+//	    if err := db.NewSelect().Model(&users).Where("id IN (?)", bun.In(ids)).Scan(ctx); err != nil {
+//	        return nil, err
+//	    }
+//	    byID := make(map[int64]*User, len(users))
+//	    for i := range users {
+//	        byID[users[i].ID] = &users[i]
+//	    }
+//	    return byID, nil
+//	}, dataloader.DataLoaderOptions{MaxBatch: 100, Cache: true})
+//
+//	author, err := loader.Load(ctx, post.AuthorID)
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// defaultWait is how long a batch collects keys when DataLoaderOptions.Wait is zero
+const defaultWait = time.Millisecond
+
+// DataLoaderOptions configures batching and caching
+type DataLoaderOptions struct {
+	MaxBatch int           // Max keys per batch call, a full batch fires immediately (0 = unlimited)
+	Wait     time.Duration // Time a batch collects keys before firing (default: 1ms)
+	Cache    bool          // Keep loaded values for the lifetime of the loader, typically one request
+}
+
+// DataLoader collects keys requested within a short window and loads them with one BatchFunc call.
+// Concurrent loads of the same key share a single result.
+type DataLoader[K comparable, V any] struct {
+	ctx     context.Context
+	batchFn func(ctx context.Context, keys []K) (map[K]V, error)
+	opts    DataLoaderOptions
+
+	mu    sync.Mutex
+	cache map[K]*thunk[V]
+	batch *batch[K, V]
+}
+
+// thunk is the pending result of one key, readable once done is closed
+type thunk[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func (t *thunk[V]) wait(ctx context.Context) (V, error) {
+	select {
+	case <-t.done:
+		return t.value, t.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// batch holds the keys scheduled for the next BatchFunc call
+type batch[K comparable, V any] struct {
+	keys   []K
+	thunks map[K]*thunk[V]
+	timer  *time.Timer
+}
+
+// NewDataLoader returns a loader calling batchFn with ctx, which should outlive every Load call,
+// usually the request context. Keys missing from the map returned by batchFn resolve to a not found error.
+func NewDataLoader[K comparable, V any](ctx context.Context, batchFn func(ctx context.Context, keys []K) (map[K]V, error), opts DataLoaderOptions) *DataLoader[K, V] {
+	if opts.Wait <= 0 {
+		opts.Wait = defaultWait
+	}
+	l := &DataLoader[K, V]{
+		ctx:     ctx,
+		batchFn: batchFn,
+		opts:    opts,
+	}
+	if opts.Cache {
+		l.cache = make(map[K]*thunk[V])
+	}
+	return l
+}
+
+// Load schedules key in the current batch and returns its value once the batch has run.
+//
+// Usage:
+//
+//	user, err := loader.Load(ctx, userID)
+//	if dbkit.IsNotFound(err) {
+//	    return nil, nil
+//	}
+func (l *DataLoader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	t := l.schedule(key)
+	l.mu.Unlock()
+
+	return t.wait(ctx)
+}
+
+// LoadMany schedules keys in the same batch and returns their values in order.
+// errs[i] is the error for keys[i] and is nil when the key loaded.
+//
+// Usage:
+//
+//	users, errs := loader.LoadMany(ctx, memberIDs)
+func (l *DataLoader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, []error) {
+	thunks := make([]*thunk[V], len(keys))
+	l.mu.Lock()
+	for i, key := range keys {
+		thunks[i] = l.schedule(key)
+	}
+	l.mu.Unlock()
+
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	for i, t := range thunks {
+		values[i], errs[i] = t.wait(ctx)
+	}
+	return values, errs
+}
+
+// Clear removes key from the cache, so the next Load fetches it again
+func (l *DataLoader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.cache, key)
+}
+
+// schedule returns the thunk for key, reusing a cached or already scheduled one.
+// Callers must hold l.mu.
+func (l *DataLoader[K, V]) schedule(key K) *thunk[V] {
+	if t, ok := l.cache[key]; ok {
+		return t
+	}
+
+	b := l.batch
+	if b == nil || (l.opts.MaxBatch > 0 && len(b.keys) >= l.opts.MaxBatch) {
+		b = &batch[K, V]{thunks: make(map[K]*thunk[V])}
+		b.timer = time.AfterFunc(l.opts.Wait, func() { l.fire(b) })
+		l.batch = b
+	}
+	if t, ok := b.thunks[key]; ok {
+		return t
+	}
+
+	t := &thunk[V]{done: make(chan struct{})}
+	b.keys = append(b.keys, key)
+	b.thunks[key] = t
+	if l.cache != nil {
+		l.cache[key] = t
+	}
+
+	// Detach a full batch even if its timer already fired: fire is then waiting for l.mu and runs it
+	if l.opts.MaxBatch > 0 && len(b.keys) >= l.opts.MaxBatch {
+		l.batch = nil
+		if b.timer.Stop() {
+			go l.run(b)
+		}
+	}
+	return t
+}
+
+// fire detaches b from the loader when its wait expires and runs it
+func (l *DataLoader[K, V]) fire(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+// run calls the batch function and resolves every thunk of b
+func (l *DataLoader[K, V]) run(b *batch[K, V]) {
+	values, err := l.call(b.keys)
+
+	var failed []K
+	for _, key := range b.keys {
+		t := b.thunks[key]
+		switch value, ok := values[key]; {
+		case err != nil:
+			t.err = err
+		case !ok:
+			t.err = &dbkit.Error{
+				Code:    dbkit.CodeNotFound,
+				Message: fmt.Sprintf("no value loaded for key %v", key),
+				Op:      "DataLoader.Load",
+			}
+		default:
+			t.value = value
+		}
+		if t.err != nil {
+			failed = append(failed, key)
+		}
+		close(t.done)
+	}
+
+	// Failed keys are not cached so a later Load can retry them
+	if l.cache != nil && len(failed) > 0 {
+		l.mu.Lock()
+		for _, key := range failed {
+			if l.cache[key] == b.thunks[key] {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// call runs the batch function, turning a panic into an error for every key
+func (l *DataLoader[K, V]) call(keys []K) (values map[K]V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &dbkit.Error{
+				Code:    dbkit.CodeUnknown,
+				Message: fmt.Sprintf("batch function panicked: %v", r),
+				Op:      "DataLoader.Load",
+			}
+		}
+	}()
+	return l.batchFn(l.ctx, keys)
+}
@@ -0,0 +1,226 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// recordingBatch returns a batch function that squares keys, skips negative ones and records each call
+func recordingBatch(calls *atomic.Int32, batches *[][]int, mu *sync.Mutex) func(context.Context, []int) (map[int]int, error) {
+	return func(ctx context.Context, keys []int) (map[int]int, error) {
+		calls.Add(1)
+		mu.Lock()
+		*batches = append(*batches, append([]int(nil), keys...))
+		mu.Unlock()
+
+		values := make(map[int]int, len(keys))
+		for _, k := range keys {
+			if k >= 0 {
+				values[k] = k * k
+			}
+		}
+		return values, nil
+	}
+}
+
+func TestLoad_ConcurrentSameKey(t *testing.T) {
+	var calls atomic.Int32
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewDataLoader(context.Background(), recordingBatch(&calls, &batches, &mu), DataLoaderOptions{Wait: 20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := loader.Load(context.Background(), 7)
+			if err != nil || v != 49 {
+				t.Errorf("Load() = %d, %v, want 49", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected batchFn to be called once, got %d", calls.Load())
+	}
+	if len(batches) == 1 && len(batches[0]) != 1 {
+		t.Errorf("Expected the key to be deduplicated, got %v", batches[0])
+	}
+}
+
+func TestLoad_BatchesKeys(t *testing.T) {
+	var calls atomic.Int32
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewDataLoader(context.Background(), recordingBatch(&calls, &batches, &mu), DataLoaderOptions{Wait: 20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			if v, err := loader.Load(context.Background(), key); err != nil || v != key*key {
+				t.Errorf("Load(%d) = %d, %v", key, v, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("Expected one batch, got %d", calls.Load())
+	}
+	keys := batches[0]
+	sort.Ints(keys)
+	if fmt.Sprint(keys) != "[1 2 3 4 5]" {
+		t.Errorf("Unexpected batch keys: %v", keys)
+	}
+}
+
+func TestLoadMany(t *testing.T) {
+	var calls atomic.Int32
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewDataLoader(context.Background(), recordingBatch(&calls, &batches, &mu), DataLoaderOptions{})
+
+	values, errs := loader.LoadMany(context.Background(), []int{2, 3, -1, 2})
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected one batch, got %d", calls.Load())
+	}
+	if values[0] != 4 || values[1] != 9 || values[3] != 4 {
+		t.Errorf("Unexpected values: %v", values)
+	}
+	if errs[0] != nil || errs[1] != nil || errs[3] != nil {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+	if !dbkit.IsNotFound(errs[2]) {
+		t.Errorf("Expected not found for a missing key, got %v", errs[2])
+	}
+}
+
+func TestLoad_MaxBatch(t *testing.T) {
+	var calls atomic.Int32
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewDataLoader(context.Background(), recordingBatch(&calls, &batches, &mu), DataLoaderOptions{MaxBatch: 2, Wait: time.Hour})
+
+	done := make(chan struct{})
+	go func() {
+		loader.LoadMany(context.Background(), []int{1, 2, 3, 4})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Full batches should fire without waiting")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected two batches, got %d", calls.Load())
+	}
+}
+
+func TestLoad_MaxBatchAfterTimerFired(t *testing.T) {
+	var calls atomic.Int32
+	var batches [][]int
+	var mu sync.Mutex
+	loader := NewDataLoader(context.Background(), recordingBatch(&calls, &batches, &mu), DataLoaderOptions{MaxBatch: 2, Wait: time.Millisecond})
+
+	// The timer fires while keys are scheduled, so Stop returns false for the full batch
+	loader.mu.Lock()
+	thunks := []*thunk[int]{loader.schedule(1)}
+	time.Sleep(20 * time.Millisecond)
+	for _, key := range []int{2, 3, 4, 5} {
+		thunks = append(thunks, loader.schedule(key))
+	}
+	loader.mu.Unlock()
+
+	for _, th := range thunks {
+		if _, err := th.wait(context.Background()); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, b := range batches {
+		if len(b) > 2 {
+			t.Errorf("Expected batches of at most 2 keys, got %v", b)
+		}
+	}
+}
+
+func TestLoad_Cache(t *testing.T) {
+	var calls atomic.Int32
+	var batches [][]int
+	var mu sync.Mutex
+	ctx := context.Background()
+
+	cached := NewDataLoader(ctx, recordingBatch(&calls, &batches, &mu), DataLoaderOptions{Cache: true})
+	_, _ = cached.Load(ctx, 1)
+	_, _ = cached.Load(ctx, 1)
+	if calls.Load() != 1 {
+		t.Errorf("Expected the second load to hit the cache, got %d calls", calls.Load())
+	}
+
+	cached.Clear(1)
+	_, _ = cached.Load(ctx, 1)
+	if calls.Load() != 2 {
+		t.Errorf("Expected a load after Clear, got %d calls", calls.Load())
+	}
+
+	calls.Store(0)
+	uncached := NewDataLoader(ctx, recordingBatch(&calls, &batches, &mu), DataLoaderOptions{})
+	_, _ = uncached.Load(ctx, 1)
+	_, _ = uncached.Load(ctx, 1)
+	if calls.Load() != 2 {
+		t.Errorf("Expected two loads without cache, got %d", calls.Load())
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	loader := NewDataLoader(ctx, func(ctx context.Context, keys []string) (map[string]int, error) {
+		if calls.Add(1) == 1 {
+			return nil, boom
+		}
+		return map[string]int{"a": 1}, nil
+	}, DataLoaderOptions{Cache: true})
+
+	if _, err := loader.Load(ctx, "a"); !errors.Is(err, boom) {
+		t.Errorf("Expected the batch error, got %v", err)
+	}
+	if v, err := loader.Load(ctx, "a"); err != nil || v != 1 {
+		t.Errorf("Expected failed keys not to be cached, got %d, %v", v, err)
+	}
+
+	panicking := NewDataLoader(ctx, func(ctx context.Context, keys []string) (map[string]int, error) {
+		panic("bad batch")
+	}, DataLoaderOptions{})
+	if _, err := panicking.Load(ctx, "a"); err == nil {
+		t.Error("Expected an error when the batch function panics")
+	}
+}
+
+func TestLoad_ContextCanceled(t *testing.T) {
+	loader := NewDataLoader(context.Background(), func(ctx context.Context, keys []int) (map[int]int, error) {
+		return nil, nil
+	}, DataLoaderOptions{Wait: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := loader.Load(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// rangeBound returns bound, or nil for an empty string so the range is unbounded on that side.
+func rangeBound(bound string) any {
+	if bound == "" {
+		return nil
+	}
+	return bound
+}
+
+// WhereOverlaps returns a query modifier matching rows where the daterange column overlaps [start, end).
+// An empty start or end leaves that side unbounded.
+//
+// Usage:
+//
+//	err := db.NewSelect().Model(&bookings).
+//	    Apply(dbkit.WhereOverlaps("period", "2024-06-01", "2024-06-08")).
+//	    Scan(ctx)
+func WhereOverlaps(column, start, end string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("? && daterange(?::date, ?::date)", bun.Ident(column), rangeBound(start), rangeBound(end))
+	}
+}
+
+// WhereContains returns a query modifier matching rows where the daterange column contains the date point.
+//
+// Usage:
+//
+//	q = dbkit.WhereContains("period", "2024-06-03")(q)
+func WhereContains(column, point string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("? @> ?::date", bun.Ident(column), point)
+	}
+}
+
+// WhereDuring returns a query modifier matching rows where the daterange column lies entirely within [start, end).
+// An empty start or end leaves that side unbounded.
+//
+// Usage:
+//
+//	q = dbkit.WhereDuring("period", "2024-06-01", "2024-07-01")(q)
+func WhereDuring(column, start, end string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("? <@ daterange(?::date, ?::date)", bun.Ident(column), rangeBound(start), rangeBound(end))
+	}
+}
+
+// CreateDateRangeIndex creates a GiST index on a range column, used by the overlap and containment operators.
+// The index is named idx_<table>_<column>_gist.
+//
+// Usage:
+//
+//	err := dbkit.CreateDateRangeIndex(ctx, db, "bookings", "period")
+func CreateDateRangeIndex(ctx context.Context, db IDB, tableName, column string) error {
+	index := "idx_" + tableName + "_" + column + "_gist"
+	_, err := db.NewRaw("CREATE INDEX IF NOT EXISTS ? ON ? USING GIST (?)",
+		bun.Ident(index), bun.Ident(tableName), bun.Ident(column)).Exec(ctx)
+	return wrapError(err, "CreateDateRangeIndex")
+}
+
+// TimestampRange is a PostgreSQL tstzrange. A zero Lower or Upper is an unbounded side.
+// Like sql.NullTime, a range with Valid false is SQL NULL.
+//
+// Usage:
+//
+//	type Subscription struct {
+//	    bun.BaseModel `bun:"table:subscriptions,alias:s"`
+//	    ID     int64                `bun:"id,pk,autoincrement"`
+//	    Period dbkit.TimestampRange `bun:"period,type:tstzrange"`
+//	}
+//
+//	sub.Period = dbkit.TimestampRange{Lower: start, Upper: end, LowerInclusive: true, Valid: true}
+type TimestampRange struct {
+	Lower          time.Time
+	Upper          time.Time
+	LowerInclusive bool
+	UpperInclusive bool
+	Empty          bool // The range contains no points
+	Valid          bool // The range is not NULL
+}
+
+// timestampRangeLayouts are the formats PostgreSQL uses for timestamptz bounds with DateStyle ISO.
+var timestampRangeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	time.RFC3339Nano,
+}
+
+// Contains reports whether t lies within the range. A NULL range contains nothing.
+func (r TimestampRange) Contains(t time.Time) bool {
+	if !r.Valid || r.Empty {
+		return false
+	}
+	if !r.Lower.IsZero() && (t.Before(r.Lower) || (!r.LowerInclusive && t.Equal(r.Lower))) {
+		return false
+	}
+	if !r.Upper.IsZero() && (t.After(r.Upper) || (!r.UpperInclusive && t.Equal(r.Upper))) {
+		return false
+	}
+	return true
+}
+
+// Value implements driver.Valuer.
+func (r TimestampRange) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	if r.Empty {
+		return "empty", nil
+	}
+
+	var b strings.Builder
+	if r.LowerInclusive && !r.Lower.IsZero() {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if !r.Lower.IsZero() {
+		b.WriteString(`"` + r.Lower.Format(time.RFC3339Nano) + `"`)
+	}
+	b.WriteByte(',')
+	if !r.Upper.IsZero() {
+		b.WriteString(`"` + r.Upper.Format(time.RFC3339Nano) + `"`)
+	}
+	if r.UpperInclusive && !r.Upper.IsZero() {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// Scan implements sql.Scanner.
+func (r *TimestampRange) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*r = TimestampRange{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("dbkit: cannot scan %T into TimestampRange", src)
+	}
+
+	if s == "empty" {
+		*r = TimestampRange{Empty: true, Valid: true}
+		return nil
+	}
+	if len(s) < 3 || !strings.ContainsRune("[(", rune(s[0])) || !strings.ContainsRune("])", rune(s[len(s)-1])) {
+		return fmt.Errorf("dbkit: invalid tstzrange %q", s)
+	}
+
+	lower, upper, ok := strings.Cut(s[1:len(s)-1], ",")
+	if !ok {
+		return fmt.Errorf("dbkit: invalid tstzrange %q", s)
+	}
+
+	parsed := TimestampRange{LowerInclusive: s[0] == '[', UpperInclusive: s[len(s)-1] == ']', Valid: true}
+	var err error
+	if parsed.Lower, err = parseRangeTimestamp(lower); err != nil {
+		return err
+	}
+	if parsed.Upper, err = parseRangeTimestamp(upper); err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// parseRangeTimestamp parses a range bound, returning the zero time for an unbounded side.
+func parseRangeTimestamp(bound string) (time.Time, error) {
+	bound = strings.Trim(bound, `"`)
+	if bound == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timestampRangeLayouts {
+		if t, err := time.Parse(layout, bound); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("dbkit: invalid tstzrange bound %q", bound)
+}
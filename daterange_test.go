@@ -0,0 +1,168 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TestBooking struct {
+	bun.BaseModel `bun:"table:test_bookings,alias:tb"`
+	ID            int64          `bun:"id,pk,autoincrement"`
+	Name          string         `bun:"name,notnull"`
+	Period        string         `bun:"period,type:daterange,notnull"`
+	Slot          TimestampRange `bun:"slot,type:tstzrange"`
+}
+
+func createBookings(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	_, _ = db.NewDropTable().Model((*TestBooking)(nil)).IfExists().Exec(ctx)
+	if _, err := db.NewCreateTable().Model((*TestBooking)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create bookings table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = db.NewDropTable().Model((*TestBooking)(nil)).IfExists().Exec(ctx) })
+
+	if err := CreateDateRangeIndex(ctx, db, "test_bookings", "period"); err != nil {
+		t.Fatalf("CreateDateRangeIndex failed: %v", err)
+	}
+
+	bookings := []TestBooking{
+		{Name: "early", Period: "[2024-06-01,2024-06-05)"},
+		{Name: "middle", Period: "[2024-06-04,2024-06-10)"},
+		{Name: "late", Period: "[2024-06-12,2024-06-20)"},
+	}
+	if _, err := db.NewInsert().Model(&bookings).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert bookings: %v", err)
+	}
+	return ctx
+}
+
+func bookingNames(t *testing.T, db *DBKit, fn func(*bun.SelectQuery) *bun.SelectQuery) []string {
+	t.Helper()
+	var bookings []TestBooking
+	if err := db.NewSelect().Model(&bookings).Apply(fn).OrderExpr("id").Scan(context.Background()); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	names := make([]string, len(bookings))
+	for i, b := range bookings {
+		names[i] = b.Name
+	}
+	return names
+}
+
+func TestWhereOverlaps(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	createBookings(t, db)
+
+	names := bookingNames(t, db, WhereOverlaps("period", "2024-06-03", "2024-06-06"))
+	if len(names) != 2 || names[0] != "early" || names[1] != "middle" {
+		t.Errorf("Expected early and middle, got %v", names)
+	}
+
+	names = bookingNames(t, db, WhereOverlaps("period", "2024-06-15", ""))
+	if len(names) != 1 || names[0] != "late" {
+		t.Errorf("Expected late for an unbounded end, got %v", names)
+	}
+}
+
+func TestWhereContainsAndDuring(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	createBookings(t, db)
+
+	names := bookingNames(t, db, WhereContains("period", "2024-06-04"))
+	if len(names) != 2 {
+		t.Errorf("Expected 2 bookings on 2024-06-04, got %v", names)
+	}
+
+	names = bookingNames(t, db, WhereDuring("period", "2024-06-01", "2024-06-11"))
+	if len(names) != 2 || names[0] != "early" || names[1] != "middle" {
+		t.Errorf("Expected early and middle within the period, got %v", names)
+	}
+}
+
+func TestTimestampRange_RoundTrip(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createBookings(t, db)
+
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	slot := TimestampRange{Lower: start, Upper: start.Add(time.Hour), LowerInclusive: true, Valid: true}
+	booking := &TestBooking{Name: "slot", Period: "[2024-06-01,2024-06-02)", Slot: slot}
+	if _, err := db.NewInsert().Model(booking).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	var loaded TestBooking
+	if err := db.NewSelect().Model(&loaded).Where("id = ?", booking.ID).Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if !loaded.Slot.Lower.Equal(slot.Lower) || !loaded.Slot.Upper.Equal(slot.Upper) ||
+		!loaded.Slot.LowerInclusive || loaded.Slot.UpperInclusive || !loaded.Slot.Valid {
+		t.Errorf("Expected %+v, got %+v", slot, loaded.Slot)
+	}
+}
+
+func TestTimestampRange_Scan(t *testing.T) {
+	var r TimestampRange
+	if err := r.Scan(`["2024-06-01 09:00:00+00","2024-06-01 10:30:00.5+05:30")`); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !r.LowerInclusive || r.UpperInclusive || !r.Valid {
+		t.Errorf("Unexpected bounds: %+v", r)
+	}
+	if !r.Lower.Equal(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected lower bound: %s", r.Lower)
+	}
+	if !r.Upper.Equal(time.Date(2024, 6, 1, 5, 0, 0, 5e8, time.UTC)) {
+		t.Errorf("Unexpected upper bound: %s", r.Upper)
+	}
+
+	if err := r.Scan([]byte(`(,"2024-06-01 09:00:00+00"]`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !r.Lower.IsZero() || !r.UpperInclusive {
+		t.Errorf("Expected unbounded lower side, got %+v", r)
+	}
+
+	if err := r.Scan("empty"); err != nil || !r.Empty {
+		t.Errorf("Expected empty range, got %+v (%v)", r, err)
+	}
+	if err := r.Scan("garbage"); err == nil {
+		t.Error("Expected an error for an invalid range")
+	}
+	if err := r.Scan(nil); err != nil || r.Valid {
+		t.Errorf("Expected a NULL range, got %+v (%v)", r, err)
+	}
+}
+
+func TestTimestampRange_ValueAndContains(t *testing.T) {
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	r := TimestampRange{Lower: start, Upper: start.Add(time.Hour), LowerInclusive: true, Valid: true}
+
+	v, err := r.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if v != `["2024-06-01T09:00:00Z","2024-06-01T10:00:00Z")` {
+		t.Errorf("Unexpected value: %v", v)
+	}
+	if v, _ := (TimestampRange{Valid: true}).Value(); v != "(,)" {
+		t.Errorf("Expected unbounded range, got %v", v)
+	}
+	if v, err := (TimestampRange{Lower: start}).Value(); v != nil || err != nil {
+		t.Errorf("Expected NULL for an invalid range, got %v (%v)", v, err)
+	}
+
+	if !r.Contains(start) || r.Contains(start.Add(time.Hour)) || r.Contains(start.Add(-time.Second)) {
+		t.Error("Unexpected Contains result at the bounds")
+	}
+	if (TimestampRange{Empty: true, Valid: true}).Contains(start) || (TimestampRange{}).Contains(start) {
+		t.Error("Expected empty and NULL ranges to contain nothing")
+	}
+}
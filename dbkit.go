@@ -3,7 +3,12 @@ package dbkit
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -15,7 +20,17 @@ import (
 // DBKit wraps bun.DB with additional functionality
 type DBKit struct {
 	*bun.DB
-	config Config
+	config    Config
+	connector *breakerConnector
+	rds       []*rdsConnector
+
+	tracker      *hooks.TrackingHook
+	n1           *hooks.N1DetectorHook // Nil unless Config.DetectN1
+	shutdownCh   chan struct{}
+	shutdownOnce sync.Once
+
+	healthMu     sync.RWMutex
+	healthChecks map[string]func(ctx context.Context) error
 }
 
 // New creates a new database connection with the given configuration
@@ -23,24 +38,101 @@ func New(cfg Config) (*DBKit, error) {
 	// Apply defaults for zero values
 	cfg.applyDefaults()
 
-	if cfg.URL == "" {
+	problems := cfg.Validate()
+	if err := validationError(problems); err != nil {
+		return nil, &Error{
+			Code:    CodeValidation,
+			Message: err.Error(),
+			Op:      "New",
+			Cause:   err,
+		}
+	}
+	if cfg.Logger != nil {
+		for _, p := range problems {
+			cfg.Logger.Warn("dbkit config warning", slog.String("field", p.Field), slog.String("message", p.Message))
+		}
+	}
+
+	// Create a pgdriver connector with timeouts for the URL and each fallback
+	urls := append([]string{cfg.URL}, cfg.FallbackURLs...)
+	rds, err := newRDSConnectors(cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+	connector := newFailoverConnector(urls, func(url string) driver.Connector {
+		if c, ok := rds[url]; ok {
+			return c
+		}
+		return pgConnector(cfg, url)
+	}, cfg.FailoverInterval)
+
+	// Start with the first reachable URL
+	if err := connector.selectReachable(cfg.DialTimeout); err != nil {
+		stopRDSConnectors(rds)
 		return nil, &Error{
 			Code:    CodeConnectionFailed,
-			Message: "database URL is required",
+			Message: "failed to connect to database",
 			Op:      "New",
+			Cause:   err,
 		}
 	}
 
-	// Create pgdriver connector with timeouts
-	connector := pgdriver.NewConnector(
-		pgdriver.WithDSN(cfg.URL),
+	db, err := newDBKit(cfg, connector)
+	if err != nil {
+		stopRDSConnectors(rds)
+		return nil, err
+	}
+	for _, url := range urls {
+		if c, ok := rds[url]; ok {
+			db.rds = append(db.rds, c)
+			delete(rds, url)
+		}
+	}
+
+	// Verify connection
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, &Error{
+			Code:    CodeConnectionFailed,
+			Message: "failed to connect to database",
+			Op:      "New",
+			Cause:   err,
+		}
+	}
+
+	return db, nil
+}
+
+// pgConnector creates a pgdriver connector for url with the configured timeouts.
+// extra options are applied last and override the URL settings.
+func pgConnector(cfg Config, url string, extra ...pgdriver.Option) driver.Connector {
+	opts := []pgdriver.Option{
+		pgdriver.WithDSN(url),
 		pgdriver.WithDialTimeout(cfg.DialTimeout),
 		pgdriver.WithReadTimeout(cfg.ReadTimeout),
 		pgdriver.WithWriteTimeout(cfg.WriteTimeout),
-	)
+	}
+	if cfg.ApplicationName != "" {
+		opts = append(opts, pgdriver.WithApplicationName(cfg.ApplicationName))
+	}
+	opts = append(opts, extra...)
+	return pgdriver.NewConnector(opts...)
+}
+
+// newDBKit creates a DBKit with a connection pool on top of connector.
+func newDBKit(cfg Config, connector driver.Connector) (*DBKit, error) {
+	// Wrap the connector so a circuit breaker can be installed later
+	bc := &breakerConnector{
+		Connector: connector,
+		reconnect: &reconnector{enabled: cfg.AutoReconnect, maxElapsed: cfg.ReconnectMaxElapsed, sem: make(chan struct{}, 1)},
+		pgBouncer: cfg.PgBouncerMode,
+	}
 
 	// Open sql.DB
-	sqlDB := sql.OpenDB(connector)
+	sqlDB := sql.OpenDB(bc)
 
 	// Configure pool
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
@@ -52,43 +144,130 @@ func New(cfg Config) (*DBKit, error) {
 	bunDB := bun.NewDB(sqlDB, pgdialect.New())
 
 	db := &DBKit{
-		DB:     bunDB,
-		config: cfg,
+		DB:         bunDB,
+		config:     cfg,
+		connector:  bc,
+		tracker:    hooks.NewTrackingHook(),
+		shutdownCh: make(chan struct{}),
 	}
 
+	// Track in-flight queries for graceful shutdown
+	bunDB.AddQueryHook(db.tracker)
+	// Apply per-query timeouts from hooks.WithQueryTimeout
+	bunDB.AddQueryHook(hooks.NewQueryTimeoutHook())
+
 	// Add observability hooks
-	if cfg.Logger != nil && (cfg.LogQueries || cfg.LogSlowQueries > 0) {
-		bunDB.AddQueryHook(hooks.NewLoggerHook(cfg.Logger, cfg.LogQueries, cfg.LogSlowQueries))
+	if cfg.Logger != nil && (cfg.LogQueries || cfg.LogSlowQueries > 0 || cfg.ExplainSlowQueries) {
+		logger := hooks.NewLoggerHook(cfg.Logger, cfg.LogQueries, cfg.LogSlowQueries)
+		logger.LogParameters = cfg.LogParameters
+		logger.SensitiveParamIndexes = cfg.LogSensitiveParams
+		logger.MaxParamLength = cfg.LogMaxParamLength
+		if cfg.ExplainSlowQueries {
+			logger.WithExplain(cfg.ExplainThreshold, explainQuery(bunDB.DB))
+		}
+		var hook bun.QueryHook = logger
+		if cfg.LogSampleRate > 0 && cfg.LogSampleRate < 1 {
+			sampling := hooks.NewSamplingHook(cfg.LogSampleRate, hook)
+			sampling.AlwaysSampleErrors = true
+			hook = sampling
+		}
+		bunDB.AddQueryHook(hook)
 	}
 	if cfg.MetricsRegistry != nil {
-		hook, err := hooks.NewMetricsHook(cfg.MetricsRegistry)
+		var metricsOpts hooks.MetricsHookOptions
+		if cfg.MetricsOptions != nil {
+			metricsOpts = *cfg.MetricsOptions
+		}
+		hook, err := hooks.NewMetricsHook(cfg.MetricsRegistry, metricsOpts)
 		if err != nil {
 			return nil, fmt.Errorf("dbkit: failed to create metrics hook: %w", err)
 		}
 		bunDB.AddQueryHook(hook)
+
+		txHook, err := hooks.NewTransactionHook(cfg.MetricsRegistry, cfg.TxMetrics)
+		if err != nil {
+			return nil, fmt.Errorf("dbkit: failed to create transaction metrics hook: %w", err)
+		}
+		bunDB.AddQueryHook(txHook)
+
+		waitHook, err := hooks.NewPoolWaitHook(cfg.MetricsRegistry, cfg.PoolWait)
+		if err != nil {
+			return nil, fmt.Errorf("dbkit: failed to create pool wait hook: %w", err)
+		}
+		bunDB.AddQueryHook(waitHook)
 	}
 	if cfg.Tracer != nil {
-		bunDB.AddQueryHook(hooks.NewTracingHook(cfg.Tracer))
+		bunDB.AddQueryHook(hooks.NewTracingHook(cfg.Tracer, cfg.TracingOptions))
+	}
+	if cfg.DetectN1 {
+		db.n1 = hooks.NewN1DetectorHook(cfg.N1Threshold, n1Handler(cfg))
+		if cfg.N1Window > 0 {
+			db.n1.WindowMs = int(cfg.N1Window.Milliseconds())
+		}
+		bunDB.AddQueryHook(db.n1)
 	}
 
-	// Verify connection
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
-	defer cancel()
+	return db, nil
+}
 
-	if err := bunDB.PingContext(ctx); err != nil {
-		return nil, &Error{
-			Code:    CodeConnectionFailed,
-			Message: "failed to connect to database",
-			Op:      "New",
-			Cause:   err,
+// explainQuery returns an ExplainFunc that runs a plain EXPLAIN (without ANALYZE, so the query
+// is never executed again) on its own pooled connection. It bypasses bun hooks, gives up when
+// the pool has no spare connection and bounds lock waits, so it cannot block on locks held
+// by the caller's transaction.
+func explainQuery(sqlDB *sql.DB) hooks.ExplainFunc {
+	return func(ctx context.Context, query string) (json.RawMessage, error) {
+		if stats := sqlDB.Stats(); stats.MaxOpenConnections > 0 && stats.Idle == 0 && stats.InUse >= stats.MaxOpenConnections {
+			return nil, errors.New("dbkit: no idle connection to explain the query")
+		}
+
+		tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		timeout := hooks.DefaultExplainTimeout.Milliseconds()
+		for _, setting := range []string{"lock_timeout", "statement_timeout"} {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL %s = %d", setting, timeout)); err != nil {
+				return nil, err
+			}
 		}
+
+		var plan []byte
+		if err := tx.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query).Scan(&plan); err != nil {
+			return nil, err
+		}
+		return json.RawMessage(plan), nil
 	}
+}
 
-	return db, nil
+// FlushN1 restarts N+1 detection, so repetitions are counted from zero again. Call it at request
+// boundaries, e.g. from a middleware. It does nothing unless Config.DetectN1 is set.
+//
+// Usage:
+//
+//	defer db.FlushN1()
+func (db *DBKit) FlushN1() {
+	db.n1.Flush()
+}
+
+// n1Handler returns the configured N+1 handler, or one that logs a warning.
+func n1Handler(cfg Config) func(pattern string, count int) {
+	if cfg.N1Handler != nil {
+		return cfg.N1Handler
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(pattern string, count int) {
+		logger.Warn("possible N+1 query", slog.String("pattern", pattern), slog.Int("count", count))
+	}
 }
 
 // Close closes the database connection
 func (db *DBKit) Close() error {
+	db.stopRDS()
 	return db.DB.Close()
 }
 
@@ -1,8 +1,12 @@
 package dbkit
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -128,6 +132,8 @@ func TestWrapPgError(t *testing.T) {
 		{"40P01", CodeDeadlock},
 		{"57014", CodeTimeout},
 		{"08000", CodeConnectionFailed},
+		{"23P01", CodeExclusion},
+		{"P0001", CodeRaisedException},
 		{"99999", CodeUnknown},
 	}
 
@@ -176,6 +182,45 @@ func TestIsDuplicate(t *testing.T) {
 	}
 }
 
+func TestIsExclusion_IsRaisedException(t *testing.T) {
+	exclusion := wrapError(&pgconn.PgError{Code: "23P01", ConstraintName: "bookings_no_overlap"}, "Create")
+	if !IsExclusion(exclusion) || IsRaisedException(exclusion) {
+		t.Errorf("Expected only IsExclusion for 23P01, got %v", exclusion)
+	}
+
+	raised := wrapError(&pgconn.PgError{Code: "P0001", Message: "insufficient balance"}, "Transfer")
+	if !IsRaisedException(raised) || IsExclusion(raised) {
+		t.Errorf("Expected only IsRaisedException for P0001, got %v", raised)
+	}
+	if !strings.Contains(raised.Error(), "insufficient balance") {
+		t.Errorf("Expected the raised message to be kept, got %q", raised.Error())
+	}
+}
+
+func TestIsDataException(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"22012", true}, // division_by_zero
+		{"22P02", true}, // invalid_text_representation
+		{"22003", true}, // numeric_value_out_of_range
+		{"23505", false},
+		{"P0001", false},
+	}
+
+	for _, tt := range tests {
+		err := wrapError(&pgconn.PgError{Code: tt.code}, "Query")
+		if got := IsDataException(err); got != tt.want {
+			t.Errorf("IsDataException(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	if IsDataException(errors.New("plain")) || IsDataException(nil) {
+		t.Error("Expected non-PostgreSQL errors not to be data exceptions")
+	}
+}
+
 func TestIsRetryable(t *testing.T) {
 	tests := []struct {
 		code     ErrorCode
@@ -320,3 +365,161 @@ func TestReadOnlyTxOptions(t *testing.T) {
 		t.Error("should be read-only")
 	}
 }
+
+func TestConfig_Validate(t *testing.T) {
+	if errs := DefaultConfig("postgres://localhost/app").Validate(); len(errs) != 0 {
+		t.Errorf("DefaultConfig should be valid, got %v", errs)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		field   string
+		warning bool
+	}{
+		{"missing URL", func(c *Config) { c.URL = "" }, "URL", false},
+		{"unparseable URL", func(c *Config) { c.URL = "localhost:5432" }, "URL", false},
+		{"no open conns", func(c *Config) { c.MaxOpenConns = 0; c.MaxIdleConns = 0 }, "MaxOpenConns", false},
+		{"idle exceeds open", func(c *Config) { c.MaxIdleConns = 50 }, "MaxIdleConns", false},
+		{"zero dial timeout", func(c *Config) { c.DialTimeout = 0 }, "DialTimeout", false},
+		{"zero read timeout", func(c *Config) { c.ReadTimeout = 0 }, "ReadTimeout", false},
+		{"negative write timeout", func(c *Config) { c.WriteTimeout = -1 }, "WriteTimeout", false},
+		{"lifetime not above idle time", func(c *Config) { c.ConnMaxLifetime = c.ConnMaxIdleTime }, "ConnMaxLifetime", false},
+		{"many open conns", func(c *Config) { c.MaxOpenConns = 200 }, "MaxOpenConns", true},
+		{"high slow query threshold", func(c *Config) { c.LogSlowQueries = 5 * time.Second }, "LogSlowQueries", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("postgres://localhost/app")
+			tt.modify(&cfg)
+
+			errs := cfg.Validate()
+			if len(errs) != 1 {
+				t.Fatalf("Expected exactly one problem, got %v", errs)
+			}
+			if errs[0].Field != tt.field || errs[0].Warning != tt.warning {
+				t.Errorf("Expected %s (warning=%v), got %+v", tt.field, tt.warning, errs[0])
+			}
+
+			if err := validationError(errs); (err == nil) != tt.warning {
+				t.Errorf("Expected validationError to ignore only warnings, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	cfg := DefaultConfig("")
+	cfg.MaxIdleConns = 100
+
+	_, err := New(cfg)
+
+	var verr *ConfigValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected ConfigValidationError, got %v", err)
+	}
+	if !IsValidation(err) || IsConnection(err) {
+		t.Errorf("Expected a validation error, not a connection error, got %v", err)
+	}
+	if len(verr.Errors) != 2 {
+		t.Errorf("Expected URL and MaxIdleConns errors, got %v", verr.Errors)
+	}
+	if !strings.Contains(err.Error(), "URL") || !strings.Contains(err.Error(), "MaxIdleConns") {
+		t.Errorf("Expected a joined error message, got %q", err.Error())
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want int
+	}{
+		{CodeNotFound, 404},
+		{CodeDuplicate, 409},
+		{CodeForeignKey, 409},
+		{CodeCheckViolation, 422},
+		{CodeNotNullViolation, 422},
+		{CodeTimeout, 503},
+		{CodeConnectionFailed, 503},
+		{CodeConflict, 409},
+		{CodeExclusion, 409},
+		{CodeRaisedException, 500},
+		{CodeUnknown, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := fmt.Errorf("handler: %w", &Error{Code: tt.code, Message: "test"})
+			if got := HTTPStatus(err); got != tt.want {
+				t.Errorf("HTTPStatus(%s) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+
+	if got := HTTPStatus(errors.New("plain")); got != 500 {
+		t.Errorf("Expected 500 for plain errors, got %d", got)
+	}
+	if got := HTTPStatus(nil); got != 200 {
+		t.Errorf("Expected 200 for nil, got %d", got)
+	}
+}
+
+func TestError_MarshalJSON(t *testing.T) {
+	err := &Error{
+		Code:       CodeDuplicate,
+		Message:    "duplicate key value violates unique constraint",
+		Op:         "Create",
+		Table:      "users",
+		Column:     "email",
+		Constraint: "users_email_key",
+		Detail:     "Key (email)=(a@b.c) already exists.",
+		Hint:       "Use another email",
+		Query:      "INSERT INTO users ...",
+		Cause:      errors.New("secret internals"),
+	}
+
+	status, body := ErrorResponse(err)
+	if status != 409 {
+		t.Errorf("Expected 409, got %d", status)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	for _, key := range []string{"code", "message", "column", "constraint"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Expected field %q in %s", key, body)
+		}
+	}
+	for _, key := range []string{"op", "table", "detail", "hint", "cause", "query", "Cause", "Query"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("Field %q must be omitted: %s", key, body)
+		}
+	}
+	if fields["message"] != "duplicate value" {
+		t.Errorf("Expected the fixed message of the code, got %v", fields["message"])
+	}
+	for _, secret := range []string{"secret internals", "a@b.c", "violates unique constraint"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("%q leaked into JSON: %s", secret, body)
+		}
+	}
+
+	// Raw driver messages of unknown errors are not sent either
+	_, body = ErrorResponse(&Error{Code: CodeUnknown, Message: `relation "secret_table" does not exist`})
+	if string(body) != `{"code":"UNKNOWN","message":"internal error"}` {
+		t.Errorf("Unexpected body %s", body)
+	}
+}
+
+func TestErrorResponse_PlainError(t *testing.T) {
+	status, body := ErrorResponse(errors.New("pq: password authentication failed"))
+	if status != 500 {
+		t.Errorf("Expected 500, got %d", status)
+	}
+	if string(body) != `{"code":"UNKNOWN","message":"internal error"}` {
+		t.Errorf("Unexpected body %s", body)
+	}
+}
@@ -0,0 +1,146 @@
+package dbkitmock
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+)
+
+// connector is a database/sql connector that routes every statement to a MockDB.
+type connector struct {
+	mock *MockDB
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &conn{mock: c.mock}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return mockDriver{}
+}
+
+// mockDriver exists to satisfy driver.Connector; connections are only opened through connector.
+type mockDriver struct{}
+
+func (mockDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("dbkitmock: use dbkitmock.New")
+}
+
+// conn is a fake connection. Transactions are accepted and ignored.
+type conn struct {
+	mock *MockDB
+}
+
+var (
+	_ driver.QueryerContext = (*conn)(nil)
+	_ driver.ExecerContext  = (*conn)(nil)
+	_ driver.ConnBeginTx    = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("dbkitmock: prepared statements are not supported")
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) { return tx{}, nil }
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.mock.query(query, namedValues(args))
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.mock.exec(query, namedValues(args))
+}
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type result struct {
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return 0, errors.New("dbkitmock: LastInsertId is not supported")
+}
+func (r result) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+// rows is an in-memory driver.Rows.
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func namedValues(args []driver.NamedValue) []any {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// driverValue converts a Go value to a value database/sql can hand to Bun's scanners.
+func driverValue(v reflect.Value) (driver.Value, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, nil
+		}
+		return valuer.Value()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return driverValue(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value, nil
+	case []byte:
+		return value, nil
+	}
+
+	if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(v.Interface())
+}
@@ -0,0 +1,154 @@
+package dbkitmock
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/fernandezvara/dbkit"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// MemoryDB is an in-memory record store for unit tests.
+// Records are grouped by table name and queried with Go predicates; no SQL is involved.
+// MemoryDB is safe for concurrent use.
+//
+// Usage:
+//
+//	mem := dbkitmock.NewMemoryDB()
+//	mem.Insert(User{ID: "1", Name: "Alice", Active: true})
+//
+//	active := dbkitmock.FindAll(mem, func(u User) bool { return u.Active })
+type MemoryDB struct {
+	mu      sync.RWMutex
+	tables  *schema.Tables
+	records map[string][]any
+}
+
+// NewMemoryDB creates an empty MemoryDB.
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{
+		tables:  pgdialect.New().Tables(),
+		records: make(map[string][]any),
+	}
+}
+
+// TableName returns the table name Bun uses for model, which may be a struct or a pointer to one.
+func (m *MemoryDB) TableName(model any) string {
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return m.tables.Get(typ).Name
+}
+
+// Insert stores records under their model's table name.
+// Records are stored by value; pointers are dereferenced.
+func (m *MemoryDB) Insert(records ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, record := range records {
+		v := reflect.ValueOf(record)
+		for v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		table := m.TableName(v.Interface())
+		m.records[table] = append(m.records[table], v.Interface())
+	}
+}
+
+// Records returns a copy of all records stored under tableName.
+func (m *MemoryDB) Records(tableName string) []any {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]any(nil), m.records[tableName]...)
+}
+
+// Len returns the number of records stored under tableName.
+func (m *MemoryDB) Len(tableName string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.records[tableName])
+}
+
+// Reset removes all records.
+func (m *MemoryDB) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = make(map[string][]any)
+}
+
+// FindAll returns all records of type T matching filter. A nil filter matches everything.
+func FindAll[T any](m *MemoryDB, filter func(T) bool) []T {
+	var zero T
+	table := m.TableName(zero)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var items []T
+	for _, record := range m.records[table] {
+		item, ok := record.(T)
+		if ok && (filter == nil || filter(item)) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// FindOne returns the first record of type T matching filter, or dbkit.ErrNotFound.
+func FindOne[T any](m *MemoryDB, filter func(T) bool) (*T, error) {
+	items := FindAll(m, filter)
+	if len(items) == 0 {
+		return nil, dbkit.ErrNotFound
+	}
+	return &items[0], nil
+}
+
+// Count returns the number of records of type T matching filter.
+func Count[T any](m *MemoryDB, filter func(T) bool) int {
+	return len(FindAll(m, filter))
+}
+
+// Update applies fn to every record of type T matching filter and returns the number updated.
+func Update[T any](m *MemoryDB, filter func(T) bool, fn func(*T)) int {
+	var zero T
+	table := m.TableName(zero)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updated := 0
+	for i, record := range m.records[table] {
+		item, ok := record.(T)
+		if ok && (filter == nil || filter(item)) {
+			fn(&item)
+			m.records[table][i] = item
+			updated++
+		}
+	}
+	return updated
+}
+
+// Delete removes every record of type T matching filter and returns the number removed.
+func Delete[T any](m *MemoryDB, filter func(T) bool) int {
+	var zero T
+	table := m.TableName(zero)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.records[table][:0]
+	removed := 0
+	for _, record := range m.records[table] {
+		item, ok := record.(T)
+		if ok && (filter == nil || filter(item)) {
+			removed++
+			continue
+		}
+		kept = append(kept, record)
+	}
+	m.records[table] = kept
+	return removed
+}
@@ -0,0 +1,74 @@
+package dbkitmock
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+func TestMemoryDB(t *testing.T) {
+	mem := NewMemoryDB()
+	mem.Insert(
+		testUser{ID: "1", Name: "Alice", Active: true},
+		&testUser{ID: "2", Name: "Bob"},
+		testUser{ID: "3", Name: "Carol", Active: true},
+	)
+
+	if mem.TableName(testUser{}) != "users" {
+		t.Errorf("Expected table users, got %s", mem.TableName(testUser{}))
+	}
+	if mem.Len("users") != 3 {
+		t.Errorf("Expected 3 records, got %d", mem.Len("users"))
+	}
+
+	active := FindAll(mem, func(u testUser) bool { return u.Active })
+	if len(active) != 2 {
+		t.Errorf("Expected 2 active users, got %d", len(active))
+	}
+
+	bob, err := FindOne(mem, func(u testUser) bool { return u.ID == "2" })
+	if err != nil || bob.Name != "Bob" {
+		t.Errorf("Expected Bob, got %+v (%v)", bob, err)
+	}
+
+	if _, err := FindOne(mem, func(u testUser) bool { return u.ID == "9" }); !dbkit.IsNotFound(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	updated := Update(mem, func(u testUser) bool { return !u.Active }, func(u *testUser) { u.Active = true })
+	if updated != 1 || Count(mem, func(u testUser) bool { return u.Active }) != 3 {
+		t.Errorf("Expected all users active after update")
+	}
+
+	if removed := Delete(mem, func(u testUser) bool { return u.ID == "1" }); removed != 1 {
+		t.Errorf("Expected 1 removed, got %d", removed)
+	}
+	if Count[testUser](mem, nil) != 2 {
+		t.Errorf("Expected 2 users after delete, got %d", Count[testUser](mem, nil))
+	}
+
+	mem.Reset()
+	if mem.Len("users") != 0 {
+		t.Error("Expected empty store after reset")
+	}
+}
+
+func TestMemoryDB_Concurrent(t *testing.T) {
+	mem := NewMemoryDB()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mem.Insert(testUser{Name: "user"})
+			_ = FindAll[testUser](mem, nil)
+		}()
+	}
+	wg.Wait()
+
+	if mem.Len("users") != 50 {
+		t.Errorf("Expected 50 records, got %d", mem.Len("users"))
+	}
+}
@@ -0,0 +1,280 @@
+// Package dbkitmock provides fake dbkit.IDB implementations for unit tests
+package dbkitmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit"
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// Operations recognized by MockDB
+const (
+	OpSelect = "select"
+	OpInsert = "insert"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// tablePattern extracts the first table referenced by a statement.
+var tablePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE)\s+"?([\w.]+)"?`)
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Call records a statement executed against a MockDB.
+type Call struct {
+	Op    string
+	Table string
+	Query string
+	Args  []any
+}
+
+// expectation is a registered handler.
+type expectation struct {
+	op     string
+	table  string
+	result any
+	err    error
+	calls  int
+}
+
+// MockDB is a dbkit.IDB whose queries are answered by registered handlers instead of PostgreSQL.
+// Queries are built by Bun as usual; MockDB matches the resulting statement by operation and table.
+// MockDB is safe for concurrent use.
+//
+// Usage:
+//
+//	mock := dbkitmock.New()
+//	mock.OnSelect("users", User{ID: "1", Name: "Alice"}, nil)
+//
+//	svc := NewUserService(mock)
+//	user, err := svc.Get(ctx, "1")
+//
+//	mock.AssertExpectations(t)
+type MockDB struct {
+	*bun.DB
+
+	mu           sync.Mutex
+	expectations []*expectation
+	calls        []Call
+}
+
+// Ensure MockDB implements IDB
+var _ dbkit.IDB = (*MockDB)(nil)
+
+// New creates a MockDB using the PostgreSQL dialect.
+func New() *MockDB {
+	m := &MockDB{}
+	m.DB = bun.NewDB(sql.OpenDB(&connector{mock: m}), pgdialect.New())
+	return m
+}
+
+// OnSelect registers the result returned by SELECT queries on tableName.
+// result may be a struct, a pointer to a struct, a slice of structs, a scalar (e.g. for Count)
+// or nil for no rows. A non-nil err is returned instead of the result.
+func (m *MockDB) OnSelect(tableName string, result any, err error) *MockDB {
+	return m.on(OpSelect, tableName, result, err)
+}
+
+// OnInsert registers the result of INSERT statements on tableName.
+// result may be the number of rows affected, the rows returned by RETURNING, or nil (one row affected).
+func (m *MockDB) OnInsert(tableName string, result any, err error) *MockDB {
+	return m.on(OpInsert, tableName, result, err)
+}
+
+// OnUpdate registers the result of UPDATE statements on tableName. See OnInsert.
+func (m *MockDB) OnUpdate(tableName string, result any, err error) *MockDB {
+	return m.on(OpUpdate, tableName, result, err)
+}
+
+// OnDelete registers the result of DELETE statements on tableName. See OnInsert.
+func (m *MockDB) OnDelete(tableName string, result any, err error) *MockDB {
+	return m.on(OpDelete, tableName, result, err)
+}
+
+func (m *MockDB) on(op, table string, result any, err error) *MockDB {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = append(m.expectations, &expectation{op: op, table: table, result: result, err: err})
+	return m
+}
+
+// Calls returns all statements executed so far.
+func (m *MockDB) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns the number of statements executed for op on tableName.
+func (m *MockDB) CallCount(op, tableName string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, c := range m.calls {
+		if c.Op == op && c.Table == tableName {
+			count++
+		}
+	}
+	return count
+}
+
+// AssertExpectations fails the test if any registered handler was never called.
+func (m *MockDB) AssertExpectations(t *testing.T) {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.expectations {
+		if e.calls == 0 {
+			t.Errorf("dbkitmock: expected %s on %q was not called", strings.ToUpper(e.op), e.table)
+		}
+	}
+}
+
+// match records the call and returns the handler registered for the statement.
+// The most recently registered matching handler wins.
+func (m *MockDB) match(query string, args []any) (*expectation, Call, error) {
+	call := Call{Op: hooks.OperationType(query), Query: query, Args: args}
+	if sub := tablePattern.FindStringSubmatch(query); sub != nil {
+		call.Table = sub[1]
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, call)
+
+	for i := len(m.expectations) - 1; i >= 0; i-- {
+		e := m.expectations[i]
+		if e.op == call.Op && e.table == call.Table {
+			e.calls++
+			return e, call, nil
+		}
+	}
+
+	switch call.Op {
+	case "begin", "commit", "rollback", "savepoint", "release":
+		return nil, call, nil
+	}
+	return nil, call, fmt.Errorf("dbkitmock: unexpected %s on %q: %s", strings.ToUpper(call.Op), call.Table, query)
+}
+
+func (m *MockDB) query(query string, args []any) (driver.Rows, error) {
+	e, call, err := m.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return &rows{}, nil
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if call.Op != OpSelect {
+		if n, ok := rowsAffected(e.result); ok {
+			// RETURNING without configured rows: return n empty rows.
+			return &rows{values: make([][]driver.Value, n)}, nil
+		}
+	}
+	return m.toRows(e.result)
+}
+
+func (m *MockDB) exec(query string, args []any) (driver.Result, error) {
+	e, _, err := m.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return result{}, nil
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if n, ok := rowsAffected(e.result); ok {
+		return result{rowsAffected: n}, nil
+	}
+	r, err := m.toRows(e.result)
+	if err != nil {
+		return nil, err
+	}
+	return result{rowsAffected: int64(len(r.values))}, nil
+}
+
+// rowsAffected interprets nil and integer results as a number of affected rows.
+func rowsAffected(res any) (int64, bool) {
+	if res == nil {
+		return 1, true
+	}
+	v := reflect.ValueOf(res)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	}
+	return 0, false
+}
+
+// toRows converts a handler result into driver rows.
+func (m *MockDB) toRows(res any) (*rows, error) {
+	if res == nil {
+		return &rows{}, nil
+	}
+
+	v := reflect.ValueOf(res)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return &rows{}, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		r := &rows{}
+		for i := 0; i < v.Len(); i++ {
+			row, err := m.toRows(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			r.columns = row.columns
+			r.values = append(r.values, row.values...)
+		}
+		return r, nil
+	}
+
+	if v.Kind() == reflect.Struct && v.Type() != timeType {
+		table := m.Table(v.Type())
+		r := &rows{columns: make([]string, len(table.Fields))}
+		row := make([]driver.Value, len(table.Fields))
+		for i, field := range table.Fields {
+			r.columns[i] = field.Name
+			value, err := driverValue(field.Value(v))
+			if err != nil {
+				return nil, fmt.Errorf("dbkitmock: %s.%s: %w", table.TypeName, field.GoName, err)
+			}
+			row[i] = value
+		}
+		r.values = [][]driver.Value{row}
+		return r, nil
+	}
+
+	value, err := driverValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{columns: []string{"value"}, values: [][]driver.Value{{value}}}, nil
+}
@@ -0,0 +1,179 @@
+package dbkitmock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit"
+	"github.com/fernandezvara/dbkit/repo"
+	"github.com/uptrace/bun"
+)
+
+type testUser struct {
+	bun.BaseModel `bun:"table:users,alias:u"`
+	ID            string            `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Name          string            `bun:"name,notnull"`
+	Age           int               `bun:"age"`
+	Active        bool              `bun:"active,notnull"`
+	Settings      map[string]string `bun:"settings,type:jsonb"`
+	CreatedAt     time.Time         `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func TestMockDB_FindByID(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mock := New()
+	mock.OnSelect("users", testUser{
+		ID:        "11111111-1111-1111-1111-111111111111",
+		Name:      "Alice",
+		Age:       30,
+		Active:    true,
+		Settings:  map[string]string{"theme": "dark"},
+		CreatedAt: created,
+	}, nil)
+
+	users := repo.NewDBRepository[testUser, string](mock)
+	user, err := users.FindByID(ctx, "11111111-1111-1111-1111-111111111111")
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+
+	if user.Name != "Alice" || user.Age != 30 || !user.Active {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	if user.Settings["theme"] != "dark" {
+		t.Errorf("Expected settings to be decoded, got %v", user.Settings)
+	}
+	if !user.CreatedAt.Equal(created) {
+		t.Errorf("Expected created_at %v, got %v", created, user.CreatedAt)
+	}
+
+	if mock.CallCount(OpSelect, "users") != 1 {
+		t.Errorf("Expected 1 select call, got %d", mock.CallCount(OpSelect, "users"))
+	}
+	mock.AssertExpectations(t)
+}
+
+func TestMockDB_SliceAndCount(t *testing.T) {
+	ctx := context.Background()
+
+	mock := New()
+	mock.OnSelect("users", []testUser{{Name: "A"}, {Name: "B"}}, nil)
+
+	var users []testUser
+	if err := mock.NewSelect().Model(&users).Where("active = ?", true).Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(users) != 2 || users[1].Name != "B" {
+		t.Errorf("Expected 2 users, got %+v", users)
+	}
+
+	// Later registrations take precedence
+	mock.OnSelect("users", 42, nil)
+	count, err := dbkit.Count[testUser](ctx, mock, nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected count 42, got %d", count)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[0].Query == "" {
+		t.Errorf("Expected 2 recorded calls with queries, got %+v", calls)
+	}
+}
+
+func TestMockDB_Errors(t *testing.T) {
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+
+	mock := New()
+	mock.OnUpdate("users", nil, errBoom)
+
+	_, err := mock.NewUpdate().Model(&testUser{ID: "1"}).WherePK().Exec(ctx)
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Expected boom error, got %v", err)
+	}
+
+	_, err = mock.NewDelete().Model(&testUser{ID: "1"}).WherePK().Exec(ctx)
+	if err == nil {
+		t.Error("Expected error for unexpected delete")
+	}
+
+	var user testUser
+	err = mock.NewSelect().Model(&user).Scan(ctx)
+	if err == nil {
+		t.Error("Expected error for unexpected select")
+	}
+}
+
+func TestMockDB_InsertAndTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	mock := New()
+	mock.OnInsert("users", nil, nil)
+
+	users := repo.NewDBRepository[testUser, string](mock)
+	err := users.WithTransaction(ctx, func(tx repo.Repository[testUser, string]) error {
+		return tx.Create(ctx, &testUser{Name: "Bob"})
+	})
+	if err != nil {
+		t.Fatalf("Create in transaction failed: %v", err)
+	}
+
+	result, err := mock.NewDelete().Model((*testUser)(nil)).Where("1=1").Exec(ctx)
+	if err == nil {
+		t.Fatalf("Expected unexpected delete error, got result %v", result)
+	}
+
+	mock.OnDelete("users", 3, nil)
+	result, err = mock.NewDelete().Model((*testUser)(nil)).Where("active = ?", false).Exec(ctx)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if rows, _ := result.RowsAffected(); rows != 3 {
+		t.Errorf("Expected 3 rows affected, got %d", rows)
+	}
+
+	mock.AssertExpectations(t)
+}
+
+func TestMockDB_AssertExpectations(t *testing.T) {
+	mock := New()
+	mock.OnSelect("users", nil, nil)
+
+	inner := &testing.T{}
+	mock.AssertExpectations(inner)
+	if !inner.Failed() {
+		t.Error("Expected AssertExpectations to fail for uncalled handler")
+	}
+}
+
+func TestMockDB_Concurrent(t *testing.T) {
+	ctx := context.Background()
+
+	mock := New()
+	mock.OnSelect("users", testUser{Name: "Alice"}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var user testUser
+			if err := mock.NewSelect().Model(&user).Scan(ctx); err != nil {
+				t.Errorf("Select failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if mock.CallCount(OpSelect, "users") != 20 {
+		t.Errorf("Expected 20 calls, got %d", mock.CallCount(OpSelect, "users"))
+	}
+}
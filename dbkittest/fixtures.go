@@ -0,0 +1,198 @@
+package dbkittest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fernandezvara/dbkit"
+	"github.com/uptrace/bun"
+	"go.yaml.in/yaml/v2"
+)
+
+// refPattern matches "$ref: table.column" and "$ref: table[index].column".
+var refPattern = regexp.MustCompile(`^\$ref:\s*(\w+)(?:\[(\d+)\])?\.(\w+)$`)
+
+// fixtureSet tracks inserted rows so later fixtures can reference them.
+type fixtureSet struct {
+	rows map[string][]map[string]any
+}
+
+// LoadFixtures loads every .yml, .yaml and .json file in fixturesDir into the database.
+// Each file holds a list of rows (column to value maps) for the table named after the file.
+// Files are loaded in alphabetical order, so referenced tables must sort before the tables
+// that reference them. All inserts run in a single transaction that is rolled back on error.
+//
+// A string value of the form "$ref: users.id" is replaced with the id of the first row
+// loaded into users; "$ref: users[2].id" selects the third row.
+//
+// Usage:
+//
+//	if err := dbkittest.LoadFixtures(ctx, db, "testdata/fixtures"); err != nil {
+//	    t.Fatal(err)
+//	}
+func LoadFixtures(ctx context.Context, db dbkit.IDB, fixturesDir string) error {
+	entries, err := os.ReadDir(fixturesDir)
+	if err != nil {
+		return fmt.Errorf("dbkittest: failed to read fixtures dir: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yml", ".yaml", ".json":
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		set := &fixtureSet{rows: make(map[string][]map[string]any)}
+		for _, name := range files {
+			data, err := readFixtureFile(filepath.Join(fixturesDir, name))
+			if err != nil {
+				return err
+			}
+			table := strings.TrimSuffix(name, filepath.Ext(name))
+			if err := set.load(ctx, tx, table, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LoadFixture inserts rows into tableName in a single transaction.
+// "$ref" values may reference rows inserted earlier in the same call.
+//
+// Usage:
+//
+//	err := dbkittest.LoadFixture(ctx, db, "users", []map[string]any{
+//	    {"name": "Alice", "email": "alice@example.com"},
+//	})
+func LoadFixture(ctx context.Context, db dbkit.IDB, tableName string, data []map[string]any) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		set := &fixtureSet{rows: make(map[string][]map[string]any)}
+		return set.load(ctx, tx, tableName, data)
+	})
+}
+
+// load inserts rows into table, resolving references and recording the returned rows.
+func (s *fixtureSet) load(ctx context.Context, tx bun.Tx, table string, data []map[string]any) error {
+	for i, row := range data {
+		columns := make([]string, 0, len(row))
+		for col := range row {
+			columns = append(columns, col)
+		}
+		sort.Strings(columns)
+
+		idents := make([]any, len(columns))
+		values := make([]any, len(columns))
+		for j, col := range columns {
+			value, err := s.resolve(row[col])
+			if err != nil {
+				return fmt.Errorf("dbkittest: %s[%d].%s: %w", table, i, col, err)
+			}
+			idents[j] = bun.Ident(col)
+			values[j] = value
+		}
+
+		inserted := make(map[string]any)
+		err := tx.NewRaw("INSERT INTO ? (?) VALUES (?) RETURNING *",
+			bun.Ident(table), bun.In(idents), bun.In(values)).Scan(ctx, &inserted)
+		if err != nil {
+			return dbkit.WrapError(err, "LoadFixture")
+		}
+		s.rows[table] = append(s.rows[table], inserted)
+	}
+	return nil
+}
+
+// resolve replaces "$ref" strings with referenced values and encodes nested values as JSON.
+func (s *fixtureSet) resolve(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.HasPrefix(v, "$ref:") {
+			return v, nil
+		}
+		m := refPattern.FindStringSubmatch(v)
+		if m == nil {
+			return nil, fmt.Errorf("invalid reference %q", v)
+		}
+		index := 0
+		if m[2] != "" {
+			index, _ = strconv.Atoi(m[2])
+		}
+		rows := s.rows[m[1]]
+		if index >= len(rows) {
+			return nil, fmt.Errorf("reference %q: table %s has %d rows loaded", v, m[1], len(rows))
+		}
+		ref, ok := rows[index][m[3]]
+		if !ok {
+			return nil, fmt.Errorf("reference %q: unknown column %s", v, m[3])
+		}
+		return ref, nil
+	case map[any]any, map[string]any, []any:
+		data, err := json.Marshal(normalizeYAML(v))
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return v, nil
+	}
+}
+
+// readFixtureFile decodes a YAML or JSON fixture file.
+func readFixtureFile(path string) ([]map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dbkittest: failed to read fixture: %w", err)
+	}
+
+	var rows []map[string]any
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(content, &rows)
+	} else {
+		err = yaml.Unmarshal(content, &rows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dbkittest: failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return rows, nil
+}
+
+// normalizeYAML converts YAML maps with interface keys into JSON-compatible maps.
+func normalizeYAML(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(val)
+		}
+		return m
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[key] = normalizeYAML(val)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, val := range v {
+			s[i] = normalizeYAML(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,134 @@
+package dbkittest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+func createFixtureTables(t *testing.T, db *dbkit.DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, `
+		DROP TABLE IF EXISTS books;
+		DROP TABLE IF EXISTS authors;
+		CREATE TABLE authors (id bigserial PRIMARY KEY, name text NOT NULL, country text);
+		CREATE TABLE books (
+			id bigserial PRIMARY KEY,
+			title text NOT NULL,
+			author_id bigint NOT NULL REFERENCES authors (id),
+			tags jsonb
+		);`)
+	if err != nil {
+		t.Fatalf("Failed to create fixture tables: %v", err)
+	}
+	return ctx
+}
+
+func TestRefPattern(t *testing.T) {
+	tests := []struct {
+		value string
+		match bool
+	}{
+		{"$ref: users.id", true},
+		{"$ref: users[3].id", true},
+		{"$ref:users.id", true},
+		{"$ref: users", false},
+		{"users.id", false},
+	}
+
+	for _, tt := range tests {
+		if got := refPattern.MatchString(tt.value); got != tt.match {
+			t.Errorf("refPattern.MatchString(%q) = %v, expected %v", tt.value, got, tt.match)
+		}
+	}
+}
+
+func TestFixtureSet_Resolve(t *testing.T) {
+	set := &fixtureSet{rows: map[string][]map[string]any{"users": {{"id": int64(1)}}}}
+
+	if v, err := set.resolve("$ref: users.id"); err != nil || v != int64(1) {
+		t.Errorf("Expected 1, got %v (%v)", v, err)
+	}
+	if _, err := set.resolve("$ref: users[1].id"); err == nil {
+		t.Error("Expected error for out of range reference")
+	}
+	if _, err := set.resolve("$ref: posts.id"); err == nil {
+		t.Error("Expected error for unloaded table")
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createFixtureTables(t, db)
+
+	if err := LoadFixtures(ctx, db, "testdata/fixtures"); err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+
+	var authors, books, solarisByLem int
+	if err := db.NewRaw("SELECT count(*) FROM authors").Scan(ctx, &authors); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if err := db.NewRaw("SELECT count(*) FROM books").Scan(ctx, &books); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	err := db.NewRaw(`SELECT count(*) FROM books b JOIN authors a ON a.id = b.author_id
+		WHERE b.title = 'Solaris' AND a.name = 'Stanislaw Lem'`).Scan(ctx, &solarisByLem)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	if authors != 2 || books != 3 {
+		t.Errorf("Expected 2 authors and 3 books, got %d and %d", authors, books)
+	}
+	if solarisByLem != 1 {
+		t.Error("Expected Solaris to reference Stanislaw Lem")
+	}
+}
+
+func TestLoadFixtures_RollbackOnError(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createFixtureTables(t, db)
+
+	if err := LoadFixtures(ctx, db, "testdata/invalid"); err == nil {
+		t.Fatal("Expected LoadFixtures to fail on NOT NULL violation")
+	}
+
+	var authors int
+	if err := db.NewRaw("SELECT count(*) FROM authors").Scan(ctx, &authors); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if authors != 0 {
+		t.Errorf("Expected rollback to leave 0 authors, got %d", authors)
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createFixtureTables(t, db)
+
+	err := LoadFixture(ctx, db, "authors", []map[string]any{
+		{"name": "Octavia E. Butler", "country": "US"},
+		{"name": "Liu Cixin", "country": "CN"},
+	})
+	if err != nil {
+		t.Fatalf("LoadFixture failed: %v", err)
+	}
+
+	var authors int
+	if err := db.NewRaw("SELECT count(*) FROM authors").Scan(ctx, &authors); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if authors != 2 {
+		t.Errorf("Expected 2 authors, got %d", authors)
+	}
+}
@@ -0,0 +1,68 @@
+// Package dbkittest provides helpers for testing code that uses dbkit
+package dbkittest
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// TestIsolatorOption configures the transaction used by NewTestIsolator
+type TestIsolatorOption func(*dbkit.TxOptions)
+
+// WithIsolationLevel sets the isolation level of the test transaction.
+func WithIsolationLevel(level sql.IsolationLevel) TestIsolatorOption {
+	return func(opts *dbkit.TxOptions) {
+		opts.Isolation = level
+	}
+}
+
+// WithReadOnly makes the test transaction read-only.
+func WithReadOnly() TestIsolatorOption {
+	return func(opts *dbkit.TxOptions) {
+		opts.ReadOnly = true
+	}
+}
+
+// NewTestIsolator begins a transaction and returns it as an IDB together with a cleanup
+// function that rolls it back. Cleanup is also registered with t.Cleanup, so calling it is optional.
+//
+// Everything done through the returned IDB is discarded when the test ends.
+// Isolation only holds if the whole test uses the returned IDB: queries made through db directly,
+// or from parallel sub-tests sharing the transaction, are not isolated.
+//
+// Usage:
+//
+//	func TestCreateUser(t *testing.T) {
+//	    db, _ := dbkittest.NewTestIsolator(t, testDB)
+//	    _, err := db.NewInsert().Model(&user).Exec(ctx)
+//	    // ...
+//	}
+func NewTestIsolator(t *testing.T, db *dbkit.DBKit, opts ...TestIsolatorOption) (dbkit.IDB, func()) {
+	t.Helper()
+
+	txOpts := dbkit.DefaultTxOptions()
+	for _, opt := range opts {
+		opt(&txOpts)
+	}
+
+	tx, err := db.BeginWithOptions(context.Background(), txOpts)
+	if err != nil {
+		t.Fatalf("dbkittest: failed to begin transaction: %v", err)
+	}
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			if err := tx.Rollback(); err != nil {
+				t.Errorf("dbkittest: failed to roll back transaction: %v", err)
+			}
+		})
+	}
+	t.Cleanup(cleanup)
+
+	return tx, cleanup
+}
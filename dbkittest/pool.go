@@ -0,0 +1,165 @@
+package dbkittest
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fernandezvara/dbkit"
+	"github.com/uptrace/bun"
+)
+
+// TestDBPool hands out connections to isolated PostgreSQL schemas for parallel tests
+type TestDBPool struct {
+	admin      *dbkit.DBKit
+	schemas    []*poolSchema
+	available  chan *poolSchema // Semaphore of schemas not in use
+	migrations []dbkit.Migration
+}
+
+// poolSchema is a schema and a connection whose search_path points to it.
+type poolSchema struct {
+	name string
+	db   *dbkit.DBKit
+}
+
+// NewTestDBPool creates size schemas named test_<uuid> in the database at baseURL.
+// Each test gets one schema from Acquire; the schemas are dropped when t finishes.
+//
+// Usage:
+//
+//	func TestUsers(t *testing.T) {
+//	    pool := dbkittest.NewTestDBPool(t, 4, os.Getenv("TEST_DATABASE_URL"))
+//	    if err := pool.MigrateAll(ctx, migrations); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    t.Run("create", func(t *testing.T) {
+//	        t.Parallel()
+//	        db := pool.Acquire(t)
+//	        // ...
+//	    })
+//	}
+func NewTestDBPool(t *testing.T, size int, baseURL string) *TestDBPool {
+	t.Helper()
+
+	if size <= 0 {
+		t.Fatalf("dbkittest: pool size must be positive, got %d", size)
+	}
+
+	admin, err := dbkit.New(poolConfig(baseURL))
+	if err != nil {
+		t.Fatalf("dbkittest: failed to connect: %v", err)
+	}
+
+	pool := &TestDBPool{
+		admin:     admin,
+		available: make(chan *poolSchema, size),
+	}
+	t.Cleanup(pool.close(t))
+
+	ctx := context.Background()
+	for range size {
+		id, err := dbkit.NewUUIDv7()
+		if err != nil {
+			t.Fatalf("dbkittest: %v", err)
+		}
+		name := "test_" + strings.ReplaceAll(id, "-", "")
+
+		if _, err := admin.NewRaw("CREATE SCHEMA ?", bun.Ident(name)).Exec(ctx); err != nil {
+			t.Fatalf("dbkittest: failed to create schema %s: %v", name, err)
+		}
+		schema := &poolSchema{name: name}
+		pool.schemas = append(pool.schemas, schema)
+
+		schemaURL, err := withSearchPath(baseURL, name)
+		if err != nil {
+			t.Fatalf("dbkittest: invalid database URL: %v", err)
+		}
+		if schema.db, err = dbkit.New(poolConfig(schemaURL)); err != nil {
+			t.Fatalf("dbkittest: failed to connect to schema %s: %v", name, err)
+		}
+		pool.available <- schema
+	}
+
+	return pool
+}
+
+// MigrateAll applies migrations to every schema in the pool.
+// Schemas are migrated again when they are reset after a test, so call it before the first Acquire.
+func (p *TestDBPool) MigrateAll(ctx context.Context, migrations []dbkit.Migration) error {
+	for _, schema := range p.schemas {
+		if _, err := schema.db.Migrate(ctx, migrations); err != nil {
+			return err
+		}
+	}
+	p.migrations = migrations
+	return nil
+}
+
+// Acquire returns a connection to a schema used by no other test, blocking until one is free.
+// When t finishes the schema is dropped, recreated with the pool migrations and returned to the pool.
+func (p *TestDBPool) Acquire(t *testing.T) *dbkit.DBKit {
+	t.Helper()
+
+	schema := <-p.available
+	t.Cleanup(func() {
+		if err := p.reset(context.Background(), schema); err != nil {
+			t.Errorf("dbkittest: failed to reset schema %s: %v", schema.name, err)
+		}
+		p.available <- schema
+	})
+	return schema.db
+}
+
+// reset drops and recreates schema, then applies the pool migrations to it.
+func (p *TestDBPool) reset(ctx context.Context, schema *poolSchema) error {
+	if _, err := p.admin.NewRaw("DROP SCHEMA ? CASCADE", bun.Ident(schema.name)).Exec(ctx); err != nil {
+		return err
+	}
+	if _, err := p.admin.NewRaw("CREATE SCHEMA ?", bun.Ident(schema.name)).Exec(ctx); err != nil {
+		return err
+	}
+	if len(p.migrations) > 0 {
+		if _, err := schema.db.Migrate(ctx, p.migrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close returns a cleanup function that drops every schema and closes all connections.
+func (p *TestDBPool) close(t *testing.T) func() {
+	return func() {
+		ctx := context.Background()
+		for _, schema := range p.schemas {
+			if schema.db != nil {
+				_ = schema.db.Close()
+			}
+			if _, err := p.admin.NewRaw("DROP SCHEMA IF EXISTS ? CASCADE", bun.Ident(schema.name)).Exec(ctx); err != nil {
+				t.Errorf("dbkittest: failed to drop schema %s: %v", schema.name, err)
+			}
+		}
+		_ = p.admin.Close()
+	}
+}
+
+// poolConfig returns a configuration with a small connection pool for url.
+func poolConfig(url string) dbkit.Config {
+	cfg := dbkit.DefaultConfig(url)
+	cfg.MaxOpenConns = 2
+	cfg.MaxIdleConns = 1
+	return cfg
+}
+
+// withSearchPath returns baseURL with search_path set to schema.
+func withSearchPath(baseURL, schema string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,178 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DLQEntry is an item stored in the dead letter queue.
+//
+// Create the table with DeadLetterQueue.CreateTable or:
+//
+//	CREATE TABLE dead_letter_queue (
+//	    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+//	    topic VARCHAR(255) NOT NULL,
+//	    payload JSONB NOT NULL,
+//	    error_message TEXT,
+//	    attempts INT NOT NULL DEFAULT 1,
+//	    last_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+//	    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+//	);
+//	CREATE INDEX idx_dead_letter_queue_topic ON dead_letter_queue(topic, created_at);
+type DLQEntry struct {
+	bun.BaseModel `bun:"table:dead_letter_queue,alias:dlq"`
+
+	ID            string          `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Topic         string          `bun:"topic,notnull"`
+	Payload       json.RawMessage `bun:"payload,type:jsonb,notnull"`
+	ErrorMessage  string          `bun:"error_message"`
+	Attempts      int             `bun:"attempts,notnull,default:1"`
+	LastAttemptAt time.Time       `bun:"last_attempt_at,nullzero,notnull,default:current_timestamp"`
+	CreatedAt     time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// Decode unmarshals the payload into v.
+func (e DLQEntry) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// DeadLetterQueue stores items that failed processing so they can be inspected and retried
+type DeadLetterQueue struct {
+	db IDB
+}
+
+// NewDeadLetterQueue creates a dead letter queue stored in the dead_letter_queue table of db.
+//
+// Usage:
+//
+//	dlq := dbkit.NewDeadLetterQueue(db)
+//	if err := dlq.CreateTable(ctx); err != nil {
+//	    return err
+//	}
+func NewDeadLetterQueue(db IDB) *DeadLetterQueue {
+	return &DeadLetterQueue{db: db}
+}
+
+// CreateTable creates the dead_letter_queue table if it does not exist.
+func (dlq *DeadLetterQueue) CreateTable(ctx context.Context) error {
+	if _, err := dlq.db.NewCreateTable().Model((*DLQEntry)(nil)).IfNotExists().Exec(ctx); err != nil {
+		return wrapError(err, "DeadLetterQueue.CreateTable")
+	}
+	_, err := dlq.db.NewCreateIndex().Model((*DLQEntry)(nil)).
+		Index("idx_dead_letter_queue_topic").
+		IfNotExists().
+		Column("topic", "created_at").
+		Exec(ctx)
+	return wrapError(err, "DeadLetterQueue.CreateTable")
+}
+
+// Push stores item as JSON under topic, recording err as the failure reason.
+//
+// Usage:
+//
+//	if err := process(item); err != nil {
+//	    _ = dlq.Push(ctx, "emails", item, err)
+//	}
+func (dlq *DeadLetterQueue) Push(ctx context.Context, topic string, item any, err error) error {
+	payload, jerr := json.Marshal(item)
+	if jerr != nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "failed to encode dead letter payload: " + jerr.Error(),
+			Op:      "DeadLetterQueue.Push",
+			Cause:   jerr,
+		}
+	}
+
+	entry := &DLQEntry{Topic: topic, Payload: payload, Attempts: 1}
+	if err != nil {
+		entry.ErrorMessage = err.Error()
+	}
+	if _, err := dlq.db.NewInsert().Model(entry).Exec(ctx); err != nil {
+		return wrapError(err, "DeadLetterQueue.Push")
+	}
+	return nil
+}
+
+// Pop returns up to limit of the oldest entries of topic, skipping rows locked by other workers.
+// The rows stay locked until the transaction ends, so create the queue on a Tx to hold the claim
+// while processing them.
+//
+// Usage:
+//
+//	err := db.Transaction(ctx, func(tx *dbkit.Tx) error {
+//	    entries, err := dbkit.NewDeadLetterQueue(tx).Pop(ctx, "emails", 10)
+//	    // ...
+//	})
+func (dlq *DeadLetterQueue) Pop(ctx context.Context, topic string, limit int) ([]DLQEntry, error) {
+	var entries []DLQEntry
+	err := dlq.db.NewSelect().
+		Model(&entries).
+		Where("topic = ?", topic).
+		OrderExpr("created_at ASC, id ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	if err != nil {
+		return nil, wrapError(err, "DeadLetterQueue.Pop")
+	}
+	return entries, nil
+}
+
+// Len returns the number of entries stored under topic.
+func (dlq *DeadLetterQueue) Len(ctx context.Context, topic string) (int, error) {
+	count, err := dlq.db.NewSelect().Model((*DLQEntry)(nil)).Where("topic = ?", topic).Count(ctx)
+	if err != nil {
+		return 0, wrapError(err, "DeadLetterQueue.Len")
+	}
+	return count, nil
+}
+
+// Retry locks the entry with the given id and runs fn on it. The entry is removed when fn
+// succeeds; otherwise its attempts are incremented, the error is recorded and fn's error returned.
+// Returns a NotFound error if the entry does not exist or is locked by another worker.
+//
+// Usage:
+//
+//	err := dlq.Retry(ctx, entry.ID, func(e dbkit.DLQEntry) error {
+//	    var email Email
+//	    if err := e.Decode(&email); err != nil {
+//	        return err
+//	    }
+//	    return send(email)
+//	})
+func (dlq *DeadLetterQueue) Retry(ctx context.Context, id string, fn func(DLQEntry) error) error {
+	var fnErr error
+	err := dlq.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var entry DLQEntry
+		err := tx.NewSelect().Model(&entry).Where("id = ?", id).For("UPDATE SKIP LOCKED").Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			return &Error{Code: CodeNotFound, Message: "dead letter entry not found", Op: "DeadLetterQueue.Retry"}
+		}
+		if err != nil {
+			return err
+		}
+
+		if fnErr = fn(entry); fnErr == nil {
+			_, err = tx.NewDelete().Model(&entry).WherePK().Exec(ctx)
+			return err
+		}
+
+		_, err = tx.NewUpdate().Model(&entry).
+			Set("attempts = attempts + 1").
+			Set("error_message = ?", fnErr.Error()).
+			Set("last_attempt_at = NOW()").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return wrapError(err, "DeadLetterQueue.Retry")
+	}
+	return fnErr
+}
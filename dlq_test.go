@@ -0,0 +1,130 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type dlqItem struct {
+	Email string `json:"email"`
+}
+
+func setupDLQ(t *testing.T, db *DBKit) *DeadLetterQueue {
+	t.Helper()
+	ctx := context.Background()
+
+	dlq := NewDeadLetterQueue(db)
+	if err := dlq.CreateTable(ctx); err != nil {
+		t.Fatalf("CreateTable failed: %v", err)
+	}
+	_, _ = db.NewDelete().Model((*DLQEntry)(nil)).Where("1=1").Exec(ctx)
+	return dlq
+}
+
+func TestDeadLetterQueue_PushPopRetry(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	dlq := setupDLQ(t, db)
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if err := dlq.Push(ctx, "emails", dlqItem{Email: email}, errors.New("smtp down")); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	entries, err := dlq.Pop(ctx, "emails", 2)
+	if err != nil {
+		t.Fatalf("Pop failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].ErrorMessage != "smtp down" || entries[0].Attempts != 1 {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+
+	var item dlqItem
+	if err := entries[0].Decode(&item); err != nil || item.Email != "a@example.com" {
+		t.Errorf("Expected the oldest payload, got %+v (%v)", item, err)
+	}
+
+	// Successful retry removes the entry
+	if err := dlq.Retry(ctx, entries[0].ID, func(DLQEntry) error { return nil }); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+
+	// Failed retry keeps the entry and counts the attempt
+	retryErr := errors.New("still down")
+	if err := dlq.Retry(ctx, entries[1].ID, func(DLQEntry) error { return retryErr }); !errors.Is(err, retryErr) {
+		t.Fatalf("Expected retry error, got %v", err)
+	}
+
+	remaining, err := dlq.Pop(ctx, "emails", 10)
+	if err != nil {
+		t.Fatalf("Pop failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("Expected 2 remaining entries, got %d", len(remaining))
+	}
+	popped := 0
+	for _, e := range remaining {
+		if e.ID == entries[0].ID {
+			t.Error("Expected the retried entry to be removed")
+		}
+		if e.ID == entries[1].ID {
+			popped++
+			if e.Attempts != 2 || e.ErrorMessage != "still down" {
+				t.Errorf("Expected attempts and error to be updated, got %+v", e)
+			}
+		}
+	}
+	if popped != 1 {
+		t.Errorf("Expected one popped entry to remain, got %d", popped)
+	}
+
+	if err := dlq.Retry(ctx, entries[0].ID, func(DLQEntry) error { return nil }); !IsNotFound(err) {
+		t.Errorf("Expected NotFound retrying a removed entry, got %v", err)
+	}
+}
+
+func TestDeadLetterQueue_PopSkipsLocked(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	dlq := setupDLQ(t, db)
+
+	for i := 0; i < 3; i++ {
+		if err := dlq.Push(ctx, "jobs", map[string]int{"n": i}, nil); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer tx.Rollback()
+
+	claimed, err := NewDeadLetterQueue(tx).Pop(ctx, "jobs", 2)
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("Expected to claim 2 entries, got %d (%v)", len(claimed), err)
+	}
+
+	others, err := dlq.Pop(ctx, "jobs", 10)
+	if err != nil {
+		t.Fatalf("Pop failed: %v", err)
+	}
+	if len(others) != 1 {
+		t.Errorf("Expected locked entries to be skipped, got %d entries", len(others))
+	}
+}
+
+func TestDeadLetterQueue_PushEncodeError(t *testing.T) {
+	db, _ := newFakeDB(t)
+	err := NewDeadLetterQueue(db).Push(context.Background(), "jobs", make(chan int), nil)
+	if err == nil {
+		t.Error("Expected an error for a payload that cannot be encoded")
+	}
+}
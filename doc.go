@@ -102,5 +102,23 @@ DBKit provides rich error types for PostgreSQL errors:
 	        fmt.Println(dbErr.Detail)     // Key (email)=(test@example.com) already exists
 	    }
 	}
+
+Exclusion constraints are the usual way to forbid overlapping ranges, such as
+two bookings of the same room for intersecting dates:
+
+	CREATE EXTENSION IF NOT EXISTS btree_gist;
+	ALTER TABLE bookings ADD CONSTRAINT bookings_no_overlap
+	    EXCLUDE USING gist (room_id WITH =, during WITH &&);
+
+Violations are reported with IsExclusion. Errors raised from PL/pgSQL with
+RAISE EXCEPTION are reported with IsRaisedException and keep their message,
+and invalid input (22xxx) is reported with IsDataException:
+
+	switch {
+	case dbkit.IsExclusion(err):
+	    return ErrRoomTaken
+	case dbkit.IsRaisedException(err):
+	    return fmt.Errorf("rejected: %w", err)
+	}
 */
 package dbkit
@@ -0,0 +1,371 @@
+package dbkit
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"golang.org/x/crypto/hkdf"
+)
+
+// EncryptedTag is the struct tag that marks a string field for encryption: `bun_enc:"true"`.
+const EncryptedTag = "bun_enc"
+
+// encryptedPrefix marks encrypted values so they can be told apart from plaintext.
+const encryptedPrefix = "enc:v1:"
+
+// encryptionInfo is the HKDF info string used to derive the AES key.
+const encryptionInfo = "dbkit field encryption"
+
+// ErrDecryption is returned when an encrypted value cannot be decrypted.
+var ErrDecryption = errors.New("dbkit: failed to decrypt value")
+
+// fieldCipher encrypts column values with AES-256-GCM.
+// The table and column are bound as associated data, so ciphertext cannot be moved between columns.
+type fieldCipher struct {
+	aead cipher.AEAD
+}
+
+// newFieldCipher derives an AES-256 key from key with HKDF-SHA256.
+func newFieldCipher(key []byte) (*fieldCipher, error) {
+	if len(key) == 0 {
+		return nil, errors.New("dbkit: encryption key is required")
+	}
+
+	derived := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte(encryptionInfo)), derived); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fieldCipher{aead: aead}, nil
+}
+
+func (c *fieldCipher) encrypt(plaintext, ad string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(ad))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *fieldCipher) decrypt(value, ad string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		// Plaintext written before encryption was enabled
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrDecryption
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(ad))
+	if err != nil {
+		return "", ErrDecryption
+	}
+	return string(plaintext), nil
+}
+
+// EncryptedDB wraps an IDB and transparently encrypts fields tagged with `bun_enc:"true"`.
+// Tagged fields must be string or *string. Values are encrypted before insert/update
+// and decrypted after select; the model passed in always holds plaintext afterwards.
+//
+// Usage:
+//
+//	type Customer struct {
+//	    ID  string `bun:"id,pk"`
+//	    SSN string `bun:"ssn" bun_enc:"true"`
+//	}
+//
+//	edb := dbkit.NewEncryptedDB(db, key)
+//	err := edb.Insert(ctx, &customer)
+//	err = edb.Select(ctx, &customers, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Where("id = ?", id)
+//	})
+//
+// EncryptedDB deliberately does not expose the underlying query builders: a
+// NewInsert or NewUpdate issued around it would write plaintext into encrypted
+// columns.
+type EncryptedDB struct {
+	db     IDB
+	cipher *fieldCipher
+	err    error
+}
+
+// NewEncryptedDB creates an EncryptedDB. The AES key is derived from key with HKDF,
+// so key may be any high-entropy secret. An empty key makes every operation fail.
+func NewEncryptedDB(db IDB, key []byte) *EncryptedDB {
+	c, err := newFieldCipher(key)
+	return &EncryptedDB{db: db, cipher: c, err: err}
+}
+
+// Insert encrypts tagged fields and inserts model.
+func (e *EncryptedDB) Insert(ctx context.Context, model any) error {
+	restore, err := e.seal(model)
+	if err != nil {
+		return wrapError(err, "EncryptedDB.Insert")
+	}
+	defer restore()
+
+	if _, err := e.db.NewInsert().Model(model).Exec(ctx); err != nil {
+		return wrapError(err, "EncryptedDB.Insert")
+	}
+	return nil
+}
+
+// Update encrypts tagged fields and updates model by primary key.
+func (e *EncryptedDB) Update(ctx context.Context, model any) error {
+	restore, err := e.seal(model)
+	if err != nil {
+		return wrapError(err, "EncryptedDB.Update")
+	}
+	defer restore()
+
+	if _, err := e.db.NewUpdate().Model(model).WherePK().Exec(ctx); err != nil {
+		return wrapError(err, "EncryptedDB.Update")
+	}
+	return nil
+}
+
+// Select scans matching rows into model (a pointer to a struct or slice) and decrypts tagged fields.
+func (e *EncryptedDB) Select(ctx context.Context, model any, queryFn func(*bun.SelectQuery) *bun.SelectQuery) error {
+	q := e.db.NewSelect().Model(model)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return wrapError(err, "EncryptedDB.Select")
+	}
+	if err := e.Decrypt(model); err != nil {
+		return wrapError(err, "EncryptedDB.Select")
+	}
+	return nil
+}
+
+// Encrypt encrypts tagged fields of model in place. Use it with queries built directly on Bun.
+func (e *EncryptedDB) Encrypt(model any) error {
+	_, err := e.seal(model)
+	return err
+}
+
+// Decrypt decrypts tagged fields of model in place.
+func (e *EncryptedDB) Decrypt(model any) error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.eachEncryptedField(model, func(field reflect.Value, ad string) error {
+		return transformField(field, func(s string) (string, error) {
+			return e.cipher.decrypt(s, ad)
+		})
+	})
+}
+
+// seal encrypts tagged fields in place and returns a function restoring the plaintext.
+func (e *EncryptedDB) seal(model any) (func(), error) {
+	if e.err != nil {
+		return func() {}, e.err
+	}
+
+	type saved struct {
+		field reflect.Value
+		value reflect.Value
+	}
+	var originals []saved
+	restore := func() {
+		for _, s := range originals {
+			s.field.Set(s.value)
+		}
+	}
+
+	err := e.eachEncryptedField(model, func(field reflect.Value, ad string) error {
+		original := reflect.New(field.Type()).Elem()
+		original.Set(field)
+		originals = append(originals, saved{field: field, value: original})
+
+		return transformField(field, func(s string) (string, error) {
+			return e.cipher.encrypt(s, ad)
+		})
+	})
+	if err != nil {
+		restore()
+		return func() {}, err
+	}
+	return restore, nil
+}
+
+// eachEncryptedField calls fn for every tagged field of a struct or slice model.
+func (e *EncryptedDB) eachEncryptedField(model any, fn func(field reflect.Value, ad string) error) error {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("dbkit: model must be a non-nil pointer, got %T", model)
+	}
+	v = v.Elem()
+
+	var structs []reflect.Value
+	switch v.Kind() {
+	case reflect.Struct:
+		structs = append(structs, v)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Pointer {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+			structs = append(structs, elem)
+		}
+	default:
+		return fmt.Errorf("dbkit: unsupported model type %T", model)
+	}
+
+	for _, strct := range structs {
+		table := e.db.Dialect().Tables().Get(strct.Type())
+		for _, field := range table.Fields {
+			if field.StructField.Tag.Get(EncryptedTag) != "true" {
+				continue
+			}
+			if err := fn(field.Value(strct), table.Name+"."+field.Name); err != nil {
+				return fmt.Errorf("dbkit: field %s: %w", field.GoName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// transformField applies fn to a string or non-nil *string field.
+func transformField(field reflect.Value, fn func(string) (string, error)) error {
+	switch {
+	case field.Kind() == reflect.String:
+		out, err := fn(field.String())
+		if err != nil {
+			return err
+		}
+		field.SetString(out)
+	case field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.String:
+		if field.IsNil() {
+			return nil
+		}
+		out, err := fn(field.Elem().String())
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(&out))
+	default:
+		return fmt.Errorf("encrypted fields must be string or *string, got %s", field.Type())
+	}
+	return nil
+}
+
+// RotateEncryptionKey re-encrypts column of tableName from oldKey to newKey in batches of BatchSize.
+// Each batch runs in its own transaction; rows already encrypted with newKey are skipped,
+// so an interrupted rotation can be resumed. The table must have a single-column primary key.
+// Returns the number of rows re-encrypted.
+//
+// Usage:
+//
+//	count, err := dbkit.RotateEncryptionKey(ctx, db, oldKey, newKey, "customers", "ssn")
+func RotateEncryptionKey(ctx context.Context, db IDB, oldKey, newKey []byte, tableName, column string) (int64, error) {
+	oldCipher, err := newFieldCipher(oldKey)
+	if err != nil {
+		return 0, wrapError(err, "RotateEncryptionKey")
+	}
+	newCipher, err := newFieldCipher(newKey)
+	if err != nil {
+		return 0, wrapError(err, "RotateEncryptionKey")
+	}
+
+	var pks []string
+	err = db.NewRaw(`SELECT a.attname FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = ?::regclass AND i.indisprimary`, tableName).Scan(ctx, &pks)
+	if err != nil && !IsNotFound(err) {
+		return 0, wrapError(err, "RotateEncryptionKey")
+	}
+	if len(pks) != 1 {
+		return 0, &Error{
+			Code:    CodeUnknown,
+			Message: "table must have a single-column primary key",
+			Op:      "RotateEncryptionKey",
+			Table:   tableName,
+		}
+	}
+
+	type encryptedRow struct {
+		ID    string         `bun:"id"`
+		Value sql.NullString `bun:"value"`
+	}
+
+	ad := tableName + "." + column
+	var total int64
+	var lastID *string
+
+	for {
+		var rows []encryptedRow
+		q := db.NewSelect().
+			TableExpr("?", bun.Ident(tableName)).
+			ColumnExpr("?::text AS id, ? AS value", bun.Ident(pks[0]), bun.Ident(column)).
+			Where("? IS NOT NULL", bun.Ident(column)).
+			OrderExpr("?", bun.Ident(pks[0])).
+			Limit(BatchSize)
+		if lastID != nil {
+			q = q.Where("? > ?", bun.Ident(pks[0]), *lastID)
+		}
+		if err := q.Scan(ctx, &rows); err != nil && !IsNotFound(err) {
+			return total, wrapError(err, "RotateEncryptionKey")
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			for _, row := range rows {
+				if _, err := newCipher.decrypt(row.Value.String, ad); err == nil && strings.HasPrefix(row.Value.String, encryptedPrefix) {
+					continue // already rotated
+				}
+
+				plaintext, err := oldCipher.decrypt(row.Value.String, ad)
+				if err != nil {
+					return fmt.Errorf("dbkit: row %s: %w", row.ID, err)
+				}
+				ciphertext, err := newCipher.encrypt(plaintext, ad)
+				if err != nil {
+					return err
+				}
+
+				_, err = tx.NewRaw("UPDATE ? SET ? = ? WHERE ?::text = ?",
+					bun.Ident(tableName), bun.Ident(column), ciphertext, bun.Ident(pks[0]), row.ID).Exec(ctx)
+				if err != nil {
+					return err
+				}
+				total++
+			}
+			return nil
+		})
+		if err != nil {
+			return total, wrapError(err, "RotateEncryptionKey")
+		}
+
+		lastID = &rows[len(rows)-1].ID
+	}
+}
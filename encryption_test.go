@@ -0,0 +1,185 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type TestCustomer struct {
+	bun.BaseModel `bun:"table:test_customers,alias:tc"`
+	ID            int64   `bun:"id,pk,autoincrement"`
+	Name          string  `bun:"name,notnull"`
+	SSN           string  `bun:"ssn" bun_enc:"true"`
+	CreditCard    *string `bun:"credit_card" bun_enc:"true"`
+}
+
+func TestFieldCipher(t *testing.T) {
+	c, err := newFieldCipher([]byte("secret"))
+	if err != nil {
+		t.Fatalf("newFieldCipher failed: %v", err)
+	}
+
+	ciphertext, err := c.encrypt("123-45-6789", "customers.ssn")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if !strings.HasPrefix(ciphertext, encryptedPrefix) || strings.Contains(ciphertext, "6789") {
+		t.Errorf("Unexpected ciphertext: %s", ciphertext)
+	}
+
+	plaintext, err := c.decrypt(ciphertext, "customers.ssn")
+	if err != nil || plaintext != "123-45-6789" {
+		t.Errorf("Expected roundtrip, got %q (%v)", plaintext, err)
+	}
+
+	if _, err := c.decrypt(ciphertext, "customers.other"); !errors.Is(err, ErrDecryption) {
+		t.Errorf("Expected ErrDecryption for different column, got %v", err)
+	}
+
+	other, _ := newFieldCipher([]byte("other secret"))
+	if _, err := other.decrypt(ciphertext, "customers.ssn"); !errors.Is(err, ErrDecryption) {
+		t.Errorf("Expected ErrDecryption for different key, got %v", err)
+	}
+
+	if plain, err := c.decrypt("legacy", "customers.ssn"); err != nil || plain != "legacy" {
+		t.Errorf("Expected plaintext passthrough, got %q (%v)", plain, err)
+	}
+
+	if _, err := newFieldCipher(nil); err == nil {
+		t.Error("Expected error for empty key")
+	}
+}
+
+func TestEncryptedDB_EncryptDecrypt(t *testing.T) {
+	db, _ := newFakeDB(t)
+	edb := NewEncryptedDB(db, []byte("secret"))
+
+	card := "4111111111111111"
+	customers := []TestCustomer{{Name: "Alice", SSN: "123-45-6789", CreditCard: &card}, {Name: "Bob"}}
+
+	if err := edb.Encrypt(&customers); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !strings.HasPrefix(customers[0].SSN, encryptedPrefix) || !strings.HasPrefix(*customers[0].CreditCard, encryptedPrefix) {
+		t.Errorf("Expected encrypted fields, got %+v", customers[0])
+	}
+	if customers[0].Name != "Alice" || customers[1].CreditCard != nil {
+		t.Error("Untagged and nil fields should be left alone")
+	}
+	if card != "4111111111111111" {
+		t.Error("Encrypt should not modify the value behind the original pointer")
+	}
+
+	if err := edb.Decrypt(&customers); err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if customers[0].SSN != "123-45-6789" || *customers[0].CreditCard != card {
+		t.Errorf("Expected plaintext after decrypt, got %+v", customers[0])
+	}
+
+	restore, err := edb.seal(&customers[0])
+	if err != nil {
+		t.Fatalf("seal failed: %v", err)
+	}
+	restore()
+	if customers[0].SSN != "123-45-6789" {
+		t.Errorf("Expected restore to bring back plaintext, got %s", customers[0].SSN)
+	}
+}
+
+func createCustomersTable(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewDropTable().Model((*TestCustomer)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop customers table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestCustomer)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create customers table: %v", err)
+	}
+	return ctx
+}
+
+func TestEncryptedDB_Roundtrip(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createCustomersTable(t, db)
+	edb := NewEncryptedDB(db, []byte("secret"))
+
+	customer := &TestCustomer{Name: "Alice", SSN: "123-45-6789"}
+	if err := edb.Insert(ctx, customer); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if customer.SSN != "123-45-6789" {
+		t.Errorf("Insert should leave plaintext in the model, got %s", customer.SSN)
+	}
+
+	var raw string
+	if err := db.NewRaw("SELECT ssn FROM test_customers WHERE id = ?", customer.ID).Scan(ctx, &raw); err != nil {
+		t.Fatalf("Raw select failed: %v", err)
+	}
+	if !strings.HasPrefix(raw, encryptedPrefix) || strings.Contains(raw, "6789") {
+		t.Errorf("Expected ciphertext in database, got %s", raw)
+	}
+
+	var found TestCustomer
+	err := edb.Select(ctx, &found, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("id = ?", customer.ID)
+	})
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if found.SSN != "123-45-6789" {
+		t.Errorf("Expected plaintext from application read, got %s", found.SSN)
+	}
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createCustomersTable(t, db)
+	oldKey, newKey := []byte("old secret"), []byte("new secret")
+
+	customers := make([]TestCustomer, 250)
+	for i := range customers {
+		customers[i] = TestCustomer{Name: "Customer", SSN: "ssn"}
+	}
+	if err := NewEncryptedDB(db, oldKey).Insert(ctx, &customers); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	count, err := RotateEncryptionKey(ctx, db, oldKey, newKey, "test_customers", "ssn")
+	if err != nil {
+		t.Fatalf("RotateEncryptionKey failed: %v", err)
+	}
+	if count != 250 {
+		t.Errorf("Expected 250 rows rotated, got %d", count)
+	}
+
+	var rotated []TestCustomer
+	if err := NewEncryptedDB(db, newKey).Select(ctx, &rotated, nil); err != nil {
+		t.Fatalf("Select with new key failed: %v", err)
+	}
+	for _, c := range rotated {
+		if c.SSN != "ssn" {
+			t.Fatalf("Expected decrypted ssn, got %s", c.SSN)
+		}
+	}
+
+	var stale []TestCustomer
+	if err := NewEncryptedDB(db, oldKey).Select(ctx, &stale, nil); !errors.Is(err, ErrDecryption) {
+		t.Errorf("Expected old key to fail after rotation, got %v", err)
+	}
+
+	// Rerunning is a no-op
+	count, err = RotateEncryptionKey(ctx, db, oldKey, newKey, "test_customers", "ssn")
+	if err != nil || count != 0 {
+		t.Errorf("Expected no rows on second rotation, got %d (%v)", count, err)
+	}
+}
@@ -0,0 +1,100 @@
+package dbkit
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// ConfigFromEnv builds a Config from environment variables on top of DefaultConfig.
+// DATABASE_URL is required. Durations use Go syntax ("30s", "5m").
+//
+//	DATABASE_URL, DB_APPLICATION_NAME
+//	DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS
+//	DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME
+//	DB_DIAL_TIMEOUT, DB_READ_TIMEOUT, DB_WRITE_TIMEOUT
+//	DB_LOG_QUERIES, DB_LOG_SLOW_QUERIES
+//
+// All missing or malformed variables are reported together as a *ConfigValidationError.
+//
+// Usage:
+//
+//	cfg, err := dbkit.ConfigFromEnv()
+//	if err != nil {
+//	    return err
+//	}
+//	db, err := dbkit.New(cfg.WithLogger(logger))
+func ConfigFromEnv() (Config, error) {
+	p := envParser{}
+	cfg := DefaultConfig(os.Getenv("DATABASE_URL"))
+	if cfg.URL == "" {
+		p.errs = append(p.errs, ConfigError{Field: "DATABASE_URL", Message: "is required"})
+	}
+	cfg.ApplicationName = os.Getenv("DB_APPLICATION_NAME")
+
+	p.int("DB_MAX_OPEN_CONNS", &cfg.MaxOpenConns)
+	p.int("DB_MAX_IDLE_CONNS", &cfg.MaxIdleConns)
+	p.duration("DB_CONN_MAX_LIFETIME", &cfg.ConnMaxLifetime)
+	p.duration("DB_CONN_MAX_IDLE_TIME", &cfg.ConnMaxIdleTime)
+	p.duration("DB_DIAL_TIMEOUT", &cfg.DialTimeout)
+	p.duration("DB_READ_TIMEOUT", &cfg.ReadTimeout)
+	p.duration("DB_WRITE_TIMEOUT", &cfg.WriteTimeout)
+	p.bool("DB_LOG_QUERIES", &cfg.LogQueries)
+	p.duration("DB_LOG_SLOW_QUERIES", &cfg.LogSlowQueries)
+
+	if len(p.errs) > 0 {
+		return cfg, &ConfigValidationError{Errors: p.errs}
+	}
+	return cfg, nil
+}
+
+// MustConfigFromEnv is like ConfigFromEnv but panics on error.
+//
+// Usage:
+//
+//	db, err := dbkit.New(dbkit.MustConfigFromEnv())
+func MustConfigFromEnv() Config {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// envParser parses optional variables into config fields, collecting errors.
+type envParser struct {
+	errs []ConfigError
+}
+
+func (p *envParser) int(name string, dst *int) {
+	if v, ok := os.LookupEnv(name); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			p.errs = append(p.errs, ConfigError{Field: name, Message: "must be an integer, got " + strconv.Quote(v)})
+			return
+		}
+		*dst = n
+	}
+}
+
+func (p *envParser) duration(name string, dst *time.Duration) {
+	if v, ok := os.LookupEnv(name); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			p.errs = append(p.errs, ConfigError{Field: name, Message: "must be a duration like 30s or 5m, got " + strconv.Quote(v)})
+			return
+		}
+		*dst = d
+	}
+}
+
+func (p *envParser) bool(name string, dst *bool) {
+	if v, ok := os.LookupEnv(name); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			p.errs = append(p.errs, ConfigError{Field: name, Message: "must be a boolean, got " + strconv.Quote(v)})
+			return
+		}
+		*dst = b
+	}
+}
@@ -0,0 +1,98 @@
+package dbkit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://user:pass@db:5432/app")
+	t.Setenv("DB_MAX_OPEN_CONNS", "50")
+	t.Setenv("DB_MAX_IDLE_CONNS", "10")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "10m")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "2m")
+	t.Setenv("DB_DIAL_TIMEOUT", "3s")
+	t.Setenv("DB_READ_TIMEOUT", "15s")
+	t.Setenv("DB_WRITE_TIMEOUT", "20s")
+	t.Setenv("DB_LOG_QUERIES", "true")
+	t.Setenv("DB_LOG_SLOW_QUERIES", "250ms")
+	t.Setenv("DB_APPLICATION_NAME", "billing")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+
+	if cfg.URL != "postgres://user:pass@db:5432/app" {
+		t.Errorf("Unexpected URL %q", cfg.URL)
+	}
+	if cfg.MaxOpenConns != 50 || cfg.MaxIdleConns != 10 {
+		t.Errorf("Unexpected pool limits %d/%d", cfg.MaxOpenConns, cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime != 10*time.Minute || cfg.ConnMaxIdleTime != 2*time.Minute {
+		t.Errorf("Unexpected lifetimes %v/%v", cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime)
+	}
+	if cfg.DialTimeout != 3*time.Second || cfg.ReadTimeout != 15*time.Second || cfg.WriteTimeout != 20*time.Second {
+		t.Errorf("Unexpected timeouts %v/%v/%v", cfg.DialTimeout, cfg.ReadTimeout, cfg.WriteTimeout)
+	}
+	if !cfg.LogQueries || cfg.LogSlowQueries != 250*time.Millisecond {
+		t.Errorf("Unexpected logging %v/%v", cfg.LogQueries, cfg.LogSlowQueries)
+	}
+	if cfg.ApplicationName != "billing" {
+		t.Errorf("Unexpected application name %q", cfg.ApplicationName)
+	}
+}
+
+func TestConfigFromEnv_Defaults(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://localhost/app")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+
+	want := DefaultConfig("postgres://localhost/app")
+	if cfg.MaxOpenConns != want.MaxOpenConns || cfg.DialTimeout != want.DialTimeout || cfg.LogQueries {
+		t.Errorf("Expected defaults, got %+v", cfg)
+	}
+}
+
+func TestConfigFromEnv_Errors(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("DB_MAX_OPEN_CONNS", "many")
+	t.Setenv("DB_READ_TIMEOUT", "30")
+	t.Setenv("DB_LOG_QUERIES", "sometimes")
+
+	_, err := ConfigFromEnv()
+
+	var verr *ConfigValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected ConfigValidationError, got %v", err)
+	}
+	errs := verr.Errors
+
+	fields := make(map[string]bool)
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	for _, name := range []string{"DATABASE_URL", "DB_MAX_OPEN_CONNS", "DB_READ_TIMEOUT", "DB_LOG_QUERIES"} {
+		if !fields[name] {
+			t.Errorf("Expected an error for %s, got %v", name, err)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("Expected 4 errors, got %d: %v", len(errs), err)
+	}
+}
+
+func TestMustConfigFromEnv_Panics(t *testing.T) {
+	t.Setenv("DATABASE_URL", "")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustConfigFromEnv to panic")
+		}
+	}()
+	MustConfigFromEnv()
+}
@@ -1,11 +1,17 @@
 package dbkit
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/uptrace/bun/driver/pgdriver"
 )
 
 // ErrorCode represents a database error classification
@@ -22,6 +28,10 @@ const (
 	CodeSerialization    ErrorCode = "SERIALIZATION"
 	CodeDeadlock         ErrorCode = "DEADLOCK"
 	CodeConflict         ErrorCode = "CONFLICT"
+	CodeExclusion        ErrorCode = "EXCLUSION"
+	CodeRaisedException  ErrorCode = "RAISED_EXCEPTION"
+	CodeValidation       ErrorCode = "VALIDATION"
+	CodeUnsupported      ErrorCode = "UNSUPPORTED"
 	CodeUnknown          ErrorCode = "UNKNOWN"
 )
 
@@ -36,6 +46,10 @@ var (
 	ErrTimeout          = errors.New("dbkit: operation timeout")
 	ErrSerialization    = errors.New("dbkit: serialization failure")
 	ErrDeadlock         = errors.New("dbkit: deadlock detected")
+	ErrExclusion        = errors.New("dbkit: exclusion constraint violation")
+	ErrRaisedException  = errors.New("dbkit: application exception")
+	ErrValidation       = errors.New("dbkit: validation failed")
+	ErrUnsupported      = errors.New("dbkit: operation not supported")
 )
 
 // Error is a rich database error with context
@@ -70,6 +84,46 @@ func (e *Error) Unwrap() error {
 	return e.Cause
 }
 
+// MarshalJSON serializes the error for API responses: the code, a fixed message for the code,
+// and the column and constraint names. Message, Detail and Hint can hold driver messages and
+// key values, so they are omitted along with Op, Table, Query and Cause.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code       ErrorCode `json:"code"`
+		Message    string    `json:"message"`
+		Column     string    `json:"column,omitempty"`
+		Constraint string    `json:"constraint,omitempty"`
+	}{e.Code, publicMessage(e.Code), e.Column, e.Constraint})
+}
+
+// publicMessage returns the message shown to API clients for code
+func publicMessage(code ErrorCode) string {
+	switch code {
+	case CodeNotFound:
+		return "record not found"
+	case CodeDuplicate:
+		return "duplicate value"
+	case CodeForeignKey:
+		return "referenced record does not exist or is still referenced"
+	case CodeCheckViolation:
+		return "check constraint violation"
+	case CodeNotNullViolation:
+		return "required value missing"
+	case CodeConnectionFailed, CodeTimeout:
+		return "service unavailable"
+	case CodeSerialization, CodeDeadlock, CodeConflict:
+		return "conflicting update, try again"
+	case CodeExclusion:
+		return "conflicts with an existing record"
+	case CodeValidation:
+		return "validation failed"
+	case CodeUnsupported:
+		return "operation not supported"
+	default:
+		return "internal error"
+	}
+}
+
 // Is implements errors.Is for sentinel error matching
 func (e *Error) Is(target error) bool {
 	switch e.Code {
@@ -91,6 +145,14 @@ func (e *Error) Is(target error) bool {
 		return target == ErrSerialization
 	case CodeDeadlock:
 		return target == ErrDeadlock
+	case CodeExclusion:
+		return target == ErrExclusion
+	case CodeRaisedException:
+		return target == ErrRaisedException
+	case CodeValidation:
+		return target == ErrValidation
+	case CodeUnsupported:
+		return target == ErrUnsupported
 	}
 	return false
 }
@@ -118,11 +180,37 @@ func wrapError(err error, op string) error {
 	}
 
 	// PostgreSQL specific errors
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
+	if pgErr := asPgError(err); pgErr != nil {
 		return wrapPgError(pgErr, op)
 	}
 
+	// Deadline from the context or the driver read/write timeouts
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &Error{
+			Code:    CodeTimeout,
+			Message: "query exceeded its deadline",
+			Op:      op,
+			Cause:   err,
+		}
+	}
+
+	// Model validation
+	var validationErr ValidationError
+	var validationErrs ValidationErrors
+	if errors.As(err, &validationErrs) || errors.As(err, &validationErr) {
+		if len(validationErrs) > 0 {
+			validationErr = validationErrs[0]
+		}
+		return &Error{
+			Code:    CodeValidation,
+			Message: err.Error(),
+			Op:      op,
+			Column:  validationErr.Field,
+			Cause:   err,
+		}
+	}
+
 	// Generic wrapping
 	return &Error{
 		Code:    CodeUnknown,
@@ -132,6 +220,36 @@ func wrapError(err error, op string) error {
 	}
 }
 
+// WrapError converts a raw database error to a rich Error for operation op.
+// It is intended for packages built on top of dbkit; nil and already wrapped errors pass through.
+func WrapError(err error, op string) error {
+	return wrapError(err, op)
+}
+
+// asPgError extracts a PostgreSQL error from pgx or pgdriver errors, or returns nil.
+func asPgError(err error) *pgconn.PgError {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr
+	}
+
+	var drvErr pgdriver.Error
+	if errors.As(err, &drvErr) {
+		return &pgconn.PgError{
+			Severity:       drvErr.Field('S'),
+			Code:           drvErr.Field('C'),
+			Message:        drvErr.Field('M'),
+			Detail:         drvErr.Field('D'),
+			Hint:           drvErr.Field('H'),
+			SchemaName:     drvErr.Field('s'),
+			TableName:      drvErr.Field('t'),
+			ColumnName:     drvErr.Field('c'),
+			ConstraintName: drvErr.Field('n'),
+		}
+	}
+	return nil
+}
+
 // wrapPgError converts PostgreSQL errors to rich errors
 func wrapPgError(pgErr *pgconn.PgError, op string) *Error {
 	e := &Error{
@@ -159,6 +277,12 @@ func wrapPgError(pgErr *pgconn.PgError, op string) *Error {
 	case "23514": // check_violation
 		e.Code = CodeCheckViolation
 		e.Message = "check constraint violation"
+	case "23P01": // exclusion_violation
+		e.Code = CodeExclusion
+		e.Message = "conflicting key value violates exclusion constraint"
+	case "P0001": // raise_exception
+		e.Code = CodeRaisedException
+		e.Message = pgErr.Message
 	case "40001": // serialization_failure
 		e.Code = CodeSerialization
 		e.Message = "serialization failure, retry transaction"
@@ -205,6 +329,34 @@ func IsNotNullViolation(err error) bool {
 	return errors.Is(err, ErrNotNullViolation)
 }
 
+// IsExclusion checks if error is an exclusion constraint error
+func IsExclusion(err error) bool {
+	return errors.Is(err, ErrExclusion)
+}
+
+// IsRaisedException checks if error was raised with RAISE EXCEPTION in PL/pgSQL
+func IsRaisedException(err error) bool {
+	return errors.Is(err, ErrRaisedException)
+}
+
+// IsValidation checks if error was returned by a model's Validate method or by New for an invalid Config
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsUnsupported checks if error was returned for an operation disabled by the configuration,
+// such as session-level features in pgBouncer mode
+func IsUnsupported(err error) bool {
+	return errors.Is(err, ErrUnsupported)
+}
+
+// IsDataException checks if error belongs to the PostgreSQL data exception class (22xxx),
+// such as invalid input syntax, division by zero or numeric overflow
+func IsDataException(err error) bool {
+	pgErr := asPgError(err)
+	return pgErr != nil && strings.HasPrefix(pgErr.Code, "22")
+}
+
 // IsConnection checks if error is a connection error
 func IsConnection(err error) bool {
 	return errors.Is(err, ErrConnection)
@@ -220,6 +372,55 @@ func IsRetryable(err error) bool {
 	return errors.Is(err, ErrSerialization) || errors.Is(err, ErrDeadlock)
 }
 
+// HTTPStatus maps an error to an HTTP status code.
+// Errors that are not dbkit errors map to 500, nil maps to 200. So does CodeRaisedException:
+// what a RAISE EXCEPTION means is up to the function that raised it.
+//
+// Usage:
+//
+//	http.Error(w, err.Error(), dbkit.HTTPStatus(err))
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	code, _ := GetErrorCode(err)
+	switch code {
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeDuplicate, CodeForeignKey, CodeConflict, CodeExclusion:
+		return http.StatusConflict
+	case CodeCheckViolation, CodeNotNullViolation, CodeValidation:
+		return http.StatusUnprocessableEntity
+	case CodeTimeout, CodeConnectionFailed:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ErrorResponse returns the HTTP status and JSON body for err, see Error.MarshalJSON for the fields.
+// Errors that are not dbkit errors are reported as a generic internal error.
+//
+// Usage:
+//
+//	status, body := dbkit.ErrorResponse(err)
+//	w.Header().Set("Content-Type", "application/json")
+//	w.WriteHeader(status)
+//	w.Write(body)
+func ErrorResponse(err error) (statusCode int, body []byte) {
+	var dbErr *Error
+	if !errors.As(err, &dbErr) {
+		dbErr = &Error{Code: CodeUnknown}
+	}
+
+	body, merr := json.Marshal(dbErr)
+	if merr != nil {
+		body = []byte(`{"code":"UNKNOWN","message":"internal error"}`)
+	}
+	return HTTPStatus(err), body
+}
+
 // GetErrorCode extracts the error code if it's a dbkit error
 func GetErrorCode(err error) (ErrorCode, bool) {
 	var dbErr *Error
@@ -0,0 +1,118 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+)
+
+// EventCollector is implemented by models that record domain events, e.g. by embedding AggregateRoot
+type EventCollector interface {
+	CollectEvents() []any
+}
+
+// AggregateRoot records domain events on a model until they are collected.
+// It has no columns, so it can be embedded in any Bun model.
+//
+// Usage:
+//
+//	type Order struct {
+//	    bun.BaseModel `bun:"table:orders,alias:o"`
+//	    dbkit.BaseModel
+//	    dbkit.AggregateRoot
+//	    Total int64 `bun:"total,notnull"`
+//	}
+//
+//	order.AddEvent(OrderPlaced{OrderID: order.ID})
+type AggregateRoot struct {
+	events []any
+}
+
+// AddEvent records a domain event
+func (a *AggregateRoot) AddEvent(e any) {
+	a.events = append(a.events, e)
+}
+
+// CollectEvents returns the recorded events and clears them
+func (a *AggregateRoot) CollectEvents() []any {
+	events := a.events
+	a.events = nil
+	return events
+}
+
+// txTracker holds the models tracked in a transaction
+type txTracker struct {
+	mu     sync.Mutex
+	models []EventCollector
+}
+
+func (t *txTracker) add(model EventCollector) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.models = append(t.models, model)
+}
+
+func (t *txTracker) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.models)
+}
+
+func (t *txTracker) truncate(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.models = t.models[:n]
+}
+
+// collect returns the events of every tracked model in tracking order.
+func (t *txTracker) collect() []any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var events []any
+	for _, model := range t.models {
+		events = append(events, model.CollectEvents()...)
+	}
+	return events
+}
+
+// Track registers a model whose events EventDispatchingTransaction dispatches after commit.
+// Models tracked in a savepoint that is rolled back are dropped.
+func (tx *Tx) Track(model EventCollector) {
+	tx.tracker.add(model)
+}
+
+// EventDispatchingTransaction runs fn in a transaction and, after it commits, passes the
+// events of every model registered with tx.Track to dispatcher. Nothing is dispatched on rollback.
+// A dispatch error is returned after the data has been committed.
+//
+// Usage:
+//
+//	err := db.EventDispatchingTransaction(ctx, bus.Publish, func(tx *dbkit.Tx) error {
+//	    order.AddEvent(OrderPlaced{Total: order.Total})
+//	    tx.Track(&order)
+//	    return dbkit.Create(ctx, tx, &order)
+//	})
+func (db *DBKit) EventDispatchingTransaction(ctx context.Context, dispatcher func(events []any) error, fn TxFunc) error {
+	var tracker *txTracker
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		tracker = tx.tracker
+		return fn(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	events := tracker.collect()
+	if len(events) == 0 {
+		return nil
+	}
+	if err := dispatcher(events); err != nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "transaction committed but event dispatch failed: " + err.Error(),
+			Op:      "EventDispatchingTransaction",
+			Cause:   err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type aggregateOrder struct {
+	bun.BaseModel `bun:"table:test_aggregate_orders,alias:tao"`
+	IntBaseModel
+	AggregateRoot
+	Name string `bun:"name,notnull"`
+}
+
+func TestAggregateRoot_CollectEvents(t *testing.T) {
+	var order aggregateOrder
+	order.AddEvent(orderPlaced{ID: "1"})
+	order.AddEvent(orderPlaced{ID: "2"})
+
+	events := order.CollectEvents()
+	if len(events) != 2 || events[0].(orderPlaced).ID != "1" {
+		t.Errorf("Expected both events in order, got %v", events)
+	}
+	if events := order.CollectEvents(); len(events) != 0 {
+		t.Errorf("Expected events to be cleared, got %v", events)
+	}
+}
+
+func TestEventDispatchingTransaction_DispatchAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	var first, second aggregateOrder
+	var dispatched []any
+	err := db.EventDispatchingTransaction(ctx, func(events []any) error {
+		dispatched = append(dispatched, events...)
+		return nil
+	}, func(tx *Tx) error {
+		first.AddEvent(orderPlaced{ID: "1"})
+		second.AddEvent(orderPlaced{ID: "2"})
+		tx.Track(&first)
+		tx.Track(&second)
+		if len(dispatched) != 0 {
+			t.Error("Expected no dispatch before commit")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EventDispatchingTransaction failed: %v", err)
+	}
+	if len(dispatched) != 2 || dispatched[1].(orderPlaced).ID != "2" {
+		t.Errorf("Expected both events in tracking order, got %v", dispatched)
+	}
+}
+
+func TestEventDispatchingTransaction_NoDispatchOnRollback(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	calls := 0
+	fnErr := errors.New("boom")
+	err := db.EventDispatchingTransaction(ctx, func(events []any) error {
+		calls++
+		return nil
+	}, func(tx *Tx) error {
+		var order aggregateOrder
+		order.AddEvent(orderPlaced{ID: "1"})
+		tx.Track(&order)
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Errorf("Expected fn error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no dispatch, got %d calls", calls)
+	}
+}
+
+func TestEventDispatchingTransaction_SavepointRollbackDropsTracked(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	var kept, dropped aggregateOrder
+	var dispatched []any
+	err := db.EventDispatchingTransaction(ctx, func(events []any) error {
+		dispatched = append(dispatched, events...)
+		return nil
+	}, func(tx *Tx) error {
+		kept.AddEvent(orderPlaced{ID: "kept"})
+		tx.Track(&kept)
+
+		_ = tx.Transaction(ctx, func(nested *Tx) error {
+			dropped.AddEvent(orderPlaced{ID: "dropped"})
+			nested.Track(&dropped)
+			return errors.New("rollback savepoint")
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EventDispatchingTransaction failed: %v", err)
+	}
+	if len(dispatched) != 1 || dispatched[0].(orderPlaced).ID != "kept" {
+		t.Errorf("Expected only the kept event, got %v", dispatched)
+	}
+}
+
+func TestEventDispatchingTransaction_DispatchError(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	dispatchErr := errors.New("broker down")
+	err := db.EventDispatchingTransaction(ctx, func(events []any) error {
+		return dispatchErr
+	}, func(tx *Tx) error {
+		var order aggregateOrder
+		order.AddEvent(orderPlaced{ID: "1"})
+		tx.Track(&order)
+		return nil
+	})
+	if !errors.Is(err, dispatchErr) {
+		t.Errorf("Expected dispatch error to be wrapped, got %v", err)
+	}
+}
+
+func TestEventDispatchingTransaction_Insert(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	if _, err := db.NewCreateTable().Model((*aggregateOrder)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*aggregateOrder)(nil)).IfExists().Exec(ctx)
+
+	order := aggregateOrder{Name: "Order 1"}
+	var dispatched []any
+	err := db.EventDispatchingTransaction(ctx, func(events []any) error {
+		count, err := db.NewSelect().Model((*aggregateOrder)(nil)).Where("id = ?", order.ID).Count(ctx)
+		if err != nil {
+			return err
+		}
+		if count != 1 {
+			t.Errorf("Expected the row to be committed before dispatch, got %d", count)
+		}
+		dispatched = append(dispatched, events...)
+		return nil
+	}, func(tx *Tx) error {
+		order.AddEvent(orderPlaced{ID: order.Name})
+		tx.Track(&order)
+		_, err := tx.NewInsert().Model(&order).Exec(ctx)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("EventDispatchingTransaction failed: %v", err)
+	}
+	if len(dispatched) != 1 || dispatched[0].(orderPlaced).ID != order.Name {
+		t.Errorf("Expected the order event, got %v", dispatched)
+	}
+}
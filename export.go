@@ -0,0 +1,71 @@
+package dbkit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/uptrace/bun"
+)
+
+// StreamJSON writes the matching records of T to w as a JSON array, fetching chunkSize rows at a time
+// with ScanChunked. Each chunk is flushed to w once encoded, so memory use does not grow with the
+// result. Nothing is written when the query fails before the first chunk; a later failure leaves the
+// array unterminated. Returns the number of records written.
+//
+// Usage:
+//
+//	w.Header().Set("Content-Type", "application/json")
+//	n, err := dbkit.StreamJSON[Order](ctx, db, w, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Where("created_at >= ?", since).Order("id ASC")
+//	}, 1000)
+func StreamJSON[T any](ctx context.Context, db IDB, w io.Writer, queryFn func(*bun.SelectQuery) *bun.SelectQuery, chunkSize int) (int64, error) {
+	bw := bufio.NewWriter(w)
+	_ = bw.WriteByte('[')
+
+	var count int64
+	err := ScanChunked[T](ctx, db, chunkSize, queryFn, func(chunk []T) error {
+		for i := range chunk {
+			b, err := json.Marshal(&chunk[i])
+			if err != nil {
+				return wrapError(err, "StreamJSON.Encode")
+			}
+			if count > 0 {
+				_ = bw.WriteByte(',')
+			}
+			_, _ = bw.Write(b)
+			count++
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		return count, err
+	}
+
+	_ = bw.WriteByte(']')
+	return count, bw.Flush()
+}
+
+// StreamJSONL is StreamJSON writing newline-delimited JSON, one record per line without an array.
+//
+// Usage:
+//
+//	w.Header().Set("Content-Type", "application/x-ndjson")
+//	n, err := dbkit.StreamJSONL[Order](ctx, db, w, nil, 1000)
+func StreamJSONL[T any](ctx context.Context, db IDB, w io.Writer, queryFn func(*bun.SelectQuery) *bun.SelectQuery, chunkSize int) (int64, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	var count int64
+	err := ScanChunked[T](ctx, db, chunkSize, queryFn, func(chunk []T) error {
+		for i := range chunk {
+			if err := enc.Encode(&chunk[i]); err != nil {
+				return wrapError(err, "StreamJSONL.Encode")
+			}
+			count++
+		}
+		return bw.Flush()
+	})
+	return count, err
+}
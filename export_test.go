@@ -0,0 +1,87 @@
+package dbkit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+func TestStreamJSON_Empty(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	n, err := StreamJSON[TestModel](ctx, db, &buf, nil, 100)
+	if err != nil || n != 0 || buf.String() != "[]" {
+		t.Errorf("Expected an empty array, got %q (%d, %v)", buf.String(), n, err)
+	}
+
+	buf.Reset()
+	n, err = StreamJSONL[TestModel](ctx, db, &buf, nil, 100)
+	if err != nil || n != 0 || buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q (%d, %v)", buf.String(), n, err)
+	}
+}
+
+func TestStreamJSON(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 10000)
+	byAge := func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("age ASC", "email ASC")
+	}
+
+	var buf bytes.Buffer
+	n, err := StreamJSON[TestModel](ctx, db, &buf, byAge, 500)
+	if err != nil {
+		t.Fatalf("StreamJSON failed: %v", err)
+	}
+	var models []TestModel
+	if err := json.Unmarshal(buf.Bytes(), &models); err != nil {
+		t.Fatalf("Output is not a JSON array: %v", err)
+	}
+	if n != 10000 || len(models) != 10000 {
+		t.Fatalf("Expected 10000 records, got %d written and %d parsed", n, len(models))
+	}
+	if models[0].Age != 0 || models[len(models)-1].Age != 99 || models[0].Email != "user0@example.com" {
+		t.Errorf("Unexpected records %+v ... %+v", models[0], models[len(models)-1])
+	}
+
+	buf.Reset()
+	n, err = StreamJSONL[TestModel](ctx, db, &buf, byAge, 500)
+	if err != nil {
+		t.Fatalf("StreamJSONL failed: %v", err)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var m TestModel
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("Line %d is not a JSON object: %v", lines, err)
+		}
+		if want := models[lines]; m.ID != want.ID || m.Email != want.Email || m.Name != fmt.Sprintf("User %d", emailIndex(t, m.Email)) {
+			t.Fatalf("Line %d: expected %+v, got %+v", lines, want, m)
+		}
+		lines++
+	}
+	if n != 10000 || lines != 10000 {
+		t.Errorf("Expected 10000 lines, got %d written and %d parsed", n, lines)
+	}
+}
+
+// emailIndex returns i of the user<i>@example.com emails of insertTestModels
+func emailIndex(t *testing.T, email string) int {
+	t.Helper()
+	var i int
+	if _, err := fmt.Sscanf(email, "user%d@example.com", &i); err != nil {
+		t.Fatalf("Unexpected email %q", email)
+	}
+	return i
+}
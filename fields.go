@@ -0,0 +1,106 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/uptrace/bun"
+)
+
+// UpdateField sets a single column of the record with the given primary key, without loading it.
+// fieldName must be a column of T and, when allowed columns are given, one of them.
+// Returns ErrNotFound if no record has the ID.
+//
+// Usage:
+//
+//	err := dbkit.UpdateField[User](ctx, db, userID, "status", "banned", "status", "role")
+func UpdateField[T any](ctx context.Context, db IDB, id any, fieldName string, value any, allowed ...string) error {
+	if err := validateField[T](db, fieldName, allowed, "UpdateField"); err != nil {
+		return err
+	}
+
+	result, err := db.NewUpdate().Model((*T)(nil)).
+		Set("? = ?", bun.Ident(fieldName), value).
+		Where("?PKs = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "UpdateField")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "UpdateField"}
+	}
+	return nil
+}
+
+// IncrementField adds delta to a numeric column of the record with the given primary key in a single
+// statement and returns the new value. Use a negative delta to decrement. See UpdateField for allowed.
+//
+// Usage:
+//
+//	views, err := dbkit.IncrementField[Post](ctx, db, postID, "views", 1, "views")
+func IncrementField[T any](ctx context.Context, db IDB, id any, fieldName string, delta int64, allowed ...string) (int64, error) {
+	if err := validateField[T](db, fieldName, allowed, "IncrementField"); err != nil {
+		return 0, err
+	}
+
+	var value int64
+	err := db.NewUpdate().Model((*T)(nil)).
+		Set("? = ? + ?", bun.Ident(fieldName), bun.Ident(fieldName), delta).
+		Where("?PKs = ?", id).
+		Returning("?", bun.Ident(fieldName)).
+		Scan(ctx, &value)
+	if err != nil {
+		return 0, wrapError(err, "IncrementField")
+	}
+	return value, nil
+}
+
+// ToggleField negates a boolean column of the record with the given primary key in a single
+// statement and returns the new value. See UpdateField for allowed.
+//
+// Usage:
+//
+//	active, err := dbkit.ToggleField[User](ctx, db, userID, "active", "active")
+func ToggleField[T any](ctx context.Context, db IDB, id any, fieldName string, allowed ...string) (bool, error) {
+	if err := validateField[T](db, fieldName, allowed, "ToggleField"); err != nil {
+		return false, err
+	}
+
+	var value bool
+	err := db.NewUpdate().Model((*T)(nil)).
+		Set("? = NOT ?", bun.Ident(fieldName), bun.Ident(fieldName)).
+		Where("?PKs = ?", id).
+		Returning("?", bun.Ident(fieldName)).
+		Scan(ctx, &value)
+	if err != nil {
+		return false, wrapError(err, "ToggleField")
+	}
+	return value, nil
+}
+
+// validateField rejects column names that are not in allowed or not a column of T.
+// Column names often come from requests, for instance a "field" query parameter.
+func validateField[T any](db IDB, fieldName string, allowed []string, op string) error {
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	if len(allowed) > 0 && !slices.Contains(allowed, fieldName) {
+		return &Error{
+			Code:    CodeValidation,
+			Message: fmt.Sprintf("column %q is not allowed", fieldName),
+			Op:      op,
+			Table:   table.Name,
+			Column:  fieldName,
+		}
+	}
+	if _, ok := table.FieldMap[fieldName]; !ok {
+		return &Error{
+			Code:    CodeValidation,
+			Message: fmt.Sprintf("%s has no column %q", table.TypeName, fieldName),
+			Op:      op,
+			Table:   table.Name,
+			Column:  fieldName,
+		}
+	}
+	return nil
+}
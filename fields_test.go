@@ -0,0 +1,91 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpdateField_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+	ctx := context.Background()
+
+	if err := UpdateField[TestModel](ctx, db, "id-1", "name", "Bob"); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	want := `UPDATE "test_models" AS "tm" SET "name" = 'Bob' WHERE ("id" = 'id-1')`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+
+	_, _ = IncrementField[TestModel](ctx, db, "id-1", "age", 5)
+	want = `UPDATE "test_models" AS "tm" SET "age" = "age" + 5 WHERE ("id" = 'id-1') RETURNING "age"`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+
+	_, _ = ToggleField[TestModel](ctx, db, "id-1", "active")
+	want = `UPDATE "test_models" AS "tm" SET "active" = NOT "active" WHERE ("id" = 'id-1') RETURNING "active"`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+}
+
+func TestUpdateField_Validation(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	if err := UpdateField[TestModel](ctx, db, "id-1", "email", "x@example.com", "name", "age"); !IsValidation(err) {
+		t.Errorf("Expected a column outside the whitelist to be rejected, got %v", err)
+	}
+	if _, err := IncrementField[TestModel](ctx, db, "id-1", `age" = 0; --`, 1); !IsValidation(err) {
+		t.Errorf("Expected an unknown column to be rejected, got %v", err)
+	}
+	if _, err := ToggleField[TestModel](ctx, db, "id-1", "verified", "verified"); !IsValidation(err) {
+		t.Errorf("Expected a whitelisted but unknown column to be rejected, got %v", err)
+	}
+}
+
+func TestUpdateField(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	model := TestModel{Name: "Alice", Email: "alice@example.com", Age: 30}
+	if _, err := db.NewInsert().Model(&model).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	age, err := IncrementField[TestModel](ctx, db, model.ID, "age", 5, "age")
+	if err != nil || age != 35 {
+		t.Fatalf("Expected age 35, got %d, %v", age, err)
+	}
+	if age, err = IncrementField[TestModel](ctx, db, model.ID, "age", -10, "age"); err != nil || age != 25 {
+		t.Errorf("Expected age 25, got %d, %v", age, err)
+	}
+
+	active, err := ToggleField[TestModel](ctx, db, model.ID, "active")
+	if err != nil || !active {
+		t.Errorf("Expected active to be toggled on, got %v, %v", active, err)
+	}
+
+	if err := UpdateField[TestModel](ctx, db, model.ID, "name", "Bob", "name"); err != nil {
+		t.Fatalf("UpdateField failed: %v", err)
+	}
+	found, err := FindByID[TestModel](ctx, db, model.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if found.Name != "Bob" || found.Age != 25 || !found.Active || found.Email != "alice@example.com" {
+		t.Errorf("Unexpected record %+v", found)
+	}
+
+	missing := "00000000-0000-0000-0000-000000000000"
+	if _, err := IncrementField[TestModel](ctx, db, missing, "age", 1); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := ToggleField[TestModel](ctx, db, missing, "active"); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
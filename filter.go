@@ -0,0 +1,218 @@
+package dbkit
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// FilterType is the type a filter value is parsed as
+type FilterType int
+
+const (
+	FilterString FilterType = iota
+	FilterInt
+	FilterBool
+	FilterTime // RFC 3339 timestamp or 2006-01-02 date
+)
+
+// filterOperators maps query parameter suffixes to SQL conditions
+var filterOperators = map[string]string{
+	"eq":         "? = ?",
+	"gt":         "? > ?",
+	"gte":        "? >= ?",
+	"lt":         "? < ?",
+	"lte":        "? <= ?",
+	"in":         "? IN (?)",
+	"contains":   "? LIKE ?",
+	"startswith": "? LIKE ?",
+	"isnull":     "",
+}
+
+// likeEscaper escapes LIKE wildcards in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ParseFilter converts query parameters of the form field__operator=value into WHERE conditions.
+// Operators are eq, gt, gte, lt, lte, in (comma separated), contains, startswith (strings only,
+// case sensitive) and isnull (true or false). A parameter named like an allowed field without an
+// operator is an eq filter; other parameters without an operator, such as sort or page, are ignored.
+// Unknown fields, unknown operators and unparsable values are returned together as a validation error.
+//
+// Usage:
+//
+//	// GET /users?age__gt=25&active=true&name__contains=alice
+//	filter, err := dbkit.ParseFilter(r.URL.Query(), map[string]dbkit.FilterType{
+//	    "age":    dbkit.FilterInt,
+//	    "active": dbkit.FilterBool,
+//	    "name":   dbkit.FilterString,
+//	})
+//	if dbkit.IsValidation(err) {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	err = db.NewSelect().Model(&users).Apply(filter).Scan(ctx)
+func ParseFilter(query url.Values, allowedFields map[string]FilterType) (func(*bun.SelectQuery) *bun.SelectQuery, error) {
+	type condition struct {
+		query string
+		args  []any
+	}
+	var conditions []condition
+	var errs ValidationErrors
+
+	// Sorted for a stable statement text
+	for _, param := range slices.Sorted(maps.Keys(query)) {
+		field, op, hasOp := strings.Cut(param, "__")
+		if !hasOp {
+			if _, ok := allowedFields[param]; !ok {
+				continue
+			}
+			op = "eq"
+		}
+
+		typ, ok := allowedFields[field]
+		if !ok {
+			errs = append(errs, ValidationError{Field: field, Message: "is not a filterable field"})
+			continue
+		}
+		expr, ok := filterOperators[op]
+		if !ok {
+			errs = append(errs, ValidationError{Field: param, Message: fmt.Sprintf("unknown operator %q", op)})
+			continue
+		}
+		if (op == "contains" || op == "startswith") && typ != FilterString {
+			errs = append(errs, ValidationError{Field: param, Message: "operator requires a string field"})
+			continue
+		}
+
+		for _, raw := range query[param] {
+			switch op {
+			case "isnull":
+				isNull, err := strconv.ParseBool(raw)
+				if err != nil {
+					errs = append(errs, ValidationError{Field: param, Message: "must be true or false"})
+					continue
+				}
+				if isNull {
+					conditions = append(conditions, condition{"? IS NULL", []any{bun.Ident(field)}})
+				} else {
+					conditions = append(conditions, condition{"? IS NOT NULL", []any{bun.Ident(field)}})
+				}
+			case "in":
+				parts := strings.Split(raw, ",")
+				values := make([]any, 0, len(parts))
+				for _, part := range parts {
+					v, err := parseFilterValue(typ, strings.TrimSpace(part))
+					if err != nil {
+						errs = append(errs, ValidationError{Field: param, Message: err.Error()})
+						continue
+					}
+					values = append(values, v)
+				}
+				conditions = append(conditions, condition{expr, []any{bun.Ident(field), bun.In(values)}})
+			case "contains":
+				conditions = append(conditions, condition{expr, []any{bun.Ident(field), "%" + likeEscaper.Replace(raw) + "%"}})
+			case "startswith":
+				conditions = append(conditions, condition{expr, []any{bun.Ident(field), likeEscaper.Replace(raw) + "%"}})
+			default:
+				v, err := parseFilterValue(typ, raw)
+				if err != nil {
+					errs = append(errs, ValidationError{Field: param, Message: err.Error()})
+					continue
+				}
+				conditions = append(conditions, condition{expr, []any{bun.Ident(field), v}})
+			}
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, wrapError(err, "ParseFilter")
+	}
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		for _, c := range conditions {
+			q = q.Where(c.query, c.args...)
+		}
+		return q
+	}, nil
+}
+
+// parseFilterValue parses raw as typ
+func parseFilterValue(typ FilterType, raw string) (any, error) {
+	switch typ {
+	case FilterInt:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return v, nil
+	case FilterBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return v, nil
+	case FilterTime:
+		if v, err := time.Parse(time.RFC3339, raw); err == nil {
+			return v, nil
+		}
+		v, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a RFC 3339 timestamp or date", raw)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
+
+// ParseSort converts the sort query parameter, a comma separated list of columns prefixed with
+// - for descending order, into ORDER BY clauses. Columns not in allowedColumns are returned
+// as a validation error.
+//
+// Usage:
+//
+//	// GET /users?sort=-created_at,name
+//	sort, err := dbkit.ParseSort(r.URL.Query(), []string{"created_at", "name"})
+//	err = db.NewSelect().Model(&users).Apply(filter).Apply(sort).Scan(ctx)
+func ParseSort(query url.Values, allowedColumns []string) (func(*bun.SelectQuery) *bun.SelectQuery, error) {
+	type order struct {
+		column string
+		desc   bool
+	}
+	var orders []order
+	var errs ValidationErrors
+
+	for _, raw := range query["sort"] {
+		for _, col := range strings.Split(raw, ",") {
+			col = strings.TrimSpace(col)
+			if col == "" {
+				continue
+			}
+			col, desc := strings.CutPrefix(col, "-")
+			if !slices.Contains(allowedColumns, col) {
+				errs = append(errs, ValidationError{Field: col, Message: "is not a sortable column"})
+				continue
+			}
+			orders = append(orders, order{col, desc})
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, wrapError(err, "ParseSort")
+	}
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		for _, o := range orders {
+			if o.desc {
+				q = q.OrderExpr("? DESC", bun.Ident(o.column))
+			} else {
+				q = q.OrderExpr("? ASC", bun.Ident(o.column))
+			}
+		}
+		return q
+	}, nil
+}
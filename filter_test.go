@@ -0,0 +1,135 @@
+package dbkit
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+var testFilterFields = map[string]FilterType{
+	"name":       FilterString,
+	"age":        FilterInt,
+	"active":     FilterBool,
+	"created_at": FilterTime,
+}
+
+func filterSQL(t *testing.T, fn func(*bun.SelectQuery) *bun.SelectQuery) string {
+	t.Helper()
+	db, _ := newFakeDB(t)
+	var models []TestModel
+	sql := db.NewSelect().Model(&models).Apply(fn).String()
+	_, where, _ := strings.Cut(sql, " FROM \"test_models\" AS \"tm\" ")
+	return where
+}
+
+func TestParseFilter_Operators(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"name__eq=alice", `WHERE ("name" = 'alice')`},
+		{"name=alice", `WHERE ("name" = 'alice')`},
+		{"age__gt=25", `WHERE ("age" > 25)`},
+		{"age__gte=25", `WHERE ("age" >= 25)`},
+		{"age__lt=25", `WHERE ("age" < 25)`},
+		{"age__lte=25", `WHERE ("age" <= 25)`},
+		{"age__in=1,2,3", `WHERE ("age" IN (1, 2, 3))`},
+		{"name__contains=al%25ice", `WHERE ("name" LIKE '%al\%ice%')`},
+		{"name__startswith=al", `WHERE ("name" LIKE 'al%')`},
+		{"age__isnull=true", `WHERE ("age" IS NULL)`},
+		{"age__isnull=false", `WHERE ("age" IS NOT NULL)`},
+		{"active__eq=true", `WHERE ("active" = TRUE)`},
+		{"created_at__gte=2024-06-01", `WHERE ("created_at" >= '2024-06-01 00:00:00+00:00')`},
+		{"age__gt=25&active=true&sort=name&page=2", `WHERE ("active" = TRUE) AND ("age" > 25)`},
+		{"age__gt=1&age__gt=2", `WHERE ("age" > 1) AND ("age" > 2)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery failed: %v", err)
+			}
+			fn, err := ParseFilter(query, testFilterFields)
+			if err != nil {
+				t.Fatalf("ParseFilter failed: %v", err)
+			}
+			if got := filterSQL(t, fn); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		field string
+	}{
+		{"password__eq=x", "password"},
+		{"age__like=1", "age__like"},
+		{"age__gt=old", "age__gt"},
+		{"active__eq=maybe", "active__eq"},
+		{"created_at__lt=yesterday", "created_at__lt"},
+		{"age__contains=1", "age__contains"},
+		{"name__isnull=sometimes", "name__isnull"},
+		{"age__in=1,x", "age__in"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			fn, err := ParseFilter(query, testFilterFields)
+			if fn != nil {
+				t.Error("Expected no filter on error")
+			}
+			if !IsValidation(err) {
+				t.Fatalf("Expected a validation error, got %v", err)
+			}
+			var verr ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Errorf("Expected field %q in the error, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestParseFilter_ListsEveryError(t *testing.T) {
+	query, _ := url.ParseQuery("password=x&secret__eq=y&token__gt=1")
+	_, err := ParseFilter(query, testFilterFields)
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	if len(errs) != 2 || errs[0].Field != "secret" || errs[1].Field != "token" {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	query, _ := url.ParseQuery("sort=-created_at,name")
+	fn, err := ParseSort(query, []string{"created_at", "name"})
+	if err != nil {
+		t.Fatalf("ParseSort failed: %v", err)
+	}
+	if got, want := filterSQL(t, fn), `ORDER BY "created_at" DESC, "name" ASC`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	fn, err = ParseSort(url.Values{}, []string{"name"})
+	if err != nil {
+		t.Fatalf("ParseSort failed: %v", err)
+	}
+	if got := filterSQL(t, fn); got != "" {
+		t.Errorf("Expected no ORDER BY without a sort parameter, got %s", got)
+	}
+
+	query, _ = url.ParseQuery("sort=name,-password")
+	if _, err := ParseSort(query, []string{"name"}); !IsValidation(err) || !strings.Contains(err.Error(), "password") {
+		t.Errorf("Expected a validation error naming the column, got %v", err)
+	}
+}
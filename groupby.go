@@ -0,0 +1,95 @@
+package dbkit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/uptrace/bun"
+)
+
+// GroupByRow is a single group key and its aggregate value.
+type GroupByRow[K comparable, V any] struct {
+	Key   K `bun:"key"`
+	Value V `bun:"value"`
+}
+
+// GroupByResult holds grouped rows in query order, e.g. when the query is ordered by the aggregate.
+type GroupByResult[K comparable, V any] []GroupByRow[K, V]
+
+// ToMap converts the rows to a map from group key to aggregate value.
+func (r GroupByResult[K, V]) ToMap() map[K]V {
+	m := make(map[K]V, len(r))
+	for _, row := range r {
+		m[row.Key] = row.Value
+	}
+	return m
+}
+
+// groupByQuery builds SELECT groupExpr AS key, aggregateExpr AS value ... GROUP BY groupExpr.
+func groupByQuery[T any](db IDB, groupColumn, aggregateExpr string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) *bun.SelectQuery {
+	q := db.NewSelect().
+		Model((*T)(nil)).
+		ColumnExpr(groupColumn + " AS key").
+		ColumnExpr(aggregateExpr + " AS value").
+		GroupExpr(groupColumn)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+	return q
+}
+
+// GroupBy aggregates T's records per value of groupColumn into a map.
+// groupColumn and aggregateExpr are SQL expressions and must not contain user input.
+//
+// Usage:
+//
+//	counts, err := dbkit.GroupBy[User, string, int](ctx, db, "country", "COUNT(*)", func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Where("active = ?", true)
+//	})
+func GroupBy[T any, K comparable, V any](ctx context.Context, db IDB, groupColumn, aggregateExpr string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (map[K]V, error) {
+	rows, err := GroupByOrdered[T, K, V](ctx, db, groupColumn, aggregateExpr, queryFn)
+	if err != nil {
+		return nil, wrapError(err, "GroupBy")
+	}
+	return rows.ToMap(), nil
+}
+
+// GroupByOrdered is like GroupBy but keeps the rows in query order.
+//
+// Usage:
+//
+//	top, err := dbkit.GroupByOrdered[Order, string, float64](ctx, db, "customer_id", "SUM(total)", func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.OrderExpr("value DESC").Limit(10)
+//	})
+func GroupByOrdered[T any, K comparable, V any](ctx context.Context, db IDB, groupColumn, aggregateExpr string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (GroupByResult[K, V], error) {
+	rows := GroupByResult[K, V]{}
+	if err := groupByQuery[T](db, groupColumn, aggregateExpr, queryFn).Scan(ctx, &rows); err != nil {
+		return nil, wrapError(err, "GroupByOrdered")
+	}
+	return rows, nil
+}
+
+// GroupByMulti aggregates T's records per combination of groupColumns.
+// Each map holds the group columns by name and the aggregate under "value".
+// Expressions can be named with an alias, e.g. "age / 10 * 10 AS bracket".
+//
+// Usage:
+//
+//	rows, err := dbkit.GroupByMulti[Order](ctx, db, []string{"country", "status"}, "COUNT(*)", nil)
+func GroupByMulti[T any](ctx context.Context, db IDB, groupColumns []string, aggregateExpr string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]map[string]any, error) {
+	q := db.NewSelect().Model((*T)(nil))
+	for i, col := range groupColumns {
+		// Group by position so aliased expressions work
+		q = q.ColumnExpr(col).GroupExpr(strconv.Itoa(i + 1))
+	}
+	q = q.ColumnExpr(aggregateExpr + " AS value")
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	rows := []map[string]any{}
+	if err := q.Scan(ctx, &rows); err != nil {
+		return nil, wrapError(err, "GroupByMulti")
+	}
+	return rows, nil
+}
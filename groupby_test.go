@@ -0,0 +1,106 @@
+package dbkit
+
+import (
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+func TestGroupByResultToMap(t *testing.T) {
+	rows := GroupByResult[string, int]{{Key: "a", Value: 1}, {Key: "b", Value: 2}}
+	m := rows.ToMap()
+	if len(m) != 2 || m["a"] != 1 || m["b"] != 2 {
+		t.Errorf("Unexpected map %v", m)
+	}
+	if m := (GroupByResult[string, int]{}).ToMap(); m == nil || len(m) != 0 {
+		t.Errorf("Expected an empty map, got %v", m)
+	}
+}
+
+func TestGroupByQuery(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	sql := groupByQuery[TestModel](db, "age / 10 * 10", "COUNT(*)", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("active = ?", true)
+	}).String()
+	want := `SELECT age / 10 * 10 AS key, COUNT(*) AS value FROM "test_models" AS "tm" WHERE (active = TRUE) GROUP BY age / 10 * 10`
+	if sql != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", sql, want)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 25) // ages 0..24
+
+	counts, err := GroupBy[TestModel, int, int64](ctx, db, "age / 10 * 10", "COUNT(*)", nil)
+	if err != nil {
+		t.Fatalf("GroupBy failed: %v", err)
+	}
+	want := map[int]int64{0: 10, 10: 10, 20: 5}
+	if len(counts) != len(want) {
+		t.Fatalf("Expected %d brackets, got %v", len(want), counts)
+	}
+	for bracket, n := range want {
+		if counts[bracket] != n {
+			t.Errorf("Bracket %d: expected %d, got %d", bracket, n, counts[bracket])
+		}
+	}
+
+	active, err := GroupBy[TestModel, int, int64](ctx, db, "age / 10 * 10", "COUNT(*)", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("active = ?", true)
+	})
+	if err != nil {
+		t.Fatalf("GroupBy with filter failed: %v", err)
+	}
+	if active[0] != 5 || active[10] != 5 || active[20] != 3 {
+		t.Errorf("Unexpected active counts %v", active)
+	}
+}
+
+func TestGroupByOrdered(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 25)
+
+	rows, err := GroupByOrdered[TestModel, int, int64](ctx, db, "age / 10 * 10", "COUNT(*)", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.OrderExpr("value ASC, key ASC")
+	})
+	if err != nil {
+		t.Fatalf("GroupByOrdered failed: %v", err)
+	}
+	if len(rows) != 3 || rows[0].Key != 20 || rows[0].Value != 5 || rows[1].Key != 0 {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+}
+
+func TestGroupByMulti(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 20)
+
+	rows, err := GroupByMulti[TestModel](ctx, db, []string{"active", "age / 10 * 10 AS bracket"}, "COUNT(*)", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.OrderExpr("bracket ASC, active ASC")
+	})
+	if err != nil {
+		t.Fatalf("GroupByMulti failed: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 groups, got %v", rows)
+	}
+	for _, row := range rows {
+		if _, ok := row["active"]; !ok {
+			t.Errorf("Missing active column in %v", row)
+		}
+		if row["value"] != int64(5) {
+			t.Errorf("Expected 5 users per group, got %v", row)
+		}
+	}
+}
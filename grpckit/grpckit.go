@@ -0,0 +1,120 @@
+// Package grpckit runs gRPC unary calls in dbkit transactions.
+//
+// The package does not import google.golang.org/grpc. The interceptor uses function types with
+// the same shape as grpc.UnaryServerInterceptor, adapted in one line when the server is created:
+//
+//	interceptor := grpckit.NewTransactionInterceptor(db)
+//	server := grpc.NewServer(grpc.UnaryInterceptor(
+//	    func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+//	        return interceptor(ctx, req, info.FullMethod, grpckit.UnaryHandler(handler))
+//	    },
+//	))
+package grpckit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+// UnaryHandler has the signature of grpc.UnaryHandler
+type UnaryHandler func(ctx context.Context, req any) (any, error)
+
+// UnaryInterceptor is a grpc.UnaryServerInterceptor that receives the full method name
+// instead of *grpc.UnaryServerInfo
+type UnaryInterceptor func(ctx context.Context, req any, fullMethod string, handler UnaryHandler) (any, error)
+
+type txContextKey struct{}
+type dbContextKey struct{}
+type skipContextKey struct{}
+
+// NewTransactionInterceptor returns an interceptor that runs each call in a transaction,
+// committed when the handler succeeds and rolled back when it returns an error or panics.
+// The first of opts, if any, configures the transaction.
+// Calls whose context is marked with SkipTransaction run without one.
+//
+// Usage:
+//
+//	interceptor := grpckit.NewTransactionInterceptor(db, dbkit.SerializableTxOptions())
+func NewTransactionInterceptor(db *dbkit.DBKit, opts ...dbkit.TxOptions) UnaryInterceptor {
+	txOpts := dbkit.DefaultTxOptions()
+	if len(opts) > 0 {
+		txOpts = opts[0]
+	}
+
+	return func(ctx context.Context, req any, fullMethod string, handler UnaryHandler) (resp any, err error) {
+		ctx = context.WithValue(ctx, dbContextKey{}, db)
+		if skipped(ctx) {
+			return handler(ctx, req)
+		}
+
+		tx, err := db.BeginWithOptions(ctx, txOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				panic(p)
+			}
+		}()
+
+		resp, err = handler(context.WithValue(ctx, txContextKey{}, tx), req)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return nil, fmt.Errorf("grpckit: rollback failed for %s: %v (original error: %w)", fullMethod, rbErr, err)
+			}
+			return nil, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// TxFromGRPCContext returns the transaction started by the interceptor, if any.
+//
+// Usage:
+//
+//	tx, ok := grpckit.TxFromGRPCContext(ctx)
+func TxFromGRPCContext(ctx context.Context) (*dbkit.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*dbkit.Tx)
+	return tx, ok
+}
+
+// DBFromGRPCContext returns the transaction of the call, or the DBKit when the call
+// runs without one. Returns false outside the interceptor.
+//
+// Usage:
+//
+//	db, _ := grpckit.DBFromGRPCContext(ctx)
+//	err := db.NewSelect().Model(&users).Scan(ctx)
+func DBFromGRPCContext(ctx context.Context) (dbkit.IDB, bool) {
+	if tx, ok := TxFromGRPCContext(ctx); ok {
+		return tx, true
+	}
+	db, ok := ctx.Value(dbContextKey{}).(*dbkit.DBKit)
+	return db, ok
+}
+
+// SkipTransaction marks ctx so the interceptor runs the call without a transaction.
+// Set it in an interceptor that runs before the transaction interceptor, e.g. for read-only methods.
+//
+// Usage:
+//
+//	if strings.HasPrefix(info.FullMethod, "/users.Users/Get") {
+//	    ctx = grpckit.SkipTransaction(ctx)
+//	}
+func SkipTransaction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipContextKey{}, true)
+}
+
+// skipped reports whether ctx was marked with SkipTransaction.
+func skipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipContextKey{}).(bool)
+	return skip
+}
@@ -3,15 +3,32 @@ package dbkit
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
 )
 
 // HealthStatus represents the database health status
 type HealthStatus struct {
-	Healthy   bool          `json:"healthy"`
-	Latency   time.Duration `json:"latency"`
-	Error     string        `json:"error,omitempty"`
-	PoolStats PoolStats     `json:"pool_stats"`
+	Healthy   bool                   `json:"healthy"`
+	Latency   time.Duration          `json:"latency"`
+	Error     string                 `json:"error,omitempty"`
+	PoolStats PoolStats              `json:"pool_stats"`
+	Circuit   string                 `json:"circuit,omitempty"`   // Circuit breaker state, if installed
+	PgBouncer bool                   `json:"pgbouncer,omitempty"` // Running behind pgBouncer in transaction mode
+	Checks    map[string]CheckResult `json:"checks,omitempty"`    // Results of the checks added with AddHealthCheck
+}
+
+// CheckResult is the outcome of a named health check
+type CheckResult struct {
+	Healthy bool          `json:"healthy"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// HealthOptions configures HealthWithOptions
+type HealthOptions struct {
+	ProbeQuery   string        // Query run instead of a ping, e.g. "SELECT 1 FROM orders LIMIT 1"
+	ProbeTimeout time.Duration // Deadline of the probe and of each named check (0 = deadline of ctx)
 }
 
 // PoolStats contains connection pool statistics
@@ -29,37 +46,126 @@ type PoolStats struct {
 
 // Health performs a health check with detailed status
 func (db *DBKit) Health(ctx context.Context) HealthStatus {
-	start := time.Now()
+	return db.HealthWithOptions(ctx, HealthOptions{})
+}
 
-	err := db.Ping(ctx)
+// HealthWithOptions performs a health check with a custom probe. The database is healthy
+// when the probe and every check added with AddHealthCheck succeed.
+//
+// Usage:
+//
+//	status := db.HealthWithOptions(ctx, dbkit.HealthOptions{
+//	    ProbeQuery:   "SELECT 1 FROM orders LIMIT 1",
+//	    ProbeTimeout: time.Second,
+//	})
+func (db *DBKit) HealthWithOptions(ctx context.Context, opts HealthOptions) HealthStatus {
+	start := time.Now()
+	err := runHealthCheck(ctx, opts.ProbeTimeout, func(ctx context.Context) error {
+		if opts.ProbeQuery != "" {
+			_, err := db.ExecContext(ctx, opts.ProbeQuery)
+			return err
+		}
+		return db.Ping(ctx)
+	})
 	latency := time.Since(start)
 
-	stats := db.Stats()
-	poolStats := PoolStats{
-		MaxOpenConnections: stats.MaxOpenConnections,
-		OpenConnections:    stats.OpenConnections,
-		InUse:              stats.InUse,
-		Idle:               stats.Idle,
-		WaitCount:          stats.WaitCount,
-		WaitDuration:       stats.WaitDuration,
-		MaxIdleClosed:      stats.MaxIdleClosed,
-		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
-		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
-	}
-
 	status := HealthStatus{
 		Healthy:   err == nil,
 		Latency:   latency,
-		PoolStats: poolStats,
+		PoolStats: PoolStatsFromSQL(db.Stats()),
+		PgBouncer: db.IsPgBouncer(),
 	}
 
 	if err != nil {
 		status.Error = err.Error()
 	}
 
+	if db.circuitBreaker() != nil {
+		state := db.BreakerState()
+		status.Circuit = state.String()
+		if state != CircuitClosed {
+			status.Healthy = false
+		}
+	}
+
+	if checks := db.runHealthChecks(ctx, opts.ProbeTimeout); len(checks) > 0 {
+		status.Checks = checks
+		for _, check := range checks {
+			if !check.Healthy {
+				status.Healthy = false
+			}
+		}
+	}
+
 	return status
 }
 
+// AddHealthCheck registers a named check run by Health and HealthWithOptions.
+// Adding a check with an existing name replaces it.
+//
+// Usage:
+//
+//	db.AddHealthCheck("replication_lag", func(ctx context.Context) error {
+//	    var lag float64
+//	    err := db.NewRaw("SELECT EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())").Scan(ctx, &lag)
+//	    if err == nil && lag > 30 {
+//	        err = fmt.Errorf("replica is %.0fs behind", lag)
+//	    }
+//	    return err
+//	})
+func (db *DBKit) AddHealthCheck(name string, fn func(ctx context.Context) error) {
+	db.healthMu.Lock()
+	defer db.healthMu.Unlock()
+	if db.healthChecks == nil {
+		db.healthChecks = make(map[string]func(ctx context.Context) error)
+	}
+	db.healthChecks[name] = fn
+}
+
+// runHealthChecks runs the named checks concurrently
+func (db *DBKit) runHealthChecks(ctx context.Context, timeout time.Duration) map[string]CheckResult {
+	db.healthMu.RLock()
+	checks := make(map[string]func(ctx context.Context) error, len(db.healthChecks))
+	for name, fn := range db.healthChecks {
+		checks[name] = fn
+	}
+	db.healthMu.RUnlock()
+	if len(checks) == 0 {
+		return nil
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]CheckResult, len(checks))
+	for name, fn := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := runHealthCheck(ctx, timeout, fn)
+			result := CheckResult{Healthy: err == nil, Latency: time.Since(start)}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runHealthCheck runs fn with a child context bounded by timeout, if set
+func runHealthCheck(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
+
 // IsHealthy returns true if the database is reachable
 func (db *DBKit) IsHealthy(ctx context.Context) bool {
 	return db.Ping(ctx) == nil
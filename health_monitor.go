@@ -0,0 +1,160 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthMonitor samples db.Health periodically and keeps the latest results for dashboards.
+type HealthMonitor struct {
+	db       *DBKit
+	interval time.Duration
+
+	mu          sync.RWMutex
+	history     []*HealthStatus // Ring buffer
+	next        int             // Position of the next sample in history
+	count       int             // Samples stored, up to len(history)
+	subscribers []chan *HealthStatus
+	stopped     bool
+}
+
+// NewHealthMonitor creates a monitor sampling every interval (default: 10s) and keeping the last
+// historySize samples (default: 60). Call Start to begin sampling.
+//
+// Usage:
+//
+//	monitor := dbkit.NewHealthMonitor(db, 10*time.Second, 360)
+//	monitor.Start(ctx)
+//	log.Printf("availability %.2f%%, avg latency %v", monitor.Availability()*100, monitor.AvgLatency())
+func NewHealthMonitor(db *DBKit, interval time.Duration, historySize int) *HealthMonitor {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if historySize <= 0 {
+		historySize = 60
+	}
+	return &HealthMonitor{
+		db:       db,
+		interval: interval,
+		history:  make([]*HealthStatus, historySize),
+	}
+}
+
+// Start samples in a background goroutine until ctx is cancelled, which also closes the
+// subscription channels.
+func (hm *HealthMonitor) Start(ctx context.Context) {
+	go hm.run(ctx)
+}
+
+func (hm *HealthMonitor) run(ctx context.Context) {
+	defer hm.stop()
+
+	ticker := time.NewTicker(hm.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			status := hm.db.Health(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			hm.record(&status)
+		}
+	}
+}
+
+// record stores a sample and notifies subscribers, skipping those that aren't keeping up
+func (hm *HealthMonitor) record(status *HealthStatus) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	hm.history[hm.next] = status
+	hm.next = (hm.next + 1) % len(hm.history)
+	if hm.count < len(hm.history) {
+		hm.count++
+	}
+
+	for _, ch := range hm.subscribers {
+		select {
+		case ch <- status:
+		default:
+		}
+	}
+}
+
+func (hm *HealthMonitor) stop() {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.stopped = true
+	for _, ch := range hm.subscribers {
+		close(ch)
+	}
+	hm.subscribers = nil
+}
+
+// History returns the stored samples, oldest first.
+func (hm *HealthMonitor) History() []*HealthStatus {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	history := make([]*HealthStatus, 0, hm.count)
+	start := (hm.next - hm.count + len(hm.history)) % len(hm.history)
+	for i := range hm.count {
+		history = append(history, hm.history[(start+i)%len(hm.history)])
+	}
+	return history
+}
+
+// AvgLatency returns the mean probe latency of the stored samples, or 0 without samples.
+func (hm *HealthMonitor) AvgLatency() time.Duration {
+	history := hm.History()
+	if len(history) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, status := range history {
+		total += status.Latency
+	}
+	return total / time.Duration(len(history))
+}
+
+// Availability returns the fraction of healthy samples, or 0 without samples.
+func (hm *HealthMonitor) Availability() float64 {
+	history := hm.History()
+	if len(history) == 0 {
+		return 0
+	}
+	healthy := 0
+	for _, status := range history {
+		if status.Healthy {
+			healthy++
+		}
+	}
+	return float64(healthy) / float64(len(history))
+}
+
+// Subscribe returns a channel receiving each new sample. Samples are dropped for
+// subscribers that aren't keeping up. The channel is closed when the monitor stops.
+//
+// Usage:
+//
+//	for status := range monitor.Subscribe() {
+//	    if !status.Healthy {
+//	        alert(status.Error)
+//	    }
+//	}
+func (hm *HealthMonitor) Subscribe() <-chan *HealthStatus {
+	ch := make(chan *HealthStatus, 1)
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	if hm.stopped {
+		close(ch)
+		return ch
+	}
+	hm.subscribers = append(hm.subscribers, ch)
+	return ch
+}
@@ -0,0 +1,73 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHealthMonitor_History(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	monitor := NewHealthMonitor(db, 5*time.Millisecond, 5)
+	samples := monitor.Subscribe()
+	monitor.Start(ctx)
+
+	for range 5 {
+		select {
+		case <-samples:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for a sample")
+		}
+	}
+
+	if n := len(monitor.History()); n != 5 {
+		t.Errorf("Expected 5 samples, got %d", n)
+	}
+	if a := monitor.Availability(); a != 1.0 {
+		t.Errorf("Expected availability 1.0, got %v", a)
+	}
+	if monitor.AvgLatency() <= 0 {
+		t.Error("Expected a positive average latency")
+	}
+
+	cancel()
+	closed := make(chan struct{})
+	go func() {
+		for range samples {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the subscription to be closed when the context is cancelled")
+	}
+	if _, ok := <-monitor.Subscribe(); ok {
+		t.Error("Expected subscriptions after stop to be closed")
+	}
+}
+
+func TestHealthMonitor_RingBuffer(t *testing.T) {
+	monitor := NewHealthMonitor(nil, time.Second, 3)
+	if monitor.Availability() != 0 || monitor.AvgLatency() != 0 {
+		t.Error("Expected zero values without samples")
+	}
+
+	for i := 1; i <= 5; i++ {
+		monitor.record(&HealthStatus{Healthy: i != 4, Latency: time.Duration(i) * time.Millisecond})
+	}
+
+	history := monitor.History()
+	if len(history) != 3 || history[0].Latency != 3*time.Millisecond || history[2].Latency != 5*time.Millisecond {
+		t.Fatalf("Expected the last 3 samples oldest first, got %v", history)
+	}
+	if got := monitor.AvgLatency(); got != 4*time.Millisecond {
+		t.Errorf("Expected 4ms average latency, got %v", got)
+	}
+	if got := monitor.Availability(); got < 0.66 || got > 0.67 {
+		t.Errorf("Expected availability 2/3, got %v", got)
+	}
+}
@@ -321,3 +321,77 @@ func createTable(t *testing.T, db *DBKit) context.Context {
 	_, _ = db.NewDelete().Model((*TestModel)(nil)).Where("1=1").Exec(ctx)
 	return ctx
 }
+
+func TestHealth_CustomChecks(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	if status := db.Health(ctx); !status.Healthy || status.Checks != nil {
+		t.Fatalf("Expected healthy status without checks, got %+v", status)
+	}
+
+	db.AddHealthCheck("cache", func(ctx context.Context) error { return nil })
+	db.AddHealthCheck("queue", func(ctx context.Context) error { return fmt.Errorf("queue unreachable") })
+
+	status := db.Health(ctx)
+	if status.Healthy {
+		t.Error("Expected a failing check to make the status unhealthy")
+	}
+	if status.Error != "" {
+		t.Errorf("Expected the ping to succeed, got %s", status.Error)
+	}
+	if c := status.Checks["cache"]; !c.Healthy || c.Error != "" {
+		t.Errorf("Expected cache check to pass, got %+v", c)
+	}
+	if c := status.Checks["queue"]; c.Healthy || c.Error != "queue unreachable" {
+		t.Errorf("Expected queue check to fail, got %+v", c)
+	}
+
+	// Replacing the failing check restores health
+	db.AddHealthCheck("queue", func(ctx context.Context) error { return nil })
+	if status := db.Health(ctx); !status.Healthy || len(status.Checks) != 2 {
+		t.Errorf("Expected healthy status with 2 checks, got %+v", status)
+	}
+}
+
+func TestHealthWithOptions_ProbeQuery(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	var deadline bool
+	db.AddHealthCheck("deadline", func(ctx context.Context) error {
+		_, deadline = ctx.Deadline()
+		return nil
+	})
+
+	status := db.HealthWithOptions(context.Background(), HealthOptions{
+		ProbeQuery:   "SELECT 1 FROM orders LIMIT 1",
+		ProbeTimeout: time.Second,
+	})
+	if !status.Healthy || status.Latency <= 0 {
+		t.Errorf("Expected healthy status with latency, got %+v", status)
+	}
+	if hook.last != "SELECT 1 FROM orders LIMIT 1" {
+		t.Errorf("Expected the probe query to run, got %q", hook.last)
+	}
+	if !deadline {
+		t.Error("Expected checks to run with the probe timeout")
+	}
+}
+
+func TestHealthWithOptions_ProbeTimeout(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	status := db.HealthWithOptions(context.Background(), HealthOptions{
+		ProbeQuery:   "SELECT pg_sleep(1)",
+		ProbeTimeout: 50 * time.Millisecond,
+	})
+	if status.Healthy || status.Error == "" {
+		t.Errorf("Expected the probe to time out, got %+v", status)
+	}
+	if status.Latency > 500*time.Millisecond {
+		t.Errorf("Expected the probe to stop at its timeout, took %v", status.Latency)
+	}
+}
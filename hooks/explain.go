@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// DefaultPlanCacheTTL is how long an explained plan is reused for the same normalized query
+const DefaultPlanCacheTTL = time.Minute
+
+// DefaultExplainTimeout bounds how long explaining a slow query may delay its caller
+const DefaultExplainTimeout = 500 * time.Millisecond
+
+// lockingClause matches the row locking clauses of SELECT ... FOR UPDATE and friends
+var lockingClause = regexp.MustCompile(`(?i)\bFOR\s+(UPDATE|SHARE|NO\s+KEY\s+UPDATE|KEY\s+SHARE)\b`)
+
+// IsPlainSelect reports whether query is a SELECT that takes no row locks, the only queries
+// worth explaining from a hook: writes and locking reads may block on the caller's transaction
+func IsPlainSelect(query string) bool {
+	return OperationType(query) == "select" && !lockingClause.MatchString(query)
+}
+
+// ExplainFunc returns the EXPLAIN plan of an already formatted query
+type ExplainFunc func(ctx context.Context, query string) (json.RawMessage, error)
+
+// PlanCache remembers plans by normalized query so the same query is not explained repeatedly
+type PlanCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	plans map[string]cachedPlan
+}
+
+type cachedPlan struct {
+	plan      json.RawMessage
+	expiresAt time.Time
+}
+
+// NewPlanCache creates a plan cache that keeps plans for ttl
+func NewPlanCache(ttl time.Duration) *PlanCache {
+	if ttl <= 0 {
+		ttl = DefaultPlanCacheTTL
+	}
+	return &PlanCache{
+		ttl:   ttl,
+		plans: make(map[string]cachedPlan),
+	}
+}
+
+// Get returns the cached plan for the query, if it has not expired
+func (c *PlanCache) Get(query string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.plans[NormalizeQuery(query)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.plan, true
+}
+
+// Set stores the plan for the query, dropping expired entries
+func (c *PlanCache) Set(query string, plan json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range c.plans {
+		if now.After(entry.expiresAt) {
+			delete(c.plans, key)
+		}
+	}
+	c.plans[NormalizeQuery(query)] = cachedPlan{plan: plan, expiresAt: now.Add(c.ttl)}
+}
@@ -3,18 +3,34 @@ package hooks
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
 )
 
+// DefaultMaxParamLength is the length long string parameters are truncated to
+const DefaultMaxParamLength = 100
+
 // LoggerHook implements query logging
 type LoggerHook struct {
+	// Parameter logging, off by default as values may contain personal data
+	LogParameters         bool  // Add the query parameters as a JSON array in the params attribute
+	SensitiveParamIndexes []int // Zero-based parameter positions logged as "[REDACTED]", per row for multi-row models
+	MaxParamLength        int   // Length long string parameters are truncated to (default: 100)
+
 	logger        *slog.Logger
 	logAll        bool
 	slowThreshold time.Duration
+
+	explain          ExplainFunc
+	explainThreshold time.Duration
+	plans            *PlanCache
 }
 
 // NewLoggerHook creates a new logger hook
@@ -26,6 +42,15 @@ func NewLoggerHook(logger *slog.Logger, logAll bool, slowThreshold time.Duration
 	}
 }
 
+// WithExplain attaches the EXPLAIN plan to the log entry of queries slower than threshold.
+// Plans are cached per normalized query for DefaultPlanCacheTTL.
+func (h *LoggerHook) WithExplain(threshold time.Duration, explain ExplainFunc) *LoggerHook {
+	h.explain = explain
+	h.explainThreshold = threshold
+	h.plans = NewPlanCache(DefaultPlanCacheTTL)
+	return h
+}
+
 // BeforeQuery is called before a query is executed
 func (h *LoggerHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
 	return ctx
@@ -34,9 +59,11 @@ func (h *LoggerHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) con
 // AfterQuery is called after a query is executed
 func (h *LoggerHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	duration := time.Since(event.StartTime)
+	slow := h.slowThreshold > 0 && duration >= h.slowThreshold
+	explain := h.explain != nil && event.Err == nil && duration >= h.explainThreshold
 
 	// Skip if not logging all and not slow
-	if !h.logAll && (h.slowThreshold == 0 || duration < h.slowThreshold) {
+	if !h.logAll && !slow && !explain {
 		return
 	}
 
@@ -53,18 +80,135 @@ func (h *LoggerHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	if h.logAll {
 		attrs = append(attrs, slog.String("query", query))
 	}
+	if h.LogParameters && (h.logAll || slow || explain) {
+		if params := h.params(event); params != nil {
+			attrs = append(attrs, slog.Any("params", params))
+		}
+	}
 
 	if event.Err != nil {
 		attrs = append(attrs, slog.String("error", event.Err.Error()))
 		h.logger.LogAttrs(ctx, slog.LevelError, "database query failed", attrs...)
-	} else if h.slowThreshold > 0 && duration >= h.slowThreshold {
+	} else if slow || explain {
 		attrs = append(attrs, slog.String("query", query))
+		if explain {
+			if plan, ok := h.plan(ctx, event.Query); ok {
+				attrs = append(attrs, slog.Any("plan", plan))
+			}
+		}
 		h.logger.LogAttrs(ctx, slog.LevelWarn, "slow database query", attrs...)
 	} else if h.logAll {
 		h.logger.LogAttrs(ctx, slog.LevelDebug, "database query", attrs...)
 	}
 }
 
+// params returns the parameters of event as a JSON array, redacted and truncated, or nil if there are none
+func (h *LoggerHook) params(event *bun.QueryEvent) json.RawMessage {
+	values, width := queryParameters(event)
+	if len(values) == 0 {
+		return nil
+	}
+
+	maxLen := h.MaxParamLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxParamLength
+	}
+
+	out := make([]json.RawMessage, len(values))
+	for i, v := range values {
+		// Model parameters repeat for each row, the indexes apply to the columns of every row
+		if slices.Contains(h.SensitiveParamIndexes, i%width) {
+			v = "[REDACTED]"
+		}
+		switch s := v.(type) {
+		case string:
+			if len(s) > maxLen {
+				v = s[:maxLen] + "..."
+			}
+		case []byte:
+			if len(s) > maxLen {
+				v = string(s[:maxLen]) + "..."
+			} else {
+				v = string(s)
+			}
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			b, _ = json.Marshal(fmt.Sprint(v))
+		}
+		out[i] = b
+	}
+
+	b, _ := json.Marshal(out)
+	return b
+}
+
+// queryParameters returns the arguments of raw queries, or the column values of the model
+// of insert and update queries, in table column order and row after row.
+// width is the number of parameters per row: the column count, or all arguments of raw queries.
+func queryParameters(event *bun.QueryEvent) (params []any, width int) {
+	if len(event.QueryArgs) > 0 {
+		return event.QueryArgs, len(event.QueryArgs)
+	}
+
+	var model bun.Model
+	switch q := event.IQuery.(type) {
+	case *bun.InsertQuery:
+		model = q.GetModel()
+	case *bun.UpdateQuery:
+		model = q.GetModel()
+	}
+	tm, ok := model.(bun.TableModel)
+	if !ok {
+		return nil, 0
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(tm.Value()))
+	var rows []reflect.Value
+	switch v.Kind() {
+	case reflect.Struct:
+		rows = []reflect.Value{v}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			rows = append(rows, reflect.Indirect(v.Index(i)))
+		}
+	default:
+		return nil, 0
+	}
+
+	fields := tm.Table().Fields
+	for _, row := range rows {
+		for _, f := range fields {
+			params = append(params, f.Value(row).Interface())
+		}
+	}
+	return params, len(fields)
+}
+
+// plan returns the cached or freshly explained plan for a plain SELECT.
+// Explaining is bounded by DefaultExplainTimeout so a busy pool does not stall the caller.
+func (h *LoggerHook) plan(ctx context.Context, query string) (json.RawMessage, bool) {
+	if !IsPlainSelect(query) {
+		return nil, false
+	}
+
+	if plan, ok := h.plans.Get(query); ok {
+		return plan, true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultExplainTimeout)
+	defer cancel()
+
+	plan, err := h.explain(ctx, query)
+	if err != nil {
+		h.logger.LogAttrs(ctx, slog.LevelDebug, "database explain failed", slog.String("error", err.Error()))
+		return nil, false
+	}
+	h.plans.Set(query, plan)
+	return plan, true
+}
+
 // OperationType extracts the operation type from a query
 func OperationType(query string) string {
 	query = strings.TrimSpace(strings.ToUpper(query))
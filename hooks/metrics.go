@@ -2,36 +2,93 @@ package hooks
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/uptrace/bun"
 )
 
+// DefaultMetricsMaxTables is the default number of distinct table label values
+const DefaultMetricsMaxTables = 100
+
+// DefaultQueryDurationBuckets are the dbkit_query_duration_seconds buckets used when none are configured
+var DefaultQueryDurationBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultMetricsMaxFingerprints is the default number of distinct query label values
+const DefaultMetricsMaxFingerprints = 100
+
+// OtherTable is the table label used for excluded, unknown or overflowing tables
+const OtherTable = "other"
+
+// OtherFingerprint is the query label used once MaxFingerprints is reached
+const OtherFingerprint = "other"
+
+// MetricsHookOptions limits the cardinality of the table and query labels and sets the histogram buckets
+type MetricsHookOptions struct {
+	QueryDurationBuckets []float64 // Strictly increasing positive bounds in seconds (default: DefaultQueryDurationBuckets)
+
+	ExcludeTables []string // Tables always reported as "other"
+	MaxTables     int      // Distinct tables labeled before the rest fall into "other" (default: 100)
+
+	EnableQueryFingerprint bool // Add a query label holding FingerprintQuery of the statement
+	MaxFingerprints        int  // Distinct fingerprints labeled before the rest fall into "other" (default: 100)
+}
+
 // MetricsHook implements Prometheus metrics collection
 type MetricsHook struct {
 	queryDuration *prometheus.HistogramVec
 	queryTotal    *prometheus.CounterVec
 	queryErrors   *prometheus.CounterVec
+
+	opts         MetricsHookOptions
+	tables       *labelLimiter
+	fingerprints *labelLimiter
 }
 
 // NewMetricsHook creates a new metrics hook and registers collectors
-func NewMetricsHook(registry prometheus.Registerer) (*MetricsHook, error) {
+func NewMetricsHook(registry prometheus.Registerer, opts MetricsHookOptions) (*MetricsHook, error) {
+	if opts.MaxTables <= 0 {
+		opts.MaxTables = DefaultMetricsMaxTables
+	}
+	if opts.MaxFingerprints <= 0 {
+		opts.MaxFingerprints = DefaultMetricsMaxFingerprints
+	}
+	if opts.QueryDurationBuckets == nil {
+		opts.QueryDurationBuckets = DefaultQueryDurationBuckets
+	}
+	if err := validateBuckets(opts.QueryDurationBuckets); err != nil {
+		return nil, err
+	}
+
+	labels := []string{"operation", "table"}
+	if opts.EnableQueryFingerprint {
+		labels = append(labels, "query")
+	}
+
 	h := &MetricsHook{
+		opts:         opts,
+		tables:       newLabelLimiter(opts.MaxTables),
+		fingerprints: newLabelLimiter(opts.MaxFingerprints),
 		queryDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "dbkit_query_duration_seconds",
 				Help:    "Duration of database queries in seconds",
-				Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+				Buckets: opts.QueryDurationBuckets,
 			},
-			[]string{"operation"},
+			labels,
 		),
 		queryTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "dbkit_queries_total",
 				Help: "Total number of database queries",
 			},
-			[]string{"operation"},
+			labels,
 		),
 		queryErrors: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -65,11 +122,108 @@ func (h *MetricsHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) co
 func (h *MetricsHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	duration := time.Since(event.StartTime).Seconds()
 	op := OperationType(event.Query)
+	labels := []string{op, h.tableLabel(QueryTable(event.IQuery))}
+	if h.opts.EnableQueryFingerprint {
+		labels = append(labels, h.fingerprints.label(FingerprintQuery(event.Query), OtherFingerprint))
+	}
 
-	h.queryDuration.WithLabelValues(op).Observe(duration)
-	h.queryTotal.WithLabelValues(op).Inc()
+	h.queryDuration.WithLabelValues(labels...).Observe(duration)
+	h.queryTotal.WithLabelValues(labels...).Inc()
 
 	if event.Err != nil {
 		h.queryErrors.WithLabelValues(op).Inc()
 	}
 }
+
+// validateBuckets checks that histogram buckets are non-empty, positive and strictly increasing
+func validateBuckets(buckets []float64) error {
+	if len(buckets) == 0 {
+		return errors.New("histogram buckets must not be empty")
+	}
+	for i, b := range buckets {
+		if b <= 0 {
+			return fmt.Errorf("histogram bucket %v must be positive", b)
+		}
+		if i > 0 && b <= buckets[i-1] {
+			return fmt.Errorf("histogram buckets must be strictly increasing, %v follows %v", b, buckets[i-1])
+		}
+	}
+	return nil
+}
+
+// tableLabel returns the label value for table, admitting new tables until MaxTables is reached
+func (h *MetricsHook) tableLabel(table string) string {
+	if table == "" || slices.Contains(h.opts.ExcludeTables, table) {
+		return OtherTable
+	}
+	return h.tables.label(table, OtherTable)
+}
+
+// labelLimiter caps the distinct values of a label, admitting the first max values seen
+type labelLimiter struct {
+	max    int
+	mu     sync.RWMutex
+	values map[string]struct{}
+}
+
+func newLabelLimiter(limit int) *labelLimiter {
+	return &labelLimiter{max: limit, values: make(map[string]struct{})}
+}
+
+// label returns value if it is admitted, or other once the limit is reached
+func (l *labelLimiter) label(value, other string) string {
+	l.mu.RLock()
+	_, ok := l.values[value]
+	l.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.values[value]; ok {
+		return value
+	}
+	if len(l.values) >= l.max {
+		return other
+	}
+	l.values[value] = struct{}{}
+	return value
+}
+
+var (
+	uuidLiteral = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	valueList   = regexp.MustCompile(`\(\?(?:, \?)*\)(?:, \(\?(?:, \?)*\))*`)
+)
+
+// FingerprintQuery returns the structure of sql with literals, placeholders and UUIDs replaced by "?".
+// Lists of values collapse to a single "(?)" so IN lists and multi-row inserts of any length match.
+//
+// Usage:
+//
+//	hooks.FingerprintQuery("SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'bob'")
+//	// SELECT * FROM users WHERE id IN (?) AND name = ?
+func FingerprintQuery(sql string) string {
+	sql = uuidLiteral.ReplaceAllString(sql, "?")
+	return valueList.ReplaceAllString(NormalizeQuery(sql), "(?)")
+}
+
+// QueryTable returns the unquoted table name of a bun query, or "" for raw queries
+func QueryTable(query bun.Query) string {
+	var table string
+	switch q := query.(type) {
+	case *bun.SelectQuery:
+		table = q.GetTableName()
+	case *bun.InsertQuery:
+		table = q.GetTableName()
+	case *bun.UpdateQuery:
+		table = q.GetTableName()
+	case *bun.DeleteQuery:
+		table = q.GetTableName()
+	case *bun.MergeQuery:
+		table = q.GetTableName()
+	case *bun.TruncateTableQuery:
+		table = q.GetTableName()
+	}
+	return strings.ReplaceAll(table, `"`, "")
+}
@@ -0,0 +1,157 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// DefaultN1WindowMs is the default N+1 detection window in milliseconds
+const DefaultN1WindowMs = 1000
+
+// N1DetectorHook reports query patterns repeated more than a threshold within a time window,
+// which usually indicates an N+1 query problem. Intended for development.
+type N1DetectorHook struct {
+	// WindowMs is the window in milliseconds in which repeated queries are counted
+	WindowMs int
+
+	threshold int
+	handler   func(pattern string, count int)
+
+	mu      sync.Mutex
+	windows map[string]*n1Window
+}
+
+type n1Window struct {
+	start time.Time
+	count int
+}
+
+// NewN1DetectorHook creates a new N+1 detector hook.
+// handler is called with the normalized query and its count as soon as a pattern occurs more
+// than threshold times within a window, once per window. A nil handler or non-positive
+// threshold disables the hook.
+func NewN1DetectorHook(threshold int, handler func(pattern string, count int)) *N1DetectorHook {
+	return &N1DetectorHook{
+		WindowMs:  DefaultN1WindowMs,
+		threshold: threshold,
+		handler:   handler,
+		windows:   make(map[string]*n1Window),
+	}
+}
+
+func (h *N1DetectorHook) enabled() bool {
+	return h != nil && h.handler != nil && h.threshold > 0
+}
+
+// BeforeQuery is called before a query is executed
+func (h *N1DetectorHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery counts the query and reports its pattern when the count crosses the threshold
+func (h *N1DetectorHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if !h.enabled() {
+		return
+	}
+
+	pattern := NormalizeQuery(event.Query)
+	now := time.Now()
+	window := time.Duration(h.WindowMs) * time.Millisecond
+
+	h.mu.Lock()
+	for p, w := range h.windows {
+		if now.Sub(w.start) > window {
+			delete(h.windows, p)
+		}
+	}
+	w, ok := h.windows[pattern]
+	if !ok {
+		w = &n1Window{start: now}
+		h.windows[pattern] = w
+	}
+	w.count++
+	count := w.count
+	h.mu.Unlock()
+
+	// Call the handler outside the lock, it may run queries
+	if count == h.threshold+1 {
+		h.handler(pattern, count)
+	}
+}
+
+// Flush closes all open windows, so the next queries are counted from zero.
+// Call it at request boundaries to keep the repetitions of one request from adding to the next.
+func (h *N1DetectorHook) Flush() {
+	if !h.enabled() {
+		return
+	}
+
+	h.mu.Lock()
+	clear(h.windows)
+	h.mu.Unlock()
+}
+
+// NormalizeQuery returns the query fingerprint used to group queries: quoted strings,
+// numbers and placeholders are replaced with "?" and whitespace is collapsed.
+//
+// Usage:
+//
+//	hooks.NormalizeQuery("SELECT * FROM users WHERE id = 42 AND name = 'bob'")
+//	// SELECT * FROM users WHERE id = ? AND name = ?
+func NormalizeQuery(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	space := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			continue
+		case c == '\'':
+			// Quoted string, '' is an escaped quote
+			for i++; i < len(sql); i++ {
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			c = '?'
+		case c == '$' && i+1 < len(sql) && isDigit(sql[i+1]):
+			for i+1 < len(sql) && isDigit(sql[i+1]) {
+				i++
+			}
+			c = '?'
+		case isDigit(c) && (i == 0 || !isIdentChar(sql[i-1])):
+			for i+1 < len(sql) && (isDigit(sql[i+1]) || sql[i+1] == '.') {
+				i++
+			}
+			c = '?'
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '"' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uptrace/bun"
+)
+
+// PoolWaitHookOptions configures the connection wait metrics
+type PoolWaitHookOptions struct {
+	ExhaustionThreshold time.Duration // Waits longer than this count as pool exhaustion (default: 100ms)
+	Buckets             []float64     // Histogram buckets in seconds (default: .0001 to 5)
+}
+
+// defaultPoolWaitBuckets starts below a millisecond, where waits on a healthy pool fall
+var defaultPoolWaitBuckets = []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+// PoolWaitHook measures how long queries wait for a pooled connection.
+// The wait runs from the query start until the connection receives the statement, which dbkit
+// reports with MarkConnAcquired. Queries on other connectors are not observed.
+type PoolWaitHook struct {
+	wait       *prometheus.HistogramVec
+	exhaustion prometheus.Counter
+	threshold  time.Duration
+}
+
+type connAcquiredKey struct{}
+
+// NewPoolWaitHook creates a pool wait hook and registers dbkit_connection_wait_seconds
+// and dbkit_pool_exhaustion_total
+func NewPoolWaitHook(registry prometheus.Registerer, opts PoolWaitHookOptions) (*PoolWaitHook, error) {
+	if opts.ExhaustionThreshold <= 0 {
+		opts.ExhaustionThreshold = 100 * time.Millisecond
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = defaultPoolWaitBuckets
+	}
+	if err := validateBuckets(opts.Buckets); err != nil {
+		return nil, err
+	}
+
+	h := &PoolWaitHook{
+		threshold: opts.ExhaustionThreshold,
+		wait: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "dbkit_connection_wait_seconds",
+				Help:    "Time queries waited for a pooled connection in seconds",
+				Buckets: opts.Buckets,
+			},
+			[]string{"operation"},
+		),
+		exhaustion: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "dbkit_pool_exhaustion_total",
+				Help: "Number of queries that waited longer than the exhaustion threshold for a connection",
+			},
+		),
+	}
+
+	// Register metrics
+	for _, c := range []prometheus.Collector{h.wait, h.exhaustion} {
+		if err := registry.Register(c); err != nil {
+			// Check if already registered
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				return nil, err
+			}
+		}
+	}
+
+	return h, nil
+}
+
+// MarkConnAcquired records that the query running with ctx has received its connection.
+// Only the first call for a query is kept.
+func MarkConnAcquired(ctx context.Context) {
+	if acquired, ok := ctx.Value(connAcquiredKey{}).(*atomic.Int64); ok {
+		acquired.CompareAndSwap(0, time.Now().UnixNano())
+	}
+}
+
+// BeforeQuery adds the slot MarkConnAcquired fills to the query context
+func (h *PoolWaitHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return context.WithValue(ctx, connAcquiredKey{}, new(atomic.Int64))
+}
+
+// AfterQuery observes the wait of queries that reached a connection
+func (h *PoolWaitHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	acquired, ok := ctx.Value(connAcquiredKey{}).(*atomic.Int64)
+	if !ok || acquired.Load() == 0 {
+		return
+	}
+
+	wait := time.Unix(0, acquired.Load()).Sub(event.StartTime)
+	h.wait.WithLabelValues(OperationType(event.Query)).Observe(wait.Seconds())
+	if wait > h.threshold {
+		h.exhaustion.Inc()
+	}
+}
@@ -0,0 +1,203 @@
+package hooks
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+// PreparedStatementStats counts statement cache lookups
+type PreparedStatementStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// PreparedStatementHook keeps an LRU cache of prepared statements for hot queries.
+// A bun query hook cannot change how a query runs, so cached statements are executed with Exec
+// and Query. Registered as a query hook, it drops every cached statement after DDL executed
+// through bun, as PostgreSQL rejects cached plans whose result type changed.
+//
+// Usage:
+//
+//	stmts := hooks.NewPreparedStatementHook(db.DB, 100)
+//	db.AddQueryHook(stmts)
+//	defer stmts.Close()
+//
+//	rows, err := stmts.Query(ctx, "SELECT id, name FROM users WHERE org_id = $1", orgID)
+type PreparedStatementHook struct {
+	db   *bun.DB
+	size int
+
+	mu    sync.Mutex
+	lru   *list.List // Front is the most recently used *preparedEntry
+	items map[string]*list.Element
+	stats PreparedStatementStats
+}
+
+// preparedEntry is a cached statement. Entries dropped from the cache while in use are closed
+// by their last user, so a concurrent eviction cannot close a statement about to be executed.
+type preparedEntry struct {
+	key     string
+	stmt    *sql.Stmt
+	refs    int  // Callers executing the statement, guarded by PreparedStatementHook.mu
+	dropped bool // Removed from the cache, close once refs drops to zero
+}
+
+// NewPreparedStatementHook creates a statement cache holding up to cacheSize statements (default: 100)
+func NewPreparedStatementHook(db *bun.DB, cacheSize int) *PreparedStatementHook {
+	if cacheSize <= 0 {
+		cacheSize = 100
+	}
+	return &PreparedStatementHook{
+		db:    db,
+		size:  cacheSize,
+		lru:   list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Exec runs query with args through a cached prepared statement.
+// Statements other than SELECT, INSERT, UPDATE and DELETE are executed directly.
+func (h *PreparedStatementHook) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if !preparable(query) {
+		return h.db.ExecContext(ctx, query, args...)
+	}
+	entry, err := h.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer h.release(entry)
+	return entry.stmt.ExecContext(ctx, args...)
+}
+
+// Query runs query with args through a cached prepared statement.
+// Statements other than SELECT, INSERT, UPDATE and DELETE are executed directly.
+func (h *PreparedStatementHook) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if !preparable(query) {
+		return h.db.QueryContext(ctx, query, args...)
+	}
+	entry, err := h.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// Rows keep their statement usable after it is closed, until they are closed themselves
+	defer h.release(entry)
+	return entry.stmt.QueryContext(ctx, args...)
+}
+
+// Stats returns the cache counters
+func (h *PreparedStatementHook) Stats() PreparedStatementStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stats
+}
+
+// Close closes every cached statement. Statements still executing are closed when they finish.
+func (h *PreparedStatementHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var firstErr error
+	for el := h.lru.Front(); el != nil; el = el.Next() {
+		if err := h.drop(el.Value.(*preparedEntry)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	h.lru.Init()
+	clear(h.items)
+	return firstErr
+}
+
+// BeforeQuery is called before a query is executed
+func (h *PreparedStatementHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery drops the cached statements once DDL has succeeded
+func (h *PreparedStatementHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if event.Err != nil {
+		return
+	}
+	switch OperationType(event.Query) {
+	case "create", "drop", "alter":
+		_ = h.Close()
+	}
+}
+
+// acquire returns the cached statement for query, preparing it on a miss.
+// The entry stays open until it is passed to release.
+func (h *PreparedStatementHook) acquire(ctx context.Context, query string) (*preparedEntry, error) {
+	key := strings.Join(strings.Fields(query), " ")
+
+	h.mu.Lock()
+	if el, ok := h.items[key]; ok {
+		h.lru.MoveToFront(el)
+		h.stats.Hits++
+		entry := el.Value.(*preparedEntry)
+		entry.refs++
+		h.mu.Unlock()
+		return entry, nil
+	}
+	h.stats.Misses++
+	h.mu.Unlock()
+
+	stmt, err := h.db.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// Another caller prepared the same query meanwhile
+	if el, ok := h.items[key]; ok {
+		_ = stmt.Close()
+		h.lru.MoveToFront(el)
+		entry := el.Value.(*preparedEntry)
+		entry.refs++
+		return entry, nil
+	}
+	entry := &preparedEntry{key: key, stmt: stmt, refs: 1}
+	h.items[key] = h.lru.PushFront(entry)
+	for h.lru.Len() > h.size {
+		oldest := h.lru.Remove(h.lru.Back()).(*preparedEntry)
+		delete(h.items, oldest.key)
+		_ = h.drop(oldest)
+		h.stats.Evictions++
+	}
+	return entry, nil
+}
+
+// release ends a use of entry, closing it if it was dropped from the cache meanwhile
+func (h *PreparedStatementHook) release(entry *preparedEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry.refs--
+	if entry.dropped && entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// drop marks entry as removed from the cache and closes it unless it is in use.
+// The caller holds h.mu and removes the entry from the LRU list.
+func (h *PreparedStatementHook) drop(entry *preparedEntry) error {
+	entry.dropped = true
+	if entry.refs > 0 {
+		return nil
+	}
+	return entry.stmt.Close()
+}
+
+// preparable reports whether query is a statement PostgreSQL can prepare
+func preparable(query string) bool {
+	switch OperationType(query) {
+	case "select", "insert", "update", "delete":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package hooks
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/uptrace/bun"
+)
+
+// SamplingHook forwards only a fraction of queries to an inner hook
+type SamplingHook struct {
+	// AlwaysSampleErrors forwards failed queries to the inner hook's AfterQuery regardless of the rate
+	AlwaysSampleErrors bool
+
+	rate  float64
+	inner bun.QueryHook
+}
+
+// NewSamplingHook creates a hook that forwards a rate (0.0 to 1.0) of queries to inner.
+// Wrap LoggerHook to log a sample of queries.
+func NewSamplingHook(rate float64, inner bun.QueryHook) *SamplingHook {
+	return &SamplingHook{
+		rate:  min(max(rate, 0), 1),
+		inner: inner,
+	}
+}
+
+// sampledCtxKey stores the decision of one hook, so stacked sampling hooks don't read each other's
+type sampledCtxKey struct{ hook *SamplingHook }
+
+// BeforeQuery is called before a query is executed
+func (h *SamplingHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	if h.rate < 1 && (h.rate == 0 || rand.Float64() >= h.rate) {
+		return context.WithValue(ctx, sampledCtxKey{h}, false)
+	}
+	ctx = h.inner.BeforeQuery(ctx, event)
+	return context.WithValue(ctx, sampledCtxKey{h}, true)
+}
+
+// AfterQuery is called after a query is executed
+func (h *SamplingHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if sampled, _ := ctx.Value(sampledCtxKey{h}).(bool); sampled || (h.AlwaysSampleErrors && event.Err != nil) {
+		h.inner.AfterQuery(ctx, event)
+	}
+}
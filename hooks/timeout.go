@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type queryTimeoutKey struct{}
+
+type queryCancelKey struct{}
+
+// WithQueryTimeout returns a context whose queries each run with timeout as deadline.
+// The deadline starts when a query starts and can only shorten the driver ReadTimeout.
+//
+// Usage:
+//
+//	ctx := hooks.WithQueryTimeout(ctx, 50*time.Millisecond)
+//	err := db.NewSelect().Model(&users).Scan(ctx)
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, timeout)
+}
+
+// QueryTimeout returns the timeout set with WithQueryTimeout
+func QueryTimeout(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(queryTimeoutKey{}).(time.Duration)
+	return timeout, ok && timeout > 0
+}
+
+// QueryTimeoutHook applies the timeout from WithQueryTimeout to each query
+type QueryTimeoutHook struct{}
+
+// NewQueryTimeoutHook creates a new query timeout hook
+func NewQueryTimeoutHook() *QueryTimeoutHook {
+	return &QueryTimeoutHook{}
+}
+
+// BeforeQuery derives a context with the query deadline.
+// BEGIN is skipped, as its context bounds the whole transaction.
+func (h *QueryTimeoutHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	timeout, ok := QueryTimeout(ctx)
+	if !ok || event.Query == "BEGIN" {
+		return ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(ctx, queryCancelKey{}, cancel)
+}
+
+// AfterQuery releases the query context
+func (h *QueryTimeoutHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	cancel, ok := ctx.Value(queryCancelKey{}).(context.CancelFunc)
+	if !ok {
+		return
+	}
+	// QueryContext, QueryRowContext and SelectQuery.Rows run this hook before their rows are
+	// read, and report neither a result nor an error: the deadline releases the context instead
+	if event.Result == nil && event.Err == nil {
+		return
+	}
+	cancel()
+}
@@ -2,40 +2,117 @@ package hooks
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/uptrace/bun"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// TracingHookOptions customizes the spans created by TracingHook
+type TracingHookOptions struct {
+	SpanNameFormatter     func(event *bun.QueryEvent) string // Span name (default: "db." + operation)
+	BaggageKeys           []string                           // Baggage members added as baggage.<key> attributes (empty = all)
+	MaxQueryLength        int                                // Statement length recorded in db.statement (default: 500)
+	RecordQueryParameters bool                               // Record bound parameters in db.statement.parameters
+}
+
+// defaultMaxQueryLength is the db.statement length when MaxQueryLength is zero
+const defaultMaxQueryLength = 500
+
 // TracingHook implements OpenTelemetry tracing
 type TracingHook struct {
 	tracer trace.Tracer
+	opts   TracingHookOptions
 }
 
 // NewTracingHook creates a new tracing hook
-func NewTracingHook(tracer trace.Tracer) *TracingHook {
-	return &TracingHook{tracer: tracer}
+func NewTracingHook(tracer trace.Tracer, opts TracingHookOptions) *TracingHook {
+	if opts.MaxQueryLength <= 0 {
+		opts.MaxQueryLength = defaultMaxQueryLength
+	}
+	return &TracingHook{tracer: tracer, opts: opts}
 }
 
 type spanCtxKey struct{}
 
+type dbkitSpanAttrsKey struct{}
+
+// WithSpanAttributes returns a context whose queries add attrs to their spans.
+// Attributes accumulate across nested calls.
+func WithSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	existing := SpanAttributes(ctx)
+	merged := make([]attribute.KeyValue, 0, len(existing)+len(attrs))
+	merged = append(append(merged, existing...), attrs...)
+	return context.WithValue(ctx, dbkitSpanAttrsKey{}, merged)
+}
+
+// WithDBName returns a context whose query spans carry the db.name attribute
+func WithDBName(ctx context.Context, name string) context.Context {
+	return WithSpanAttributes(ctx, attribute.String("db.name", name))
+}
+
+// SpanAttributes returns the attributes added with WithSpanAttributes
+func SpanAttributes(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(dbkitSpanAttrsKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
 // BeforeQuery is called before a query is executed
 func (h *TracingHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
 	if h.tracer == nil {
 		return ctx
 	}
 
-	op := OperationType(event.Query)
+	name := "db." + OperationType(event.Query)
+	if h.opts.SpanNameFormatter != nil {
+		name = h.opts.SpanNameFormatter(event)
+	}
 
-	ctx, span := h.tracer.Start(ctx, "db."+op,
+	attrs := slices.Concat(SpanAttributes(ctx), h.baggageAttributes(ctx))
+	if h.opts.RecordQueryParameters {
+		if values, _ := queryParameters(event); len(values) > 0 {
+			params := make([]string, len(values))
+			for i, v := range values {
+				params[i] = fmt.Sprint(v)
+			}
+			attrs = append(attrs, attribute.StringSlice("db.statement.parameters", params))
+		}
+	}
+
+	ctx, span := h.tracer.Start(ctx, name,
 		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
 	)
 
 	return context.WithValue(ctx, spanCtxKey{}, span)
 }
 
+// baggageAttributes returns the baggage members of ctx selected by BaggageKeys as attributes
+func (h *TracingHook) baggageAttributes(ctx context.Context) []attribute.KeyValue {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	if len(h.opts.BaggageKeys) == 0 {
+		for _, m := range bag.Members() {
+			attrs = append(attrs, attribute.String("baggage."+m.Key(), m.Value()))
+		}
+		return attrs
+	}
+	for _, key := range h.opts.BaggageKeys {
+		if m := bag.Member(key); m.Key() != "" {
+			attrs = append(attrs, attribute.String("baggage."+key, m.Value()))
+		}
+	}
+	return attrs
+}
+
 // AfterQuery is called after a query is executed
 func (h *TracingHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	spanVal := ctx.Value(spanCtxKey{})
@@ -49,9 +126,14 @@ func (h *TracingHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	}
 	defer span.End()
 
+	// Noop and sampled-out spans discard attributes
+	if !span.IsRecording() {
+		return
+	}
+
 	query := event.Query
-	if len(query) > 500 {
-		query = query[:500] + "..."
+	if len(query) > h.opts.MaxQueryLength {
+		query = query[:h.opts.MaxQueryLength] + "..."
 	}
 
 	span.SetAttributes(
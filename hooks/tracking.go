@@ -0,0 +1,66 @@
+package hooks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/uptrace/bun"
+)
+
+// TrackingHook tracks in-flight queries so they can be drained on shutdown
+type TrackingHook struct {
+	mu     sync.Mutex
+	active int64
+	idle   chan struct{} // Closed when active drops to zero, replaced when a query starts from idle
+}
+
+// NewTrackingHook creates a new tracking hook
+func NewTrackingHook() *TrackingHook {
+	return &TrackingHook{}
+}
+
+// BeforeQuery is called before a query is executed
+func (h *TrackingHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	h.mu.Lock()
+	if h.active == 0 {
+		h.idle = make(chan struct{})
+	}
+	h.active++
+	h.mu.Unlock()
+	return ctx
+}
+
+// AfterQuery is called after a query is executed
+func (h *TrackingHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	h.mu.Lock()
+	h.active--
+	if h.active == 0 {
+		close(h.idle)
+	}
+	h.mu.Unlock()
+}
+
+// Active returns the number of in-flight queries
+func (h *TrackingHook) Active() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.active
+}
+
+// Wait blocks until there are no in-flight queries or ctx is done
+func (h *TrackingHook) Wait(ctx context.Context) error {
+	h.mu.Lock()
+	if h.active == 0 {
+		h.mu.Unlock()
+		return nil
+	}
+	idle := h.idle
+	h.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
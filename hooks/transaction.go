@@ -0,0 +1,115 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uptrace/bun"
+)
+
+// TransactionHookOptions configures the transaction duration histogram
+type TransactionHookOptions struct {
+	Buckets             []float64 // Histogram buckets in seconds (default: .005 to 60)
+	IsolationLevelLabel bool      // Add an isolation_level label, set with WithIsolationLevel
+}
+
+// defaultTransactionBuckets spans short writes to minute-long batch transactions
+var defaultTransactionBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// TransactionHook records how long transactions stay open, from BEGIN to COMMIT or ROLLBACK.
+// bun runs COMMIT and ROLLBACK hooks with the context returned for BEGIN, which carries the start time.
+type TransactionHook struct {
+	duration *prometheus.HistogramVec
+	opts     TransactionHookOptions
+}
+
+type txStartKey struct{}
+
+type txStart struct {
+	at        time.Time
+	isolation string
+}
+
+type isolationLevelKey struct{}
+
+// WithIsolationLevel returns a context whose transactions are labeled with level
+func WithIsolationLevel(ctx context.Context, level string) context.Context {
+	return context.WithValue(ctx, isolationLevelKey{}, level)
+}
+
+// NewTransactionHook creates a transaction hook and registers the dbkit_transaction_duration_seconds histogram
+func NewTransactionHook(registry prometheus.Registerer, opts TransactionHookOptions) (*TransactionHook, error) {
+	if opts.Buckets == nil {
+		opts.Buckets = defaultTransactionBuckets
+	}
+	if err := validateBuckets(opts.Buckets); err != nil {
+		return nil, err
+	}
+
+	labels := []string{"outcome"}
+	if opts.IsolationLevelLabel {
+		labels = append(labels, "isolation_level")
+	}
+
+	h := &TransactionHook{
+		opts: opts,
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "dbkit_transaction_duration_seconds",
+				Help:    "Duration of database transactions in seconds",
+				Buckets: opts.Buckets,
+			},
+			labels,
+		),
+	}
+
+	if err := registry.Register(h.duration); err != nil {
+		// Check if already registered
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// BeforeQuery stores the start time of BEGIN in the transaction context
+func (h *TransactionHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	if event.Query != "BEGIN" {
+		return ctx
+	}
+	level, _ := ctx.Value(isolationLevelKey{}).(string)
+	if level == "" {
+		level = "default"
+	}
+	return context.WithValue(ctx, txStartKey{}, &txStart{at: time.Now(), isolation: level})
+}
+
+// AfterQuery observes the transaction duration on COMMIT and ROLLBACK.
+// A failed COMMIT is reported as a rollback.
+func (h *TransactionHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	var outcome string
+	switch event.Query {
+	case "COMMIT":
+		outcome = "commit"
+		if event.Err != nil {
+			outcome = "rollback"
+		}
+	case "ROLLBACK":
+		outcome = "rollback"
+	default:
+		return
+	}
+
+	start, ok := ctx.Value(txStartKey{}).(*txStart)
+	if !ok {
+		return
+	}
+
+	labels := []string{outcome}
+	if h.opts.IsolationLevelLabel {
+		labels = append(labels, start.isolation)
+	}
+	h.duration.WithLabelValues(labels...).Observe(time.Since(start.at).Seconds())
+}
@@ -0,0 +1,96 @@
+package dbkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ReadinessStatus is the health status plus the migration state reported by HTTPReadinessHandler
+type ReadinessStatus struct {
+	HealthStatus
+	PendingMigrations bool `json:"pending_migrations"`
+}
+
+type dbContextKey struct{}
+
+// HTTPHealthHandler returns a handler that reports Health as JSON.
+// It responds 200 when healthy and 503 otherwise.
+//
+// Usage:
+//
+//	mux.Handle("/healthz", db.HTTPHealthHandler())
+func (db *DBKit) HTTPHealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := db.Health(r.Context())
+		writeHealthJSON(w, status.Healthy, status)
+	})
+}
+
+// HTTPReadinessHandler returns a handler like HTTPHealthHandler that also
+// responds 503 while any of the migrations is pending.
+//
+// Usage:
+//
+//	mux.Handle("/readyz", db.HTTPReadinessHandler(migrations))
+func (db *DBKit) HTTPReadinessHandler(migrations []Migration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		status := ReadinessStatus{HealthStatus: db.Health(ctx)}
+
+		if status.Healthy {
+			pending, err := db.HasPendingMigrations(ctx, migrations)
+			switch {
+			case err != nil:
+				status.Healthy = false
+				status.Error = err.Error()
+			case pending:
+				status.Healthy = false
+				status.PendingMigrations = true
+			}
+		}
+
+		writeHealthJSON(w, status.Healthy, status)
+	})
+}
+
+// HTTPMiddleware makes db available to handlers through DBFromContext.
+//
+// Usage:
+//
+//	http.ListenAndServe(":8080", db.HTTPMiddleware(mux))
+func (db *DBKit) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ContextWithDB(r.Context(), db)))
+	})
+}
+
+// ContextWithDB returns a copy of ctx carrying db, as HTTPMiddleware does.
+//
+// Usage:
+//
+//	ctx := dbkit.ContextWithDB(context.Background(), db)
+func ContextWithDB(ctx context.Context, db *DBKit) context.Context {
+	return context.WithValue(ctx, dbContextKey{}, db)
+}
+
+// DBFromContext returns the DBKit injected by HTTPMiddleware, or nil.
+//
+// Usage:
+//
+//	db := dbkit.DBFromContext(r.Context())
+func DBFromContext(ctx context.Context) *DBKit {
+	db, _ := ctx.Value(dbContextKey{}).(*DBKit)
+	return db
+}
+
+// writeHealthJSON writes body with 200 when healthy and 503 otherwise.
+func writeHealthJSON(w http.ResponseWriter, healthy bool, body any) {
+	code := http.StatusOK
+	if !healthy {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
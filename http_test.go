@@ -0,0 +1,89 @@
+package dbkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// serveHealth performs a GET against handler and decodes the JSON body.
+func serveHealth(t *testing.T, handler http.Handler) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", ct)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Response is not valid JSON: %v\n%s", err, rec.Body.String())
+	}
+	return rec, body
+}
+
+func TestHTTPHealthHandler(t *testing.T) {
+	db, connector := newFakeDB(t)
+
+	rec, body := serveHealth(t, db.HTTPHealthHandler())
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+	if body["healthy"] != true {
+		t.Errorf("Expected healthy=true, got %v", body)
+	}
+	if _, ok := body["pool_stats"]; !ok {
+		t.Errorf("Expected pool_stats in body, got %v", body)
+	}
+
+	connector.failing.Store(true)
+	rec, body = serveHealth(t, db.HTTPHealthHandler())
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+	if body["healthy"] != false || body["error"] == "" {
+		t.Errorf("Expected healthy=false with an error, got %v", body)
+	}
+}
+
+func TestHTTPReadinessHandler(t *testing.T) {
+	db, connector := newFakeDB(t)
+
+	rec, body := serveHealth(t, db.HTTPReadinessHandler(nil))
+	if rec.Code != http.StatusOK || body["pending_migrations"] != false {
+		t.Errorf("Expected 200 without pending migrations, got %d %v", rec.Code, body)
+	}
+
+	migrations := []Migration{{ID: "001", Description: "create users", SQL: "CREATE TABLE users (id int)"}}
+	rec, body = serveHealth(t, db.HTTPReadinessHandler(migrations))
+	if rec.Code != http.StatusServiceUnavailable || body["pending_migrations"] != true {
+		t.Errorf("Expected 503 with pending migrations, got %d %v", rec.Code, body)
+	}
+
+	connector.failing.Store(true)
+	rec, body = serveHealth(t, db.HTTPReadinessHandler(nil))
+	if rec.Code != http.StatusServiceUnavailable || body["healthy"] != false {
+		t.Errorf("Expected 503 for unhealthy database, got %d %v", rec.Code, body)
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	var got *DBKit
+	handler := db.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = DBFromContext(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got != db {
+		t.Error("Expected handler to receive the DBKit from the request context")
+	}
+	if DBFromContext(context.Background()) != nil {
+		t.Error("Expected nil DBKit outside the middleware")
+	}
+}
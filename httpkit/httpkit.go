@@ -0,0 +1,156 @@
+// Package httpkit makes a DBKit and per-request transactions available to HTTP handlers
+package httpkit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"maps"
+	"net/http"
+
+	"github.com/fernandezvara/dbkit"
+)
+
+type txContextKey struct{}
+
+// Middleware stores db in the request context, where DB and IDB find it.
+// It is equivalent to db.HTTPMiddleware.
+//
+// Usage:
+//
+//	http.ListenAndServe(":8080", httpkit.Middleware(db)(mux))
+func Middleware(db *dbkit.DBKit) func(http.Handler) http.Handler {
+	return db.HTTPMiddleware
+}
+
+// DB returns the DBKit stored by Middleware or TransactionMiddleware.
+func DB(ctx context.Context) (*dbkit.DBKit, bool) {
+	db := dbkit.DBFromContext(ctx)
+	return db, db != nil
+}
+
+// MustDB returns the DBKit stored in ctx and panics if there is none.
+func MustDB(ctx context.Context) *dbkit.DBKit {
+	db, ok := DB(ctx)
+	if !ok {
+		panic("httpkit: no DBKit in context, is the middleware installed?")
+	}
+	return db
+}
+
+// IDB returns the request transaction started by TransactionMiddleware, or the DBKit stored
+// by Middleware when the request has no transaction.
+//
+// Usage:
+//
+//	func createUser(w http.ResponseWriter, r *http.Request) {
+//	    db, _ := httpkit.IDB(r.Context())
+//	    _, err := db.NewInsert().Model(&user).Exec(r.Context())
+//	    // ...
+//	}
+func IDB(ctx context.Context) (dbkit.IDB, bool) {
+	if tx, ok := ctx.Value(txContextKey{}).(*dbkit.Tx); ok {
+		return tx, true
+	}
+	if db, ok := DB(ctx); ok {
+		return db, true
+	}
+	return nil, false
+}
+
+// TransactionMiddleware runs each request in a transaction. The transaction is committed when the
+// handler responds with a status below 400, so writes followed by a redirect are kept, and rolled
+// back on 4xx and 5xx statuses or if the handler panics.
+// The first of opts, if any, configures the transaction.
+//
+// The response is buffered until the transaction ends, so a failed commit is reported to the
+// client as a 500 instead of the handler's status; streaming handlers should manage their
+// transaction themselves. The transaction is not bound to the request context: a client that
+// disconnects after the handler succeeded does not roll it back.
+//
+// Usage:
+//
+//	mux.Handle("/orders", httpkit.TransactionMiddleware(db)(ordersHandler))
+func TransactionMiddleware(db *dbkit.DBKit, opts ...dbkit.TxOptions) func(http.Handler) http.Handler {
+	txOpts := dbkit.DefaultTxOptions()
+	if len(opts) > 0 {
+		txOpts = opts[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tx, err := db.BeginWithOptions(context.WithoutCancel(r.Context()), txOpts)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			// No-op once committed
+			defer func() { _ = tx.Rollback() }()
+
+			ctx := context.WithValue(r.Context(), txContextKey{}, tx)
+			if _, ok := DB(ctx); !ok {
+				ctx = dbkit.ContextWithDB(ctx, db)
+			}
+
+			buf := newBufferedResponse()
+			next.ServeHTTP(buf, r.WithContext(ctx))
+
+			if buf.Status() < http.StatusBadRequest {
+				if err := tx.Commit(); err != nil {
+					logger := db.Config().Logger
+					if logger == nil {
+						logger = slog.Default()
+					}
+					logger.ErrorContext(r.Context(), "request transaction commit failed",
+						slog.String("method", r.Method), slog.String("path", r.URL.Path), slog.String("error", err.Error()))
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+			}
+			buf.writeTo(w)
+		})
+	}
+}
+
+// bufferedResponse holds a handler's response until its transaction ends
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header)}
+}
+
+func (r *bufferedResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferedResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *bufferedResponse) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// Status returns the written status, 200 if the handler wrote nothing.
+func (r *bufferedResponse) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// writeTo sends the buffered headers, status and body to w
+func (r *bufferedResponse) writeTo(w http.ResponseWriter) {
+	maps.Copy(w.Header(), r.header)
+	w.WriteHeader(r.Status())
+	_, _ = r.body.WriteTo(w)
+}
@@ -0,0 +1,196 @@
+package dbkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// IdempotencyKey is a stored response for an idempotency key.
+//
+// Create the table with IdempotencyStore.CreateTable or:
+//
+//	CREATE TABLE idempotency_keys (
+//	    key VARCHAR(255) PRIMARY KEY,
+//	    response_hash VARCHAR(64) NOT NULL,
+//	    response_body JSONB,
+//	    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+//	    expires_at TIMESTAMPTZ NOT NULL
+//	);
+//	CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+type IdempotencyKey struct {
+	bun.BaseModel `bun:"table:idempotency_keys,alias:ik"`
+
+	Key          string          `bun:"key,pk"`
+	ResponseHash string          `bun:"response_hash,notnull"`
+	ResponseBody json.RawMessage `bun:"response_body,type:jsonb"`
+	CreatedAt    time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	ExpiresAt    time.Time       `bun:"expires_at,notnull"`
+}
+
+// IdempotencyStore runs an operation at most once per key and replays its stored response
+type IdempotencyStore struct {
+	db *DBKit
+}
+
+// NewIdempotencyStore creates a store backed by the idempotency_keys table of db.
+//
+// Usage:
+//
+//	store := dbkit.NewIdempotencyStore(db)
+//	if err := store.CreateTable(ctx); err != nil {
+//	    return err
+//	}
+func NewIdempotencyStore(db *DBKit) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// CreateTable creates the idempotency_keys table and its expiry index if they do not exist.
+// PostgreSQL does not allow NOW() in index predicates, so expired keys are filtered through
+// the expires_at index rather than a partial index.
+func (s *IdempotencyStore) CreateTable(ctx context.Context) error {
+	if _, err := s.db.NewCreateTable().Model((*IdempotencyKey)(nil)).IfNotExists().Exec(ctx); err != nil {
+		return wrapError(err, "IdempotencyStore.CreateTable")
+	}
+	_, err := s.db.NewCreateIndex().Model((*IdempotencyKey)(nil)).
+		Index("idx_idempotency_keys_expires_at").
+		IfNotExists().
+		Column("expires_at").
+		Exec(ctx)
+	return wrapError(err, "IdempotencyStore.CreateTable")
+}
+
+// Execute returns the stored response for key if it has not expired. Otherwise it calls fn
+// and stores its result as JSON for ttl. Errors returned by fn are not stored.
+// Each call runs in a serializable transaction, and concurrent calls with the same key are
+// serialized with an advisory lock on hashtext(key), so fn runs once. In pgBouncer mode the
+// lock is transaction-level and taken first in a read committed transaction instead.
+//
+// Both the first call and replays return the response as the JSON stored for it.
+//
+// Usage:
+//
+//	body, err := store.Execute(ctx, r.Header.Get("Idempotency-Key"), 24*time.Hour, func() (any, error) {
+//	    return createOrder(ctx, req)
+//	})
+//	w.Write(body)
+func (s *IdempotencyStore) Execute(ctx context.Context, key string, ttl time.Duration, fn func() (any, error)) (json.RawMessage, error) {
+	var result json.RawMessage
+	var fnErr error
+	run := func(ctx context.Context, tx bun.Tx) error {
+		var stored IdempotencyKey
+		err := tx.NewSelect().Model(&stored).Where("key = ?", key).Where("expires_at > NOW()").Scan(ctx)
+		if err == nil {
+			result = stored.ResponseBody
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return wrapError(err, "IdempotencyStore.Execute")
+		}
+
+		value, err := fn()
+		if err != nil {
+			fnErr = err
+			return err
+		}
+		body, err := json.Marshal(value)
+		if err != nil {
+			return &Error{
+				Code:    CodeUnknown,
+				Message: "failed to encode idempotent response: " + err.Error(),
+				Op:      "IdempotencyStore.Execute",
+				Cause:   err,
+			}
+		}
+		hash := sha256.Sum256(body)
+
+		entry := &IdempotencyKey{
+			Key:          key,
+			ResponseHash: hex.EncodeToString(hash[:]),
+			ResponseBody: body,
+			ExpiresAt:    time.Now().Add(ttl),
+		}
+		// An expired entry for the key is replaced
+		_, err = tx.NewInsert().Model(entry).
+			On("CONFLICT (key) DO UPDATE").
+			Set("response_hash = EXCLUDED.response_hash").
+			Set("response_body = EXCLUDED.response_body").
+			Set("created_at = NOW()").
+			Set("expires_at = EXCLUDED.expires_at").
+			Exec(ctx)
+		if err != nil {
+			return wrapError(err, "IdempotencyStore.Execute")
+		}
+		result = body
+		return nil
+	}
+
+	var err error
+	if s.db.IsPgBouncer() {
+		err = s.runXactLocked(ctx, key, run)
+	} else {
+		err = s.runSessionLocked(ctx, key, run)
+	}
+	if err != nil {
+		if fnErr != nil {
+			return nil, fnErr
+		}
+		return nil, wrapError(err, "IdempotencyStore.Execute")
+	}
+	return result, nil
+}
+
+// runSessionLocked runs fn in a serializable transaction on a connection holding the session
+// advisory lock for key. The lock is taken before the transaction starts, so its snapshot
+// includes a response stored by a concurrent call with the same key.
+func (s *IdempotencyStore) runSessionLocked(ctx context.Context, key string, fn func(context.Context, bun.Tx) error) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext(?))", key); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtext(?))", key); err != nil {
+			// Never return a connection holding the lock to the pool
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	return conn.RunInTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, fn)
+}
+
+// runXactLocked runs fn in a read committed transaction that first takes the transaction
+// advisory lock for key, as session locks don't survive pgBouncer's transaction pooling.
+// Each statement after the lock sees a response committed by a concurrent call.
+func (s *IdempotencyStore) runXactLocked(ctx context.Context, key string, fn func(context.Context, bun.Tx) error) error {
+	return s.db.RunInTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted}, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", key); err != nil {
+			return err
+		}
+		return fn(ctx, tx)
+	})
+}
+
+// Purge deletes expired keys and returns how many were removed.
+//
+// Usage:
+//
+//	removed, err := store.Purge(ctx)
+func (s *IdempotencyStore) Purge(ctx context.Context) (int64, error) {
+	res, err := s.db.NewDelete().Model((*IdempotencyKey)(nil)).Where("expires_at <= NOW()").Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "IdempotencyStore.Purge")
+	}
+	return res.RowsAffected()
+}
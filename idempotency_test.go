@@ -0,0 +1,155 @@
+package dbkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type idempotentResponse struct {
+	OrderID string `json:"order_id"`
+}
+
+func setupIdempotencyStore(t *testing.T, db *DBKit) *IdempotencyStore {
+	t.Helper()
+	ctx := context.Background()
+
+	store := NewIdempotencyStore(db)
+	if err := store.CreateTable(ctx); err != nil {
+		t.Fatalf("CreateTable failed: %v", err)
+	}
+	_, _ = db.NewDelete().Model((*IdempotencyKey)(nil)).Where("1=1").Exec(ctx)
+	return store
+}
+
+func TestIdempotencyStore_Execute(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	store := setupIdempotencyStore(t, db)
+
+	calls := 0
+	fn := func() (any, error) {
+		calls++
+		return idempotentResponse{OrderID: "order-1"}, nil
+	}
+
+	first, err := store.Execute(ctx, "req-1", time.Hour, fn)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var created idempotentResponse
+	if err := json.Unmarshal(first, &created); err != nil || created.OrderID != "order-1" {
+		t.Errorf("Expected fn result, got %s (%v)", first, err)
+	}
+
+	second, err := store.Execute(ctx, "req-1", time.Hour, fn)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected fn to be called once, got %d", calls)
+	}
+
+	var replayed idempotentResponse
+	if err := json.Unmarshal(second, &replayed); err != nil || replayed.OrderID != "order-1" {
+		t.Errorf("Expected stored response, got %s (%v)", second, err)
+	}
+}
+
+func TestIdempotencyStore_ErrorsAreNotStored(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	store := setupIdempotencyStore(t, db)
+
+	fnErr := errors.New("payment declined")
+	if _, err := store.Execute(ctx, "req-err", time.Hour, func() (any, error) { return nil, fnErr }); !errors.Is(err, fnErr) {
+		t.Fatalf("Expected fn error, got %v", err)
+	}
+
+	calls := 0
+	if _, err := store.Execute(ctx, "req-err", time.Hour, func() (any, error) { calls++; return "ok", nil }); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected fn to run again after an error, got %d calls", calls)
+	}
+}
+
+func TestIdempotencyStore_Concurrent(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	store := setupIdempotencyStore(t, db)
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.Execute(ctx, "req-concurrent", time.Hour, func() (any, error) {
+				calls.Add(1)
+				time.Sleep(50 * time.Millisecond)
+				return "ok", nil
+			})
+			if err != nil {
+				t.Errorf("Execute failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected fn to be called once, got %d", calls.Load())
+	}
+}
+
+func TestIdempotencyStore_ExpiredAndPurge(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	store := setupIdempotencyStore(t, db)
+
+	calls := 0
+	fn := func() (any, error) { calls++; return calls, nil }
+
+	if _, err := store.Execute(ctx, "req-expired", -time.Second, fn); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if _, err := store.Execute(ctx, "req-expired", -time.Second, fn); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected expired keys to run fn again, got %d calls", calls)
+	}
+
+	removed, err := store.Purge(ctx)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 expired key to be removed, got %d", removed)
+	}
+}
+
+func TestIdempotencyStore_PgBouncerMode(t *testing.T) {
+	db := newPgBouncerDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	resp, err := NewIdempotencyStore(db).Execute(context.Background(), "key-1", time.Hour, func() (any, error) {
+		return "created", nil
+	})
+	if err != nil || string(resp) != `"created"` {
+		t.Fatalf("Expected the response of fn, got %v, %v", resp, err)
+	}
+	if len(hook.queries) < 2 || hook.queries[1] != "SELECT pg_advisory_xact_lock(hashtext('key-1'))" {
+		t.Errorf("Expected a transaction-level lock first in the transaction, got %q", hook.queries)
+	}
+}
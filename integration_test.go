@@ -36,6 +36,7 @@ func getTestDB(t *testing.T) *DBKit {
 		MaxOpenConns:    5,
 		MaxIdleConns:    2,
 		ConnMaxLifetime: time.Minute,
+		ConnMaxIdleTime: 30 * time.Second,
 		Logger:          slog.Default(),
 	})
 	if err != nil {
@@ -563,3 +564,20 @@ func TestIntegration_Upsert(t *testing.T) {
 		t.Errorf("ID should not change during upsert")
 	}
 }
+
+func TestIntegration_DriverErrorMapping(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, "DO $$ BEGIN RAISE EXCEPTION 'insufficient balance'; END $$")
+	if err = wrapError(err, "Exec"); !IsRaisedException(err) {
+		t.Errorf("Expected raised exception, got %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, "SELECT 1 / 0")
+	if !IsDataException(wrapError(err, "Exec")) {
+		t.Errorf("Expected data exception, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package dbkit
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// jsonbPathParts splits a dot-separated JSON path ("address.city", "tags.0") into its keys.
+func jsonbPathParts(path string) []string {
+	path = strings.TrimPrefix(path, "$.")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// jsonbTextPath converts a dot-separated path to a PostgreSQL text[] path for jsonb_set.
+func jsonbTextPath(path string) any {
+	return pgdialect.Array(jsonbPathParts(path))
+}
+
+// jsonbPath converts a dot-separated path to an SQL/JSON path for jsonb_path_query_first.
+// Numeric segments are treated as array indexes.
+func jsonbPath(path string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, part := range jsonbPathParts(path) {
+		if _, err := strconv.Atoi(part); err == nil {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		b.WriteString("." + strconv.Quote(part))
+	}
+	return b.String()
+}
+
+// WhereJSONBContains returns a query modifier matching rows where column contains value.
+// value is marshaled to JSON.
+//
+// Usage:
+//
+//	err := db.NewSelect().Model(&users).
+//	    Apply(dbkit.WhereJSONBContains("settings", map[string]any{"theme": "dark"})).
+//	    Scan(ctx)
+func WhereJSONBContains(column string, value any) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return q.Err(wrapError(err, "WhereJSONBContains"))
+		}
+		return q.Where("? @> ?::jsonb", bun.Ident(column), string(data))
+	}
+}
+
+// WhereJSONBContainedBy returns a query modifier matching rows where column is contained by value.
+//
+// Usage:
+//
+//	q = dbkit.WhereJSONBContainedBy("tags", []string{"go", "sql", "postgres"})(q)
+func WhereJSONBContainedBy(column string, value any) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return q.Err(wrapError(err, "WhereJSONBContainedBy"))
+		}
+		return q.Where("? <@ ?::jsonb", bun.Ident(column), string(data))
+	}
+}
+
+// WhereJSONBHasKey returns a query modifier matching rows where column has the top-level key.
+//
+// Usage:
+//
+//	q = dbkit.WhereJSONBHasKey("settings", "theme")(q)
+func WhereJSONBHasKey(column, key string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("jsonb_exists(?, ?)", bun.Ident(column), key)
+	}
+}
+
+// SetJSONBField sets the value at path inside a JSONB column of a single record.
+// The path is dot-separated; numeric segments index arrays. Missing keys are created.
+//
+// Usage:
+//
+//	err := dbkit.SetJSONBField(ctx, db, &user, "settings", "notifications.email", false)
+func SetJSONBField[T any](ctx context.Context, db bun.IDB, model *T, jsonColumn, path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return wrapError(err, "SetJSONBField")
+	}
+
+	result, err := db.NewUpdate().
+		Model(model).
+		Set("? = jsonb_set(coalesce(?, '{}'::jsonb), ?, ?::jsonb, true)",
+			bun.Ident(jsonColumn), bun.Ident(jsonColumn), jsonbTextPath(path), string(data)).
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "SetJSONBField")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError(err, "SetJSONBField")
+	}
+
+	if rows == 0 {
+		return &Error{
+			Code:    CodeNotFound,
+			Message: "record not found",
+			Op:      "SetJSONBField",
+			Cause:   ErrNotFound,
+		}
+	}
+
+	return nil
+}
+
+// SetJSONBFieldWhere sets the value at path inside a JSONB column for all matching records.
+// Returns the number of rows affected.
+//
+// Usage:
+//
+//	count, err := dbkit.SetJSONBFieldWhere[User](ctx, db, "settings", "theme", "dark", func(q *bun.UpdateQuery) *bun.UpdateQuery {
+//	    return q.Where("active = ?", true)
+//	})
+func SetJSONBFieldWhere[T any](ctx context.Context, db bun.IDB, jsonColumn, path string, value any, queryFn func(*bun.UpdateQuery) *bun.UpdateQuery) (int64, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0, wrapError(err, "SetJSONBFieldWhere")
+	}
+
+	q := db.NewUpdate().
+		Model((*T)(nil)).
+		Set("? = jsonb_set(coalesce(?, '{}'::jsonb), ?, ?::jsonb, true)",
+			bun.Ident(jsonColumn), bun.Ident(jsonColumn), jsonbTextPath(path), string(data))
+	if queryFn != nil {
+		q = queryFn(q)
+	} else {
+		q = q.Where("1=1")
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "SetJSONBFieldWhere")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// PluckJSONBField extracts the value at path inside a JSONB column from matching records.
+// Values are decoded from JSON into V; rows where the path does not exist yield the zero value.
+//
+// Usage:
+//
+//	themes, err := dbkit.PluckJSONBField[User, string](ctx, db, "settings", "theme", nil)
+//	firstTags, err := dbkit.PluckJSONBField[Post, string](ctx, db, "tags", "0", nil)
+func PluckJSONBField[T any, V any](ctx context.Context, db bun.IDB, jsonColumn, path string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]V, error) {
+	var raw []json.RawMessage
+
+	q := db.NewSelect().
+		Model((*T)(nil)).
+		ColumnExpr("jsonb_path_query_first(?, ?::jsonpath)", bun.Ident(jsonColumn), jsonbPath(path))
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx, &raw); err != nil {
+		return nil, wrapError(err, "PluckJSONBField")
+	}
+
+	values := make([]V, len(raw))
+	for i, data := range raw {
+		if len(data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(data, &values[i]); err != nil {
+			return nil, wrapError(err, "PluckJSONBField")
+		}
+	}
+
+	return values, nil
+}
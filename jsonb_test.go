@@ -0,0 +1,157 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type TestDocument struct {
+	bun.BaseModel `bun:"table:test_documents,alias:td"`
+	ID            int64          `bun:"id,pk,autoincrement"`
+	Name          string         `bun:"name,notnull"`
+	Data          map[string]any `bun:"data,type:jsonb"`
+}
+
+func createDocumentsTable(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewDropTable().Model((*TestDocument)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop documents table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestDocument)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create documents table: %v", err)
+	}
+
+	docs := []TestDocument{
+		{Name: "alice", Data: map[string]any{
+			"age":     30,
+			"active":  true,
+			"address": map[string]any{"city": "Madrid"},
+			"tags":    []string{"admin", "dev"},
+		}},
+		{Name: "bob", Data: map[string]any{
+			"age":     25,
+			"active":  false,
+			"address": map[string]any{"city": "Paris"},
+			"tags":    []string{"dev"},
+		}},
+	}
+	if _, err := db.NewInsert().Model(&docs).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert documents: %v", err)
+	}
+	return ctx
+}
+
+func TestJSONBPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"", "$"},
+		{"age", `$."age"`},
+		{"address.city", `$."address"."city"`},
+		{"tags.0", `$."tags"[0]`},
+		{"$.tags.1", `$."tags"[1]`},
+	}
+
+	for _, tt := range tests {
+		if result := jsonbPath(tt.path); result != tt.expected {
+			t.Errorf("jsonbPath(%q) = %q, expected %q", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestWhereJSONBContains(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createDocumentsTable(t, db)
+
+	var docs []TestDocument
+	err := db.NewSelect().Model(&docs).
+		Apply(WhereJSONBContains("data", map[string]any{"address": map[string]any{"city": "Madrid"}})).
+		Scan(ctx)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(docs) != 1 || docs[0].Name != "alice" {
+		t.Errorf("Expected alice, got %+v", docs)
+	}
+
+	count, err := db.NewSelect().Model((*TestDocument)(nil)).Apply(WhereJSONBHasKey("data", "tags")).Count(ctx)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 documents with tags, got %d", count)
+	}
+}
+
+func TestSetJSONBField(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createDocumentsTable(t, db)
+
+	var doc TestDocument
+	if err := db.NewSelect().Model(&doc).Where("name = ?", "alice").Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+
+	if err := SetJSONBField(ctx, db, &doc, "data", "address.city", "Barcelona"); err != nil {
+		t.Fatalf("SetJSONBField failed: %v", err)
+	}
+
+	cities, err := PluckJSONBField[TestDocument, string](ctx, db, "data", "address.city", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("name = ?", "alice")
+	})
+	if err != nil {
+		t.Fatalf("PluckJSONBField failed: %v", err)
+	}
+	if len(cities) != 1 || cities[0] != "Barcelona" {
+		t.Errorf("Expected Barcelona, got %v", cities)
+	}
+
+	count, err := SetJSONBFieldWhere[TestDocument](ctx, db, "data", "tags.0", "owner", nil)
+	if err != nil {
+		t.Fatalf("SetJSONBFieldWhere failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 rows updated, got %d", count)
+	}
+}
+
+func TestPluckJSONBField_Types(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createDocumentsTable(t, db)
+	byName := func(q *bun.SelectQuery) *bun.SelectQuery { return q.Order("name ASC") }
+
+	ages, err := PluckJSONBField[TestDocument, int](ctx, db, "data", "age", byName)
+	if err != nil {
+		t.Fatalf("PluckJSONBField int failed: %v", err)
+	}
+	if len(ages) != 2 || ages[0] != 30 || ages[1] != 25 {
+		t.Errorf("Expected [30 25], got %v", ages)
+	}
+
+	active, err := PluckJSONBField[TestDocument, bool](ctx, db, "data", "active", byName)
+	if err != nil {
+		t.Fatalf("PluckJSONBField bool failed: %v", err)
+	}
+	if len(active) != 2 || !active[0] || active[1] {
+		t.Errorf("Expected [true false], got %v", active)
+	}
+
+	tags, err := PluckJSONBField[TestDocument, string](ctx, db, "data", "tags.1", byName)
+	if err != nil {
+		t.Fatalf("PluckJSONBField array index failed: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "dev" || tags[1] != "" {
+		t.Errorf("Expected [dev \"\"], got %q", tags)
+	}
+}
@@ -0,0 +1,167 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+)
+
+// lockNamespace is the first key of the two-key advisory locks taken by LockManager
+// ("dbkt"), so named locks never collide with single-key locks such as StringAdvisoryKey.
+const lockNamespace = 0x64626b74
+
+// locksTable maps lock names to the permanent IDs used as advisory lock keys
+const locksTable = `
+CREATE TABLE IF NOT EXISTS _dbkit_locks (
+    lock_name VARCHAR(255) PRIMARY KEY,
+    lock_id SERIAL UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// UnlockFunc releases a lock. Calling it more than once is a no-op.
+type UnlockFunc func() error
+
+// ActiveLock is a named lock held or awaited by a session
+type ActiveLock struct {
+	Name    string `bun:"lock_name"`
+	LockID  int64  `bun:"lock_id"`
+	PID     int    `bun:"pid"`     // Backend process of the session
+	Granted bool   `bun:"granted"` // False while the session waits for the lock
+}
+
+// LockManager takes session-level advisory locks by name. Each name is assigned a
+// permanent ID in the _dbkit_locks table the first time it is used.
+// Not supported in pgBouncer mode.
+type LockManager struct {
+	db  *DBKit
+	ids sync.Map // lock name -> lock ID
+}
+
+// NewLockManager creates a lock manager.
+//
+// Usage:
+//
+//	locks := dbkit.NewLockManager(db)
+//	unlock, err := locks.Lock(ctx, "jobs:cleanup")
+//	if err != nil {
+//	    return err
+//	}
+//	defer unlock()
+func NewLockManager(db *DBKit) *LockManager {
+	return &LockManager{db: db}
+}
+
+// Lock acquires the named lock, blocking until it is available. The lock holds a
+// dedicated connection until the returned UnlockFunc is called.
+func (lm *LockManager) Lock(ctx context.Context, name string) (UnlockFunc, error) {
+	unlock, _, err := lm.acquire(ctx, name, "SELECT true FROM (SELECT pg_advisory_lock(?, ?)) l", "LockManager.Lock")
+	return unlock, err
+}
+
+// TryLock acquires the named lock if it is available. Returns false, and a nil UnlockFunc,
+// if another session holds it.
+//
+// Usage:
+//
+//	unlock, acquired, err := locks.TryLock(ctx, "jobs:cleanup")
+//	if err != nil || !acquired {
+//	    return err
+//	}
+//	defer unlock()
+func (lm *LockManager) TryLock(ctx context.Context, name string) (UnlockFunc, bool, error) {
+	return lm.acquire(ctx, name, "SELECT pg_try_advisory_lock(?, ?)", "LockManager.TryLock")
+}
+
+func (lm *LockManager) acquire(ctx context.Context, name, query, op string) (UnlockFunc, bool, error) {
+	if pgBouncerMode(lm.db) {
+		return nil, false, pgBouncerError(op, "session-level advisory locks are not supported in pgBouncer mode, use AdvisoryXactLock")
+	}
+	id, err := lm.lockID(ctx, name)
+	if err != nil {
+		return nil, false, wrapError(err, op)
+	}
+
+	conn, err := lm.db.Conn(ctx)
+	if err != nil {
+		return nil, false, wrapError(err, op)
+	}
+	var acquired bool
+	if err := conn.NewRaw(query, lockNamespace, id).Scan(ctx, &acquired); err != nil {
+		// The lock may have been taken before the error, don't return the connection to the pool
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		conn.Close()
+		return nil, false, wrapError(err, op)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	var once sync.Once
+	var unlockErr error
+	unlock := func() error {
+		once.Do(func() {
+			defer conn.Close()
+			// Use a fresh context so the lock is released even if ctx was cancelled
+			_, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(?, ?)", lockNamespace, id)
+			if err != nil {
+				// Never return a connection holding the lock to the pool
+				_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+			}
+			unlockErr = wrapError(err, "LockManager.Unlock")
+		})
+		return unlockErr
+	}
+	return unlock, true, nil
+}
+
+// lockID returns the ID of a lock name, creating it on first use
+func (lm *LockManager) lockID(ctx context.Context, name string) (int64, error) {
+	if id, ok := lm.ids.Load(name); ok {
+		return id.(int64), nil
+	}
+
+	if _, err := lm.db.ExecContext(ctx, locksTable); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := lm.db.NewRaw(`
+        INSERT INTO _dbkit_locks (lock_name) VALUES (?)
+        ON CONFLICT (lock_name) DO UPDATE SET lock_name = EXCLUDED.lock_name
+        RETURNING lock_id
+    `, name).Scan(ctx, &id)
+	if err != nil {
+		return 0, err
+	}
+
+	lm.ids.Store(name, id)
+	return id, nil
+}
+
+// ListActiveLocks returns the named locks held or awaited in the current database.
+//
+// Usage:
+//
+//	locks, err := dbkit.ListActiveLocks(ctx, db)
+//	for _, l := range locks {
+//	    log.Printf("%s held by pid %d: %v", l.Name, l.PID, l.Granted)
+//	}
+func ListActiveLocks(ctx context.Context, db IDB) ([]ActiveLock, error) {
+	if _, err := db.ExecContext(ctx, locksTable); err != nil {
+		return nil, wrapError(err, "ListActiveLocks")
+	}
+
+	locks := make([]ActiveLock, 0)
+	err := db.NewRaw(`
+        SELECT k.lock_name, k.lock_id, l.pid, l.granted
+        FROM pg_locks l
+        JOIN _dbkit_locks k ON l.objid::bigint = k.lock_id
+        WHERE l.locktype = 'advisory' AND l.objsubid = 2 AND l.classid = ?
+          AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database())
+        ORDER BY k.lock_name, l.granted DESC, l.pid
+    `, lockNamespace).Scan(ctx, &locks)
+	if err != nil {
+		return nil, wrapError(err, "ListActiveLocks")
+	}
+	return locks, nil
+}
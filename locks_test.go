@@ -0,0 +1,105 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLockManager_PgBouncerMode(t *testing.T) {
+	lm := NewLockManager(newPgBouncerDB(t))
+	ctx := context.Background()
+
+	if _, err := lm.Lock(ctx, "jobs"); !IsUnsupported(err) {
+		t.Errorf("Expected Lock to be unsupported, got %v", err)
+	}
+	if _, _, err := lm.TryLock(ctx, "jobs"); !IsUnsupported(err) {
+		t.Errorf("Expected TryLock to be unsupported, got %v", err)
+	}
+}
+
+func TestLockManager_MutualExclusion(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	lm := NewLockManager(db)
+
+	var inside, maxInside atomic.Int32
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 3 {
+				unlock, err := lm.Lock(ctx, "test:exclusive")
+				if err != nil {
+					t.Errorf("Lock failed: %v", err)
+					return
+				}
+				n := inside.Add(1)
+				if n > maxInside.Load() {
+					maxInside.Store(n)
+				}
+				time.Sleep(10 * time.Millisecond)
+				inside.Add(-1)
+				if err := unlock(); err != nil {
+					t.Errorf("Unlock failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInside.Load() != 1 {
+		t.Errorf("Expected at most 1 holder at a time, got %d", maxInside.Load())
+	}
+}
+
+func TestLockManager_TryLockAndList(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	lm := NewLockManager(db)
+
+	unlock, acquired, err := lm.TryLock(ctx, "test:report")
+	if err != nil || !acquired {
+		t.Fatalf("Expected to acquire the lock, got %v, %v", acquired, err)
+	}
+
+	// Another manager shares the name to ID mapping through the table
+	other, acquired, err := NewLockManager(db).TryLock(ctx, "test:report")
+	if err != nil || acquired || other != nil {
+		t.Errorf("Expected the lock to be taken, got %v, %v", acquired, err)
+	}
+
+	locks, err := ListActiveLocks(ctx, db)
+	if err != nil {
+		t.Fatalf("ListActiveLocks failed: %v", err)
+	}
+	found := false
+	for _, l := range locks {
+		if l.Name == "test:report" && l.Granted {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected test:report in active locks, got %+v", locks)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Errorf("Expected a second unlock to be a no-op, got %v", err)
+	}
+
+	unlock, acquired, err = lm.TryLock(ctx, "test:report")
+	if err != nil || !acquired {
+		t.Fatalf("Expected the released lock to be available, got %v, %v", acquired, err)
+	}
+	unlock()
+}
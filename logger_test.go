@@ -0,0 +1,155 @@
+package dbkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+)
+
+type loggedUser struct {
+	bun.BaseModel `bun:"table:logged_users"`
+	ID            int64  `bun:"id,pk"`
+	Name          string `bun:"name"`
+	Password      string `bun:"password"`
+}
+
+// paramsFromLog returns the params attribute of every JSON log entry in buf.
+func paramsFromLog(t *testing.T, buf *bytes.Buffer) [][]any {
+	t.Helper()
+
+	var all [][]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry struct {
+			Params []any `json:"params"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Log entry is not valid JSON: %v\n%s", err, line)
+		}
+		all = append(all, entry.Params)
+	}
+	return all
+}
+
+func newParamLoggingDB(t *testing.T, buf *bytes.Buffer, configure func(*Config)) *DBKit {
+	t.Helper()
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cfg := DefaultConfig("fake").WithLogger(logger)
+	configure(&cfg)
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestLoggerHook_Parameters(t *testing.T) {
+	var buf bytes.Buffer
+	db := newParamLoggingDB(t, &buf, func(cfg *Config) {
+		cfg.LogParameters = true
+		cfg.LogSensitiveParams = []int{2}
+	})
+
+	ctx := context.Background()
+	if _, err := db.NewInsert().Model(&loggedUser{ID: 7, Name: "Alice", Password: "hunter2"}).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	params := paramsFromLog(t, &buf)
+	if len(params) != 1 {
+		t.Fatalf("Expected one log entry, got %d", len(params))
+	}
+	want := []any{float64(7), "Alice", "[REDACTED]"}
+	if len(params[0]) != len(want) {
+		t.Fatalf("Expected params %v, got %v", want, params[0])
+	}
+	for i := range want {
+		if params[0][i] != want[i] {
+			t.Errorf("Expected params %v, got %v", want, params[0])
+		}
+	}
+	if strings.Contains(buf.String(), `"hunter2"]`) {
+		t.Error("Expected the sensitive parameter to be redacted")
+	}
+}
+
+func TestLoggerHook_ParametersMultiRow(t *testing.T) {
+	var buf bytes.Buffer
+	db := newParamLoggingDB(t, &buf, func(cfg *Config) {
+		cfg.LogParameters = true
+		cfg.LogSensitiveParams = []int{2}
+	})
+
+	users := []loggedUser{
+		{ID: 1, Name: "Alice", Password: "hunter2"},
+		{ID: 2, Name: "Bob", Password: "swordfish"},
+		{ID: 3, Name: "Carol", Password: "letmein"},
+	}
+	if _, err := db.NewInsert().Model(&users).Exec(context.Background()); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	params := paramsFromLog(t, &buf)
+	if len(params) != 1 || len(params[0]) != 9 {
+		t.Fatalf("Expected one entry with 9 params, got %v", params)
+	}
+	for row := range users {
+		if got := params[0][row*3+2]; got != "[REDACTED]" {
+			t.Errorf("Expected the password of row %d to be redacted, got %v", row+1, got)
+		}
+	}
+	for _, password := range []string{"hunter2", "swordfish", "letmein"} {
+		if strings.Contains(buf.String(), `"`+password+`"`) {
+			t.Errorf("Password %q was logged", password)
+		}
+	}
+}
+
+func TestLoggerHook_ParametersOptIn(t *testing.T) {
+	var buf bytes.Buffer
+	db := newParamLoggingDB(t, &buf, func(*Config) {})
+
+	if _, err := db.NewInsert().Model(&loggedUser{ID: 7, Name: "Alice"}).Exec(context.Background()); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if params := paramsFromLog(t, &buf); params[0] != nil {
+		t.Errorf("Expected no params unless enabled, got %v", params[0])
+	}
+}
+
+func TestLoggerHook_ParametersRawAndTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	hook := hooks.NewLoggerHook(logger, true, 0)
+	hook.LogParameters = true
+	hook.MaxParamLength = 5
+
+	event := &bun.QueryEvent{
+		Query:     "SELECT * FROM users WHERE name = 'abcdefgh' AND id = 1",
+		QueryArgs: []any{"abcdefgh", 1, []byte("xyz")},
+		StartTime: time.Now(),
+	}
+	hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+
+	params := paramsFromLog(t, &buf)[0]
+	if len(params) != 3 || params[0] != "abcde..." || params[1] != float64(1) || params[2] != "xyz" {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	// Select builders inline their values, there are no parameters to add
+	buf.Reset()
+	db, _ := newFakeDB(t)
+	q := db.NewSelect().Model((*loggedUser)(nil)).Where("id = ?", 1)
+	event = &bun.QueryEvent{IQuery: q, Query: q.String(), StartTime: time.Now()}
+	hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+	if params := paramsFromLog(t, &buf)[0]; params != nil {
+		t.Errorf("Expected no params for a select, got %v", params)
+	}
+}
@@ -0,0 +1,164 @@
+package dbkit
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/fernandezvara/dbkit/hooks"
+)
+
+// DefaultRefreshDebounce is the default delay between a write and the view refresh
+const DefaultRefreshDebounce = time.Second
+
+// RefreshMaterializedView executes REFRESH MATERIALIZED VIEW [CONCURRENTLY] viewName.
+// CONCURRENTLY keeps the view readable during the refresh but requires a unique index on it.
+//
+// Usage:
+//
+//	err := dbkit.RefreshMaterializedView(ctx, db, "sales_summary", true)
+func RefreshMaterializedView(ctx context.Context, db IDB, viewName string, concurrently bool) error {
+	query := "REFRESH MATERIALIZED VIEW ?"
+	if concurrently {
+		query = "REFRESH MATERIALIZED VIEW CONCURRENTLY ?"
+	}
+	if _, err := db.NewRaw(query, bun.Ident(viewName)).Exec(ctx); err != nil {
+		return wrapError(err, "RefreshMaterializedView")
+	}
+	return nil
+}
+
+// AutoRefreshHook refreshes a materialized view in the background after writes to its source tables.
+// Writes within Debounce of each other trigger a single refresh. Only bun INSERT, UPDATE and
+// DELETE queries are detected, raw SQL is not.
+//
+// The hook cannot tell which transaction a write belongs to, so a write inside a transaction
+// still schedules a refresh that may run before the COMMIT and miss it. The next successful
+// COMMIT after a write schedules another refresh, which picks the committed rows up.
+type AutoRefreshHook struct {
+	Debounce time.Duration // Delay after the last write before refreshing (default: 1s)
+
+	db            *DBKit
+	viewName      string
+	triggerTables []string
+	concurrently  bool
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	stopped   bool
+	refreshMu sync.Mutex // serializes refreshes
+
+	uncommitted   atomic.Bool  // a write was seen since the last COMMIT
+	lastRefreshed atomic.Int64 // unix nanoseconds
+	refreshCount  atomic.Int64
+}
+
+// NewAutoRefreshHook creates a hook that refreshes viewName after writes to triggerTables.
+// Writes in a transaction are refreshed once more after the next COMMIT, see AutoRefreshHook.
+//
+// Usage:
+//
+//	hook := dbkit.NewAutoRefreshHook(db, "sales_summary", []string{"orders", "order_items"}, true)
+//	hook.Debounce = 5 * time.Second
+//	db.AddQueryHook(hook)
+//	defer hook.Stop()
+func NewAutoRefreshHook(db *DBKit, viewName string, triggerTables []string, concurrently bool) *AutoRefreshHook {
+	return &AutoRefreshHook{
+		Debounce:      DefaultRefreshDebounce,
+		db:            db,
+		viewName:      viewName,
+		triggerTables: triggerTables,
+		concurrently:  concurrently,
+	}
+}
+
+// BeforeQuery implements bun.QueryHook
+func (h *AutoRefreshHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery schedules a refresh after successful writes to a trigger table, and after the
+// COMMIT that follows them
+func (h *AutoRefreshHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if event.Err != nil {
+		return
+	}
+	if event.Query == "COMMIT" {
+		if h.uncommitted.Swap(false) {
+			h.schedule()
+		}
+		return
+	}
+	switch hooks.OperationType(event.Query) {
+	case "insert", "update", "delete":
+	default:
+		return
+	}
+	if !slices.Contains(h.triggerTables, hooks.QueryTable(event.IQuery)) {
+		return
+	}
+	h.uncommitted.Store(true)
+	h.schedule()
+}
+
+// schedule starts or postpones the debounced refresh.
+func (h *AutoRefreshHook) schedule() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		return
+	}
+	if h.timer != nil {
+		h.timer.Reset(h.Debounce)
+		return
+	}
+	h.timer = time.AfterFunc(h.Debounce, h.refresh)
+}
+
+// refresh runs the refresh, logging failures.
+func (h *AutoRefreshHook) refresh() {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	if err := RefreshMaterializedView(context.Background(), h.db, h.viewName, h.concurrently); err != nil {
+		logger := h.db.config.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Error("materialized view refresh failed", slog.String("view", h.viewName), slog.Any("error", err))
+		return
+	}
+	h.lastRefreshed.Store(time.Now().UnixNano())
+	h.refreshCount.Add(1)
+}
+
+// Stop cancels a pending refresh and ignores later writes. Call it before closing the database.
+func (h *AutoRefreshHook) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+}
+
+// LastRefreshed returns when the view was last refreshed successfully, or the zero time
+func (h *AutoRefreshHook) LastRefreshed() time.Time {
+	ns := h.lastRefreshed.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// RefreshCount returns the number of successful refreshes
+func (h *AutoRefreshHook) RefreshCount() int64 {
+	return h.refreshCount.Load()
+}
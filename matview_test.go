@@ -0,0 +1,163 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestAutoRefreshHook_Debounce(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	hook := NewAutoRefreshHook(db, "test_models_summary", []string{"test_models"}, false)
+	hook.Debounce = 50 * time.Millisecond
+	db.AddQueryHook(hook)
+	defer hook.Stop()
+
+	if !hook.LastRefreshed().IsZero() || hook.RefreshCount() != 0 {
+		t.Fatal("Expected no refresh before any write")
+	}
+
+	// Reads and writes to other tables do not trigger a refresh
+	_, _ = db.NewSelect().Model((*TestModel)(nil)).Exec(ctx)
+	_, _ = db.NewUpdate().Table("other").Set("x = 1").Where("TRUE").Exec(ctx)
+
+	for range 5 {
+		if _, err := db.NewInsert().Model(&TestModel{Name: "n"}).Returning("NULL").Exec(ctx); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	if !waitFor(t, time.Second, func() bool { return hook.RefreshCount() == 1 }) {
+		t.Fatalf("Expected one debounced refresh, got %d", hook.RefreshCount())
+	}
+	if hook.LastRefreshed().IsZero() {
+		t.Error("Expected LastRefreshed to be set")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if hook.RefreshCount() != 1 {
+		t.Errorf("Expected a single refresh for the batch, got %d", hook.RefreshCount())
+	}
+}
+
+func TestAutoRefreshHook_Stop(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	hook := NewAutoRefreshHook(db, "test_models_summary", []string{"test_models"}, false)
+	hook.Debounce = 20 * time.Millisecond
+	db.AddQueryHook(hook)
+
+	if _, err := db.NewDelete().Model((*TestModel)(nil)).Where("TRUE").Exec(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	hook.Stop()
+	if _, err := db.NewDelete().Model((*TestModel)(nil)).Where("TRUE").Exec(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if hook.RefreshCount() != 0 {
+		t.Errorf("Expected no refresh after Stop, got %d", hook.RefreshCount())
+	}
+}
+
+func TestAutoRefreshHook_RefreshesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	hook := NewAutoRefreshHook(db, "test_models_summary", []string{"test_models"}, false)
+	hook.Debounce = 20 * time.Millisecond
+	db.AddQueryHook(hook)
+	defer hook.Stop()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if _, err := tx.NewInsert().Model(&TestModel{Name: "n"}).Returning("NULL").Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	// The debounce elapses before the transaction commits
+	if !waitFor(t, time.Second, func() bool { return hook.RefreshCount() == 1 }) {
+		t.Fatalf("Expected a refresh after the write, got %d", hook.RefreshCount())
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if !waitFor(t, time.Second, func() bool { return hook.RefreshCount() == 2 }) {
+		t.Fatalf("Expected another refresh after the COMMIT, got %d", hook.RefreshCount())
+	}
+
+	// Commits without writes to a trigger table don't refresh
+	if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error { return nil }); err != nil {
+		t.Fatalf("RunInTx failed: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if hook.RefreshCount() != 2 {
+		t.Errorf("Expected no refresh after an empty transaction, got %d", hook.RefreshCount())
+	}
+}
+
+func TestRefreshMaterializedView(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	if _, err := db.ExecContext(ctx, "DROP MATERIALIZED VIEW IF EXISTS test_models_summary"); err != nil {
+		t.Fatalf("Drop view failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "CREATE MATERIALIZED VIEW test_models_summary AS SELECT 1 AS id, COUNT(*) AS total FROM test_models"); err != nil {
+		t.Fatalf("Create view failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "CREATE UNIQUE INDEX ON test_models_summary (id)"); err != nil {
+		t.Fatalf("Create index failed: %v", err)
+	}
+
+	total := func() int {
+		var n int
+		if err := db.NewRaw("SELECT total FROM test_models_summary").Scan(ctx, &n); err != nil {
+			t.Fatalf("Query view failed: %v", err)
+		}
+		return n
+	}
+
+	hook := NewAutoRefreshHook(db, "test_models_summary", []string{"test_models"}, true)
+	hook.Debounce = 100 * time.Millisecond
+	db.AddQueryHook(hook)
+	defer hook.Stop()
+
+	insertTestModels(t, db, 10)
+	if total() != 0 {
+		t.Error("Expected the view to be stale before the debounce elapsed")
+	}
+	if !waitFor(t, 2*time.Second, func() bool { return total() == 10 }) {
+		t.Errorf("Expected the view to be refreshed to 10 rows, got %d", total())
+	}
+	if hook.RefreshCount() != 1 {
+		t.Errorf("Expected one refresh, got %d", hook.RefreshCount())
+	}
+
+	if err := RefreshMaterializedView(ctx, db, "test_models_summary", false); err != nil {
+		t.Errorf("RefreshMaterializedView failed: %v", err)
+	}
+	if err := RefreshMaterializedView(ctx, db, "missing_view", false); err == nil {
+		t.Error("Expected an error for a missing view")
+	}
+}
@@ -0,0 +1,383 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uptrace/bun"
+)
+
+type metricsUser struct {
+	bun.BaseModel `bun:"table:users"`
+	ID            int64  `bun:"id,pk"`
+	Name          string `bun:"name"`
+}
+
+// queryTotals returns dbkit_queries_total by operation and table.
+func queryTotals(t *testing.T, registry *prometheus.Registry) map[[2]string]float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	totals := make(map[[2]string]float64)
+	for _, family := range families {
+		if family.GetName() != "dbkit_queries_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			totals[[2]string{labels["operation"], labels["table"]}] += metric.GetCounter().GetValue()
+		}
+	}
+	return totals
+}
+
+func TestMetricsHook_TableLabel(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	db, err := newDBKit(DefaultConfig("fake").WithMetrics(registry), &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.NewInsert().Model(&metricsUser{ID: 1, Name: "Alice"}).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	totals := queryTotals(t, registry)
+	if totals[[2]string{"insert", "users"}] != 1 {
+		t.Errorf(`Expected one query with {operation="insert", table="users"}, got %v`, totals)
+	}
+	if totals[[2]string{"select", hooks.OtherTable}] != 1 {
+		t.Errorf(`Expected raw query with {operation="select", table="other"}, got %v`, totals)
+	}
+}
+
+func TestMetricsHook_TableCardinality(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	hook, err := hooks.NewMetricsHook(registry, hooks.MetricsHookOptions{
+		ExcludeTables: []string{"bun_migrations"},
+		MaxTables:     2,
+	})
+	if err != nil {
+		t.Fatalf("NewMetricsHook failed: %v", err)
+	}
+
+	db, _ := newFakeDB(t)
+	for _, table := range []string{"bun_migrations", "a", "b", "c", "a"} {
+		q := db.NewSelect().Table(table)
+		event := &bun.QueryEvent{IQuery: q, Query: q.String(), StartTime: time.Now()}
+		hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+	}
+
+	totals := queryTotals(t, registry)
+	want := map[[2]string]float64{
+		{"select", "a"}:              2,
+		{"select", "b"}:              1,
+		{"select", hooks.OtherTable}: 2,
+	}
+	for key, count := range want {
+		if totals[key] != count {
+			t.Errorf("Expected %v for %v, got %v", count, key, totals)
+		}
+	}
+	if len(totals) != len(want) {
+		t.Errorf("Unexpected label sets: %v", totals)
+	}
+}
+
+func TestFingerprintQuery(t *testing.T) {
+	same := [][2]string{
+		{`SELECT * FROM "users" WHERE id = 1`, `SELECT * FROM "users" WHERE id = 2`},
+		{`SELECT * FROM users WHERE name = 'alice'`, `SELECT * FROM users WHERE name = 'bob''s'`},
+		{`SELECT * FROM users WHERE id = $1`, `SELECT * FROM users WHERE id = ?`},
+		{
+			`SELECT * FROM users WHERE id = '550e8400-e29b-41d4-a716-446655440000'`,
+			`SELECT * FROM users WHERE id = 6ba7b810-9dad-11d1-80b4-00c04fd430c8`,
+		},
+		{`SELECT * FROM users WHERE id IN (1, 2, 3)`, `SELECT * FROM users WHERE id IN (4)`},
+		{`INSERT INTO users (id, name) VALUES (1, 'a'), (2, 'b')`, `INSERT INTO users (id, name) VALUES (3, 'c')`},
+	}
+	for _, pair := range same {
+		if a, b := hooks.FingerprintQuery(pair[0]), hooks.FingerprintQuery(pair[1]); a != b {
+			t.Errorf("Expected equal fingerprints, got %q and %q", a, b)
+		}
+	}
+
+	different := [][2]string{
+		{`SELECT * FROM users WHERE id = 1`, `SELECT * FROM orders WHERE id = 1`},
+		{`SELECT * FROM users WHERE id = 1`, `SELECT * FROM users WHERE id = 1 AND active = TRUE`},
+		{`SELECT id FROM users`, `SELECT name FROM users`},
+	}
+	for _, pair := range different {
+		if a, b := hooks.FingerprintQuery(pair[0]), hooks.FingerprintQuery(pair[1]); a == b {
+			t.Errorf("Expected different fingerprints for %q and %q", pair[0], pair[1])
+		}
+	}
+
+	if got := hooks.FingerprintQuery("SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'bob'"); got != "SELECT * FROM users WHERE id IN (?) AND name = ?" {
+		t.Errorf("Unexpected fingerprint %q", got)
+	}
+}
+
+func TestMetricsHook_QueryFingerprint(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	hook, err := hooks.NewMetricsHook(registry, hooks.MetricsHookOptions{
+		EnableQueryFingerprint: true,
+		MaxFingerprints:        2,
+	})
+	if err != nil {
+		t.Fatalf("NewMetricsHook failed: %v", err)
+	}
+
+	for _, query := range []string{
+		"SELECT * FROM a WHERE id = 1",
+		"SELECT * FROM a WHERE id = 2",
+		"SELECT * FROM b",
+		"SELECT * FROM c",
+		"SELECT * FROM d",
+	} {
+		event := &bun.QueryEvent{Query: query, StartTime: time.Now()}
+		hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "dbkit_queries_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "query" {
+					got[label.GetValue()] += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"SELECT * FROM a WHERE id = ?": 2,
+		"SELECT * FROM b":              1,
+		hooks.OtherFingerprint:         2,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Unexpected query labels: %v", got)
+	}
+	for query, count := range want {
+		if got[query] != count {
+			t.Errorf("Expected %v for %q, got %v", count, query, got)
+		}
+	}
+}
+
+// histogramCounts returns the sample counts of a histogram by label values joined with ",".
+func histogramCounts(t *testing.T, registry *prometheus.Registry, name string) map[string]uint64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var values []string
+			for _, label := range metric.GetLabel() {
+				values = append(values, label.GetValue())
+			}
+			counts[strings.Join(values, ",")] += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return counts
+}
+
+func TestTransactionHook_Duration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	db, err := newDBKit(DefaultConfig("fake").WithMetrics(registry), &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+	for i := 0; i < 10; i++ {
+		err := db.Transaction(ctx, func(tx *Tx) error {
+			time.Sleep(time.Duration(i) * time.Millisecond)
+			// Savepoints are not transactions of their own
+			_ = tx.Transaction(ctx, func(*Tx) error { return nil })
+			if i%3 == 0 {
+				return errRollback
+			}
+			return nil
+		})
+		if i%3 == 0 && !errors.Is(err, errRollback) {
+			t.Fatalf("Expected the rollback error, got %v", err)
+		}
+	}
+
+	counts := histogramCounts(t, registry, "dbkit_transaction_duration_seconds")
+	if counts["commit"] != 6 || counts["rollback"] != 4 {
+		t.Errorf("Expected 6 commits and 4 rollbacks, got %v", counts)
+	}
+	if len(counts) != 2 {
+		t.Errorf("Unexpected label sets: %v", counts)
+	}
+}
+
+func TestTransactionHook_IsolationLevel(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	cfg := DefaultConfig("fake").WithMetrics(registry)
+	cfg.TxMetrics = hooks.TransactionHookOptions{Buckets: []float64{.1, 1}, IsolationLevelLabel: true}
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	_ = db.TransactionWithOptions(ctx, SerializableTxOptions(), func(*Tx) error { return nil })
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	_ = tx.Rollback()
+
+	// Labels are sorted by name: isolation_level, outcome
+	counts := histogramCounts(t, registry, "dbkit_transaction_duration_seconds")
+	if counts["serializable,commit"] != 1 {
+		t.Errorf("Expected a serializable commit, got %v", counts)
+	}
+	if counts["default,rollback"] != 1 {
+		t.Errorf("Expected a default rollback, got %v", counts)
+	}
+}
+
+// counterValue returns the value of an unlabeled counter.
+func counterValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestPoolWaitHook_Exhaustion(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	cfg := DefaultConfig("fake").WithMetrics(registry)
+	cfg.MaxOpenConns = 1
+	cfg.MaxIdleConns = 1
+	cfg.PoolWait = hooks.PoolWaitHookOptions{ExhaustionThreshold: 20 * time.Millisecond}
+	db, err := newDBKit(cfg, &fakeConnector{queryDelay: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if got := counterValue(t, registry, "dbkit_pool_exhaustion_total"); got != 0 {
+		t.Errorf("Expected no exhaustion on an idle pool, got %v", got)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+				t.Errorf("Exec failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The single connection serves the queries one at a time, so at least two wait 50ms
+	if got := counterValue(t, registry, "dbkit_pool_exhaustion_total"); got < 2 {
+		t.Errorf("Expected at least 2 exhausted waits, got %v", got)
+	}
+	if counts := histogramCounts(t, registry, "dbkit_connection_wait_seconds"); counts["select"] != 4 {
+		t.Errorf("Expected 4 wait observations, got %v", counts)
+	}
+}
+
+func TestMetricsHook_QueryDurationBuckets(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	cfg := DefaultConfig("fake").WithMetrics(registry)
+	cfg.MetricsOptions = &hooks.MetricsHookOptions{QueryDurationBuckets: []float64{0.001, 0.01, 0.1}}
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(context.Background(), "SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	found := false
+	for _, family := range families {
+		if family.GetName() != "dbkit_query_duration_seconds" {
+			continue
+		}
+		found = true
+		if buckets := family.GetMetric()[0].GetHistogram().GetBucket(); len(buckets) != 3 {
+			t.Errorf("Expected 3 buckets, got %d", len(buckets))
+		}
+	}
+	if !found {
+		t.Fatal("Expected dbkit_query_duration_seconds to be registered")
+	}
+}
+
+func TestMetricsHook_InvalidBuckets(t *testing.T) {
+	for _, buckets := range [][]float64{{}, {0, 1}, {-1, 1}, {0.1, 0.1}, {1, 0.5}} {
+		_, err := hooks.NewMetricsHook(prometheus.NewRegistry(), hooks.MetricsHookOptions{QueryDurationBuckets: buckets})
+		if err == nil {
+			t.Errorf("Expected an error for buckets %v", buckets)
+		}
+	}
+
+	cfg := DefaultConfig("fake").WithMetrics(prometheus.NewRegistry())
+	cfg.TxMetrics.Buckets = []float64{1, 1}
+	if _, err := newDBKit(cfg, &fakeConnector{}); err == nil {
+		t.Error("Expected New to reject invalid transaction buckets")
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
 // Migration represents a single migration to execute
 type Migration struct {
-	ID          string // Unique identifier (e.g., "001", "20240115120000", or any string)
-	Description string // Human-readable description
-	SQL         string // SQL statements to execute
+	ID          string            // Unique identifier (e.g., "001", "20240115120000", or any string)
+	Description string            // Human-readable description
+	SQL         string            // SQL statements to execute
+	Author      string            // Who wrote the migration (optional)
+	Labels      map[string]string // Arbitrary metadata, e.g. {"env": "production"}, see FilterMigrations
 }
 
 // MigrationResult represents the result of running migrations
@@ -29,6 +32,8 @@ type AppliedMigration struct {
 	AppliedAt   time.Time
 	Duration    time.Duration
 	Checksum    string
+	Author      string
+	Labels      map[string]string
 }
 
 // migrationsTable is the schema for tracking migrations
@@ -40,6 +45,8 @@ CREATE TABLE IF NOT EXISTS _dbkit_migrations (
     applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     duration_ms BIGINT NOT NULL
 );
+ALTER TABLE _dbkit_migrations ADD COLUMN IF NOT EXISTS labels JSONB;
+ALTER TABLE _dbkit_migrations ADD COLUMN IF NOT EXISTS author VARCHAR(255);
 `
 
 // Migrate executes migrations in order, skipping already-applied ones
@@ -50,6 +57,16 @@ func (db *DBKit) Migrate(ctx context.Context, migrations []Migration) (*Migratio
 		Skipped: make([]string, 0),
 	}
 
+	// Reject duplicate IDs before touching the database
+	if err := ValidateMigrationIDs(migrations); err != nil {
+		return nil, &Error{
+			Code:    CodeValidation,
+			Message: err.Error(),
+			Op:      "Migrate",
+			Cause:   err,
+		}
+	}
+
 	// Ensure migrations table exists
 	if _, err := db.ExecContext(ctx, migrationsTable); err != nil {
 		return nil, &Error{
@@ -97,6 +114,8 @@ func (db *DBKit) Migrate(ctx context.Context, migrations []Migration) (*Migratio
 			AppliedAt:   time.Now(),
 			Duration:    duration,
 			Checksum:    checksum,
+			Author:      m.Author,
+			Labels:      m.Labels,
 		})
 	}
 
@@ -144,11 +163,16 @@ func (db *DBKit) applyMigration(ctx context.Context, m Migration, checksum strin
 		// Calculate duration
 		durationMs := time.Since(startTime).Milliseconds()
 
+		labels, err := migrationLabels(m.Labels)
+		if err != nil {
+			return wrapError(err, "Migrate.Record")
+		}
+
 		// Record migration
-		_, err := tx.NewRaw(`
-            INSERT INTO _dbkit_migrations (id, description, checksum, duration_ms)
-            VALUES (?, ?, ?, ?)
-        `, m.ID, m.Description, checksum, durationMs).Exec(ctx)
+		_, err = tx.NewRaw(`
+            INSERT INTO _dbkit_migrations (id, description, checksum, duration_ms, labels, author)
+            VALUES (?, ?, ?, ?, ?::jsonb, NULLIF(?, ''))
+        `, m.ID, m.Description, checksum, durationMs, labels, m.Author).Exec(ctx)
 
 		if err != nil {
 			return wrapError(err, "Migrate.Record")
@@ -195,6 +219,20 @@ func (db *DBKit) MigrationStatus(ctx context.Context, migrations []Migration) ([
 	return result, nil
 }
 
+// HasPendingMigrations reports whether any of the migrations has not been applied
+func (db *DBKit) HasPendingMigrations(ctx context.Context, migrations []Migration) (bool, error) {
+	status, err := db.MigrationStatus(ctx, migrations)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range status {
+		if !entry.Applied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // MigrationStatusEntry represents the status of a single migration
 type MigrationStatusEntry struct {
 	ID            string
@@ -217,16 +255,18 @@ func (db *DBKit) GetAppliedMigrations(ctx context.Context) ([]AppliedMigration,
 	}
 
 	var rows []struct {
-		ID          string    `bun:"id"`
-		Description string    `bun:"description"`
-		Checksum    string    `bun:"checksum"`
-		AppliedAt   time.Time `bun:"applied_at"`
-		DurationMs  int64     `bun:"duration_ms"`
+		ID          string            `bun:"id"`
+		Description string            `bun:"description"`
+		Checksum    string            `bun:"checksum"`
+		AppliedAt   time.Time         `bun:"applied_at"`
+		DurationMs  int64             `bun:"duration_ms"`
+		Author      string            `bun:"author"`
+		Labels      map[string]string `bun:"labels"`
 	}
 
 	err := db.NewSelect().
 		TableExpr("_dbkit_migrations").
-		Column("id", "description", "checksum", "applied_at", "duration_ms").
+		Column("id", "description", "checksum", "applied_at", "duration_ms", "author", "labels").
 		OrderExpr("applied_at ASC").
 		Scan(ctx, &rows)
 
@@ -242,12 +282,42 @@ func (db *DBKit) GetAppliedMigrations(ctx context.Context) ([]AppliedMigration,
 			AppliedAt:   row.AppliedAt,
 			Duration:    time.Duration(row.DurationMs) * time.Millisecond,
 			Checksum:    row.Checksum,
+			Author:      row.Author,
+			Labels:      row.Labels,
 		}
 	}
 
 	return result, nil
 }
 
+// migrationLabels encodes labels for the jsonb column, nil when there are none
+func migrationLabels(labels map[string]string) (*string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
+
+// FilterMigrations returns the migrations whose label is set to value, keeping their order.
+//
+// Usage:
+//
+//	_, err := db.Migrate(ctx, dbkit.FilterMigrations(all, "env", "production"))
+func FilterMigrations(migrations []Migration, label string, value string) []Migration {
+	var filtered []Migration
+	for _, m := range migrations {
+		if v, ok := m.Labels[label]; ok && v == value {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
 // checksumSQL creates a SHA256 checksum of SQL content
 func checksumSQL(sql string) string {
 	hash := sha256.Sum256([]byte(sql))
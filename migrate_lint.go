@@ -0,0 +1,155 @@
+package dbkit
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Checks reported by MigrationLinter
+const (
+	LintDuplicateID        = "duplicate-id"
+	LintUnsortedID         = "unsorted-id"
+	LintEmptySQL           = "empty-sql"
+	LintMissingDescription = "missing-description"
+	LintUnsafeDropTable    = "drop-table-without-if-exists"
+)
+
+// dropTablePattern matches DROP TABLE, capturing IF EXISTS when present
+var dropTablePattern = regexp.MustCompile(`(?i)\bDROP\s+TABLE\s+(IF\s+EXISTS\b)?`)
+
+// MigrationConflict is a migration ID used by more than one migration
+type MigrationConflict struct {
+	ID      string
+	Indexes []int    // Positions of the migrations using ID (ValidateMigrationIDs)
+	Sources []string // Sources contributing ID (MigrationRegistry.Build)
+}
+
+// MigrationConflictError lists every duplicated migration ID
+type MigrationConflictError struct {
+	Conflicts []MigrationConflict
+}
+
+func (e *MigrationConflictError) Error() string {
+	msgs := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		if len(c.Sources) > 0 {
+			msgs[i] = fmt.Sprintf("%s contributed by sources %v", c.ID, c.Sources)
+		} else {
+			msgs[i] = fmt.Sprintf("%s used by migrations %v", c.ID, c.Indexes)
+		}
+	}
+	return "duplicate migration IDs: " + strings.Join(msgs, "; ")
+}
+
+// MigrationLintIssue is a problem found by MigrationLinter
+type MigrationLintIssue struct {
+	ID      string // Migration ID
+	Index   int    // Position of the migration in the slice
+	Check   string // One of the Lint* constants
+	Message string
+}
+
+func (i MigrationLintIssue) String() string {
+	return fmt.Sprintf("%s [%s]: %s", i.ID, i.Check, i.Message)
+}
+
+// ValidateMigrationIDs returns a *MigrationConflictError if two migrations share an ID.
+// Migrate runs it before touching the database.
+//
+// Usage:
+//
+//	if err := dbkit.ValidateMigrationIDs(migrations); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateMigrationIDs(migrations []Migration) error {
+	conflicts := migrationConflicts(migrations)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return &MigrationConflictError{Conflicts: conflicts}
+}
+
+// migrationConflicts returns the duplicated IDs in order of first use
+func migrationConflicts(migrations []Migration) []MigrationConflict {
+	indexes := make(map[string][]int, len(migrations))
+	var order []string
+	for i, m := range migrations {
+		if _, ok := indexes[m.ID]; !ok {
+			order = append(order, m.ID)
+		}
+		indexes[m.ID] = append(indexes[m.ID], i)
+	}
+
+	var conflicts []MigrationConflict
+	for _, id := range order {
+		if len(indexes[id]) > 1 {
+			conflicts = append(conflicts, MigrationConflict{ID: id, Indexes: indexes[id]})
+		}
+	}
+	return conflicts
+}
+
+// ValidateMigrationOrder returns an error if the migration IDs are not sorted lexicographically,
+// which keeps timestamp-based IDs in the order they were written.
+//
+// Usage:
+//
+//	if err := dbkit.ValidateMigrationOrder(migrations); err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateMigrationOrder(migrations []Migration) error {
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].ID < migrations[i-1].ID {
+			return &Error{
+				Code:    CodeValidation,
+				Message: fmt.Sprintf("migration %s is listed after %s", migrations[i].ID, migrations[i-1].ID),
+				Op:      "ValidateMigrationOrder",
+			}
+		}
+	}
+	return nil
+}
+
+// MigrationLinter checks migrations for duplicate and unsorted IDs, empty SQL, missing
+// descriptions and DROP TABLE without IF EXISTS. Issues are sorted by migration position.
+//
+// Usage:
+//
+//	for _, issue := range dbkit.MigrationLinter(migrations) {
+//	    fmt.Println(issue)
+//	}
+func MigrationLinter(migrations []Migration) []MigrationLintIssue {
+	var issues []MigrationLintIssue
+	add := func(i int, check, msg string) {
+		issues = append(issues, MigrationLintIssue{ID: migrations[i].ID, Index: i, Check: check, Message: msg})
+	}
+
+	for _, c := range migrationConflicts(migrations) {
+		for _, i := range c.Indexes[1:] {
+			add(i, LintDuplicateID, fmt.Sprintf("ID already used by migration %d", c.Indexes[0]))
+		}
+	}
+
+	for i, m := range migrations {
+		if i > 0 && m.ID < migrations[i-1].ID {
+			add(i, LintUnsortedID, fmt.Sprintf("ID sorts before the previous migration %s", migrations[i-1].ID))
+		}
+		if strings.TrimSpace(m.SQL) == "" {
+			add(i, LintEmptySQL, "SQL is empty")
+		}
+		if strings.TrimSpace(m.Description) == "" {
+			add(i, LintMissingDescription, "description is empty")
+		}
+		for _, match := range dropTablePattern.FindAllStringSubmatch(m.SQL, -1) {
+			if match[1] == "" {
+				add(i, LintUnsafeDropTable, "DROP TABLE without IF EXISTS")
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool { return issues[a].Index < issues[b].Index })
+	return issues
+}
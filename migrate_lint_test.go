@@ -0,0 +1,114 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func wellFormedMigrations() []Migration {
+	return []Migration{
+		{ID: "20240101120000", Description: "Create users", SQL: "CREATE TABLE users (id BIGSERIAL PRIMARY KEY);"},
+		{ID: "20240102120000", Description: "Drop legacy table", SQL: "DROP TABLE IF EXISTS legacy_users;"},
+		{ID: "20240103120000", Description: "Add name", SQL: "ALTER TABLE users ADD COLUMN name TEXT;"},
+	}
+}
+
+func TestValidateMigrationIDs(t *testing.T) {
+	if err := ValidateMigrationIDs(wellFormedMigrations()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	migrations := append(wellFormedMigrations(),
+		Migration{ID: "20240101120000", Description: "Other branch", SQL: "SELECT 1"},
+		Migration{ID: "20240103120000", Description: "Other branch", SQL: "SELECT 2"},
+		Migration{ID: "20240101120000", Description: "Third branch", SQL: "SELECT 3"},
+	)
+	err := ValidateMigrationIDs(migrations)
+
+	var conflictErr *MigrationConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected MigrationConflictError, got %v", err)
+	}
+	want := []MigrationConflict{
+		{ID: "20240101120000", Indexes: []int{0, 3, 5}},
+		{ID: "20240103120000", Indexes: []int{2, 4}},
+	}
+	if !reflect.DeepEqual(conflictErr.Conflicts, want) {
+		t.Errorf("expected %v, got %v", want, conflictErr.Conflicts)
+	}
+}
+
+func TestMigrateRejectsDuplicateIDs(t *testing.T) {
+	db, connector := newFakeDB(t)
+	migrations := []Migration{{ID: "001", SQL: "SELECT 1"}, {ID: "001", SQL: "SELECT 2"}}
+
+	_, err := db.Migrate(context.Background(), migrations)
+	if !IsValidation(err) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	var conflictErr *MigrationConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Errorf("expected MigrationConflictError as cause, got %v", err)
+	}
+	if connector.dials.Load() != 0 {
+		t.Error("expected Migrate not to touch the database")
+	}
+}
+
+func TestValidateMigrationOrder(t *testing.T) {
+	if err := ValidateMigrationOrder(wellFormedMigrations()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	migrations := wellFormedMigrations()
+	migrations[1], migrations[2] = migrations[2], migrations[1]
+	if err := ValidateMigrationOrder(migrations); !IsValidation(err) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
+
+func TestMigrationLinter(t *testing.T) {
+	if issues := MigrationLinter(wellFormedMigrations()); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+
+	tests := []struct {
+		name   string
+		modify func([]Migration) []Migration
+		check  string
+		index  int
+	}{
+		{"duplicate ID", func(m []Migration) []Migration {
+			return append(m, Migration{ID: m[2].ID, Description: "Copy", SQL: "SELECT 1"})
+		}, LintDuplicateID, 3},
+		{"unsorted ID", func(m []Migration) []Migration {
+			m[2].ID = "20231231120000"
+			return m
+		}, LintUnsortedID, 2},
+		{"empty SQL", func(m []Migration) []Migration {
+			m[1].SQL = "  \n "
+			return m
+		}, LintEmptySQL, 1},
+		{"missing description", func(m []Migration) []Migration {
+			m[0].Description = ""
+			return m
+		}, LintMissingDescription, 0},
+		{"drop table without if exists", func(m []Migration) []Migration {
+			m[1].SQL = "DROP TABLE IF EXISTS a;\ndrop  table legacy_users;"
+			return m
+		}, LintUnsafeDropTable, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := MigrationLinter(tt.modify(wellFormedMigrations()))
+			if len(issues) != 1 {
+				t.Fatalf("expected 1 issue, got %v", issues)
+			}
+			if issues[0].Check != tt.check || issues[0].Index != tt.index {
+				t.Errorf("expected %s at %d, got %v", tt.check, tt.index, issues[0])
+			}
+		})
+	}
+}
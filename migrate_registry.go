@@ -0,0 +1,114 @@
+package dbkit
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultMigrationRegistry is the package-level registry modules register their migrations with
+var DefaultMigrationRegistry *MigrationRegistry
+
+func init() {
+	DefaultMigrationRegistry = NewMigrationRegistry()
+}
+
+// MigrationRegistry merges migrations shipped by several sources, such as the host
+// application and its plugins. It is safe for concurrent use.
+//
+// Usage:
+//
+//	func init() {
+//	    dbkit.DefaultMigrationRegistry.Register("billing", 10, billingMigrations)
+//	}
+//
+//	migrations, err := dbkit.DefaultMigrationRegistry.Build()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	_, err = db.Migrate(ctx, migrations)
+type MigrationRegistry struct {
+	mu      sync.Mutex
+	sources map[string]migrationSource
+}
+
+type migrationSource struct {
+	priority   int
+	migrations []Migration
+}
+
+// NewMigrationRegistry creates an empty registry
+func NewMigrationRegistry() *MigrationRegistry {
+	return &MigrationRegistry{sources: make(map[string]migrationSource)}
+}
+
+// Register adds the migrations of source. Sources with a lower priority run first.
+// Registering a source again replaces its migrations.
+func (r *MigrationRegistry) Register(source string, priority int, migrations []Migration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[source] = migrationSource{
+		priority:   priority,
+		migrations: append([]Migration(nil), migrations...),
+	}
+}
+
+// UnregisterSource removes the migrations of source
+func (r *MigrationRegistry) UnregisterSource(source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sources, source)
+}
+
+// Build returns the registered migrations ordered by priority, then by ID within a priority.
+// It returns a *MigrationConflictError if an ID is contributed more than once.
+func (r *MigrationRegistry) Build() ([]Migration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type entry struct {
+		priority  int
+		migration Migration
+	}
+	var entries []entry
+	sources := make(map[string][]string)
+	var order []string
+	for _, name := range names {
+		src := r.sources[name]
+		for _, m := range src.migrations {
+			if _, ok := sources[m.ID]; !ok {
+				order = append(order, m.ID)
+			}
+			sources[m.ID] = append(sources[m.ID], name)
+			entries = append(entries, entry{priority: src.priority, migration: m})
+		}
+	}
+
+	var conflicts []MigrationConflict
+	for _, id := range order {
+		if len(sources[id]) > 1 {
+			conflicts = append(conflicts, MigrationConflict{ID: id, Sources: sources[id]})
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &MigrationConflictError{Conflicts: conflicts}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].priority != entries[j].priority {
+			return entries[i].priority < entries[j].priority
+		}
+		return entries[i].migration.ID < entries[j].migration.ID
+	})
+
+	migrations := make([]Migration, len(entries))
+	for i, e := range entries {
+		migrations[i] = e.migration
+	}
+	return migrations, nil
+}
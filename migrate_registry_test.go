@@ -0,0 +1,81 @@
+package dbkit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func migrationIDs(migrations []Migration) []string {
+	ids := make([]string, len(migrations))
+	for i, m := range migrations {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+func TestMigrationRegistryBuild(t *testing.T) {
+	r := NewMigrationRegistry()
+	r.Register("billing", 10, []Migration{
+		{ID: "billing_002", SQL: "SELECT 2"},
+		{ID: "billing_001", SQL: "SELECT 1"},
+	})
+	r.Register("app", 0, []Migration{
+		{ID: "app_002", SQL: "SELECT 2"},
+		{ID: "app_001", SQL: "SELECT 1"},
+	})
+	r.Register("audit", 10, []Migration{
+		{ID: "audit_001", SQL: "SELECT 1"},
+	})
+
+	migrations, err := r.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	want := []string{"app_001", "app_002", "audit_001", "billing_001", "billing_002"}
+	if got := migrationIDs(migrations); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	r.UnregisterSource("app")
+	migrations, _ = r.Build()
+	want = []string{"audit_001", "billing_001", "billing_002"}
+	if got := migrationIDs(migrations); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v after UnregisterSource, got %v", want, got)
+	}
+}
+
+func TestMigrationRegistryConflict(t *testing.T) {
+	r := NewMigrationRegistry()
+	r.Register("app", 0, []Migration{{ID: "001", SQL: "SELECT 1"}})
+	r.Register("plugin", 5, []Migration{{ID: "001", SQL: "SELECT 2"}})
+
+	_, err := r.Build()
+	var conflictErr *MigrationConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected MigrationConflictError, got %v", err)
+	}
+	want := []MigrationConflict{{ID: "001", Sources: []string{"app", "plugin"}}}
+	if !reflect.DeepEqual(conflictErr.Conflicts, want) {
+		t.Errorf("expected %v, got %v", want, conflictErr.Conflicts)
+	}
+
+	// Registering a source again replaces its migrations
+	r.Register("plugin", 5, []Migration{{ID: "plugin_001", SQL: "SELECT 2"}})
+	if _, err := r.Build(); err != nil {
+		t.Errorf("expected no conflict after replacing the source, got %v", err)
+	}
+}
+
+func TestDefaultMigrationRegistry(t *testing.T) {
+	if DefaultMigrationRegistry == nil {
+		t.Fatal("expected DefaultMigrationRegistry to be initialized")
+	}
+	DefaultMigrationRegistry.Register("test", 0, []Migration{{ID: "test_001", SQL: "SELECT 1"}})
+	defer DefaultMigrationRegistry.UnregisterSource("test")
+
+	migrations, err := DefaultMigrationRegistry.Build()
+	if err != nil || len(migrations) != 1 {
+		t.Errorf("expected the registered migration, got %v (%v)", migrations, err)
+	}
+}
@@ -0,0 +1,147 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SquashedMigrationID returns the ID SquashMigrations records for the range fromID to toID
+func SquashedMigrationID(fromID, toID string) string {
+	return fromID + "_to_" + toID
+}
+
+// SquashMigrations replaces the applied migrations with IDs between fromID and toID (inclusive,
+// compared lexicographically) with a single record for squashedSQL, identified by
+// SquashedMigrationID(fromID, toID). squashedSQL is not executed; the schema must already match it.
+//
+// squashedSQL runs on databases that never applied the range, so it must produce the same
+// schema and be idempotent relative to the current one. Check it first with ValidateSquash.
+// The workflow is:
+//
+//  1. Call ValidateSquash and SquashMigrations on every environment
+//  2. Deploy with the range replaced by Migration{ID: SquashedMigrationID(fromID, toID), SQL: squashedSQL}
+//  3. Remove the old migration files
+//
+// Usage:
+//
+//	if err := dbkit.ValidateSquash(ctx, db, "001", "120", squashed); err != nil {
+//	    return err
+//	}
+//	err := db.SquashMigrations(ctx, "001", "120", squashed)
+func (db *DBKit) SquashMigrations(ctx context.Context, fromID, toID, squashedSQL string) error {
+	if err := validateSquashArgs("SquashMigrations", fromID, toID, squashedSQL); err != nil {
+		return err
+	}
+
+	// Ensure migrations table exists
+	if _, err := db.ExecContext(ctx, migrationsTable); err != nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "failed to create migrations table",
+			Op:      "SquashMigrations",
+			Cause:   err,
+		}
+	}
+
+	return db.Transaction(ctx, func(tx *Tx) error {
+		var removed []struct {
+			ID         string `bun:"id"`
+			DurationMs int64  `bun:"duration_ms"`
+		}
+		// The squashed ID sorts inside the range; keep it out so squashing twice fails
+		err := tx.NewRaw("DELETE FROM _dbkit_migrations WHERE id >= ? AND id <= ? AND id <> ? RETURNING id, duration_ms",
+			fromID, toID, SquashedMigrationID(fromID, toID)).Scan(ctx, &removed)
+		if err != nil {
+			return wrapError(err, "SquashMigrations.Delete")
+		}
+		if len(removed) == 0 {
+			return &Error{
+				Code:    CodeNotFound,
+				Message: fmt.Sprintf("no applied migrations between %s and %s", fromID, toID),
+				Op:      "SquashMigrations",
+			}
+		}
+
+		var durationMs int64
+		for _, r := range removed {
+			durationMs += r.DurationMs
+		}
+
+		_, err = tx.NewRaw(`
+            INSERT INTO _dbkit_migrations (id, description, checksum, duration_ms)
+            VALUES (?, ?, ?, ?)
+        `, SquashedMigrationID(fromID, toID),
+			fmt.Sprintf("Squashed %d migrations from %s to %s", len(removed), fromID, toID),
+			checksumSQL(squashedSQL), durationMs).Exec(ctx)
+		if err != nil {
+			return wrapError(err, "SquashMigrations.Record")
+		}
+		return nil
+	})
+}
+
+// ValidateSquash runs squashedSQL in a transaction that is always rolled back, and checks that
+// migrations between fromID and toID have been applied. An error means SquashMigrations is unsafe.
+//
+// Usage:
+//
+//	if err := dbkit.ValidateSquash(ctx, db, "001", "120", squashed); err != nil {
+//	    log.Fatalf("squash is not idempotent: %v", err)
+//	}
+func ValidateSquash(ctx context.Context, db *DBKit, fromID, toID, squashedSQL string) error {
+	if err := validateSquashArgs("ValidateSquash", fromID, toID, squashedSQL); err != nil {
+		return err
+	}
+
+	applied, err := db.getAppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	inRange := 0
+	for id := range applied {
+		if id >= fromID && id <= toID && id != SquashedMigrationID(fromID, toID) {
+			inRange++
+		}
+	}
+	if inRange == 0 {
+		return &Error{
+			Code:    CodeNotFound,
+			Message: fmt.Sprintf("no applied migrations between %s and %s", fromID, toID),
+			Op:      "ValidateSquash",
+		}
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, squashedSQL); err != nil {
+		return &Error{
+			Code:    CodeValidation,
+			Message: fmt.Sprintf("squashed SQL failed against the current schema: %v", err),
+			Op:      "ValidateSquash",
+			Query:   truncateSQL(squashedSQL, 200),
+			Cause:   err,
+		}
+	}
+	return nil
+}
+
+func validateSquashArgs(op, fromID, toID, squashedSQL string) error {
+	msg := ""
+	switch {
+	case fromID == "" || toID == "":
+		msg = "fromID and toID are required"
+	case fromID > toID:
+		msg = fmt.Sprintf("fromID %s sorts after toID %s", fromID, toID)
+	case strings.TrimSpace(squashedSQL) == "":
+		msg = "squashed SQL is empty"
+	}
+	if msg == "" {
+		return nil
+	}
+	return &Error{Code: CodeValidation, Message: msg, Op: op}
+}
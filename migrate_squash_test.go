@@ -0,0 +1,87 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSquashMigrationsArgs(t *testing.T) {
+	db, connector := newFakeDB(t)
+	ctx := context.Background()
+
+	for _, args := range [][3]string{
+		{"", "002", "SELECT 1"},
+		{"003", "002", "SELECT 1"},
+		{"001", "002", "  "},
+	} {
+		if err := db.SquashMigrations(ctx, args[0], args[1], args[2]); !IsValidation(err) {
+			t.Errorf("SquashMigrations%v: expected a validation error, got %v", args, err)
+		}
+		if err := ValidateSquash(ctx, db, args[0], args[1], args[2]); !IsValidation(err) {
+			t.Errorf("ValidateSquash%v: expected a validation error, got %v", args, err)
+		}
+	}
+	if connector.dials.Load() != 0 {
+		t.Error("expected invalid arguments to be rejected before touching the database")
+	}
+}
+
+func TestSquashMigrations(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, _ = db.NewDropTable().IfExists().TableExpr("squash_items").Exec(ctx)
+	if _, err := db.NewDropTable().IfExists().TableExpr("_dbkit_migrations").Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop migrations table: %v", err)
+	}
+
+	migrations := []Migration{
+		{ID: "001_create_items", Description: "Create items", SQL: "CREATE TABLE squash_items (id BIGSERIAL PRIMARY KEY);"},
+		{ID: "002_add_name", Description: "Add name", SQL: "ALTER TABLE squash_items ADD COLUMN name TEXT;"},
+	}
+	if _, err := db.Migrate(ctx, migrations); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	squashed := "CREATE TABLE IF NOT EXISTS squash_items (id BIGSERIAL PRIMARY KEY, name TEXT);"
+
+	if err := ValidateSquash(ctx, db, "001_create_items", "002_add_name", "CREATE TABLE squash_items (id BIGINT);"); !IsValidation(err) {
+		t.Errorf("expected non-idempotent SQL to fail validation, got %v", err)
+	}
+	if err := ValidateSquash(ctx, db, "001_create_items", "002_add_name", squashed); err != nil {
+		t.Fatalf("ValidateSquash failed: %v", err)
+	}
+	if err := ValidateSquash(ctx, db, "100", "200", squashed); !IsNotFound(err) {
+		t.Errorf("expected an empty range to be rejected, got %v", err)
+	}
+
+	if err := db.SquashMigrations(ctx, "001_create_items", "002_add_name", squashed); err != nil {
+		t.Fatalf("SquashMigrations failed: %v", err)
+	}
+
+	applied, err := db.GetAppliedMigrations(ctx)
+	if err != nil {
+		t.Fatalf("GetAppliedMigrations failed: %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("expected only the squashed migration, got %+v", applied)
+	}
+	id := SquashedMigrationID("001_create_items", "002_add_name")
+	if applied[0].ID != id || applied[0].Checksum != checksumSQL(squashed) {
+		t.Errorf("unexpected squashed migration %+v", applied[0])
+	}
+
+	// The squashed migration is skipped where the range was applied
+	result, err := db.Migrate(ctx, []Migration{{ID: id, Description: "Squashed", SQL: squashed}})
+	if err != nil {
+		t.Fatalf("Migrate after squash failed: %v", err)
+	}
+	if len(result.Applied) != 0 || len(result.Skipped) != 1 {
+		t.Errorf("expected the squashed migration to be skipped, got %+v", result)
+	}
+
+	if err := db.SquashMigrations(ctx, "001_create_items", "002_add_name", squashed); !IsNotFound(err) {
+		t.Errorf("expected no migrations left to squash, got %v", err)
+	}
+}
@@ -593,3 +593,108 @@ func TestMigration_EmptyMigrations(t *testing.T) {
 		t.Errorf("Expected 0 applied migrations, got %d", len(applied))
 	}
 }
+
+func TestHasPendingMigrations(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS _dbkit_migrations")
+	migrations := []Migration{{ID: "001", Description: "noop", SQL: "SELECT 1"}}
+
+	pending, err := db.HasPendingMigrations(ctx, migrations)
+	if err != nil || !pending {
+		t.Fatalf("Expected pending migrations, got %v, %v", pending, err)
+	}
+
+	if _, err := db.Migrate(ctx, migrations); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	pending, err = db.HasPendingMigrations(ctx, migrations)
+	if err != nil || pending {
+		t.Errorf("Expected no pending migrations, got %v, %v", pending, err)
+	}
+}
+
+func TestMigration_LabelsAndAuthor(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	_, _ = db.NewDropTable().IfExists().TableExpr("labeled_items").Exec(ctx)
+	_, err := db.NewDropTable().IfExists().TableExpr("_dbkit_migrations").Exec(ctx)
+	if err != nil {
+		t.Fatalf("Failed to drop migrations table: %v", err)
+	}
+
+	// A table created before the metadata columns existed is upgraded in place
+	_, err = db.ExecContext(ctx, `CREATE TABLE _dbkit_migrations (
+        id VARCHAR(255) PRIMARY KEY, description TEXT, checksum VARCHAR(64) NOT NULL,
+        applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), duration_ms BIGINT NOT NULL)`)
+	if err != nil {
+		t.Fatalf("Failed to create legacy migrations table: %v", err)
+	}
+
+	migrations := []Migration{
+		{
+			ID:          "001_create_labeled_items",
+			Description: "Create labeled items",
+			SQL:         "CREATE TABLE labeled_items (id BIGSERIAL PRIMARY KEY);",
+			Author:      "alice",
+			Labels:      map[string]string{"env": "production", "team": "billing"},
+		},
+		{
+			ID:          "002_seed_labeled_items",
+			Description: "Seed labeled items",
+			SQL:         "INSERT INTO labeled_items DEFAULT VALUES;",
+		},
+	}
+	if _, err := db.Migrate(ctx, migrations); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	applied, err := db.GetAppliedMigrations(ctx)
+	if err != nil {
+		t.Fatalf("GetAppliedMigrations failed: %v", err)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("Expected 2 applied migrations, got %d", len(applied))
+	}
+
+	byID := map[string]AppliedMigration{}
+	for _, m := range applied {
+		byID[m.ID] = m
+	}
+	first := byID["001_create_labeled_items"]
+	if first.Author != "alice" {
+		t.Errorf("Expected author alice, got %q", first.Author)
+	}
+	if first.Labels["env"] != "production" || first.Labels["team"] != "billing" || len(first.Labels) != 2 {
+		t.Errorf("Expected labels to round-trip, got %v", first.Labels)
+	}
+	second := byID["002_seed_labeled_items"]
+	if second.Author != "" || len(second.Labels) != 0 {
+		t.Errorf("Expected no metadata, got %q %v", second.Author, second.Labels)
+	}
+}
+
+func TestFilterMigrations(t *testing.T) {
+	all := []Migration{
+		{ID: "001", Labels: map[string]string{"env": "production"}},
+		{ID: "002"},
+		{ID: "003", Labels: map[string]string{"env": "staging"}},
+		{ID: "004", Labels: map[string]string{"env": "production", "team": "billing"}},
+	}
+
+	got := FilterMigrations(all, "env", "production")
+	if len(got) != 2 || got[0].ID != "001" || got[1].ID != "004" {
+		t.Errorf("Expected 001 and 004, got %v", got)
+	}
+	if got := FilterMigrations(all, "env", ""); len(got) != 0 {
+		t.Errorf("Expected unlabeled migrations not to match an empty value, got %v", got)
+	}
+	if got := FilterMigrations(all, "team", "billing"); len(got) != 1 || got[0].ID != "004" {
+		t.Errorf("Expected 004, got %v", got)
+	}
+}
@@ -56,6 +56,24 @@ func (m *SoftDeletableModel) IsDeleted() bool {
 	return m.DeletedAt != nil
 }
 
+// ParanoidModel is SoftDeletableModel recording who deleted and restored a record.
+// Use ParanoidSoftDelete and ParanoidRestore to fill the fields.
+//
+// Usage:
+//
+//	type User struct {
+//	    bun.BaseModel `bun:"table:users,alias:u"`
+//	    dbkit.BaseModel
+//	    dbkit.ParanoidModel
+//	    Email string `bun:"email,notnull,unique"`
+//	}
+type ParanoidModel struct {
+	SoftDeletableModel
+	DeletedBy  string     `bun:"deleted_by,nullzero"`
+	RestoredAt *time.Time `bun:"restored_at,nullzero"`
+	RestoredBy string     `bun:"restored_by,nullzero"`
+}
+
 // VersionedModel adds optimistic locking capability to models.
 // Embed this alongside BaseModel for version-based conflict detection.
 //
@@ -126,6 +144,49 @@ func (m *FullModel) IsDeleted() bool {
 	return m.DeletedAt != nil
 }
 
+// IntBaseModel is BaseModel with an auto-incremented BIGSERIAL ID, for schemas with integer keys.
+//
+// Usage:
+//
+//	type Invoice struct {
+//	    bun.BaseModel `bun:"table:invoices,alias:i"`
+//	    dbkit.IntBaseModel
+//	    Total int64 `bun:"total,notnull"`
+//	}
+type IntBaseModel struct {
+	ID        int64     `bun:"id,pk,autoincrement"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// IntFullModel is FullModel with an auto-incremented BIGSERIAL ID.
+type IntFullModel struct {
+	ID        int64      `bun:"id,pk,autoincrement"`
+	CreatedAt time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	DeletedAt *time.Time `bun:"deleted_at,soft_delete,nullzero"`
+	Version   int64      `bun:"version,notnull,default:1"`
+}
+
+// IsDeleted returns true if the model has been soft deleted.
+func (m *IntFullModel) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+// DDLIntBaseModel returns the column definitions of IntBaseModel for hand-written CREATE TABLE statements.
+//
+// Usage:
+//
+//	migration := dbkit.Migration{
+//	    ID:  "001",
+//	    SQL: "CREATE TABLE invoices (" + dbkit.DDLIntBaseModel() + ", total BIGINT NOT NULL)",
+//	}
+func DDLIntBaseModel() string {
+	return "id BIGSERIAL PRIMARY KEY, " +
+		"created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+		"updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP"
+}
+
 // BeforeAppendModel is a Bun hook that updates the UpdatedAt timestamp
 // before insert or update operations.
 var _ bun.BeforeAppendModelHook = (*BaseModel)(nil)
@@ -177,3 +238,37 @@ func (m *FullModel) BeforeAppendModel(ctx context.Context, query schema.Query) e
 	}
 	return nil
 }
+
+// BeforeAppendModel is a Bun hook for IntBaseModel.
+var _ bun.BeforeAppendModelHook = (*IntBaseModel)(nil)
+
+func (m *IntBaseModel) BeforeAppendModel(ctx context.Context, query schema.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		now := time.Now()
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+	case *bun.UpdateQuery:
+		m.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// BeforeAppendModel is a Bun hook for IntFullModel.
+var _ bun.BeforeAppendModelHook = (*IntFullModel)(nil)
+
+func (m *IntFullModel) BeforeAppendModel(ctx context.Context, query schema.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		now := time.Now()
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+	case *bun.UpdateQuery:
+		m.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package dbkit
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/uptrace/bun"
 )
 
 func TestBaseModel_Fields(t *testing.T) {
@@ -96,3 +100,97 @@ func TestTimestampedModel_Fields(t *testing.T) {
 		t.Error("UpdatedAt should not be zero")
 	}
 }
+
+type TestInvoice struct {
+	bun.BaseModel `bun:"table:test_invoices,alias:ti"`
+	IntBaseModel
+	Total int64 `bun:"total,notnull"`
+}
+
+type TestIntFull struct {
+	bun.BaseModel `bun:"table:test_int_full,alias:tif"`
+	IntFullModel
+	Name string `bun:"name,notnull"`
+}
+
+func TestDDLIntBaseModel(t *testing.T) {
+	ddl := DDLIntBaseModel()
+	for _, col := range []string{"id BIGSERIAL PRIMARY KEY", "created_at TIMESTAMPTZ", "updated_at TIMESTAMPTZ"} {
+		if !strings.Contains(ddl, col) {
+			t.Errorf("Expected %q in %q", col, ddl)
+		}
+	}
+}
+
+func TestIntBaseModel_AutoIncrement(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS test_invoices"); err != nil {
+		t.Fatalf("Drop failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "CREATE TABLE test_invoices ("+DDLIntBaseModel()+", total BIGINT NOT NULL)"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	first := &TestInvoice{Total: 100}
+	second := &TestInvoice{Total: 200}
+	for _, inv := range []*TestInvoice{first, second} {
+		if _, err := db.NewInsert().Model(inv).Exec(ctx); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("Expected IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+	if first.CreatedAt.IsZero() || first.UpdatedAt.IsZero() {
+		t.Error("Expected timestamps to be set")
+	}
+
+	found, err := FindByID[TestInvoice](ctx, db, int64(2))
+	if err != nil || found.Total != 200 {
+		t.Errorf("FindByID failed: %+v, %v", found, err)
+	}
+	if exists, _ := ExistsByID[TestInvoice](ctx, db, int64(3)); exists {
+		t.Error("Expected ID 3 not to exist")
+	}
+	if err := DeleteByID[TestInvoice](ctx, db, int64(1)); err != nil {
+		t.Errorf("DeleteByID failed: %v", err)
+	}
+	if err := DeleteByID[TestInvoice](ctx, db, int64(1)); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestIntFullModel(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.NewDropTable().Model((*TestIntFull)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Drop failed: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestIntFull)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	model := &TestIntFull{Name: "full"}
+	if _, err := db.NewInsert().Model(model).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if model.ID != 1 || model.Version != 1 {
+		t.Errorf("Expected ID 1 version 1, got %+v", model.IntFullModel)
+	}
+
+	if err := DeleteByID[TestIntFull](ctx, db, model.ID); err != nil {
+		t.Fatalf("DeleteByID failed: %v", err)
+	}
+	if exists, _ := ExistsByID[TestIntFull](ctx, db, model.ID); exists {
+		t.Error("Expected soft-deleted record to be hidden")
+	}
+	var deleted TestIntFull
+	if err := db.NewSelect().Model(&deleted).WhereAllWithDeleted().Where("id = ?", model.ID).Scan(ctx); err != nil || !deleted.IsDeleted() {
+		t.Errorf("Expected a soft-deleted record, got %+v, %v", deleted, err)
+	}
+}
@@ -0,0 +1,137 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{`SELECT * FROM "users" WHERE "id" = 42`, `SELECT * FROM "users" WHERE "id" = ?`},
+		{`SELECT * FROM users WHERE name = 'O''Brien'`, `SELECT * FROM users WHERE name = ?`},
+		{"SELECT *\n  FROM users\tWHERE id = $1", `SELECT * FROM users WHERE id = ?`},
+		{`SELECT * FROM t2 WHERE price > 10.5`, `SELECT * FROM t2 WHERE price > ?`},
+		{`SELECT * FROM users WHERE id IN (1, 2, 3)`, `SELECT * FROM users WHERE id IN (?, ?, ?)`},
+	}
+
+	for _, tt := range tests {
+		if result := hooks.NormalizeQuery(tt.query); result != tt.expected {
+			t.Errorf("NormalizeQuery(%q) = %q, expected %q", tt.query, result, tt.expected)
+		}
+	}
+
+	if hooks.NormalizeQuery(`SELECT * FROM t WHERE id = 1`) != hooks.NormalizeQuery(`SELECT * FROM t WHERE id = 2`) {
+		t.Error("Queries differing only by literals should normalize equally")
+	}
+}
+
+func TestN1Detector(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		reports = map[string]int{}
+		calls   int
+	)
+
+	cfg := DefaultConfig("fake").WithN1Detection(5, func(pattern string, count int) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		reports[pattern] = count
+	})
+	cfg.N1Window = time.Minute
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	// The burst is reported as it crosses the threshold, without a later query or a flush
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		var m TestModel
+		_ = db.NewSelect().Model(&m).Where("id = ?", i).Scan(ctx)
+	}
+
+	mu.Lock()
+	if calls != 1 {
+		t.Fatalf("Expected handler to fire once, got %d calls: %v", calls, reports)
+	}
+	for pattern, count := range reports {
+		if count != 6 {
+			t.Errorf("Expected the 6th query to be reported for %q, got %d", pattern, count)
+		}
+	}
+	mu.Unlock()
+
+	// After a flush, the pattern is counted and reported again
+	db.FlushN1()
+	for i := 0; i < 6; i++ {
+		var m TestModel
+		_ = db.NewSelect().Model(&m).Where("id = ?", i).Scan(ctx)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 {
+		t.Errorf("Expected a second report after FlushN1, got %d calls", calls)
+	}
+}
+
+func TestN1Detector_Window(t *testing.T) {
+	var count int
+	hook := hooks.NewN1DetectorHook(3, func(pattern string, n int) { count++ })
+	hook.WindowMs = 20
+	event := &bun.QueryEvent{Query: `SELECT * FROM users WHERE id = 1`}
+
+	for i := 0; i < 3; i++ {
+		hook.AfterQuery(context.Background(), event)
+	}
+	time.Sleep(30 * time.Millisecond)
+	hook.AfterQuery(context.Background(), event)
+
+	if count != 0 {
+		t.Errorf("Expected repetitions in different windows not to be reported, got %d reports", count)
+	}
+}
+
+func TestN1Detector_Flush(t *testing.T) {
+	var count int
+	hook := hooks.NewN1DetectorHook(3, func(pattern string, n int) { count = n })
+	event := &bun.QueryEvent{Query: `SELECT * FROM users WHERE id = 1`}
+
+	for i := 0; i < 3; i++ {
+		hook.AfterQuery(context.Background(), event)
+	}
+	hook.Flush()
+	hook.AfterQuery(context.Background(), event)
+
+	if count != 0 {
+		t.Errorf("Expected flush to restart the count, got a report of %d", count)
+	}
+}
+
+func TestFlushN1_Disabled(t *testing.T) {
+	db, _ := newFakeDB(t)
+	db.FlushN1() // Must not panic without DetectN1
+}
+
+func TestN1Detector_DisabledNoAlloc(t *testing.T) {
+	hook := hooks.NewN1DetectorHook(0, nil)
+	event := &bun.QueryEvent{Query: `SELECT * FROM users WHERE id = 1`}
+	ctx := context.Background()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ctx = hook.BeforeQuery(ctx, event)
+		hook.AfterQuery(ctx, event)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected zero allocations for disabled hook, got %v", allocs)
+	}
+}
@@ -0,0 +1,149 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/uptrace/bun"
+)
+
+// DefaultWorkers is the default number of workers for parallel batch operations.
+const DefaultWorkers = 4
+
+// batchRange identifies a batch by its bounds within the items slice.
+type batchRange struct {
+	start, end int
+}
+
+// runParallelBatches splits n items into batches and processes them with a pool of workers.
+// The first error cancels the remaining work; it is returned after all workers exit.
+// If ctx ends before every batch was processed, its error is returned with the partial count.
+func runParallelBatches(parent context.Context, n, batchSize, workers int, fn func(ctx context.Context, start, end int) (int64, error)) (int64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	if batchSize <= 0 {
+		batchSize = BatchSize
+	}
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	work := make(chan batchRange)
+	var (
+		wg        sync.WaitGroup
+		totalRows int64
+		processed int64
+		firstErr  error
+		errOnce   sync.Once
+	)
+	batches := int64((n + batchSize - 1) / batchSize)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for br := range work {
+				if ctx.Err() != nil {
+					continue
+				}
+				rows, err := fn(ctx, br.start, br.end)
+				atomic.AddInt64(&totalRows, rows)
+				if err == nil {
+					atomic.AddInt64(&processed, 1)
+				} else {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i += batchSize {
+		end := i + batchSize
+		if end > n {
+			end = n
+		}
+		select {
+		case work <- batchRange{start: i, end: end}:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	rows := atomic.LoadInt64(&totalRows)
+	if firstErr != nil {
+		return rows, firstErr
+	}
+	if atomic.LoadInt64(&processed) < batches {
+		return rows, wrapError(parent.Err(), "ParallelBatch")
+	}
+	return rows, nil
+}
+
+// ParallelBatchInsert inserts records in batches using a pool of concurrent workers.
+// Each worker acquires its own connection from the pool, so db should be a *DBKit
+// or *bun.DB rather than a transaction.
+// The first failing batch cancels the remaining work.
+// Returns the total number of rows affected.
+//
+// Usage:
+//
+//	count, err := dbkit.ParallelBatchInsert(ctx, db, users, 100, 4)
+func ParallelBatchInsert[T any](ctx context.Context, db bun.IDB, items []T, batchSize, workers int) (int64, error) {
+	return runParallelBatches(ctx, len(items), batchSize, workers, func(ctx context.Context, start, end int) (int64, error) {
+		batch := items[start:end]
+		result, err := db.NewInsert().Model(&batch).Exec(ctx)
+		if err != nil {
+			return 0, wrapError(err, "ParallelBatchInsert")
+		}
+		rows, _ := result.RowsAffected()
+		return rows, nil
+	})
+}
+
+// ParallelBatchUpdate updates records in batches using a pool of concurrent workers.
+// Returns the total number of rows affected.
+//
+// Usage:
+//
+//	count, err := dbkit.ParallelBatchUpdate(ctx, db, users, 100, 4)
+func ParallelBatchUpdate[T any](ctx context.Context, db bun.IDB, items []T, batchSize, workers int) (int64, error) {
+	return runParallelBatches(ctx, len(items), batchSize, workers, func(ctx context.Context, start, end int) (int64, error) {
+		var rows int64
+		for j := start; j < end; j++ {
+			result, err := db.NewUpdate().Model(&items[j]).WherePK().Exec(ctx)
+			if err != nil {
+				return rows, wrapError(err, "ParallelBatchUpdate")
+			}
+			n, _ := result.RowsAffected()
+			rows += n
+		}
+		return rows, nil
+	})
+}
+
+// ParallelBatchUpsert performs upserts in batches using a pool of concurrent workers.
+// Returns the total number of rows affected.
+//
+// Usage:
+//
+//	count, err := dbkit.ParallelBatchUpsert(ctx, db, users, []string{"email"}, []string{"name"}, 100, 4)
+func ParallelBatchUpsert[T any](ctx context.Context, db bun.IDB, items []T, conflictColumns, updateColumns []string, batchSize, workers int) (int64, error) {
+	return runParallelBatches(ctx, len(items), batchSize, workers, func(ctx context.Context, start, end int) (int64, error) {
+		rows, err := upsertBatch(ctx, db, items[start:end], conflictColumns, updateColumns)
+		if err != nil {
+			return 0, wrapError(err, "ParallelBatchUpsert")
+		}
+		return rows, nil
+	})
+}
@@ -0,0 +1,126 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunParallelBatches_AllBatches(t *testing.T) {
+	var calls int32
+	total, err := runParallelBatches(context.Background(), 400, 100, 4, func(ctx context.Context, start, end int) (int64, error) {
+		atomic.AddInt32(&calls, 1)
+		return int64(end - start), nil
+	})
+	if err != nil {
+		t.Fatalf("runParallelBatches failed: %v", err)
+	}
+	if total != 400 {
+		t.Errorf("Expected 400 rows, got %d", total)
+	}
+	if calls != 4 {
+		t.Errorf("Expected 4 batches, got %d", calls)
+	}
+}
+
+func TestRunParallelBatches_ErrorCancels(t *testing.T) {
+	batchErr := errors.New("batch failed")
+	var calls int32
+
+	_, err := runParallelBatches(context.Background(), 10000, 10, 1, func(ctx context.Context, start, end int) (int64, error) {
+		atomic.AddInt32(&calls, 1)
+		if start == 0 {
+			return 0, batchErr
+		}
+		return int64(end - start), nil
+	})
+	if !errors.Is(err, batchErr) {
+		t.Fatalf("Expected batch error, got %v", err)
+	}
+	if calls >= 1000 {
+		t.Errorf("Expected remaining batches to be cancelled, got %d calls", calls)
+	}
+}
+
+func TestRunParallelBatches_ParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+
+	total, err := runParallelBatches(ctx, 1000, 10, 2, func(ctx context.Context, start, end int) (int64, error) {
+		if atomic.AddInt32(&calls, 1) == 5 {
+			cancel()
+		}
+		return int64(end - start), nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a context error, got %v after %d rows", err, total)
+	}
+	if total >= 1000 {
+		t.Errorf("Expected a partial count, got %d", total)
+	}
+}
+
+func TestParallelBatchInsert_Empty(t *testing.T) {
+	count, err := ParallelBatchInsert[TestModel](context.Background(), nil, nil, 100, 4)
+	if err != nil {
+		t.Errorf("ParallelBatchInsert with empty slice should not error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 count, got %d", count)
+	}
+}
+
+func TestParallelBatchInsert(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	models := make([]TestModel, 400)
+	for i := range models {
+		models[i] = TestModel{Name: "Parallel", Email: fmt.Sprintf("parallel%d@example.com", i)}
+	}
+
+	count, err := ParallelBatchInsert(ctx, db, models, 100, 4)
+	if err != nil {
+		t.Fatalf("ParallelBatchInsert failed: %v", err)
+	}
+	if count != 400 {
+		t.Errorf("Expected 400 rows inserted, got %d", count)
+	}
+
+	total, err := Count[TestModel](ctx, db, nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if total != 400 {
+		t.Errorf("Expected 400 rows in table, got %d", total)
+	}
+}
+
+func TestParallelBatchInsert_ErrorCancels(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	models := make([]TestModel, 400)
+	for i := range models {
+		models[i] = TestModel{Name: "Parallel", Email: "dup@example.com"}
+	}
+
+	_, err := ParallelBatchInsert(ctx, db, models, 100, 4)
+	if err == nil {
+		t.Fatal("Expected error from duplicate emails")
+	}
+
+	total, err := Count[TestModel](ctx, db, nil)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if total != 0 {
+		t.Errorf("Expected no rows inserted, got %d", total)
+	}
+}
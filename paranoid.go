@@ -0,0 +1,111 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/uptrace/bun"
+)
+
+// DeletionValidator is implemented by models that guard their own deletion and restoration.
+// ParanoidSoftDelete calls CanDelete and ParanoidRestore calls CanRestore before executing
+// the query, and return the error unchanged when it is already a dbkit error.
+//
+// Usage:
+//
+//	func (u *User) CanDelete(ctx context.Context) error {
+//	    if u.OpenOrders > 0 {
+//	        return errors.New("user has open orders")
+//	    }
+//	    return nil
+//	}
+//
+//	func (u *User) CanRestore(ctx context.Context) error { return nil }
+type DeletionValidator interface {
+	CanDelete(ctx context.Context) error
+	CanRestore(ctx context.Context) error
+}
+
+// ParanoidSoftDelete soft deletes a model embedding ParanoidModel, recording deletedBy.
+// The model is reloaded from the updated row. Returns a not found error if the record
+// doesn't exist or is already deleted.
+//
+// Usage:
+//
+//	err := dbkit.ParanoidSoftDelete(ctx, db, &user, currentUserID)
+func ParanoidSoftDelete[T any](ctx context.Context, db bun.IDB, model *T, deletedBy string) error {
+	if v, ok := any(model).(DeletionValidator); ok {
+		if err := v.CanDelete(ctx); err != nil {
+			return deletionRejected(err, "ParanoidSoftDelete")
+		}
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	result, err := db.NewUpdate().
+		Model(model).
+		Apply(softDeleteSet(table)).
+		Set("deleted_by = NULLIF(?, '')", deletedBy).
+		WherePK().
+		Returning("*").
+		Exec(ctx)
+	return paranoidResult(result, err, "ParanoidSoftDelete", table.Name)
+}
+
+// ParanoidRestore restores a soft-deleted model embedding ParanoidModel, recording restoredBy.
+// DeletedBy is kept as the last deletion. Returns a not found error if the record
+// doesn't exist or is not deleted.
+//
+// Usage:
+//
+//	err := dbkit.ParanoidRestore(ctx, db, &user, currentUserID)
+func ParanoidRestore[T any](ctx context.Context, db bun.IDB, model *T, restoredBy string) error {
+	if v, ok := any(model).(DeletionValidator); ok {
+		if err := v.CanRestore(ctx); err != nil {
+			return deletionRejected(err, "ParanoidRestore")
+		}
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	q := db.NewUpdate().
+		Model(model).
+		Set("deleted_at = NULL").
+		Set("restored_at = NOW()").
+		Set("restored_by = NULLIF(?, '')", restoredBy)
+	if _, ok := table.FieldMap["updated_at"]; ok {
+		q = q.Set("updated_at = NOW()")
+	}
+	result, err := q.WherePK().WhereDeleted().Returning("*").Exec(ctx)
+	return paranoidResult(result, err, "ParanoidRestore", table.Name)
+}
+
+// deletionRejected reports a DeletionValidator error as a validation error, keeping it as the cause
+func deletionRejected(err error, op string) error {
+	var dbErr *Error
+	if errors.As(err, &dbErr) {
+		return err
+	}
+	return &Error{
+		Code:    CodeValidation,
+		Message: err.Error(),
+		Op:      op,
+		Cause:   err,
+	}
+}
+
+// paranoidResult turns an update matching no row into a not found error
+func paranoidResult(result sql.Result, err error, op, table string) error {
+	if err != nil {
+		return wrapError(err, op)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{
+			Code:    CodeNotFound,
+			Message: "record not found",
+			Op:      op,
+			Table:   table,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+var errHasPosts = errors.New("author has posts")
+
+type paranoidAuthor struct {
+	bun.BaseModel `bun:"table:test_paranoid_authors,alias:pa"`
+	ID            string `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Name          string `bun:"name,notnull"`
+	ParanoidModel
+
+	db       bun.IDB `bun:"-"`
+	noRevive bool    `bun:"-"`
+}
+
+// CanDelete rejects authors still referenced by a post
+func (a *paranoidAuthor) CanDelete(ctx context.Context) error {
+	count, err := a.db.NewSelect().Model((*paranoidPost)(nil)).Where("author_id = ?", a.ID).Count(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errHasPosts
+	}
+	return nil
+}
+
+func (a *paranoidAuthor) CanRestore(ctx context.Context) error {
+	if a.noRevive {
+		return errors.New("restore disabled")
+	}
+	return nil
+}
+
+type paranoidPost struct {
+	bun.BaseModel `bun:"table:test_paranoid_posts,alias:pp"`
+	ID            string `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	AuthorID      string `bun:"author_id,type:uuid,notnull"`
+}
+
+func createParanoidTables(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, model := range []any{(*paranoidPost)(nil), (*paranoidAuthor)(nil)} {
+		if _, err := db.NewDropTable().Model(model).IfExists().Exec(ctx); err != nil {
+			t.Fatalf("Failed to drop table: %v", err)
+		}
+	}
+	for _, model := range []any{(*paranoidAuthor)(nil), (*paranoidPost)(nil)} {
+		if _, err := db.NewCreateTable().Model(model).Exec(ctx); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		db.NewDropTable().Model((*paranoidPost)(nil)).IfExists().Exec(ctx)
+		db.NewDropTable().Model((*paranoidAuthor)(nil)).IfExists().Exec(ctx)
+	})
+	return ctx
+}
+
+func TestParanoidRestore_ValidatorRejects(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	author := &paranoidAuthor{ID: "a", noRevive: true}
+	err := ParanoidRestore(context.Background(), db, author, "admin")
+	if !IsValidation(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	if hook.last != "" {
+		t.Errorf("Expected no query, got %s", hook.last)
+	}
+}
+
+func TestParanoidSoftDelete_DependentRecords(t *testing.T) {
+	db := getTestDB(t)
+	ctx := createParanoidTables(t, db)
+
+	author := &paranoidAuthor{Name: "Ann", db: db}
+	if _, err := db.NewInsert().Model(author).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	if _, err := db.NewInsert().Model(&paranoidPost{AuthorID: author.ID}).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert post: %v", err)
+	}
+
+	err := ParanoidSoftDelete(ctx, db, author, "admin")
+	if !errors.Is(err, errHasPosts) {
+		t.Fatalf("Expected CanDelete error, got %v", err)
+	}
+	if !IsValidation(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	if author.IsDeleted() {
+		t.Error("Expected author not to be deleted")
+	}
+}
+
+func TestParanoidSoftDeleteAndRestore(t *testing.T) {
+	db := getTestDB(t)
+	ctx := createParanoidTables(t, db)
+
+	author := &paranoidAuthor{Name: "Bob", db: db}
+	if _, err := db.NewInsert().Model(author).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+
+	if err := ParanoidSoftDelete(ctx, db, author, "admin"); err != nil {
+		t.Fatalf("ParanoidSoftDelete failed: %v", err)
+	}
+	if !author.IsDeleted() || author.DeletedBy != "admin" {
+		t.Errorf("Expected author deleted by admin, got %v %q", author.DeletedAt, author.DeletedBy)
+	}
+	if err := ParanoidSoftDelete(ctx, db, author, "admin"); !IsNotFound(err) {
+		t.Errorf("Expected not found deleting twice, got %v", err)
+	}
+
+	if err := ParanoidRestore(ctx, db, author, "support"); err != nil {
+		t.Fatalf("ParanoidRestore failed: %v", err)
+	}
+	if author.IsDeleted() || author.RestoredAt == nil || author.RestoredBy != "support" {
+		t.Errorf("Expected author restored by support, got %v %v %q", author.DeletedAt, author.RestoredAt, author.RestoredBy)
+	}
+	if err := ParanoidRestore(ctx, db, author, "support"); !IsNotFound(err) {
+		t.Errorf("Expected not found restoring twice, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PartitionStrategy is the period covered by each partition of a range-partitioned table
+type PartitionStrategy string
+
+const (
+	ByDay   PartitionStrategy = "day"
+	ByMonth PartitionStrategy = "month"
+	ByYear  PartitionStrategy = "year"
+)
+
+// PartitionOptions configures CreatePartitionedTable
+type PartitionOptions struct {
+	Model            any               // Bun model defining the columns; its primary key must include PartitionBy
+	PartitionBy      string            // Date or timestamp column to partition on
+	Strategy         PartitionStrategy // Period of each partition (default: ByMonth)
+	RetentionPeriods int               // Periods kept by DropOldPartitions when keepPeriods is 0 (0 = keep all)
+}
+
+// partitionsTable records the strategy of each table created by CreatePartitionedTable
+const partitionsTable = `
+CREATE TABLE IF NOT EXISTS _dbkit_partitions (
+    table_name VARCHAR(255) PRIMARY KEY,
+    partition_by VARCHAR(255) NOT NULL,
+    strategy VARCHAR(20) NOT NULL,
+    retention_periods INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+`
+
+// partitionedTable is a row of _dbkit_partitions
+type partitionedTable struct {
+	bun.BaseModel `bun:"table:_dbkit_partitions"`
+
+	TableName        string            `bun:"table_name,pk"`
+	PartitionBy      string            `bun:"partition_by"`
+	Strategy         PartitionStrategy `bun:"strategy"`
+	RetentionPeriods int               `bun:"retention_periods"`
+}
+
+// start returns the beginning of the period containing t, in UTC.
+func (s PartitionStrategy) start(t time.Time) time.Time {
+	t = t.UTC()
+	switch s {
+	case ByDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case ByYear:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// add moves t by n periods.
+func (s PartitionStrategy) add(t time.Time, n int) time.Time {
+	switch s {
+	case ByDay:
+		return t.AddDate(0, 0, n)
+	case ByYear:
+		return t.AddDate(n, 0, 0)
+	}
+	return t.AddDate(0, n, 0)
+}
+
+// layout is the time layout of partition name suffixes.
+func (s PartitionStrategy) layout() string {
+	switch s {
+	case ByDay:
+		return "20060102"
+	case ByYear:
+		return "2006"
+	}
+	return "200601"
+}
+
+func (s PartitionStrategy) valid() bool {
+	return s == ByDay || s == ByMonth || s == ByYear
+}
+
+// partitionName returns the name of the partition of tableName starting at start, e.g. events_p202406.
+func partitionName(tableName string, strategy PartitionStrategy, start time.Time) string {
+	return tableName + "_p" + start.Format(strategy.layout())
+}
+
+// CreatePartitionedTable creates tableName from opts.Model, partitioned by range on opts.PartitionBy,
+// together with the partition for the current period. The strategy is recorded so
+// EnsurePartition and DropOldPartitions know the partition boundaries.
+//
+// Usage:
+//
+//	err := dbkit.CreatePartitionedTable(ctx, db, "events", dbkit.PartitionOptions{
+//	    Model:            (*Event)(nil),
+//	    PartitionBy:      "created_at",
+//	    Strategy:         dbkit.ByMonth,
+//	    RetentionPeriods: 12,
+//	})
+func CreatePartitionedTable(ctx context.Context, db IDB, tableName string, opts PartitionOptions) error {
+	if opts.Strategy == "" {
+		opts.Strategy = ByMonth
+	}
+	if !opts.Strategy.valid() || opts.PartitionBy == "" || opts.Model == nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "partitioned table requires a model, a partition column and a valid strategy",
+			Op:      "CreatePartitionedTable",
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, partitionsTable); err != nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "failed to create partitions table",
+			Op:      "CreatePartitionedTable",
+			Cause:   err,
+		}
+	}
+
+	_, err := db.NewCreateTable().
+		Model(opts.Model).
+		ModelTableExpr("?", bun.Ident(tableName)).
+		IfNotExists().
+		PartitionBy("RANGE (?)", bun.Ident(opts.PartitionBy)).
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "CreatePartitionedTable")
+	}
+
+	_, err = db.NewInsert().
+		Model(&partitionedTable{
+			TableName:        tableName,
+			PartitionBy:      opts.PartitionBy,
+			Strategy:         opts.Strategy,
+			RetentionPeriods: opts.RetentionPeriods,
+		}).
+		On("CONFLICT (table_name) DO UPDATE").
+		Set("partition_by = EXCLUDED.partition_by").
+		Set("strategy = EXCLUDED.strategy").
+		Set("retention_periods = EXCLUDED.retention_periods").
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "CreatePartitionedTable")
+	}
+
+	return EnsurePartition(ctx, db, tableName, time.Now())
+}
+
+// getPartitionedTable loads the recorded strategy of tableName.
+func getPartitionedTable(ctx context.Context, db IDB, tableName, op string) (*partitionedTable, error) {
+	var pt partitionedTable
+	err := db.NewSelect().Model(&pt).Where("table_name = ?", tableName).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &Error{
+			Code:    CodeNotFound,
+			Message: fmt.Sprintf("table %s was not created by CreatePartitionedTable", tableName),
+			Op:      op,
+		}
+	}
+	if err != nil {
+		return nil, wrapError(err, op)
+	}
+	return &pt, nil
+}
+
+// EnsurePartition creates the partition of tableName covering date if it does not exist.
+// Concurrent calls are serialized with an advisory lock on the table.
+//
+// Usage:
+//
+//	// Create next month's partition ahead of time
+//	err := dbkit.EnsurePartition(ctx, db, "events", time.Now().AddDate(0, 1, 0))
+func EnsurePartition(ctx context.Context, db IDB, tableName string, date time.Time) error {
+	pt, err := getPartitionedTable(ctx, db, tableName, "EnsurePartition")
+	if err != nil {
+		return err
+	}
+
+	start := pt.Strategy.start(date)
+	end := pt.Strategy.add(start, 1)
+	name := partitionName(tableName, pt.Strategy, start)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", "dbkit_partition:"+tableName); err != nil {
+			return err
+		}
+		_, err := tx.NewRaw("CREATE TABLE IF NOT EXISTS ? PARTITION OF ? FOR VALUES FROM (?) TO (?)",
+			bun.Ident(name), bun.Ident(tableName),
+			start.Format("2006-01-02 15:04:05Z07:00"), end.Format("2006-01-02 15:04:05Z07:00"),
+		).Exec(ctx)
+		return err
+	})
+	return wrapError(err, "EnsurePartition")
+}
+
+// DropOldPartitions drops the partitions of tableName that ended before the last keepPeriods
+// periods, counting the current one. A keepPeriods of 0 uses the table's RetentionPeriods;
+// if that is 0 too nothing is dropped. Returns the number of partitions dropped.
+//
+// Usage:
+//
+//	// Keep this month and the previous two
+//	dropped, err := dbkit.DropOldPartitions(ctx, db, "events", 3)
+func DropOldPartitions(ctx context.Context, db IDB, tableName string, keepPeriods int) (int, error) {
+	pt, err := getPartitionedTable(ctx, db, tableName, "DropOldPartitions")
+	if err != nil {
+		return 0, err
+	}
+	if keepPeriods <= 0 {
+		keepPeriods = pt.RetentionPeriods
+	}
+	if keepPeriods <= 0 {
+		return 0, nil
+	}
+
+	var partitions []string
+	err = db.NewRaw(`
+        SELECT c.relname
+        FROM pg_inherits i
+        JOIN pg_class c ON c.oid = i.inhrelid
+        WHERE i.inhparent = to_regclass(?)
+    `, tableName).Scan(ctx, &partitions)
+	if err != nil {
+		return 0, wrapError(err, "DropOldPartitions")
+	}
+
+	cutoff := pt.Strategy.add(pt.Strategy.start(time.Now()), -(keepPeriods - 1))
+	prefix := tableName[strings.LastIndex(tableName, ".")+1:] + "_p"
+	schema := ""
+	if i := strings.LastIndex(tableName, "."); i >= 0 {
+		schema = tableName[:i+1]
+	}
+
+	dropped := 0
+	for _, partition := range partitions {
+		suffix, ok := strings.CutPrefix(partition, prefix)
+		if !ok {
+			continue // Not created by EnsurePartition
+		}
+		start, err := time.Parse(pt.Strategy.layout(), suffix)
+		if err != nil || !start.Before(cutoff) {
+			continue
+		}
+		if _, err := db.NewRaw("DROP TABLE IF EXISTS ?", bun.Ident(schema+partition)).Exec(ctx); err != nil {
+			return dropped, wrapError(err, "DropOldPartitions")
+		}
+		dropped++
+	}
+	return dropped, nil
+}
@@ -0,0 +1,132 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TestPartitionedEvent struct {
+	bun.BaseModel `bun:"table:test_events,alias:te"`
+	ID            int64     `bun:"id,pk,autoincrement"`
+	CreatedAt     time.Time `bun:"created_at,pk,notnull"`
+	Name          string    `bun:"name,notnull"`
+}
+
+func createPartitionedEvents(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	_, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS test_events CASCADE")
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS test_events CASCADE") })
+
+	err := CreatePartitionedTable(ctx, db, "test_events", PartitionOptions{
+		Model:       (*TestPartitionedEvent)(nil),
+		PartitionBy: "created_at",
+		Strategy:    ByMonth,
+	})
+	if err != nil {
+		t.Fatalf("CreatePartitionedTable failed: %v", err)
+	}
+	return ctx
+}
+
+func TestPartitionStrategy_Periods(t *testing.T) {
+	date := time.Date(2024, 6, 15, 13, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		strategy PartitionStrategy
+		start    time.Time
+		next     time.Time
+		name     string
+	}{
+		{ByDay, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC), "events_p20240615"},
+		{ByMonth, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), "events_p202406"},
+		{ByYear, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "events_p2024"},
+	}
+	for _, tt := range tests {
+		start := tt.strategy.start(date)
+		if !start.Equal(tt.start) {
+			t.Errorf("%s: expected start %s, got %s", tt.strategy, tt.start, start)
+		}
+		if next := tt.strategy.add(start, 1); !next.Equal(tt.next) {
+			t.Errorf("%s: expected next %s, got %s", tt.strategy, tt.next, next)
+		}
+		if name := partitionName("events", tt.strategy, start); name != tt.name {
+			t.Errorf("%s: expected name %s, got %s", tt.strategy, tt.name, name)
+		}
+	}
+}
+
+func TestCreatePartitionedTable_InvalidOptions(t *testing.T) {
+	db, _ := newFakeDB(t)
+	err := CreatePartitionedTable(context.Background(), db, "events", PartitionOptions{Strategy: "week"})
+	if err == nil {
+		t.Error("Expected an error for invalid options")
+	}
+}
+
+func TestDropOldPartitions(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createPartitionedEvents(t, db)
+
+	now := ByMonth.start(time.Now())
+	months := []time.Time{ByMonth.add(now, -2), ByMonth.add(now, -1), now}
+	for i, month := range months {
+		if err := EnsurePartition(ctx, db, "test_events", month); err != nil {
+			t.Fatalf("EnsurePartition failed: %v", err)
+		}
+		if _, err := db.NewInsert().Model(&TestPartitionedEvent{Name: "event", CreatedAt: month}).Exec(ctx); err != nil {
+			t.Fatalf("Insert into month %d failed: %v", i, err)
+		}
+	}
+
+	dropped, err := DropOldPartitions(ctx, db, "test_events", 2)
+	if err != nil {
+		t.Fatalf("DropOldPartitions failed: %v", err)
+	}
+	if dropped != 1 {
+		t.Errorf("Expected 1 partition dropped, got %d", dropped)
+	}
+
+	count, err := db.NewSelect().Model((*TestPartitionedEvent)(nil)).Count(ctx)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 rows in the kept partitions, got %d", count)
+	}
+}
+
+func TestEnsurePartition_Concurrent(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createPartitionedEvents(t, db)
+
+	next := ByMonth.add(ByMonth.start(time.Now()), 1)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := EnsurePartition(ctx, db, "test_events", next); err != nil {
+				t.Errorf("EnsurePartition failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestEnsurePartition_UnknownTable(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createPartitionedEvents(t, db)
+
+	if err := EnsurePartition(ctx, db, "test_not_partitioned", time.Now()); !IsNotFound(err) {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package dbkit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PgBouncerDisabledFeatures lists the features disabled by Config.PgBouncerMode.
+// pgBouncer in transaction mode hands each transaction a different server connection,
+// so nothing may outlive a transaction.
+var PgBouncerDisabledFeatures = []string{
+	"session-level advisory locks (WithAdvisoryLock uses a transaction-level lock)",
+	"SET statements other than SET LOCAL and SET TRANSACTION",
+	"PREPARE and prepared statements (including hooks.PreparedStatementHook)",
+	"LISTEN and RESET statements",
+}
+
+// sessionAdvisoryLock matches the session-level advisory lock functions, not the _xact_ variants
+var sessionAdvisoryLock = regexp.MustCompile(`(?i)\bpg_(?:try_)?advisory_(?:lock|unlock)(?:_shared|_all)?\s*\(`)
+
+// pgBouncerViolation returns why query is unsafe behind pgBouncer in transaction mode, or ""
+func pgBouncerViolation(query string) string {
+	stmt := strings.ToLower(trimLeadingComments(query))
+	switch {
+	case strings.HasPrefix(stmt, "set ") &&
+		!strings.HasPrefix(stmt, "set local ") &&
+		!strings.HasPrefix(stmt, "set transaction ") &&
+		!strings.HasPrefix(stmt, "set constraints "):
+		return "session-level SET is not supported in pgBouncer mode, use SET LOCAL inside a transaction"
+	case strings.HasPrefix(stmt, "prepare ") || strings.HasPrefix(stmt, "deallocate "):
+		return "prepared statements are not supported in pgBouncer mode"
+	case strings.HasPrefix(stmt, "listen ") || strings.HasPrefix(stmt, "unlisten "):
+		return "LISTEN is not supported in pgBouncer mode, listen on a direct connection"
+	case strings.HasPrefix(stmt, "reset "):
+		return "RESET is not supported in pgBouncer mode, use SET LOCAL inside a transaction"
+	case sessionAdvisoryLock.MatchString(query):
+		return "session-level advisory locks are not supported in pgBouncer mode, use AdvisoryXactLock inside a transaction"
+	}
+	return ""
+}
+
+// trimLeadingComments removes whitespace and the -- and /* */ comments before the first statement keyword
+func trimLeadingComments(query string) string {
+	for {
+		query = strings.TrimSpace(query)
+		switch {
+		case strings.HasPrefix(query, "--"):
+			_, rest, ok := strings.Cut(query, "\n")
+			if !ok {
+				return ""
+			}
+			query = rest
+		case strings.HasPrefix(query, "/*"):
+			_, rest, ok := strings.Cut(query[2:], "*/")
+			if !ok {
+				return ""
+			}
+			query = rest
+		default:
+			return query
+		}
+	}
+}
+
+// pgBouncerError returns the error for a feature disabled in pgBouncer mode
+func pgBouncerError(op, message string) error {
+	return &Error{
+		Code:    CodeUnsupported,
+		Message: message,
+		Op:      op,
+	}
+}
+
+// pgBouncerMode reports whether db belongs to a DBKit configured with PgBouncerMode
+func pgBouncerMode(db IDB) bool {
+	switch v := db.(type) {
+	case *DBKit:
+		return v.IsPgBouncer()
+	case *Tx:
+		return v.db != nil && v.db.IsPgBouncer()
+	}
+	return false
+}
+
+// IsPgBouncer reports whether the connection is configured for pgBouncer transaction pooling
+func (db *DBKit) IsPgBouncer() bool {
+	return db.config.PgBouncerMode
+}
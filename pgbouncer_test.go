@@ -0,0 +1,134 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+)
+
+func newPgBouncerDB(t *testing.T) *DBKit {
+	t.Helper()
+
+	cfg := DefaultConfig("fake")
+	cfg.PgBouncerMode = true
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestPgBouncerViolation(t *testing.T) {
+	tests := []struct {
+		query  string
+		unsafe bool
+	}{
+		{"SET search_path TO tenant_1", true},
+		{"  set statement_timeout = 0", true},
+		{"SET SESSION app.tenant_id = '1'", true},
+		{"SET LOCAL app.tenant_id = '1'", false},
+		{"SET TRANSACTION ISOLATION LEVEL SERIALIZABLE", false},
+		{"SET CONSTRAINTS ALL DEFERRED", false},
+		{"PREPARE q AS SELECT 1", true},
+		{"DEALLOCATE q", true},
+		{"SELECT pg_advisory_lock(1)", true},
+		{"SELECT pg_try_advisory_lock(1)", true},
+		{"SELECT pg_advisory_unlock_all()", true},
+		{"SELECT pg_advisory_xact_lock(1)", false},
+		{"SELECT pg_try_advisory_xact_lock_shared(1)", false},
+		{"UPDATE users SET name = 'a'", false},
+		{"SELECT * FROM settings", false},
+		{"-- tenant\nSET search_path TO tenant_1", true},
+		{"/* app:api */ SET statement_timeout = 0", true},
+		{"/* a */ -- b\n /* c */ PREPARE q AS SELECT 1", true},
+		{"/* SET search_path */ SELECT 1", false},
+		{"LISTEN jobs", true},
+		{"UNLISTEN *", true},
+		{"RESET search_path", true},
+		{"RESET ALL", true},
+	}
+	for _, tt := range tests {
+		if got := pgBouncerViolation(tt.query) != ""; got != tt.unsafe {
+			t.Errorf("pgBouncerViolation(%q) unsafe = %v, want %v", tt.query, got, tt.unsafe)
+		}
+	}
+}
+
+func TestPgBouncerModeRejectsSessionStatements(t *testing.T) {
+	db := newPgBouncerDB(t)
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, "SET search_path TO tenant_1"); !IsUnsupported(err) {
+		t.Errorf("expected SET to be unsupported, got %v", err)
+	}
+	if _, err := db.QueryContext(ctx, "SELECT pg_advisory_lock(1)"); !IsUnsupported(err) {
+		t.Errorf("expected session advisory locks to be unsupported, got %v", err)
+	}
+	if _, err := db.DB.DB.PrepareContext(ctx, "SELECT 1"); !IsUnsupported(err) {
+		t.Errorf("expected prepared statements to be unsupported, got %v", err)
+	}
+
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		_, err := tx.ExecContext(ctx, "SET LOCAL app.tenant_id = '1'")
+		return err
+	})
+	if err != nil {
+		t.Errorf("expected SET LOCAL to be allowed, got %v", err)
+	}
+}
+
+func TestPgBouncerModeAdvisoryLocks(t *testing.T) {
+	db := newPgBouncerDB(t)
+	ctx := context.Background()
+
+	if _, err := TryAdvisoryLock(ctx, db, 1); !IsUnsupported(err) {
+		t.Errorf("expected TryAdvisoryLock to be unsupported, got %v", err)
+	}
+	if err := AdvisoryLock(ctx, db, 1); !IsUnsupported(err) {
+		t.Errorf("expected AdvisoryLock to be unsupported, got %v", err)
+	}
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		return AdvisoryUnlock(ctx, tx, 1)
+	})
+	if !IsUnsupported(err) {
+		t.Errorf("expected AdvisoryUnlock in a transaction to be unsupported, got %v", err)
+	}
+
+	ran := false
+	if err := WithAdvisoryLock(ctx, db, 1, func() error { ran = true; return nil }); err != nil {
+		t.Fatalf("expected WithAdvisoryLock to use a transaction-level lock, got %v", err)
+	}
+	if !ran {
+		t.Error("expected fn to run")
+	}
+}
+
+func TestPgBouncerModeHealthAndValidate(t *testing.T) {
+	db := newPgBouncerDB(t)
+	if !db.IsPgBouncer() {
+		t.Error("expected IsPgBouncer")
+	}
+	if !db.Health(context.Background()).PgBouncer {
+		t.Error("expected HealthStatus.PgBouncer")
+	}
+
+	plain, _ := newFakeDB(t)
+	if plain.IsPgBouncer() || plain.Health(context.Background()).PgBouncer {
+		t.Error("expected pgBouncer mode to be off by default")
+	}
+
+	cfg := DefaultConfig("postgres://localhost/app")
+	cfg.PgBouncerMode = true
+	warnings := 0
+	for _, e := range cfg.Validate() {
+		if e.Field == "PgBouncerMode" {
+			if !e.Warning {
+				t.Errorf("expected a warning, got %v", e)
+			}
+			warnings++
+		}
+	}
+	if warnings != len(PgBouncerDisabledFeatures) {
+		t.Errorf("expected a warning per disabled feature, got %d", warnings)
+	}
+}
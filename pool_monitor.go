@@ -0,0 +1,184 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	poolSaturationWindow = time.Minute // Window of SaturationPct
+	poolMonitorRetention = time.Hour   // Age after which samples and wait events are dropped
+)
+
+// PoolSample is a timestamped snapshot of the connection pool statistics
+type PoolSample struct {
+	Time  time.Time
+	Stats PoolStats
+}
+
+// PoolWaitEvent records callers that waited for a connection between two samples
+type PoolWaitEvent struct {
+	Time         time.Time
+	Waits        int64         // WaitCount increase since the previous sample
+	WaitDuration time.Duration // WaitDuration increase since the previous sample
+	InUse        int
+	MaxOpen      int
+}
+
+type poolAlert struct {
+	threshold float64
+	handler   func(saturation float64)
+	firing    bool
+}
+
+// PoolMonitor samples db.Stats periodically to analyse pool saturation over time.
+type PoolMonitor struct {
+	db       *DBKit
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples []PoolSample
+	events  []PoolWaitEvent
+	alerts  []*poolAlert
+}
+
+// NewPoolMonitor creates a monitor sampling the pool every sampleInterval (default: 1s).
+// Samples and wait events are kept for an hour. Call Start to begin sampling.
+//
+// Usage:
+//
+//	monitor := dbkit.NewPoolMonitor(db, time.Second)
+//	monitor.AlertAbove(80, func(saturation float64) {
+//	    log.Printf("connection pool %.0f%% saturated", saturation)
+//	})
+//	monitor.Start(ctx)
+func NewPoolMonitor(db *DBKit, sampleInterval time.Duration) *PoolMonitor {
+	if sampleInterval <= 0 {
+		sampleInterval = time.Second
+	}
+	return &PoolMonitor{
+		db:       db,
+		interval: sampleInterval,
+	}
+}
+
+// Start samples in a background goroutine until ctx is cancelled.
+func (pm *PoolMonitor) Start(ctx context.Context) {
+	go pm.run(ctx)
+}
+
+func (pm *PoolMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(pm.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			pm.record(now, PoolStatsFromSQL(pm.db.Stats()))
+		}
+	}
+}
+
+// record stores a sample, derives its wait event and runs the alerts outside the lock
+func (pm *PoolMonitor) record(now time.Time, stats PoolStats) {
+	pm.mu.Lock()
+	if n := len(pm.samples); n > 0 {
+		prev := pm.samples[n-1].Stats
+		if stats.WaitCount > prev.WaitCount {
+			pm.events = append(pm.events, PoolWaitEvent{
+				Time:         now,
+				Waits:        stats.WaitCount - prev.WaitCount,
+				WaitDuration: stats.WaitDuration - prev.WaitDuration,
+				InUse:        stats.InUse,
+				MaxOpen:      stats.MaxOpenConnections,
+			})
+		}
+	}
+	pm.samples = append(pm.samples, PoolSample{Time: now, Stats: stats})
+	pm.prune(now)
+
+	saturation := pm.saturation(now)
+	var fire []func(float64)
+	for _, alert := range pm.alerts {
+		if saturation > alert.threshold {
+			if !alert.firing {
+				fire = append(fire, alert.handler)
+			}
+			alert.firing = true
+		} else {
+			alert.firing = false
+		}
+	}
+	pm.mu.Unlock()
+
+	for _, handler := range fire {
+		handler(saturation)
+	}
+}
+
+// prune drops samples and events older than the retention
+func (pm *PoolMonitor) prune(now time.Time) {
+	cutoff := now.Add(-poolMonitorRetention)
+	i := 0
+	for i < len(pm.samples) && pm.samples[i].Time.Before(cutoff) {
+		i++
+	}
+	pm.samples = pm.samples[i:]
+
+	i = 0
+	for i < len(pm.events) && pm.events[i].Time.Before(cutoff) {
+		i++
+	}
+	pm.events = pm.events[i:]
+}
+
+// saturation averages InUse / MaxOpenConnections over the samples of the last minute
+func (pm *PoolMonitor) saturation(now time.Time) float64 {
+	cutoff := now.Add(-poolSaturationWindow)
+	var total float64
+	var n int
+	for i := len(pm.samples) - 1; i >= 0 && !pm.samples[i].Time.Before(cutoff); i-- {
+		stats := pm.samples[i].Stats
+		if stats.MaxOpenConnections > 0 {
+			total += float64(stats.InUse) / float64(stats.MaxOpenConnections) * 100
+		}
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / float64(n)
+}
+
+// Samples returns the stored samples, oldest first.
+func (pm *PoolMonitor) Samples() []PoolSample {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return append([]PoolSample(nil), pm.samples...)
+}
+
+// WaitEvents returns the samples at which WaitCount had increased, oldest first.
+func (pm *PoolMonitor) WaitEvents() []PoolWaitEvent {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return append([]PoolWaitEvent(nil), pm.events...)
+}
+
+// SaturationPct returns the average percentage of connections in use over the last minute.
+// Pools without a MaxOpenConnections limit count as 0% saturated.
+func (pm *PoolMonitor) SaturationPct() float64 {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return pm.saturation(time.Now())
+}
+
+// AlertAbove calls handler with the saturation when a sample takes SaturationPct above threshold.
+// The alert fires once per excursion and re-arms when the saturation drops back to the threshold.
+// Handlers run in the monitor goroutine and must not block.
+func (pm *PoolMonitor) AlertAbove(threshold float64, handler func(saturation float64)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.alerts = append(pm.alerts, &poolAlert{threshold: threshold, handler: handler})
+}
@@ -0,0 +1,101 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestPoolMonitor_Saturation(t *testing.T) {
+	db, _ := newFakeDB(t)
+	db.DB.DB.SetMaxOpenConns(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Hold every connection
+	var conns []*sql.Conn
+	for range 2 {
+		conn, err := db.DB.DB.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Conn failed: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+
+	alerts := make(chan float64, 1)
+	monitor := NewPoolMonitor(db, 5*time.Millisecond)
+	monitor.AlertAbove(50, func(saturation float64) {
+		select {
+		case alerts <- saturation:
+		default:
+		}
+	})
+	monitor.Start(ctx)
+
+	select {
+	case saturation := <-alerts:
+		if saturation != 100 {
+			t.Errorf("Expected 100%% saturation in the alert, got %v", saturation)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the saturation alert to fire")
+	}
+	if monitor.SaturationPct() <= 0 {
+		t.Errorf("Expected a positive saturation, got %v", monitor.SaturationPct())
+	}
+
+	// Queue a caller once the monitor has a baseline sample
+	waiter := make(chan struct{})
+	go func() {
+		defer close(waiter)
+		if conn, err := db.DB.DB.Conn(ctx); err == nil {
+			_ = conn.Close()
+		}
+	}()
+	time.Sleep(20 * time.Millisecond) // Let a sample observe the wait
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	<-waiter
+
+	deadline := time.Now().Add(time.Second)
+	for len(monitor.WaitEvents()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	events := monitor.WaitEvents()
+	if len(events) == 0 {
+		t.Fatal("Expected a wait event")
+	}
+	if events[0].Waits != 1 {
+		t.Errorf("Expected 1 wait, got %d", events[0].Waits)
+	}
+}
+
+func TestPoolMonitor_AlertRearms(t *testing.T) {
+	monitor := NewPoolMonitor(nil, time.Second)
+	var fired []float64
+	monitor.AlertAbove(50, func(saturation float64) { fired = append(fired, saturation) })
+
+	now := time.Now()
+	sample := func(offset time.Duration, inUse int) {
+		monitor.record(now.Add(offset), PoolStats{MaxOpenConnections: 10, InUse: inUse})
+	}
+	sample(0, 8)            // 80%
+	sample(time.Second, 10) // 90% average, already firing
+	sample(2*time.Minute, 1)
+	sample(4*time.Minute, 6)
+
+	if len(fired) != 2 || fired[0] != 80 || fired[1] != 60 {
+		t.Errorf("Expected alerts at 80 and 60, got %v", fired)
+	}
+	if n := len(monitor.Samples()); n != 4 {
+		t.Errorf("Expected 4 samples, got %d", n)
+	}
+
+	sample(2*time.Hour, 0)
+	if n := len(monitor.Samples()); n != 1 {
+		t.Errorf("Expected samples older than the retention to be dropped, got %d", n)
+	}
+}
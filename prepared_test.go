@@ -0,0 +1,196 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// prepareConnector records the statements its connections prepare and execute.
+type prepareConnector struct {
+	mu       sync.Mutex
+	prepared []string
+	direct   []string
+	executed int
+	closed   int
+}
+
+func (c *prepareConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &prepareConn{c: c}, nil
+}
+
+func (c *prepareConnector) Driver() driver.Driver { return nil }
+
+type prepareConn struct {
+	c *prepareConnector
+}
+
+func (cn *prepareConn) Prepare(query string) (driver.Stmt, error) {
+	cn.c.mu.Lock()
+	defer cn.c.mu.Unlock()
+	cn.c.prepared = append(cn.c.prepared, query)
+	return &prepareStmt{c: cn.c}, nil
+}
+
+func (cn *prepareConn) Close() error              { return nil }
+func (cn *prepareConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (cn *prepareConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	cn.c.mu.Lock()
+	defer cn.c.mu.Unlock()
+	cn.c.direct = append(cn.c.direct, query)
+	return driver.RowsAffected(0), nil
+}
+
+type prepareStmt struct {
+	c *prepareConnector
+}
+
+func (s *prepareStmt) Close() error {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.closed++
+	return nil
+}
+
+func (s *prepareStmt) NumInput() int { return -1 }
+
+func (s *prepareStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.executed++
+	return driver.RowsAffected(1), nil
+}
+
+func (s *prepareStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.mu.Lock()
+	defer s.c.mu.Unlock()
+	s.c.executed++
+	return fakeRows{}, nil
+}
+
+func newPreparedHook(t *testing.T, size int) (*hooks.PreparedStatementHook, *bun.DB, *prepareConnector) {
+	t.Helper()
+	connector := &prepareConnector{}
+	sqldb := sql.OpenDB(connector)
+	sqldb.SetMaxOpenConns(1)
+	db := bun.NewDB(sqldb, pgdialect.New())
+	t.Cleanup(func() { _ = db.Close() })
+
+	hook := hooks.NewPreparedStatementHook(db, size)
+	db.AddQueryHook(hook)
+	return hook, db, connector
+}
+
+func TestPreparedStatementHook_CachesStatements(t *testing.T) {
+	ctx := context.Background()
+	hook, _, connector := newPreparedHook(t, 10)
+
+	for i := 0; i < 2; i++ {
+		rows, err := hook.Query(ctx, "SELECT id FROM users WHERE org_id = $1", i)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		_ = rows.Close()
+	}
+	if _, err := hook.Exec(ctx, "UPDATE users\n   SET active = $1", true); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if _, err := hook.Exec(ctx, "UPDATE users SET active = $1", false); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	stats := hook.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %+v", stats)
+	}
+	if len(connector.prepared) != 2 {
+		t.Errorf("Expected each query to be prepared once, got %v", connector.prepared)
+	}
+	if connector.executed != 4 {
+		t.Errorf("Expected 4 statement executions, got %d", connector.executed)
+	}
+}
+
+func TestPreparedStatementHook_Eviction(t *testing.T) {
+	ctx := context.Background()
+	hook, _, connector := newPreparedHook(t, 2)
+
+	for _, query := range []string{"SELECT 1", "SELECT 2", "SELECT 1", "SELECT 3", "SELECT 2"} {
+		if _, err := hook.Exec(ctx, query); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+	}
+
+	// SELECT 2 is the least recently used when SELECT 3 is added
+	stats := hook.Stats()
+	if stats.Hits != 1 || stats.Misses != 4 || stats.Evictions != 2 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if connector.closed != 2 {
+		t.Errorf("Expected evicted statements to be closed, got %d", connector.closed)
+	}
+}
+
+func TestPreparedStatementHook_DDL(t *testing.T) {
+	ctx := context.Background()
+	hook, db, connector := newPreparedHook(t, 10)
+
+	if _, err := hook.Exec(ctx, "CREATE TABLE t (id int)"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if len(connector.prepared) != 0 || len(connector.direct) != 1 {
+		t.Errorf("Expected DDL to bypass the cache, prepared %v, direct %v", connector.prepared, connector.direct)
+	}
+
+	if _, err := hook.Exec(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if _, err := db.NewAddColumn().Table("t").ColumnExpr("name text").Exec(ctx); err != nil {
+		t.Fatalf("Add column failed: %v", err)
+	}
+	if connector.closed != 1 {
+		t.Errorf("Expected DDL through bun to close cached statements, got %d", connector.closed)
+	}
+	if _, err := hook.Exec(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if stats := hook.Stats(); stats.Hits != 0 || stats.Misses != 2 {
+		t.Errorf("Expected the statement to be prepared again, got %+v", stats)
+	}
+}
+
+func TestPreparedStatementHook_ConcurrentEviction(t *testing.T) {
+	ctx := context.Background()
+	hook, _, connector := newPreparedHook(t, 1)
+
+	// Every call evicts the statement of another caller, and Close drops them all
+	var wg sync.WaitGroup
+	for i := range 40 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := hook.Exec(ctx, fmt.Sprintf("SELECT %d", i%4)); err != nil {
+				t.Errorf("Exec failed: %v", err)
+			}
+			if i%10 == 0 {
+				_ = hook.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	_ = hook.Close()
+
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+	if connector.closed != len(connector.prepared) {
+		t.Errorf("Expected all %d prepared statements to be closed, got %d", len(connector.prepared), connector.closed)
+	}
+}
@@ -0,0 +1,371 @@
+package dbkit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// RDS IAM tokens are valid for 15 minutes and refreshed 2 minutes before they expire
+const (
+	rdsTokenTTL        = 15 * time.Minute
+	rdsRefreshBefore   = 2 * time.Minute
+	rdsRefreshRetry    = 10 * time.Second
+	rdsEmptyBodySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// RDSIAMConfig enables AWS RDS IAM authentication. The URL password is replaced by a signed token.
+type RDSIAMConfig struct {
+	Region      string                 // AWS region of the instance, e.g. "eu-west-1" (required)
+	DBUser      string                 // Database user granted rds_iam (required)
+	Endpoint    string                 // host:port the token is signed for (default: host and port of each URL)
+	Credentials AWSCredentialsProvider // Signing credentials (default: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN)
+}
+
+// AWSCredentials are the keys used to sign RDS IAM tokens
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary credentials
+}
+
+// AWSCredentialsProvider returns the current AWS credentials.
+// Wrap an aws-sdk-go-v2 aws.CredentialsProvider to use the SDK credential chain.
+//
+// Usage:
+//
+//	type sdkCredentials struct{ aws.CredentialsProvider }
+//
+//	func (p sdkCredentials) Retrieve(ctx context.Context) (dbkit.AWSCredentials, error) {
+//	    c, err := p.CredentialsProvider.Retrieve(ctx)
+//	    return dbkit.AWSCredentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}, err
+//	}
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// EnvAWSCredentials reads credentials from the standard AWS environment variables
+type EnvAWSCredentials struct{}
+
+// Retrieve returns the credentials from the environment
+func (EnvAWSCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	creds := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+	}
+	return creds, nil
+}
+
+// BuildRDSAuthToken returns an RDS IAM authentication token for dbUser on endpoint (host:port),
+// a SigV4 presigned connect URL valid for 15 minutes from now.
+func BuildRDSAuthToken(endpoint, region, dbUser string, creds AWSCredentials, now time.Time) (string, error) {
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return "", errors.New("dbkit: AWS credentials are missing")
+	}
+
+	now = now.UTC()
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := date + "/" + region + "/rds-db/aws4_request"
+
+	query := map[string]string{
+		"Action":              "connect",
+		"DBUser":              dbUser,
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    creds.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       fmt.Sprint(int(rdsTokenTTL.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if creds.SessionToken != "" {
+		query["X-Amz-Security-Token"] = creds.SessionToken
+	}
+	canonicalQuery := sigV4Query(query)
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		"/",
+		canonicalQuery,
+		"host:" + endpoint + "\n",
+		"host",
+		rdsEmptyBodySHA256,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	for _, part := range []string{region, "rds-db", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return endpoint + "/?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+// sigV4Query encodes query sorted by key with RFC 3986 escaping
+func sigV4Query(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = sigV4Escape(k) + "=" + sigV4Escape(query[k])
+	}
+	return strings.Join(parts, "&")
+}
+
+func sigV4Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// rdsEndpoint returns the host:port of dsn, defaulting the port to 5432
+func rdsEndpoint(dsn string) (string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "5432"
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// rdsConnector dials with the current IAM token, refreshing it in the background before it expires
+type rdsConnector struct {
+	cfg      RDSIAMConfig
+	endpoint string
+	connect  func(token string) driver.Connector
+	logger   *slog.Logger
+
+	ttl, refreshBefore, retry time.Duration
+
+	mu        sync.RWMutex
+	connector driver.Connector
+	token     string
+	expires   time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newRDSConnector generates the first token and starts the refresh goroutine.
+// connect builds a connector authenticating with token as password.
+func newRDSConnector(cfg RDSIAMConfig, endpoint string, connect func(token string) driver.Connector, logger *slog.Logger) (*rdsConnector, error) {
+	c := newRDSConnectorWithSchedule(cfg, endpoint, connect, logger, rdsTokenTTL, rdsRefreshBefore, rdsRefreshRetry)
+	if err := c.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	go c.run()
+	return c, nil
+}
+
+func newRDSConnectorWithSchedule(cfg RDSIAMConfig, endpoint string, connect func(string) driver.Connector, logger *slog.Logger, ttl, refreshBefore, retry time.Duration) *rdsConnector {
+	if cfg.Endpoint != "" {
+		endpoint = cfg.Endpoint
+	}
+	if cfg.Credentials == nil {
+		cfg.Credentials = EnvAWSCredentials{}
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &rdsConnector{
+		cfg:           cfg,
+		endpoint:      endpoint,
+		connect:       connect,
+		logger:        logger,
+		ttl:           ttl,
+		refreshBefore: refreshBefore,
+		retry:         retry,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+func (c *rdsConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.RLock()
+	connector := c.connector
+	c.mu.RUnlock()
+	return connector.Connect(ctx)
+}
+
+func (c *rdsConnector) Driver() driver.Driver {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connector.Driver()
+}
+
+// refresh generates a new token. On failure the previous token is kept while it is still valid.
+// Failures are always logged, as errors once no valid token is left.
+func (c *rdsConnector) refresh(ctx context.Context) error {
+	token, err := c.generate(ctx)
+	if err != nil {
+		c.mu.RLock()
+		valid := time.Now().Before(c.expires)
+		c.mu.RUnlock()
+		if valid {
+			c.logger.Warn("dbkit: RDS IAM token refresh failed, keeping the current token",
+				slog.String("endpoint", c.endpoint), slog.String("error", err.Error()))
+		} else {
+			c.logger.Error("dbkit: RDS IAM token refresh failed and no valid token is left, new connections will fail",
+				slog.String("endpoint", c.endpoint), slog.String("error", err.Error()))
+		}
+		return err
+	}
+
+	c.mu.Lock()
+	c.token = token
+	c.expires = time.Now().Add(c.ttl)
+	c.connector = c.connect(token)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *rdsConnector) generate(ctx context.Context) (string, error) {
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", &Error{
+			Code:    CodeConnectionFailed,
+			Message: "failed to retrieve AWS credentials",
+			Op:      "RefreshRDSToken",
+			Cause:   err,
+		}
+	}
+	token, err := BuildRDSAuthToken(c.endpoint, c.cfg.Region, c.cfg.DBUser, creds, time.Now())
+	if err != nil {
+		return "", &Error{
+			Code:    CodeConnectionFailed,
+			Message: "failed to build RDS IAM token",
+			Op:      "RefreshRDSToken",
+			Cause:   err,
+		}
+	}
+	return token, nil
+}
+
+// run refreshes the token refreshBefore its expiry, retrying failures every retry interval
+func (c *rdsConnector) run() {
+	defer close(c.done)
+
+	for {
+		c.mu.RLock()
+		wait := time.Until(c.expires.Add(-c.refreshBefore))
+		c.mu.RUnlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for c.refresh(context.Background()) != nil {
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(c.retry):
+			}
+		}
+	}
+}
+
+// close stops the refresh goroutine
+func (c *rdsConnector) close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+// newRDSConnectors returns an IAM connector per URL when cfg.RDSIAM is set, keyed by URL
+func newRDSConnectors(cfg Config, urls []string) (map[string]*rdsConnector, error) {
+	if cfg.RDSIAM == nil {
+		return nil, nil
+	}
+
+	connectors := make(map[string]*rdsConnector, len(urls))
+	for _, dsn := range urls {
+		if _, ok := connectors[dsn]; ok {
+			continue
+		}
+		endpoint, err := rdsEndpoint(dsn)
+		if err != nil {
+			stopRDSConnectors(connectors)
+			return nil, &Error{Code: CodeConnectionFailed, Message: "invalid database URL", Op: "New", Cause: err}
+		}
+		c, err := newRDSConnector(*cfg.RDSIAM, endpoint, func(token string) driver.Connector {
+			return pgConnector(cfg, dsn, pgdriver.WithUser(cfg.RDSIAM.DBUser), pgdriver.WithPassword(token))
+		}, cfg.Logger)
+		if err != nil {
+			stopRDSConnectors(connectors)
+			return nil, err
+		}
+		connectors[dsn] = c
+	}
+	return connectors, nil
+}
+
+func stopRDSConnectors(connectors map[string]*rdsConnector) {
+	for _, c := range connectors {
+		c.close()
+	}
+}
+
+// stopRDS stops the token refresh goroutines
+func (db *DBKit) stopRDS() {
+	for _, c := range db.rds {
+		c.close()
+	}
+}
+
+// RefreshRDSToken generates new RDS IAM tokens for every URL now, instead of waiting for the
+// background refresh. New connections use the new token; open connections are not affected.
+//
+// Usage:
+//
+//	if err := db.RefreshRDSToken(ctx); err != nil {
+//	    log.Printf("token refresh failed: %v", err)
+//	}
+func (db *DBKit) RefreshRDSToken(ctx context.Context) error {
+	if len(db.rds) == 0 {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "RDS IAM authentication is not configured",
+			Op:      "RefreshRDSToken",
+		}
+	}
+	for _, c := range db.rds {
+		if err := c.refresh(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
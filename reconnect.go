@@ -0,0 +1,114 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	reconnectInitialBackoff = 100 * time.Millisecond
+	reconnectMaxBackoff     = 30 * time.Second
+)
+
+// reconnector retries failed dials with exponential backoff while Config.AutoReconnect is set.
+// A reconnect holds the lock, so dials from concurrent operations queue behind it
+// until it ends or their context is done.
+type reconnector struct {
+	enabled    bool
+	maxElapsed time.Duration
+
+	sem         chan struct{} // Lock with capacity 1, held by the reconnecting caller
+	generation  atomic.Int64  // Incremented after every successful reconnect
+	onReconnect atomic.Pointer[func(attempt int, err error)]
+}
+
+// dial connects with connect, reconnecting with backoff when the database is unreachable.
+func (r *reconnector) dial(ctx context.Context, connect func(context.Context) (driver.Conn, error)) (driver.Conn, error) {
+	if !r.enabled {
+		return connect(ctx)
+	}
+
+	if err := r.lock(ctx); err != nil {
+		return nil, err
+	}
+	generation := r.generation.Load()
+	r.unlock()
+
+	conn, err := connect(ctx)
+	if err == nil || !isConnectionFailure(err) {
+		return conn, err
+	}
+	return r.reconnect(ctx, generation, connect)
+}
+
+// reconnect dials until it succeeds, ReconnectMaxElapsed elapses or ctx is done.
+// If another caller reconnected since generation, a single dial is attempted.
+func (r *reconnector) reconnect(ctx context.Context, generation int64, connect func(context.Context) (driver.Conn, error)) (driver.Conn, error) {
+	if err := r.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.unlock()
+
+	if r.generation.Load() != generation {
+		return connect(ctx)
+	}
+
+	start := time.Now()
+	backoff := reconnectInitialBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := connect(ctx)
+		r.notify(attempt, err)
+		if err == nil {
+			r.generation.Add(1)
+			return conn, nil
+		}
+		if !isConnectionFailure(err) || time.Since(start)+backoff > r.maxElapsed {
+			return nil, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, reconnectMaxBackoff)
+	}
+}
+
+// lock acquires the reconnect lock, giving up when ctx is done.
+func (r *reconnector) lock(ctx context.Context) error {
+	select {
+	case r.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unlock releases the reconnect lock.
+func (r *reconnector) unlock() {
+	<-r.sem
+}
+
+// notify calls the OnReconnect callback, if any.
+func (r *reconnector) notify(attempt int, err error) {
+	if fn := r.onReconnect.Load(); fn != nil {
+		(*fn)(attempt, err)
+	}
+}
+
+// OnReconnect sets a callback run after every reconnect attempt made while Config.AutoReconnect is set.
+// err is nil for the attempt that succeeded.
+//
+// Usage:
+//
+//	db.OnReconnect(func(attempt int, err error) {
+//	    logger.Warn("database reconnect", "attempt", attempt, "error", err)
+//	})
+func (db *DBKit) OnReconnect(fn func(attempt int, err error)) {
+	db.connector.reconnect.onReconnect.Store(&fn)
+}
@@ -0,0 +1,124 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newReconnectingFakeDB(t *testing.T, maxElapsed time.Duration) (*DBKit, *fakeConnector) {
+	t.Helper()
+
+	cfg := DefaultConfig("fake")
+	cfg.AutoReconnect = true
+	cfg.ReconnectMaxElapsed = maxElapsed
+	connector := &fakeConnector{}
+	db, err := newDBKit(cfg, connector)
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	db.DB.DB.SetMaxIdleConns(-1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db, connector
+}
+
+func TestAutoReconnect_SucceedsAfterRecovery(t *testing.T) {
+	ctx := context.Background()
+	db, connector := newReconnectingFakeDB(t, 5*time.Second)
+
+	var mu sync.Mutex
+	var attempts []int
+	var lastErr error
+	db.OnReconnect(func(attempt int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, attempt)
+		lastErr = err
+	})
+
+	connector.failing.Store(true)
+	time.AfterFunc(250*time.Millisecond, func() { connector.failing.Store(false) })
+
+	if _, err := db.NewSelect().ColumnExpr("1").Exec(ctx); err != nil {
+		t.Fatalf("Expected query to succeed after reconnect, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(attempts) < 2 {
+		t.Errorf("Expected several reconnect attempts, got %v", attempts)
+	}
+	if lastErr != nil {
+		t.Errorf("Expected the last attempt to succeed, got %v", lastErr)
+	}
+}
+
+func TestAutoReconnect_GivesUpAfterMaxElapsed(t *testing.T) {
+	ctx := context.Background()
+	db, connector := newReconnectingFakeDB(t, 300*time.Millisecond)
+	connector.failing.Store(true)
+
+	start := time.Now()
+	if err := db.Ping(ctx); err == nil {
+		t.Fatal("Expected dial error")
+	}
+	if connector.dials.Load() < 2 {
+		t.Errorf("Expected several dial attempts, got %d", connector.dials.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected to give up after ReconnectMaxElapsed, took %s", elapsed)
+	}
+}
+
+func TestAutoReconnect_StopsOnContextCancel(t *testing.T) {
+	db, connector := newReconnectingFakeDB(t, time.Minute)
+	connector.failing.Store(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := db.Ping(ctx); err == nil {
+		t.Fatal("Expected an error after the context was cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected to stop when the context was cancelled, took %s", elapsed)
+	}
+}
+
+func TestAutoReconnect_Disabled(t *testing.T) {
+	ctx := context.Background()
+	db, connector := newFakeDB(t)
+	connector.failing.Store(true)
+
+	called := false
+	db.OnReconnect(func(attempt int, err error) { called = true })
+	if err := db.Ping(ctx); err == nil {
+		t.Fatal("Expected dial error")
+	}
+	if called || connector.dials.Load() > 2 {
+		t.Errorf("Expected no reconnect attempts, got %d dials", connector.dials.Load())
+	}
+}
+
+func TestAutoReconnect_WaitingDialHonorsContext(t *testing.T) {
+	db, connector := newReconnectingFakeDB(t, time.Minute)
+	connector.failing.Store(true)
+
+	// A first caller keeps reconnecting in the background
+	bg, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go func() { _ = db.Ping(bg) }()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancelWait := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelWait()
+	start := time.Now()
+	if err := db.Ping(ctx); err == nil {
+		t.Fatal("Expected an error while the database is down")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the waiting dial to stop with its context, took %s", elapsed)
+	}
+}
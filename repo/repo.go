@@ -0,0 +1,120 @@
+// Package repo provides a generic repository abstraction over dbkit
+package repo
+
+import (
+	"context"
+
+	"github.com/fernandezvara/dbkit"
+	"github.com/uptrace/bun"
+)
+
+// Repository is a minimal CRUD interface for a model type T with primary key type ID.
+// Business logic can depend on Repository instead of a database handle.
+type Repository[T any, ID any] interface {
+	FindByID(ctx context.Context, id ID) (*T, error)
+	FindOne(ctx context.Context, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (*T, error)
+	FindAll(ctx context.Context, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]T, error)
+	Create(ctx context.Context, model *T) error
+	Update(ctx context.Context, model *T) error
+	Delete(ctx context.Context, model *T) error
+	Count(ctx context.Context, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (int, error)
+}
+
+// TransactionalRepository is a Repository that can run a function within a transaction.
+// The repository passed to fn is bound to the transaction.
+type TransactionalRepository[T any, ID any] interface {
+	Repository[T, ID]
+	WithTransaction(ctx context.Context, fn func(Repository[T, ID]) error) error
+}
+
+// dbRepository implements TransactionalRepository using a dbkit.IDB.
+type dbRepository[T any, ID any] struct {
+	db dbkit.IDB
+}
+
+// Ensure dbRepository implements TransactionalRepository
+var _ TransactionalRepository[struct{}, string] = (*dbRepository[struct{}, string])(nil)
+
+// NewDBRepository creates a repository backed by db, which may be a *dbkit.DBKit or a *dbkit.Tx.
+// The returned repository is also a TransactionalRepository.
+//
+// Usage:
+//
+//	users := repo.NewDBRepository[User, string](db)
+//	user, err := users.FindByID(ctx, id)
+func NewDBRepository[T any, ID any](db dbkit.IDB) TransactionalRepository[T, ID] {
+	return &dbRepository[T, ID]{db: db}
+}
+
+// FindByID finds a record by primary key.
+func (r *dbRepository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
+	var model T
+	if err := r.db.NewSelect().Model(&model).Where("?PKs = ?", id).Scan(ctx); err != nil {
+		return nil, dbkit.WrapError(err, "FindByID")
+	}
+	return &model, nil
+}
+
+// FindOne finds the first record matching the query.
+func (r *dbRepository[T, ID]) FindOne(ctx context.Context, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (*T, error) {
+	var model T
+	q := r.db.NewSelect().Model(&model)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+	if err := q.Limit(1).Scan(ctx); err != nil {
+		return nil, dbkit.WrapError(err, "FindOne")
+	}
+	return &model, nil
+}
+
+// FindAll finds all records matching the query.
+func (r *dbRepository[T, ID]) FindAll(ctx context.Context, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]T, error) {
+	var items []T
+	q := r.db.NewSelect().Model(&items)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, dbkit.WrapError(err, "FindAll")
+	}
+	return items, nil
+}
+
+// Create inserts a record, validating models that implement dbkit.Validatable.
+func (r *dbRepository[T, ID]) Create(ctx context.Context, model *T) error {
+	return dbkit.Create(ctx, r.db, model)
+}
+
+// Update updates a record by primary key, validating models that implement dbkit.Validatable.
+func (r *dbRepository[T, ID]) Update(ctx context.Context, model *T) error {
+	return dbkit.Update(ctx, r.db, model)
+}
+
+// Delete deletes a record by primary key.
+// Models with a soft delete column are soft deleted by Bun.
+func (r *dbRepository[T, ID]) Delete(ctx context.Context, model *T) error {
+	if _, err := r.db.NewDelete().Model(model).WherePK().Exec(ctx); err != nil {
+		return dbkit.WrapError(err, "Delete")
+	}
+	return nil
+}
+
+// Count counts records matching the query.
+func (r *dbRepository[T, ID]) Count(ctx context.Context, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (int, error) {
+	return dbkit.Count[T](ctx, r.db, queryFn)
+}
+
+// WithTransaction runs fn with a repository bound to a transaction.
+// If the repository is already bound to a transaction, a savepoint is used.
+//
+// Usage:
+//
+//	err := users.WithTransaction(ctx, func(tx repo.Repository[User, string]) error {
+//	    return tx.Create(ctx, &user)
+//	})
+func (r *dbRepository[T, ID]) WithTransaction(ctx context.Context, fn func(Repository[T, ID]) error) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return fn(NewDBRepository[T, ID](tx))
+	})
+}
@@ -0,0 +1,121 @@
+package dbkit
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy configures RetryableOperation and retried transactions
+type RetryPolicy struct {
+	MaxAttempts    int                  // Attempts including the first one (default: 3)
+	InitialBackoff time.Duration        // Wait before the first retry (default: 50ms)
+	MaxBackoff     time.Duration        // Upper bound of the wait between attempts (default: 2s)
+	Multiplier     float64              // Backoff growth per attempt (default: 2)
+	ShouldRetry    func(err error) bool // Errors worth retrying (default: IsTransient)
+}
+
+// DefaultRetryPolicy returns a policy making 3 attempts with exponential backoff from 50ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// withDefaults fills in zero values with defaults
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	d := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = d.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = d.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = d.Multiplier
+	}
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = IsTransient
+	}
+	return p
+}
+
+// IsTransient checks if the error may go away on retry: serialization failures, deadlocks,
+// connection errors and timeouts. Unwrapped driver errors are classified too.
+func IsTransient(err error) bool {
+	err = wrapError(err, "")
+	return IsRetryable(err) || IsConnection(err) || IsTimeout(err)
+}
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy returns a context under which transactions started with DBKit.Transaction,
+// TransactionWithOptions, ReadOnlyTransaction, SerializableTransaction and InTransaction are
+// retried with policy, unless their TxOptions set another one.
+//
+// Usage:
+//
+//	ctx = dbkit.WithRetryPolicy(ctx, dbkit.DefaultRetryPolicy())
+//	err := db.Transaction(ctx, transfer) // Retried on transient errors
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, &policy)
+}
+
+// RetryPolicyFromContext returns the policy stored by WithRetryPolicy.
+// It returns false inside RetryableOperation, so nested operations don't multiply attempts.
+func RetryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	p, _ := ctx.Value(retryPolicyKey{}).(*RetryPolicy)
+	if p == nil {
+		return RetryPolicy{}, false
+	}
+	return *p, true
+}
+
+// RetryableOperation runs fn until it succeeds, returns an error that policy.ShouldRetry
+// rejects, or runs out of attempts, waiting with exponential backoff between attempts.
+// Each retry uses the retry budget of ctx, if any (see WithRetryBudget).
+// fn must be safe to run again: a connection lost during a commit may have committed.
+//
+// Usage:
+//
+//	err := dbkit.RetryableOperation(ctx, dbkit.DefaultRetryPolicy(), func(ctx context.Context) error {
+//	    return dbkit.Create(ctx, db, &event)
+//	})
+func RetryableOperation(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+	budget := RetryBudgetFromContext(ctx)
+	attemptCtx := context.WithValue(ctx, retryPolicyKey{}, (*RetryPolicy)(nil))
+
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(attemptCtx)
+		if err == nil || attempt >= policy.MaxAttempts || !policy.ShouldRetry(err) || ctx.Err() != nil {
+			return err
+		}
+		if err := budget.Take("RetryableOperation"); err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return wrapError(ctx.Err(), "RetryableOperation")
+		case <-timer.C:
+		}
+		backoff = min(time.Duration(float64(backoff)*policy.Multiplier), policy.MaxBackoff)
+	}
+}
+
+// txRetryPolicy returns the retry policy of a transaction: from opts, or else from ctx
+func txRetryPolicy(ctx context.Context, opts TxOptions) (RetryPolicy, bool) {
+	if opts.Retry != nil {
+		return *opts.Retry, true
+	}
+	return RetryPolicyFromContext(ctx)
+}
@@ -0,0 +1,70 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrBudgetExhausted is returned by retry loops once the retry budget of the context is spent
+var ErrBudgetExhausted = errors.New("dbkit: retry budget exhausted")
+
+type retryBudgetKey struct{}
+
+// RetryBudget caps the retries of every retry loop sharing a context, such as all the
+// operations of one request. Retry loops call Take before each retry; first attempts are free.
+type RetryBudget struct {
+	MaxAttempts int
+	used        atomic.Int64
+}
+
+// WithRetryBudget returns a context allowing maxAttempts retries in total across
+// RetryOnConflict, RetryableOperation and the crdb retry loop.
+//
+// Usage:
+//
+//	ctx = dbkit.WithRetryBudget(r.Context(), 5)
+//	err := dbkit.RetryOnConflict(ctx, 3, updateBalance)
+func WithRetryBudget(ctx context.Context, maxAttempts int) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, &RetryBudget{MaxAttempts: maxAttempts})
+}
+
+// RetryBudgetFromContext returns the budget stored by WithRetryBudget, or nil
+func RetryBudgetFromContext(ctx context.Context) *RetryBudget {
+	b, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return b
+}
+
+// RemainingRetries returns the retries left in the budget of ctx, or -1 without a budget
+func RemainingRetries(ctx context.Context) int {
+	b := RetryBudgetFromContext(ctx)
+	if b == nil {
+		return -1
+	}
+	return b.Remaining()
+}
+
+// Remaining returns the retries left
+func (b *RetryBudget) Remaining() int {
+	if left := b.MaxAttempts - int(b.used.Load()); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// Take uses one retry, returning an error wrapping ErrBudgetExhausted if none is left.
+// A nil budget never runs out.
+func (b *RetryBudget) Take(op string) error {
+	if b == nil {
+		return nil
+	}
+	if b.used.Add(1) > int64(b.MaxAttempts) {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "retry budget exhausted",
+			Op:      op,
+			Cause:   ErrBudgetExhausted,
+		}
+	}
+	return nil
+}
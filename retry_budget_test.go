@@ -0,0 +1,79 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// conflictOnce fails its first call with a version conflict
+func conflictOnce() func() error {
+	calls := 0
+	return func() error {
+		calls++
+		if calls == 1 {
+			return &Error{Code: CodeConflict, Message: "version mismatch", Cause: ErrConflict}
+		}
+		return nil
+	}
+}
+
+func TestRetryBudgetAcrossOperations(t *testing.T) {
+	ctx := WithRetryBudget(context.Background(), 2)
+	if got := RemainingRetries(ctx); got != 2 {
+		t.Fatalf("expected 2 remaining retries, got %d", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := RetryOnConflict(ctx, 3, conflictOnce()); err != nil {
+			t.Fatalf("operation %d failed: %v", i+1, err)
+		}
+	}
+	if got := RemainingRetries(ctx); got != 0 {
+		t.Errorf("expected 0 remaining retries, got %d", got)
+	}
+
+	err := RetryOnConflict(ctx, 3, conflictOnce())
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected the third operation to fail with ErrBudgetExhausted, got %v", err)
+	}
+
+	// Operations that need no retry still succeed
+	if err := RetryOnConflict(ctx, 3, func() error { return nil }); err != nil {
+		t.Errorf("expected first attempts to be free, got %v", err)
+	}
+}
+
+func TestRetryBudgetWithoutBudget(t *testing.T) {
+	ctx := context.Background()
+	if got := RemainingRetries(ctx); got != -1 {
+		t.Errorf("expected -1 without a budget, got %d", got)
+	}
+	calls := 0
+	err := RetryOnConflict(ctx, 3, func() error {
+		calls++
+		return &Error{Code: CodeConflict, Cause: ErrConflict}
+	})
+	if !IsConflict(err) || calls != 3 {
+		t.Errorf("expected 3 attempts ending in a conflict, got %d (%v)", calls, err)
+	}
+}
+
+func TestRetryBudgetIsShared(t *testing.T) {
+	ctx := WithRetryBudget(context.Background(), 3)
+	budget := RetryBudgetFromContext(WithTenant(ctx, "acme"))
+	if budget == nil {
+		t.Fatal("expected derived contexts to share the budget")
+	}
+	for i := 0; i < 3; i++ {
+		if err := budget.Take("test"); err != nil {
+			t.Fatalf("Take %d failed: %v", i+1, err)
+		}
+	}
+	if err := budget.Take("test"); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if RemainingRetries(ctx) != 0 {
+		t.Errorf("expected no retries left, got %d", RemainingRetries(ctx))
+	}
+}
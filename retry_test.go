@@ -0,0 +1,192 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+var fastRetry = RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+func TestRetryableOperation_SucceedsOnThirdAttempt(t *testing.T) {
+	errs := []error{
+		&Error{Code: CodeConnectionFailed, Message: "connection refused"},
+		&Error{Code: CodeConnectionFailed, Message: "connection refused"},
+		nil,
+	}
+	attempts := 0
+	err := RetryableOperation(context.Background(), fastRetry, func(ctx context.Context) error {
+		err := errs[attempts]
+		attempts++
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryableOperation_StopsOnPermanentError(t *testing.T) {
+	attempts := 0
+	err := RetryableOperation(context.Background(), fastRetry, func(ctx context.Context) error {
+		attempts++
+		return &Error{Code: CodeDuplicate, Message: "duplicate"}
+	})
+	if !IsDuplicate(err) || attempts != 1 {
+		t.Errorf("Expected 1 attempt returning the error, got %d attempts, %v", attempts, err)
+	}
+}
+
+func TestRetryableOperation_MaxAttemptsAndBudget(t *testing.T) {
+	timeout := &Error{Code: CodeTimeout, Message: "query exceeded its deadline"}
+
+	attempts := 0
+	err := RetryableOperation(context.Background(), fastRetry, func(ctx context.Context) error {
+		attempts++
+		return timeout
+	})
+	if err != timeout || attempts != 5 {
+		t.Errorf("Expected 5 attempts, got %d, %v", attempts, err)
+	}
+
+	attempts = 0
+	err = RetryableOperation(WithRetryBudget(context.Background(), 1), fastRetry, func(ctx context.Context) error {
+		attempts++
+		return timeout
+	})
+	if !errors.Is(err, ErrBudgetExhausted) || attempts != 2 {
+		t.Errorf("Expected the budget to stop after 2 attempts, got %d, %v", attempts, err)
+	}
+}
+
+func TestRetryableOperation_ShouldRetry(t *testing.T) {
+	errFlaky := errors.New("flaky upstream")
+	policy := fastRetry
+	policy.ShouldRetry = func(err error) bool { return errors.Is(err, errFlaky) }
+
+	attempts := 0
+	err := RetryableOperation(context.Background(), policy, func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errFlaky
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("Expected success on attempt 2, got %d, %v", attempts, err)
+	}
+}
+
+func TestRetryableOperation_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}
+
+	attempts := 0
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	err := RetryableOperation(ctx, policy, func(ctx context.Context) error {
+		attempts++
+		return &Error{Code: CodeConnectionFailed}
+	})
+	if !errors.Is(err, context.Canceled) || attempts != 1 {
+		t.Errorf("Expected cancellation during the backoff, got %d attempts, %v", attempts, err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{&Error{Code: CodeSerialization}, true},
+		{&Error{Code: CodeDeadlock}, true},
+		{&Error{Code: CodeConnectionFailed}, true},
+		{context.DeadlineExceeded, true},
+		{&Error{Code: CodeNotFound}, false},
+		{sql.ErrNoRows, false},
+		{nil, false},
+	} {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestTransaction_RetryPolicyFromContext(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := WithRetryPolicy(context.Background(), fastRetry)
+
+	attempts := 0
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		attempts++
+		if attempts < 3 {
+			return &Error{Code: CodeSerialization, Message: "could not serialize access"}
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected success on attempt 3, got %d, %v", attempts, err)
+	}
+
+	// Nested transactions within a retried one are not retried again
+	attempts = 0
+	err = RetryableOperation(ctx, fastRetry, func(ctx context.Context) error {
+		return db.Transaction(ctx, func(tx *Tx) error {
+			attempts++
+			return &Error{Code: CodeDeadlock}
+		})
+	})
+	if !IsRetryable(err) || attempts != fastRetry.MaxAttempts {
+		t.Errorf("Expected %d attempts, got %d, %v", fastRetry.MaxAttempts, attempts, err)
+	}
+}
+
+func TestTransactionWithOptions_Retry(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	opts := SerializableTxOptions()
+	opts.Retry = &fastRetry
+	attempts := 0
+	err := db.TransactionWithOptions(context.Background(), opts, func(tx *Tx) error {
+		attempts++
+		return &Error{Code: CodeSerialization}
+	})
+	if !IsRetryable(err) || attempts != 5 {
+		t.Errorf("Expected 5 attempts, got %d, %v", attempts, err)
+	}
+
+	// Without a policy a transaction runs once
+	attempts = 0
+	_ = db.SerializableTransaction(context.Background(), func(tx *Tx) error {
+		attempts++
+		return &Error{Code: CodeSerialization}
+	})
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt without a policy, got %d", attempts)
+	}
+}
+
+func TestTransaction_ContextMarksAttempt(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := WithRetryPolicy(context.Background(), fastRetry)
+
+	inner := 0
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		if _, ok := RetryPolicyFromContext(tx.Context()); ok {
+			t.Error("Expected tx.Context() to carry no retry policy")
+		}
+		return db.Transaction(tx.Context(), func(*Tx) error {
+			inner++
+			return &Error{Code: CodeDeadlock}
+		})
+	})
+	if !IsRetryable(err) || inner != fastRetry.MaxAttempts {
+		t.Errorf("Expected %d inner attempts, got %d, %v", fastRetry.MaxAttempts, inner, err)
+	}
+}
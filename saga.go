@@ -0,0 +1,219 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SagaStatus is the state of a saga execution
+type SagaStatus string
+
+const (
+	SagaRunning            SagaStatus = "running"
+	SagaCompleted          SagaStatus = "completed"
+	SagaCompensated        SagaStatus = "compensated"         // A step failed and every completed step was compensated
+	SagaCompensationFailed SagaStatus = "compensation_failed" // A step failed and at least one compensation failed too
+)
+
+// SagaStepStatus is the state of a single saga step
+type SagaStepStatus string
+
+const (
+	SagaStepPending            SagaStepStatus = "pending"
+	SagaStepCompleted          SagaStepStatus = "completed"
+	SagaStepFailed             SagaStepStatus = "failed"
+	SagaStepCompensated        SagaStepStatus = "compensated"
+	SagaStepCompensationFailed SagaStepStatus = "compensation_failed"
+)
+
+// SagaStep is a step of a saga and the compensation that undoes it.
+// Compensate may be nil for steps with nothing to undo.
+type SagaStep struct {
+	Name       string
+	Execute    func(ctx context.Context, tx *Tx) error
+	Compensate func(ctx context.Context, tx *Tx) error
+}
+
+// SagaStepState is the recorded state of a step
+type SagaStepState struct {
+	Name   string         `json:"name"`
+	Status SagaStepStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// SagaExecution records a saga run in the saga_executions table.
+//
+// Create the table with CreateSagaTable or:
+//
+//	CREATE TABLE saga_executions (
+//	    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+//	    name VARCHAR NOT NULL,
+//	    status VARCHAR NOT NULL,
+//	    steps JSONB NOT NULL,
+//	    error VARCHAR,
+//	    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+//	    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+//	);
+type SagaExecution struct {
+	bun.BaseModel `bun:"table:saga_executions,alias:se"`
+
+	ID        string          `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Name      string          `bun:"name,notnull"`
+	Status    SagaStatus      `bun:"status,notnull"`
+	Steps     []SagaStepState `bun:"steps,type:jsonb,notnull"`
+	Error     string          `bun:"error"`
+	CreatedAt time.Time       `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time       `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// SagaCompensationError is a compensation that failed
+type SagaCompensationError struct {
+	Step string
+	Err  error
+}
+
+// SagaError reports the step that failed and any compensations that failed after it
+type SagaError struct {
+	SagaID        string
+	Step          string
+	Err           error
+	Compensations []SagaCompensationError
+}
+
+func (e *SagaError) Error() string {
+	msg := fmt.Sprintf("saga step %q failed: %v", e.Step, e.Err)
+	if len(e.Compensations) > 0 {
+		failed := make([]string, len(e.Compensations))
+		for i, c := range e.Compensations {
+			failed[i] = fmt.Sprintf("%s: %v", c.Step, c.Err)
+		}
+		msg += " (compensations failed: " + strings.Join(failed, "; ") + ")"
+	}
+	return msg
+}
+
+func (e *SagaError) Unwrap() error {
+	return e.Err
+}
+
+// SagaCoordinator runs a sequence of steps, compensating completed steps when one fails
+type SagaCoordinator struct {
+	name  string
+	steps []SagaStep
+}
+
+// NewSagaCoordinator creates a saga with the given name and steps.
+//
+// Usage:
+//
+//	saga := dbkit.NewSagaCoordinator("place_order",
+//	    dbkit.SagaStep{Name: "reserve", Execute: reserveStock, Compensate: releaseStock},
+//	    dbkit.SagaStep{Name: "charge", Execute: chargeCard, Compensate: refundCard},
+//	)
+//	err := saga.Run(ctx, db)
+func NewSagaCoordinator(name string, steps ...SagaStep) *SagaCoordinator {
+	return &SagaCoordinator{name: name, steps: steps}
+}
+
+// AddStep appends a step to the saga.
+func (s *SagaCoordinator) AddStep(step SagaStep) *SagaCoordinator {
+	s.steps = append(s.steps, step)
+	return s
+}
+
+// CreateSagaTable creates the saga_executions table used by SagaCoordinator.Run if it does not exist.
+//
+// Usage:
+//
+//	if err := dbkit.CreateSagaTable(ctx, db); err != nil {
+//	    return err
+//	}
+func CreateSagaTable(ctx context.Context, db IDB) error {
+	_, err := db.NewCreateTable().Model((*SagaExecution)(nil)).IfNotExists().Exec(ctx)
+	return wrapError(err, "CreateSagaTable")
+}
+
+// Run executes the steps in order, each in its own transaction, recording progress in the
+// saga_executions table (see CreateSagaTable). When a step fails, the compensations of the
+// completed steps run in reverse order, each in its own transaction, and a *SagaError is returned.
+// A step is only recorded as completed if its transaction commits.
+//
+// Compensations and the final state are not bound to ctx: a step that fails because ctx was
+// cancelled or timed out is still compensated.
+func (s *SagaCoordinator) Run(ctx context.Context, db *DBKit) error {
+	exec := &SagaExecution{Name: s.name, Status: SagaRunning, Steps: make([]SagaStepState, len(s.steps))}
+	for i, step := range s.steps {
+		exec.Steps[i] = SagaStepState{Name: step.Name, Status: SagaStepPending}
+	}
+	if _, err := db.NewInsert().Model(exec).Returning("id").Exec(ctx); err != nil {
+		return wrapError(err, "Saga.Run")
+	}
+
+	for i, step := range s.steps {
+		err := db.Transaction(ctx, func(tx *Tx) error {
+			if err := step.Execute(ctx, tx); err != nil {
+				return err
+			}
+			exec.Steps[i].Status = SagaStepCompleted
+			return saveSagaExecution(ctx, tx, exec)
+		})
+		if err == nil {
+			continue
+		}
+
+		exec.Steps[i] = SagaStepState{Name: step.Name, Status: SagaStepFailed, Error: err.Error()}
+		sagaErr := &SagaError{SagaID: exec.ID, Step: step.Name, Err: err}
+		cleanupCtx := context.WithoutCancel(ctx)
+		s.compensate(cleanupCtx, db, exec, i, sagaErr)
+
+		exec.Status = SagaCompensated
+		if len(sagaErr.Compensations) > 0 {
+			exec.Status = SagaCompensationFailed
+		}
+		exec.Error = sagaErr.Error()
+		if err := saveSagaExecution(cleanupCtx, db, exec); err != nil {
+			return fmt.Errorf("%w (failed to record saga state: %v)", sagaErr, err)
+		}
+		return sagaErr
+	}
+
+	exec.Status = SagaCompleted
+	return saveSagaExecution(context.WithoutCancel(ctx), db, exec)
+}
+
+// compensate undoes the steps before failed in reverse order, collecting failures in sagaErr.
+func (s *SagaCoordinator) compensate(ctx context.Context, db *DBKit, exec *SagaExecution, failed int, sagaErr *SagaError) {
+	for i := failed - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate == nil {
+			exec.Steps[i].Status = SagaStepCompensated
+			continue
+		}
+
+		err := db.Transaction(ctx, func(tx *Tx) error {
+			if err := step.Compensate(ctx, tx); err != nil {
+				return err
+			}
+			exec.Steps[i].Status = SagaStepCompensated
+			return saveSagaExecution(ctx, tx, exec)
+		})
+		if err != nil {
+			exec.Steps[i] = SagaStepState{Name: step.Name, Status: SagaStepCompensationFailed, Error: err.Error()}
+			sagaErr.Compensations = append(sagaErr.Compensations, SagaCompensationError{Step: step.Name, Err: err})
+		}
+	}
+}
+
+// saveSagaExecution stores the status and step states of exec.
+func saveSagaExecution(ctx context.Context, db IDB, exec *SagaExecution) error {
+	_, err := db.NewUpdate().Model(exec).
+		Column("status", "steps", "error").
+		Set("updated_at = NOW()").
+		WherePK().
+		Exec(ctx)
+	return wrapError(err, "Saga.Run")
+}
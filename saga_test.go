@@ -0,0 +1,196 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSagaError_Error(t *testing.T) {
+	cause := errors.New("card declined")
+	err := &SagaError{
+		Step:          "charge",
+		Err:           cause,
+		Compensations: []SagaCompensationError{{Step: "reserve", Err: errors.New("stock service down")}},
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("Expected SagaError to unwrap to the step error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `"charge"`) || !strings.Contains(msg, "reserve: stock service down") {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+}
+
+func createSagaTable(t *testing.T, db *DBKit) {
+	t.Helper()
+	if err := CreateSagaTable(context.Background(), db); err != nil {
+		t.Fatalf("CreateSagaTable failed: %v", err)
+	}
+}
+
+func TestSagaCoordinator_CompensatesOnFailure(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTable(t, db)
+	createSagaTable(t, db)
+
+	var compensated []string
+	stepErr := errors.New("payment declined")
+	saga := NewSagaCoordinator("place_order",
+		SagaStep{
+			Name: "reserve",
+			Execute: func(ctx context.Context, tx *Tx) error {
+				_, err := tx.NewInsert().Model(&TestModel{Name: "Reserved", Email: "reserved@example.com"}).Exec(ctx)
+				return err
+			},
+			Compensate: func(ctx context.Context, tx *Tx) error {
+				compensated = append(compensated, "reserve")
+				_, err := tx.NewDelete().Model((*TestModel)(nil)).Where("email = ?", "reserved@example.com").Exec(ctx)
+				return err
+			},
+		},
+		SagaStep{
+			Name: "charge",
+			Execute: func(ctx context.Context, tx *Tx) error {
+				if _, err := tx.NewInsert().Model(&TestModel{Name: "Charged", Email: "charged@example.com"}).Exec(ctx); err != nil {
+					return err
+				}
+				return stepErr
+			},
+			Compensate: func(ctx context.Context, tx *Tx) error {
+				compensated = append(compensated, "charge")
+				return nil
+			},
+		},
+	).AddStep(SagaStep{
+		Name: "ship",
+		Execute: func(ctx context.Context, tx *Tx) error {
+			t.Error("Expected step after the failure not to run")
+			return nil
+		},
+	})
+
+	err := saga.Run(ctx, db)
+	var sagaErr *SagaError
+	if !errors.As(err, &sagaErr) {
+		t.Fatalf("Expected SagaError, got %v", err)
+	}
+	if sagaErr.Step != "charge" || !errors.Is(err, stepErr) || len(sagaErr.Compensations) != 0 {
+		t.Errorf("Unexpected saga error: %+v", sagaErr)
+	}
+	if len(compensated) != 1 || compensated[0] != "reserve" {
+		t.Errorf("Expected only the completed step to be compensated, got %v", compensated)
+	}
+
+	count, err := db.NewSelect().Model((*TestModel)(nil)).Count(ctx)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected every change to be undone, got %d rows", count)
+	}
+
+	var exec SagaExecution
+	if err := db.NewSelect().Model(&exec).Where("id = ?", sagaErr.SagaID).Scan(ctx); err != nil {
+		t.Fatalf("Failed to load saga execution: %v", err)
+	}
+	if exec.Status != SagaCompensated || exec.Error == "" {
+		t.Errorf("Expected compensated saga with an error, got %s %q", exec.Status, exec.Error)
+	}
+	want := []SagaStepStatus{SagaStepCompensated, SagaStepFailed, SagaStepPending}
+	for i, status := range want {
+		if exec.Steps[i].Status != status {
+			t.Errorf("Step %s: expected %s, got %s", exec.Steps[i].Name, status, exec.Steps[i].Status)
+		}
+	}
+}
+
+func TestSagaCoordinator_CompensationFailure(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	createSagaTable(t, db)
+
+	saga := NewSagaCoordinator("failing_compensation",
+		SagaStep{
+			Name:       "first",
+			Execute:    func(ctx context.Context, tx *Tx) error { return nil },
+			Compensate: func(ctx context.Context, tx *Tx) error { return errors.New("cannot undo") },
+		},
+		SagaStep{
+			Name:    "second",
+			Execute: func(ctx context.Context, tx *Tx) error { return errors.New("boom") },
+		},
+	)
+
+	var sagaErr *SagaError
+	if err := saga.Run(ctx, db); !errors.As(err, &sagaErr) {
+		t.Fatalf("Expected SagaError, got %v", err)
+	}
+	if len(sagaErr.Compensations) != 1 || sagaErr.Compensations[0].Step != "first" {
+		t.Errorf("Expected the failed compensation to be reported, got %+v", sagaErr.Compensations)
+	}
+
+	var exec SagaExecution
+	if err := db.NewSelect().Model(&exec).Where("id = ?", sagaErr.SagaID).Scan(ctx); err != nil {
+		t.Fatalf("Failed to load saga execution: %v", err)
+	}
+	if exec.Status != SagaCompensationFailed || exec.Steps[0].Status != SagaStepCompensationFailed {
+		t.Errorf("Expected compensation failure to be recorded, got %+v", exec)
+	}
+}
+
+func TestSagaCoordinator_Completes(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	createSagaTable(t, db)
+
+	ran := 0
+	step := func(ctx context.Context, tx *Tx) error { ran++; return nil }
+	saga := NewSagaCoordinator("complete", SagaStep{Name: "a", Execute: step}, SagaStep{Name: "b", Execute: step})
+	if err := saga.Run(ctx, db); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if ran != 2 {
+		t.Errorf("Expected both steps to run, got %d", ran)
+	}
+}
+
+func TestSagaCoordinator_CompensatesAfterCancel(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	compensated := false
+	saga := NewSagaCoordinator("cancelled",
+		SagaStep{
+			Name:    "reserve",
+			Execute: func(ctx context.Context, tx *Tx) error { return nil },
+			Compensate: func(ctx context.Context, tx *Tx) error {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				compensated = true
+				return nil
+			},
+		},
+		SagaStep{
+			Name: "charge",
+			Execute: func(ctx context.Context, tx *Tx) error {
+				cancel()
+				return ctx.Err()
+			},
+		},
+	)
+
+	var sagaErr *SagaError
+	if err := saga.Run(ctx, db); !errors.As(err, &sagaErr) {
+		t.Fatalf("Expected SagaError, got %v", err)
+	}
+	if !compensated || len(sagaErr.Compensations) != 0 {
+		t.Errorf("Expected the completed step to be compensated after the cancellation, got %+v", sagaErr.Compensations)
+	}
+}
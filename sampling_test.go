@@ -0,0 +1,85 @@
+package dbkit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+)
+
+func runSampledQueries(hook bun.QueryHook, n int, err error) {
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		event := &bun.QueryEvent{Query: "SELECT 1", Err: err}
+		qctx := hook.BeforeQuery(ctx, event)
+		hook.AfterQuery(qctx, event)
+	}
+}
+
+func TestSamplingHook_Rate(t *testing.T) {
+	for _, rate := range []float64{0, 0.1, 0.5, 1} {
+		inner := &queryCountHook{}
+		runSampledQueries(hooks.NewSamplingHook(rate, inner), 1000, nil)
+
+		observed := float64(inner.Count()) / 1000
+		if math.Abs(observed-rate) > 0.05 {
+			t.Errorf("rate %.1f: sampled %.3f of queries", rate, observed)
+		}
+	}
+}
+
+func TestSamplingHook_AlwaysSampleErrors(t *testing.T) {
+	inner := &queryCountHook{}
+	hook := hooks.NewSamplingHook(0, inner)
+	hook.AlwaysSampleErrors = true
+
+	runSampledQueries(hook, 10, nil)
+	runSampledQueries(hook, 10, errors.New("boom"))
+
+	if inner.Count() != 10 {
+		t.Errorf("Expected only the 10 failed queries to be sampled, got %d", inner.Count())
+	}
+}
+
+func TestSamplingHook_Stacked(t *testing.T) {
+	// The outer hook samples everything, the inner one nothing: the inner decision must win
+	inner := &queryCountHook{}
+	runSampledQueries(hooks.NewSamplingHook(1, hooks.NewSamplingHook(0, inner)), 100, nil)
+
+	if inner.Count() != 0 {
+		t.Errorf("Expected the inner hook to sample no queries, got %d", inner.Count())
+	}
+}
+
+func TestSamplingHook_WrapsLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cfg := DefaultConfig("fake").WithLogger(logger)
+	NewSamplingConfig(0.2)(&cfg)
+	if cfg.LogSampleRate != 0.2 {
+		t.Fatalf("Expected sample rate 0.2, got %v", cfg.LogSampleRate)
+	}
+
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		_, _ = db.ExecContext(ctx, "SELECT 1")
+	}
+
+	logged := float64(strings.Count(buf.String(), "\n")) / 1000
+	if math.Abs(logged-0.2) > 0.05 {
+		t.Errorf("Expected about 20%% of queries logged, got %.3f", logged)
+	}
+}
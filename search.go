@@ -0,0 +1,116 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// DefaultTSConfig is the text search configuration used when none is given.
+const DefaultTSConfig = "simple"
+
+// tsConfigAlias is the alias of the relation joined by WithTSConfig.
+const tsConfigAlias = "dbkit_tsconfig"
+
+// tsConfig returns the text search configuration expression for lang.
+// An empty lang refers to the configuration joined by WithTSConfig.
+func tsConfig(lang string) schema.QueryAppender {
+	if lang == "" {
+		return bun.Safe(tsConfigAlias + ".cfg")
+	}
+	return bun.SafeQuery("?::regconfig", lang)
+}
+
+// FullTextSearch finds records whose searchColumn matches the plain-text query.
+// If lang is empty, the configuration must be provided with WithTSConfig in queryFn.
+// For index usage, create a GIN index on to_tsvector(lang, searchColumn).
+//
+// Usage:
+//
+//	posts, err := dbkit.FullTextSearch[Post](ctx, db, nil, "body", "postgres tips", "english")
+func FullTextSearch[T any](ctx context.Context, db IDB, queryFn func(*bun.SelectQuery) *bun.SelectQuery, searchColumn, query string, lang string) ([]T, error) {
+	var items []T
+
+	cfg := tsConfig(lang)
+	q := db.NewSelect().Model(&items).
+		Where("to_tsvector(?, ?) @@ plainto_tsquery(?, ?)", cfg, bun.Ident(searchColumn), cfg, query)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, wrapError(err, "FullTextSearch")
+	}
+
+	return items, nil
+}
+
+// FullTextSearchRanked is like FullTextSearch but orders results by ts_rank, most relevant first.
+//
+// Usage:
+//
+//	posts, err := dbkit.FullTextSearchRanked[Post](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+//	    return q.Limit(10)
+//	}, "body", "postgres tips", "english")
+func FullTextSearchRanked[T any](ctx context.Context, db IDB, queryFn func(*bun.SelectQuery) *bun.SelectQuery, searchColumn, query string, lang string) ([]T, error) {
+	var items []T
+
+	cfg := tsConfig(lang)
+	col := bun.Ident(searchColumn)
+	q := db.NewSelect().Model(&items).
+		Where("to_tsvector(?, ?) @@ plainto_tsquery(?, ?)", cfg, col, cfg, query).
+		OrderExpr("ts_rank(to_tsvector(?, ?), plainto_tsquery(?, ?)) DESC", cfg, col, cfg, query)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, wrapError(err, "FullTextSearchRanked")
+	}
+
+	return items, nil
+}
+
+// WithTSConfig returns a query modifier that sets the text search configuration
+// used by FullTextSearch and FullTextSearchRanked when their lang argument is empty.
+//
+// Usage:
+//
+//	english := dbkit.WithTSConfig("english")
+//	posts, err := dbkit.FullTextSearch[Post](ctx, db, english, "body", "postgres", "")
+func WithTSConfig(lang string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Join("CROSS JOIN (SELECT ?::regconfig AS cfg) AS "+tsConfigAlias, lang)
+	}
+}
+
+// BuildTSVectorTriggerSQL returns DDL that adds a "<column>_tsv" tsvector column to tableName,
+// indexes it with GIN and keeps it up to date from column with a trigger.
+//
+// Usage:
+//
+//	migration := dbkit.Migration{ID: "005", Description: "Search posts", SQL: dbkit.BuildTSVectorTriggerSQL("posts", "body", "english")}
+func BuildTSVectorTriggerSQL(tableName, column, lang string) string {
+	if lang == "" {
+		lang = DefaultTSConfig
+	}
+	// tsvector_update_trigger requires a schema-qualified configuration name.
+	if !strings.Contains(lang, ".") {
+		lang = "pg_catalog." + lang
+	}
+
+	tsvColumn := column + "_tsv"
+	trigger := tableName + "_" + tsvColumn + "_trigger"
+	index := "idx_" + tableName + "_" + tsvColumn
+
+	return fmt.Sprintf(`ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS %[2]s tsvector;
+UPDATE %[1]s SET %[2]s = to_tsvector('%[3]s', coalesce(%[4]s, ''));
+CREATE INDEX IF NOT EXISTS %[5]s ON %[1]s USING GIN (%[2]s);
+DROP TRIGGER IF EXISTS %[6]s ON %[1]s;
+CREATE TRIGGER %[6]s BEFORE INSERT OR UPDATE ON %[1]s
+FOR EACH ROW EXECUTE FUNCTION tsvector_update_trigger(%[2]s, '%[3]s', %[4]s);`,
+		tableName, tsvColumn, lang, column, index, trigger)
+}
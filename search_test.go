@@ -0,0 +1,133 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type TestArticle struct {
+	bun.BaseModel `bun:"table:test_articles,alias:ta"`
+	ID            int64  `bun:"id,pk,autoincrement"`
+	Title         string `bun:"title,notnull"`
+	Body          string `bun:"body,notnull"`
+}
+
+func createArticlesTable(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewDropTable().Model((*TestArticle)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop articles table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestArticle)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create articles table: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "CREATE INDEX idx_test_articles_body ON test_articles USING GIN (to_tsvector('english', body))"); err != nil {
+		t.Fatalf("Failed to create GIN index: %v", err)
+	}
+
+	articles := []TestArticle{
+		{Title: "Cooking", Body: "A recipe for pasta with tomato sauce"},
+		{Title: "Postgres", Body: "PostgreSQL full text search: search documents with search vectors"},
+		{Title: "Mixed", Body: "A short note about search engines"},
+	}
+	if _, err := db.NewInsert().Model(&articles).Exec(ctx); err != nil {
+		t.Fatalf("Failed to insert articles: %v", err)
+	}
+	return ctx
+}
+
+func TestBuildTSVectorTriggerSQL(t *testing.T) {
+	sql := BuildTSVectorTriggerSQL("posts", "body", "english")
+
+	for _, want := range []string{
+		"ADD COLUMN IF NOT EXISTS body_tsv tsvector",
+		"USING GIN (body_tsv)",
+		"tsvector_update_trigger(body_tsv, 'pg_catalog.english', body)",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("Expected SQL to contain %q, got:\n%s", want, sql)
+		}
+	}
+
+	if !strings.Contains(BuildTSVectorTriggerSQL("posts", "body", ""), "'pg_catalog.simple'") {
+		t.Error("Expected empty lang to default to simple")
+	}
+}
+
+func TestFullTextSearch(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createArticlesTable(t, db)
+
+	results, err := FullTextSearch[TestArticle](ctx, db, nil, "body", "pasta", "english")
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Cooking" {
+		t.Errorf("Expected Cooking article, got %+v", results)
+	}
+}
+
+func TestFullTextSearchRanked(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createArticlesTable(t, db)
+
+	results, err := FullTextSearchRanked[TestArticle](ctx, db, nil, "body", "search", "english")
+	if err != nil {
+		t.Fatalf("FullTextSearchRanked failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Title != "Postgres" {
+		t.Errorf("Expected most relevant article first, got %s", results[0].Title)
+	}
+}
+
+func TestFullTextSearch_WithTSConfig(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createArticlesTable(t, db)
+
+	results, err := FullTextSearch[TestArticle](ctx, db, WithTSConfig("english"), "body", "searching", "")
+	if err != nil {
+		t.Fatalf("FullTextSearch failed: %v", err)
+	}
+	// "searching" is stemmed to "search" by the english configuration.
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+}
+
+func TestBuildTSVectorTriggerSQL_Exec(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createArticlesTable(t, db)
+
+	if _, err := db.ExecContext(ctx, BuildTSVectorTriggerSQL("test_articles", "body", "english")); err != nil {
+		t.Fatalf("Failed to execute trigger DDL: %v", err)
+	}
+
+	article := &TestArticle{Title: "New", Body: "Triggers maintain vectors"}
+	if _, err := db.NewInsert().Model(article).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	var count int
+	err := db.NewRaw("SELECT count(*) FROM test_articles WHERE body_tsv @@ plainto_tsquery('english', 'trigger')").Scan(ctx, &count)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected trigger to populate tsvector column, got %d matches", count)
+	}
+}
@@ -0,0 +1,134 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// sequenceNamePattern matches an unquoted, optionally schema-qualified identifier
+var sequenceNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// SequenceOptions configures CreateSequence. Nil values use PostgreSQL's defaults.
+type SequenceOptions struct {
+	Start     *int64
+	Increment *int64
+	MinValue  *int64
+	MaxValue  *int64
+	Cycle     bool // Wrap around at MaxValue instead of failing
+}
+
+// sequenceIdent validates name, which PostgreSQL cannot take as a bind parameter in DDL.
+func sequenceIdent(name, op string) (bun.Safe, error) {
+	if !sequenceNamePattern.MatchString(name) {
+		return "", &Error{
+			Code:    CodeUnknown,
+			Message: fmt.Sprintf("invalid sequence name %q", name),
+			Op:      op,
+		}
+	}
+	return bun.Safe(name), nil
+}
+
+// NextVal advances the sequence and returns its new value.
+//
+// Usage:
+//
+//	invoiceNo, err := dbkit.NextVal(ctx, db, "invoice_numbers")
+func NextVal(ctx context.Context, db IDB, sequenceName string) (int64, error) {
+	var value int64
+	if err := db.NewRaw("SELECT nextval(?)", sequenceName).Scan(ctx, &value); err != nil {
+		return 0, wrapError(err, "NextVal")
+	}
+	return value, nil
+}
+
+// CurrVal returns the value last returned by NextVal for the sequence in this session.
+// It fails if NextVal was not called on the same connection, so use it within a transaction.
+//
+// Usage:
+//
+//	id, err := dbkit.CurrVal(ctx, tx, "invoice_numbers")
+func CurrVal(ctx context.Context, db IDB, sequenceName string) (int64, error) {
+	var value int64
+	if err := db.NewRaw("SELECT currval(?)", sequenceName).Scan(ctx, &value); err != nil {
+		return 0, wrapError(err, "CurrVal")
+	}
+	return value, nil
+}
+
+// SetVal sets the sequence's current value. With isCalled the next NextVal returns
+// value + increment, otherwise it returns value itself.
+//
+// Usage:
+//
+//	err := dbkit.SetVal(ctx, db, "invoice_numbers", 1000, false) // next is 1000
+func SetVal(ctx context.Context, db IDB, sequenceName string, value int64, isCalled bool) error {
+	if _, err := db.NewRaw("SELECT setval(?, ?, ?)", sequenceName, value, isCalled).Exec(ctx); err != nil {
+		return wrapError(err, "SetVal")
+	}
+	return nil
+}
+
+// CreateSequence creates a sequence if it does not exist.
+//
+// Usage:
+//
+//	start := int64(1000)
+//	err := dbkit.CreateSequence(ctx, db, "invoice_numbers", dbkit.SequenceOptions{Start: &start})
+func CreateSequence(ctx context.Context, db IDB, name string, opts SequenceOptions) error {
+	ident, err := sequenceIdent(name, "CreateSequence")
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("CREATE SEQUENCE IF NOT EXISTS ?")
+	args := []any{ident}
+	for _, opt := range []struct {
+		clause string
+		value  *int64
+	}{
+		{" INCREMENT BY ?", opts.Increment},
+		{" MINVALUE ?", opts.MinValue},
+		{" MAXVALUE ?", opts.MaxValue},
+		{" START WITH ?", opts.Start},
+	} {
+		if opt.value != nil {
+			b.WriteString(opt.clause)
+			args = append(args, *opt.value)
+		}
+	}
+	if opts.Cycle {
+		b.WriteString(" CYCLE")
+	}
+
+	if _, err := db.NewRaw(b.String(), args...).Exec(ctx); err != nil {
+		return wrapError(err, "CreateSequence")
+	}
+	return nil
+}
+
+// DropSequence drops a sequence. With ifExists a missing sequence is not an error.
+//
+// Usage:
+//
+//	err := dbkit.DropSequence(ctx, db, "invoice_numbers", true)
+func DropSequence(ctx context.Context, db IDB, name string, ifExists bool) error {
+	ident, err := sequenceIdent(name, "DropSequence")
+	if err != nil {
+		return err
+	}
+
+	query := "DROP SEQUENCE ?"
+	if ifExists {
+		query = "DROP SEQUENCE IF EXISTS ?"
+	}
+	if _, err := db.NewRaw(query, ident).Exec(ctx); err != nil {
+		return wrapError(err, "DropSequence")
+	}
+	return nil
+}
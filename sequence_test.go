@@ -0,0 +1,116 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSequenceIdent(t *testing.T) {
+	for _, name := range []string{"invoice_numbers", "billing.invoice_seq", "_s$1"} {
+		if _, err := sequenceIdent(name, "test"); err != nil {
+			t.Errorf("Expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "1seq", "seq; DROP TABLE users", `"quoted"`, "a.b.c", "seq-name"} {
+		if _, err := sequenceIdent(name, "test"); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestCreateSequence_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+	ctx := context.Background()
+
+	start, increment, maxValue := int64(10), int64(5), int64(100)
+	opts := SequenceOptions{Start: &start, Increment: &increment, MaxValue: &maxValue, Cycle: true}
+	if err := CreateSequence(ctx, db, "test_seq", opts); err != nil {
+		t.Fatalf("CreateSequence failed: %v", err)
+	}
+	want := "CREATE SEQUENCE IF NOT EXISTS test_seq INCREMENT BY 5 MAXVALUE 100 START WITH 10 CYCLE"
+	if hook.last != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", hook.last, want)
+	}
+
+	// Zero is a value, not a default
+	zero := int64(0)
+	if err := CreateSequence(ctx, db, "test_seq", SequenceOptions{MinValue: &zero, Start: &zero}); err != nil {
+		t.Fatalf("CreateSequence failed: %v", err)
+	}
+	if want := "CREATE SEQUENCE IF NOT EXISTS test_seq MINVALUE 0 START WITH 0"; hook.last != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", hook.last, want)
+	}
+
+	if err := CreateSequence(ctx, db, "bad name", opts); err == nil || !strings.Contains(err.Error(), "invalid sequence name") {
+		t.Errorf("Expected invalid name error, got %v", err)
+	}
+	if err := DropSequence(ctx, db, "x;--", true); err == nil {
+		t.Error("Expected invalid name error for DropSequence")
+	}
+}
+
+func TestSequences(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := DropSequence(ctx, db, "test_seq", true); err != nil {
+		t.Fatalf("DropSequence failed: %v", err)
+	}
+	start, increment := int64(100), int64(1)
+	if err := CreateSequence(ctx, db, "test_seq", SequenceOptions{Start: &start, Increment: &increment}); err != nil {
+		t.Fatalf("CreateSequence failed: %v", err)
+	}
+	defer DropSequence(ctx, db, "test_seq", true)
+
+	for want := int64(100); want < 103; want++ {
+		got, err := NextVal(ctx, db, "test_seq")
+		if err != nil {
+			t.Fatalf("NextVal failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected %d, got %d", want, got)
+		}
+	}
+
+	if err := SetVal(ctx, db, "test_seq", 500, true); err != nil {
+		t.Fatalf("SetVal failed: %v", err)
+	}
+	if got, _ := NextVal(ctx, db, "test_seq"); got != 501 {
+		t.Errorf("Expected 501 after SetVal(500, true), got %d", got)
+	}
+	if err := SetVal(ctx, db, "test_seq", 900, false); err != nil {
+		t.Fatalf("SetVal failed: %v", err)
+	}
+	if got, _ := NextVal(ctx, db, "test_seq"); got != 900 {
+		t.Errorf("Expected 900 after SetVal(900, false), got %d", got)
+	}
+
+	err := db.Transaction(ctx, func(tx *Tx) error {
+		next, err := NextVal(ctx, tx, "test_seq")
+		if err != nil {
+			return err
+		}
+		curr, err := CurrVal(ctx, tx, "test_seq")
+		if err != nil {
+			return err
+		}
+		if curr != next {
+			t.Errorf("Expected CurrVal %d, got %d", next, curr)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Transaction failed: %v", err)
+	}
+
+	if err := DropSequence(ctx, db, "test_seq", false); err != nil {
+		t.Errorf("DropSequence failed: %v", err)
+	}
+	if err := DropSequence(ctx, db, "test_seq", false); err == nil {
+		t.Error("Expected an error dropping a missing sequence without ifExists")
+	}
+}
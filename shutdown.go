@@ -0,0 +1,49 @@
+package dbkit
+
+import (
+	"context"
+)
+
+// Shutdown waits for in-flight queries to finish and then closes the connection pool.
+// The wait is bounded by ctx and Config.DrainTimeout, whichever ends first.
+// If draining takes too long, context.DeadlineExceeded is returned and the pool is left
+// open; call Close to force it. Callers should stop issuing new queries before calling Shutdown.
+//
+// Usage:
+//
+//	<-sigterm
+//	if err := db.Shutdown(context.Background()); err != nil {
+//	    log.Printf("database drain incomplete: %v", err)
+//	}
+func (db *DBKit) Shutdown(ctx context.Context) error {
+	if db.config.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, db.config.DrainTimeout)
+		defer cancel()
+	}
+
+	if err := db.tracker.Wait(ctx); err != nil {
+		return err
+	}
+
+	var err error
+	db.shutdownOnce.Do(func() {
+		db.stopRDS()
+		err = db.DB.Close()
+		close(db.shutdownCh)
+	})
+	if err != nil {
+		return wrapError(err, "Shutdown")
+	}
+	return nil
+}
+
+// ShutdownChan returns a channel that is closed once Shutdown has closed the pool.
+func (db *DBKit) ShutdownChan() <-chan struct{} {
+	return db.shutdownCh
+}
+
+// InFlightQueries returns the number of queries currently executing.
+func (db *DBKit) InFlightQueries() int64 {
+	return db.tracker.Active()
+}
@@ -0,0 +1,123 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/fernandezvara/dbkit/hooks"
+)
+
+func TestShutdown_WaitsForInFlightQueries(t *testing.T) {
+	db, _ := newFakeDBWithConnector(t, &fakeConnector{queryDelay: 100 * time.Millisecond})
+	ctx := context.Background()
+
+	queryDone := make(chan time.Time, 1)
+	go func() {
+		_, _ = db.NewSelect().ColumnExpr("1").Exec(ctx)
+		queryDone <- time.Now()
+	}()
+
+	// Wait until the query is in flight
+	for db.InFlightQueries() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := db.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	shutdownAt := time.Now()
+
+	finishedAt := <-queryDone
+	if shutdownAt.Before(finishedAt) {
+		t.Error("Shutdown returned before the in-flight query finished")
+	}
+
+	select {
+	case <-db.ShutdownChan():
+	default:
+		t.Error("Expected ShutdownChan to be closed")
+	}
+
+	if err := db.Shutdown(ctx); err != nil {
+		t.Errorf("Second Shutdown should be a no-op, got %v", err)
+	}
+}
+
+func TestShutdown_DrainTimeout(t *testing.T) {
+	db, _ := newFakeDBWithConnector(t, &fakeConnector{queryDelay: 200 * time.Millisecond})
+	db.config.DrainTimeout = 20 * time.Millisecond
+	ctx := context.Background()
+
+	go func() {
+		_, _ = db.NewSelect().ColumnExpr("1").Exec(ctx)
+	}()
+	for db.InFlightQueries() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	err := db.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-db.ShutdownChan():
+		t.Error("ShutdownChan should not be closed after a failed drain")
+	default:
+	}
+}
+
+func TestTrackingHook_QueriesDuringWait(t *testing.T) {
+	hook := hooks.NewTrackingHook()
+	ctx := context.Background()
+	event := &bun.QueryEvent{}
+
+	// Queries starting and ending around concurrent waits must neither panic nor block them
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			hook.AfterQuery(hook.BeforeQuery(ctx, event), event)
+		}()
+		go func() {
+			defer wg.Done()
+			waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			if err := hook.Wait(waitCtx); err != nil {
+				t.Errorf("Wait failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hook.Active() != 0 {
+		t.Errorf("Expected no active queries, got %d", hook.Active())
+	}
+}
+
+func TestShutdown_LongQuery(t *testing.T) {
+	db := getTestDB(t)
+	ctx := context.Background()
+
+	queryErr := make(chan error, 1)
+	go func() {
+		_, err := db.ExecContext(ctx, "SELECT pg_sleep(0.5)")
+		queryErr <- err
+	}()
+	for db.InFlightQueries() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := db.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := <-queryErr; err != nil {
+		t.Errorf("In-flight query should complete successfully, got %v", err)
+	}
+}
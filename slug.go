@@ -0,0 +1,79 @@
+package dbkit
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/uptrace/bun"
+)
+
+// FindBySlug returns the record whose slug column equals slug.
+// Returns ErrNotFound if there is none.
+//
+// Usage:
+//
+//	post, err := dbkit.FindBySlug[Post](ctx, db, "hello-world")
+func FindBySlug[T any](ctx context.Context, db IDB, slug string) (*T, error) {
+	var model T
+	if err := db.NewSelect().Model(&model).Where("?TableAlias.slug = ?", slug).Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindBySlug")
+	}
+	return &model, nil
+}
+
+// NormalizeSlug turns s into a URL-safe slug: lowercase ASCII letters and digits separated by single
+// hyphens. Whitespace becomes a hyphen, other characters are removed.
+//
+// Usage:
+//
+//	dbkit.NormalizeSlug("  Hello, World! ") // "hello-world"
+func NormalizeSlug(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		case r == '-' || unicode.IsSpace(r):
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// GenerateUniqueSlug normalizes base and appends "-2", "-3", ... until no record of T has the slug.
+// Soft-deleted records keep their slug, since the unique index still covers them.
+// Concurrent callers can be handed the same slug, so keep a unique index on the slug column and
+// generate again when the insert fails with IsDuplicate.
+//
+// Usage:
+//
+//	post.Slug, err = dbkit.GenerateUniqueSlug[Post](ctx, db, post.Title)
+func GenerateUniqueSlug[T any](ctx context.Context, db IDB, base string) (string, error) {
+	slug := NormalizeSlug(base)
+	if slug == "" {
+		return "", &Error{Code: CodeValidation, Message: "slug base has no letters or digits", Op: "GenerateUniqueSlug"}
+	}
+
+	candidate := slug
+	for n := 2; ; n++ {
+		q := db.NewSelect().Model((*T)(nil)).Where("?TableAlias.? = ?", bun.Ident("slug"), candidate)
+		if tm, ok := q.GetModel().(bun.TableModel); ok && tm.Table().SoftDeleteField != nil {
+			q = q.WhereAllWithDeleted()
+		}
+		exists, err := q.Exists(ctx)
+		if err != nil {
+			return "", wrapError(err, "GenerateUniqueSlug")
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = slug + "-" + strconv.Itoa(n)
+	}
+}
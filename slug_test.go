@@ -0,0 +1,130 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+type slugPost struct {
+	bun.BaseModel `bun:"table:test_slug_posts,alias:tsp"`
+	ID            int64  `bun:"id,pk,autoincrement"`
+	Title         string `bun:"title,notnull"`
+	Slug          string `bun:"slug,notnull,unique"`
+}
+
+func TestNormalizeSlug(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":            "hello-world",
+		"  Hello,   World!  ":    "hello-world",
+		"Go 1.25 -- released":    "go-125-released",
+		"already-a-slug":         "already-a-slug",
+		"--Leading and trailing": "leading-and-trailing",
+		"Crème brûlée":           "crme-brle",
+		"tabs\tand\nnewlines":    "tabs-and-newlines",
+		"!!!":                    "",
+	}
+	for in, want := range tests {
+		if got := NormalizeSlug(in); got != want {
+			t.Errorf("NormalizeSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateUniqueSlug_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+	ctx := context.Background()
+
+	_, _ = GenerateUniqueSlug[slugPost](ctx, db, "Hello World")
+	want := `SELECT EXISTS (SELECT "tsp"."id", "tsp"."title", "tsp"."slug" FROM "test_slug_posts" AS "tsp" WHERE ("tsp"."slug" = 'hello-world'))`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+
+	if _, err := GenerateUniqueSlug[slugPost](ctx, db, "?!"); !IsValidation(err) {
+		t.Errorf("Expected a validation error for an empty slug, got %v", err)
+	}
+
+	// Slugs of soft-deleted records are still taken
+	_, _ = GenerateUniqueSlug[softSlugPost](ctx, db, "Hello World")
+	want = `SELECT EXISTS (SELECT "tssp"."id", "tssp"."slug", "tssp"."deleted_at" FROM "test_soft_slug_posts" AS "tssp" WHERE ("tssp"."slug" = 'hello-world'))`
+	if hook.last != want {
+		t.Errorf("Expected %s, got %s", want, hook.last)
+	}
+}
+
+type softSlugPost struct {
+	bun.BaseModel `bun:"table:test_soft_slug_posts,alias:tssp"`
+	ID            int64  `bun:"id,pk,autoincrement"`
+	Slug          string `bun:"slug,notnull,unique"`
+	SoftDeletableModel
+}
+
+func TestGenerateUniqueSlug(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*slugPost)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*slugPost)(nil)).IfExists().Exec(ctx)
+	_, _ = db.NewDelete().Model((*slugPost)(nil)).Where("1=1").Exec(ctx)
+
+	// Concurrent callers retry on the unique index, as GenerateUniqueSlug recommends
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				slug, err := GenerateUniqueSlug[slugPost](ctx, db, "Hello World")
+				if err != nil {
+					errs <- err
+					return
+				}
+				err = Create(ctx, db, &slugPost{Title: "Hello World", Slug: slug})
+				if IsDuplicate(err) {
+					continue
+				}
+				errs <- err
+				return
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	var slugs []string
+	if err := db.NewSelect().Model((*slugPost)(nil)).Column("slug").Order("slug").Scan(ctx, &slugs); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	want := []string{"hello-world", "hello-world-2", "hello-world-3", "hello-world-4", "hello-world-5"}
+	if len(slugs) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, slugs)
+	}
+	for i := range want {
+		if slugs[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, slugs)
+			break
+		}
+	}
+
+	post, err := FindBySlug[slugPost](ctx, db, "hello-world-3")
+	if err != nil || post.Slug != "hello-world-3" {
+		t.Errorf("Expected hello-world-3, got %+v, %v", post, err)
+	}
+	if _, err := FindBySlug[slugPost](ctx, db, "missing"); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -3,9 +3,13 @@ package dbkit
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
 )
 
 // SoftDelete marks a model as deleted by setting the DeletedAt field.
@@ -52,6 +56,7 @@ func Restore[T any](ctx context.Context, db bun.IDB, model *T) (sql.Result, erro
 		Set("deleted_at = NULL").
 		Set("updated_at = ?", now).
 		WherePK().
+		Apply(onlyDeletedUpdate).
 		Exec(ctx)
 }
 
@@ -68,6 +73,7 @@ func RestoreByID[T any](ctx context.Context, db bun.IDB, id string) (sql.Result,
 		Set("deleted_at = NULL").
 		Set("updated_at = ?", now).
 		Where("id = ?", id).
+		Apply(onlyDeletedUpdate).
 		Exec(ctx)
 }
 
@@ -99,6 +105,151 @@ func HardDeleteByID[T any](ctx context.Context, db bun.IDB, id string) (sql.Resu
 		Exec(ctx)
 }
 
+// maxIDsPerStatement keeps ID lists below PostgreSQL's limit of 32767 bind parameters.
+const maxIDsPerStatement = 32767
+
+// PartialDeleteError reports that fewer records were deleted than IDs were given,
+// because some IDs did not exist (or were listed twice). It matches ErrNotFound.
+type PartialDeleteError struct {
+	Deleted   int64 // Rows actually deleted
+	Requested int   // Number of IDs given
+}
+
+func (e *PartialDeleteError) Error() string {
+	return fmt.Sprintf("dbkit: deleted %d of %d records", e.Deleted, e.Requested)
+}
+
+func (e *PartialDeleteError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// DeleteByIDs permanently removes the records with the given IDs, also for soft-deletable models.
+// Returns the number of deleted rows and a *PartialDeleteError if some IDs were not found.
+//
+// Usage:
+//
+//	count, err := dbkit.DeleteByIDs[User](ctx, db, []any{id1, id2})
+//	var partial *dbkit.PartialDeleteError
+//	if errors.As(err, &partial) {
+//	    log.Printf("%d records were already gone", partial.Requested-int(partial.Deleted))
+//	}
+func DeleteByIDs[T any](ctx context.Context, db bun.IDB, ids []any) (int64, error) {
+	var totalRows int64
+
+	for i := 0; i < len(ids); i += maxIDsPerStatement {
+		end := i + maxIDsPerStatement
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var model T
+		result, err := db.NewDelete().
+			Model(&model).
+			Where("id IN (?)", bun.In(ids[i:end])).
+			ForceDelete().
+			Exec(ctx)
+		if err != nil {
+			return totalRows, wrapError(err, "DeleteByIDs")
+		}
+
+		rows, _ := result.RowsAffected()
+		totalRows += rows
+	}
+
+	if totalRows < int64(len(ids)) {
+		return totalRows, &PartialDeleteError{Deleted: totalRows, Requested: len(ids)}
+	}
+	return totalRows, nil
+}
+
+// SoftDeleteByIDs marks the records with the given IDs as deleted.
+// Returns the number of rows marked, already deleted records are not counted.
+//
+// Usage:
+//
+//	count, err := dbkit.SoftDeleteByIDs[User](ctx, db, []string{id1, id2})
+func SoftDeleteByIDs[T any](ctx context.Context, db bun.IDB, ids []string) (int64, error) {
+	var totalRows int64
+
+	for i := 0; i < len(ids); i += maxIDsPerStatement {
+		end := i + maxIDsPerStatement
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var model T
+		result, err := db.NewUpdate().
+			Model(&model).
+			Set("deleted_at = NOW()").
+			Set("updated_at = NOW()").
+			Where("id IN (?)", bun.In(ids[i:end])).
+			Exec(ctx)
+		if err != nil {
+			return totalRows, wrapError(err, "SoftDeleteByIDs")
+		}
+
+		rows, _ := result.RowsAffected()
+		totalRows += rows
+	}
+
+	return totalRows, nil
+}
+
+// RestoreManyByIDs removes the soft delete mark from the records with the given IDs.
+// Returns the number of rows restored, records that are not deleted are not counted.
+//
+// Usage:
+//
+//	count, err := dbkit.RestoreManyByIDs[User](ctx, db, []string{id1, id2})
+func RestoreManyByIDs[T any](ctx context.Context, db bun.IDB, ids []string) (int64, error) {
+	var totalRows int64
+
+	for i := 0; i < len(ids); i += maxIDsPerStatement {
+		end := i + maxIDsPerStatement
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		rows, err := RestoreAll[T](ctx, db, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.Where("id IN (?)", bun.In(ids[i:end]))
+		})
+		totalRows += rows
+		if err != nil {
+			return totalRows, err
+		}
+	}
+
+	return totalRows, nil
+}
+
+// RestoreAll removes the soft delete mark from the deleted records matching queryFn.
+// Returns the number of rows restored.
+//
+// Usage:
+//
+//	count, err := dbkit.RestoreAll[User](ctx, db, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+//	    return q.Where("deleted_at > ?", incidentStart)
+//	})
+func RestoreAll[T any](ctx context.Context, db bun.IDB, queryFn func(*bun.UpdateQuery) *bun.UpdateQuery) (int64, error) {
+	var model T
+	q := db.NewUpdate().
+		Model(&model).
+		Set("deleted_at = NULL").
+		Set("updated_at = NOW()")
+	q = onlyDeletedUpdate(q)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "RestoreAll")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
 // NotDeleted returns a query modifier that filters out soft-deleted records.
 // Use this with Bun's query builder to exclude deleted records.
 //
@@ -118,6 +269,18 @@ func NotDeleted(q *bun.SelectQuery) *bun.SelectQuery {
 //	var deletedUsers []User
 //	db.NewSelect().Model(&deletedUsers).Apply(dbkit.OnlyDeleted).Scan(ctx)
 func OnlyDeleted(q *bun.SelectQuery) *bun.SelectQuery {
+	// Models tagged soft_delete are filtered with "deleted_at IS NULL" unless told otherwise
+	if tm, ok := q.GetModel().(bun.TableModel); ok && tm.Table().SoftDeleteField != nil {
+		return q.WhereDeleted()
+	}
+	return q.Where("deleted_at IS NOT NULL")
+}
+
+// onlyDeletedUpdate limits an update to soft-deleted records, like OnlyDeleted does for selects
+func onlyDeletedUpdate(q *bun.UpdateQuery) *bun.UpdateQuery {
+	if tm, ok := q.GetModel().(bun.TableModel); ok && tm.Table().SoftDeleteField != nil {
+		return q.WhereDeleted()
+	}
 	return q.Where("deleted_at IS NOT NULL")
 }
 
@@ -132,3 +295,200 @@ func OnlyDeleted(q *bun.SelectQuery) *bun.SelectQuery {
 func WithDeleted(q *bun.SelectQuery) *bun.SelectQuery {
 	return q.WhereAllWithDeleted()
 }
+
+// DeletedBefore returns a query modifier matching records deleted before t.
+// Combine it with OnlyDeleted on models tagged soft_delete, which Bun otherwise limits to live records.
+//
+// Usage:
+//
+//	var users []User
+//	db.NewSelect().Model(&users).Apply(dbkit.OnlyDeleted, dbkit.DeletedBefore(cutoff)).Scan(ctx)
+func DeletedBefore(t time.Time) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("deleted_at < ?", t)
+	}
+}
+
+// DeletedAfter returns a query modifier matching records deleted at or after t.
+//
+// Usage:
+//
+//	db.NewSelect().Model(&users).Apply(dbkit.OnlyDeleted, dbkit.DeletedAfter(since)).Scan(ctx)
+func DeletedAfter(t time.Time) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("deleted_at >= ?", t)
+	}
+}
+
+// DeletedBetween returns a query modifier matching records deleted within [from, to).
+//
+// Usage:
+//
+//	db.NewSelect().Model(&users).Apply(dbkit.OnlyDeleted, dbkit.DeletedBetween(monthStart, monthEnd)).Scan(ctx)
+func DeletedBetween(from, to time.Time) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("deleted_at >= ?", from).Where("deleted_at < ?", to)
+	}
+}
+
+// DeletedBeforeUpdateScope is DeletedBefore for update queries, e.g. with RestoreAll.
+//
+// Usage:
+//
+//	count, err := dbkit.RestoreAll[User](ctx, db, dbkit.DeletedBeforeUpdateScope(cutoff))
+func DeletedBeforeUpdateScope(t time.Time) func(*bun.UpdateQuery) *bun.UpdateQuery {
+	return func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("deleted_at < ?", t)
+	}
+}
+
+// DeletedAfterUpdateScope is DeletedAfter for update queries, e.g. with RestoreAll.
+//
+// Usage:
+//
+//	count, err := dbkit.RestoreAll[User](ctx, db, dbkit.DeletedAfterUpdateScope(incidentStart))
+func DeletedAfterUpdateScope(t time.Time) func(*bun.UpdateQuery) *bun.UpdateQuery {
+	return func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("deleted_at >= ?", t)
+	}
+}
+
+// CascadeChild is a child table soft-deleted along with its parent
+type CascadeChild struct {
+	Model    any            // Child model, e.g. (*Order)(nil)
+	FKColumn string         // Column referencing the parent primary key, e.g. "user_id"
+	Children []CascadeChild // Grandchildren referencing this child
+}
+
+// CascadeConfig lists the children soft-deleted by SoftDeleteWithCascade
+type CascadeConfig struct {
+	Children []CascadeChild
+}
+
+// SoftDeleteCascade soft-deletes the parent and the children whose parentFKColumn references it,
+// in one transaction. Returns the number of children marked; already deleted children are kept as is.
+//
+// Usage:
+//
+//	count, err := dbkit.SoftDeleteCascade[User, Order](ctx, db, &user, "user_id")
+func SoftDeleteCascade[Parent any, Child any](ctx context.Context, db bun.IDB, parentModel *Parent, parentFKColumn string) (int64, error) {
+	return softDeleteCascade(ctx, db, parentModel, []CascadeChild{{Model: (*Child)(nil), FKColumn: parentFKColumn}})
+}
+
+// SoftDeleteWithCascade soft-deletes model and, level by level, the children listed in cascade,
+// in one transaction. Only children deleted by this call are cascaded to their own children.
+//
+// Usage:
+//
+//	err := dbkit.SoftDeleteWithCascade(ctx, db, &user, dbkit.CascadeConfig{
+//	    Children: []dbkit.CascadeChild{{
+//	        Model:    (*Order)(nil),
+//	        FKColumn: "user_id",
+//	        Children: []dbkit.CascadeChild{{Model: (*OrderItem)(nil), FKColumn: "order_id"}},
+//	    }},
+//	})
+func SoftDeleteWithCascade[T any](ctx context.Context, db bun.IDB, model *T, cascade CascadeConfig) error {
+	_, err := softDeleteCascade(ctx, db, model, cascade.Children)
+	return err
+}
+
+// softDeleteCascade marks the parent and its children, cascading from the primary keys
+// each level actually updated
+func softDeleteCascade[T any](ctx context.Context, db bun.IDB, model *T, children []CascadeChild) (int64, error) {
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	if len(table.PKs) != 1 {
+		return 0, &Error{
+			Code:    CodeValidation,
+			Message: "cascade requires a single-column primary key",
+			Op:      "SoftDeleteCascade",
+			Table:   table.Name,
+		}
+	}
+	parentID := table.PKs[0].Value(reflect.ValueOf(model).Elem()).Interface()
+
+	var total int64
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewUpdate().
+			Model(model).
+			Apply(softDeleteSet(table)).
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return wrapError(err, "SoftDeleteCascade")
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return &Error{
+				Code:    CodeNotFound,
+				Message: "record not found or already deleted",
+				Op:      "SoftDeleteCascade",
+				Table:   table.Name,
+			}
+		}
+
+		total, err = softDeleteChildren(ctx, tx, []any{parentID}, children)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// softDeleteChildren marks the children referencing parentIDs, a slice of primary keys
+func softDeleteChildren(ctx context.Context, tx bun.Tx, parentIDs any, children []CascadeChild) (int64, error) {
+	var total int64
+	for _, child := range children {
+		table := tx.Dialect().Tables().Get(reflect.TypeOf(child.Model))
+
+		q := tx.NewUpdate().
+			Model(child.Model).
+			Apply(softDeleteSet(table)).
+			Where("? IN (?)", bun.Ident(child.FKColumn), bun.In(parentIDs)).
+			Where("deleted_at IS NULL")
+		if len(child.Children) == 0 {
+			result, err := q.Exec(ctx)
+			if err != nil {
+				return total, wrapError(err, "SoftDeleteCascade")
+			}
+			rows, _ := result.RowsAffected()
+			total += rows
+			continue
+		}
+		if len(table.PKs) != 1 {
+			return total, &Error{
+				Code:    CodeValidation,
+				Message: "cascade requires a single-column primary key",
+				Op:      "SoftDeleteCascade",
+				Table:   table.Name,
+			}
+		}
+
+		// Only the children deleted here cascade, not those deleted earlier in the transaction
+		var deleted []string
+		if err := q.Returning("?::text", table.PKs[0].SQLName).Scan(ctx, &deleted); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return total, wrapError(err, "SoftDeleteCascade")
+		}
+		total += int64(len(deleted))
+		if len(deleted) == 0 {
+			continue
+		}
+
+		rows, err := softDeleteChildren(ctx, tx, deleted, child.Children)
+		total += rows
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// softDeleteSet sets deleted_at, and updated_at when the table has it, to the transaction timestamp
+func softDeleteSet(table *schema.Table) func(*bun.UpdateQuery) *bun.UpdateQuery {
+	return func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		q = q.Set("deleted_at = NOW()")
+		if _, ok := table.FieldMap["updated_at"]; ok {
+			q = q.Set("updated_at = NOW()")
+		}
+		return q
+	}
+}
@@ -1,11 +1,45 @@
 package dbkit
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/uptrace/bun"
 )
 
+type TestSoftModel struct {
+	bun.BaseModel `bun:"table:test_soft_models,alias:tsm"`
+	ID            string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Name          string    `bun:"name,notnull"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	SoftDeletableModel
+}
+
+func createSoftModels(t *testing.T, db *DBKit, n int) (context.Context, []TestSoftModel) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewDropTable().Model((*TestSoftModel)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop soft models table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestSoftModel)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create soft models table: %v", err)
+	}
+
+	models := make([]TestSoftModel, n)
+	for i := range models {
+		models[i].Name = fmt.Sprintf("Soft %d", i)
+	}
+	if _, err := db.NewInsert().Model(&models).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	return ctx, models
+}
+
 func TestNotDeleted(t *testing.T) {
 	// Test that NotDeleted adds the correct WHERE clause
 	// This is a basic unit test - integration tests would verify actual filtering
@@ -33,3 +67,377 @@ func TestWithDeleted(t *testing.T) {
 		t.Error("WithDeleted should return a non-nil query")
 	}
 }
+
+func TestPartialDeleteError(t *testing.T) {
+	var err error = &PartialDeleteError{Deleted: 2, Requested: 3}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("PartialDeleteError should match ErrNotFound")
+	}
+	if err.Error() != "dbkit: deleted 2 of 3 records" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestDeleteByIDs_Empty(t *testing.T) {
+	count, err := DeleteByIDs[TestModel](context.Background(), nil, nil)
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0, nil for no IDs, got %d, %v", count, err)
+	}
+	count, err = SoftDeleteByIDs[TestSoftModel](context.Background(), nil, nil)
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0, nil for no IDs, got %d, %v", count, err)
+	}
+}
+
+func TestDeleteByIDs_Partial(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	models := insertTestModels(t, db, 3)
+
+	ids := []any{models[0].ID, models[1].ID, "00000000-0000-0000-0000-000000000000"}
+	count, err := DeleteByIDs[TestModel](ctx, db, ids)
+	var partial *PartialDeleteError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected PartialDeleteError, got %v", err)
+	}
+	if count != 2 || partial.Deleted != 2 || partial.Requested != 3 {
+		t.Errorf("Expected 2 of 3 deleted, got count %d, %+v", count, partial)
+	}
+
+	remaining, _ := Count[TestModel](ctx, db, nil)
+	if remaining != 1 {
+		t.Errorf("Expected 1 remaining record, got %d", remaining)
+	}
+
+	if _, err := DeleteByIDs[TestModel](ctx, db, []any{models[2].ID}); err != nil {
+		t.Errorf("Expected no error when every ID exists, got %v", err)
+	}
+}
+
+func TestDeleteByIDs_ForcesSoftDeletable(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx, models := createSoftModels(t, db, 3)
+
+	count, err := DeleteByIDs[TestSoftModel](ctx, db, []any{models[0].ID, models[1].ID})
+	if err != nil || count != 2 {
+		t.Fatalf("Expected 2 deleted, got %d, %v", count, err)
+	}
+
+	total, err := db.NewSelect().Model((*TestSoftModel)(nil)).WhereAllWithDeleted().Count(ctx)
+	if err != nil || total != 1 {
+		t.Errorf("Expected rows to be physically deleted, %d remain (%v)", total, err)
+	}
+}
+
+func TestSoftDeleteByIDs(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx, models := createSoftModels(t, db, 4)
+
+	ids := []string{models[0].ID, models[1].ID, models[2].ID}
+	count, err := SoftDeleteByIDs[TestSoftModel](ctx, db, ids)
+	if err != nil || count != 3 {
+		t.Fatalf("Expected 3 soft deleted, got %d, %v", count, err)
+	}
+
+	visible, _ := db.NewSelect().Model((*TestSoftModel)(nil)).Count(ctx)
+	all, _ := db.NewSelect().Model((*TestSoftModel)(nil)).WhereAllWithDeleted().Count(ctx)
+	if visible != 1 || all != 4 {
+		t.Errorf("Expected 1 visible of 4, got %d of %d", visible, all)
+	}
+
+	// Already deleted records are not counted again
+	count, err = SoftDeleteByIDs[TestSoftModel](ctx, db, ids)
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0 on second soft delete, got %d, %v", count, err)
+	}
+}
+
+func TestOnlyDeleted_SoftDeleteModel(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	sql := db.NewSelect().Model((*TestSoftModel)(nil)).Apply(OnlyDeleted).String()
+	if !strings.Contains(sql, `"tsm"."deleted_at" IS NOT NULL`) || strings.Contains(sql, `"deleted_at" IS NULL`) {
+		t.Errorf("Expected only deleted records filter, got %s", sql)
+	}
+}
+
+// deletedAtModel has a deleted_at column without Bun's soft_delete tag
+type deletedAtModel struct {
+	bun.BaseModel `bun:"table:test_deleted_at_models,alias:tdm"`
+	ID            string     `bun:"id,pk"`
+	DeletedAt     *time.Time `bun:"deleted_at"`
+}
+
+func TestRestoreAll_OnlyDeleted_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+	ctx := context.Background()
+
+	_, _ = RestoreAll[TestSoftModel](ctx, db, nil)
+	if !strings.Contains(hook.last, `"tsm"."deleted_at" IS NOT NULL`) {
+		t.Errorf("Expected only deleted records filter, got %s", hook.last)
+	}
+
+	hook.last = ""
+	_, _ = RestoreAll[deletedAtModel](ctx, db, nil)
+	if !strings.Contains(hook.last, `WHERE (deleted_at IS NOT NULL)`) {
+		t.Errorf("Expected only deleted records filter without soft_delete tag, got %s", hook.last)
+	}
+}
+
+func TestRestoreManyByIDs(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx, models := createSoftModels(t, db, 5)
+
+	ids := make([]string, len(models))
+	for i, m := range models {
+		ids[i] = m.ID
+	}
+	if _, err := SoftDeleteByIDs[TestSoftModel](ctx, db, ids); err != nil {
+		t.Fatalf("SoftDeleteByIDs failed: %v", err)
+	}
+
+	count, err := RestoreManyByIDs[TestSoftModel](ctx, db, ids[:3])
+	if err != nil || count != 3 {
+		t.Fatalf("Expected 3 restored, got %d, %v", count, err)
+	}
+
+	deleted, err := Count[TestSoftModel](ctx, db, OnlyDeleted)
+	if err != nil || deleted != 2 {
+		t.Errorf("Expected 2 soft deleted records, got %d, %v", deleted, err)
+	}
+
+	// Records that are not deleted are not counted
+	count, err = RestoreManyByIDs[TestSoftModel](ctx, db, ids[:3])
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0 on second restore, got %d, %v", count, err)
+	}
+
+	count, err = RestoreAll[TestSoftModel](ctx, db, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("id = ?", ids[3])
+	})
+	if err != nil || count != 1 {
+		t.Errorf("Expected 1 restored by condition, got %d, %v", count, err)
+	}
+}
+
+func TestDeletedScopes_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	sql := db.NewSelect().Model((*TestSoftModel)(nil)).Apply(OnlyDeleted, DeletedBetween(from, to)).String()
+	want := `(deleted_at >= '2024-06-01 00:00:00+00:00') AND (deleted_at < '2024-07-01 00:00:00+00:00')`
+	if !strings.Contains(sql, want) {
+		t.Errorf("Expected %s in %s", want, sql)
+	}
+
+	sql = db.NewUpdate().Model((*TestSoftModel)(nil)).Set("name = 'x'").Apply(DeletedBeforeUpdateScope(from), DeletedAfterUpdateScope(from)).String()
+	want = `(deleted_at < '2024-06-01 00:00:00+00:00') AND (deleted_at >= '2024-06-01 00:00:00+00:00')`
+	if !strings.Contains(sql, want) {
+		t.Errorf("Expected %s in %s", want, sql)
+	}
+}
+
+func TestDeletedBetween(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx, models := createSoftModels(t, db, 4)
+
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, m := range models[:3] {
+		_, err := db.NewUpdate().Model((*TestSoftModel)(nil)).
+			Set("deleted_at = ?", base.AddDate(0, 0, i*10)).
+			Where("id = ?", m.ID).
+			Exec(ctx)
+		if err != nil {
+			t.Fatalf("Failed to soft delete: %v", err)
+		}
+	}
+
+	var found []TestSoftModel
+	err := db.NewSelect().Model(&found).
+		Apply(OnlyDeleted, DeletedBetween(base.AddDate(0, 0, 5), base.AddDate(0, 0, 25))).
+		Scan(ctx)
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != models[1].ID {
+		t.Errorf("Expected only record 1 in the window, got %v", found)
+	}
+
+	before, _ := Count[TestSoftModel](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Apply(OnlyDeleted, DeletedBefore(base.AddDate(0, 0, 10)))
+	})
+	after, _ := Count[TestSoftModel](ctx, db, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Apply(OnlyDeleted, DeletedAfter(base.AddDate(0, 0, 10)))
+	})
+	if before != 1 || after != 2 {
+		t.Errorf("Expected 1 before and 2 after, got %d and %d", before, after)
+	}
+}
+
+type cascadeUser struct {
+	bun.BaseModel `bun:"table:cascade_users,alias:cu"`
+	ID            string `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Name          string `bun:"name,notnull"`
+	SoftDeletableModel
+}
+
+type cascadeOrder struct {
+	bun.BaseModel `bun:"table:cascade_orders,alias:co"`
+	ID            int64     `bun:"id,pk,autoincrement"`
+	UserID        string    `bun:"user_id,type:uuid,notnull"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	SoftDeletableModel
+}
+
+type cascadeOrderItem struct {
+	bun.BaseModel `bun:"table:cascade_order_items,alias:coi"`
+	ID            int64 `bun:"id,pk,autoincrement"`
+	OrderID       int64 `bun:"order_id,notnull"`
+	SoftDeletableModel
+}
+
+func createCascadeTables(t *testing.T, db *DBKit) (context.Context, *cascadeUser, []cascadeOrder) {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, model := range []any{(*cascadeOrderItem)(nil), (*cascadeOrder)(nil), (*cascadeUser)(nil)} {
+		if _, err := db.NewDropTable().Model(model).IfExists().Exec(ctx); err != nil {
+			t.Fatalf("Failed to drop table: %v", err)
+		}
+	}
+	for _, model := range []any{(*cascadeUser)(nil), (*cascadeOrder)(nil), (*cascadeOrderItem)(nil)} {
+		if _, err := db.NewCreateTable().Model(model).Exec(ctx); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+
+	user := &cascadeUser{Name: "Alice"}
+	other := &cascadeUser{Name: "Bob"}
+	if _, err := db.NewInsert().Model(user).Exec(ctx); err != nil {
+		t.Fatalf("Insert user failed: %v", err)
+	}
+	if _, err := db.NewInsert().Model(other).Exec(ctx); err != nil {
+		t.Fatalf("Insert user failed: %v", err)
+	}
+
+	orders := []cascadeOrder{{UserID: user.ID}, {UserID: user.ID}, {UserID: user.ID}, {UserID: other.ID}}
+	if _, err := db.NewInsert().Model(&orders).Exec(ctx); err != nil {
+		t.Fatalf("Insert orders failed: %v", err)
+	}
+	items := []cascadeOrderItem{{OrderID: orders[0].ID}, {OrderID: orders[1].ID}, {OrderID: orders[3].ID}}
+	if _, err := db.NewInsert().Model(&items).Exec(ctx); err != nil {
+		t.Fatalf("Insert items failed: %v", err)
+	}
+	return ctx, user, orders
+}
+
+func countDeleted(t *testing.T, db *DBKit, table string) int {
+	t.Helper()
+	var n int
+	if err := db.NewRaw("SELECT count(*) FROM ? WHERE deleted_at IS NOT NULL", bun.Ident(table)).Scan(context.Background(), &n); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	return n
+}
+
+func TestSoftDeleteCascade(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx, user, _ := createCascadeTables(t, db)
+
+	count, err := SoftDeleteCascade[cascadeUser, cascadeOrder](ctx, db, user, "user_id")
+	if err != nil {
+		t.Fatalf("SoftDeleteCascade failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 orders marked, got %d", count)
+	}
+
+	var orders []cascadeOrder
+	if err := db.NewSelect().Model(&orders).WhereAllWithDeleted().Where("user_id = ?", user.ID).Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	for _, o := range orders {
+		if o.DeletedAt == nil {
+			t.Errorf("Expected order %d to have deleted_at set", o.ID)
+		}
+	}
+	if n := countDeleted(t, db, "cascade_orders"); n != 3 {
+		t.Errorf("Expected only the user's 3 orders to be deleted, got %d", n)
+	}
+	if n := countDeleted(t, db, "cascade_users"); n != 1 {
+		t.Errorf("Expected the user to be deleted, got %d", n)
+	}
+}
+
+func TestSoftDeleteWithCascade_MultiLevel(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx, user, _ := createCascadeTables(t, db)
+
+	err := SoftDeleteWithCascade(ctx, db, user, CascadeConfig{
+		Children: []CascadeChild{{
+			Model:    (*cascadeOrder)(nil),
+			FKColumn: "user_id",
+			Children: []CascadeChild{{Model: (*cascadeOrderItem)(nil), FKColumn: "order_id"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("SoftDeleteWithCascade failed: %v", err)
+	}
+	if n := countDeleted(t, db, "cascade_orders"); n != 3 {
+		t.Errorf("Expected 3 deleted orders, got %d", n)
+	}
+	if n := countDeleted(t, db, "cascade_order_items"); n != 2 {
+		t.Errorf("Expected the 2 items of the user's orders to be deleted, got %d", n)
+	}
+}
+
+func TestSoftDeleteWithCascade_EarlierDeleteInSameTx(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx, user, orders := createCascadeTables(t, db)
+
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Deleted in the same transaction, so its deleted_at is also NOW()
+		if _, err := tx.NewUpdate().Model((*cascadeOrder)(nil)).Set("deleted_at = NOW()").Where("id = ?", orders[0].ID).Exec(ctx); err != nil {
+			return err
+		}
+		return SoftDeleteWithCascade(ctx, tx, user, CascadeConfig{
+			Children: []CascadeChild{{
+				Model:    (*cascadeOrder)(nil),
+				FKColumn: "user_id",
+				Children: []CascadeChild{{Model: (*cascadeOrderItem)(nil), FKColumn: "order_id"}},
+			}},
+		})
+	})
+	if err != nil {
+		t.Fatalf("SoftDeleteWithCascade failed: %v", err)
+	}
+	if n := countDeleted(t, db, "cascade_order_items"); n != 1 {
+		t.Errorf("Expected only the item of the order deleted by the cascade to be deleted, got %d", n)
+	}
+}
+
+func TestSoftDeleteCascade_NotFound(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	// The fake driver reports no affected rows
+	_, err := SoftDeleteCascade[cascadeUser, cascadeOrder](context.Background(), db, &cascadeUser{ID: "missing"}, "user_id")
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// Specification is a reusable, composable query predicate for T's records.
+// T does not appear in Apply, so Go cannot infer it: pass it explicitly, e.g. And[User](...).
+type Specification[T any] interface {
+	Apply(q *bun.SelectQuery) *bun.SelectQuery
+}
+
+// SpecFunc adapts a query modifier to a Specification
+type SpecFunc[T any] func(q *bun.SelectQuery) *bun.SelectQuery
+
+// Apply calls f(q)
+func (f SpecFunc[T]) Apply(q *bun.SelectQuery) *bun.SelectQuery {
+	return f(q)
+}
+
+// specOperators are the comparison operators accepted by WhereColumn
+var specOperators = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"LIKE": true, "NOT LIKE": true, "ILIKE": true, "NOT ILIKE": true,
+	"IS": true, "IS NOT": true, "IS DISTINCT FROM": true, "IS NOT DISTINCT FROM": true,
+}
+
+// And matches records satisfying every spec.
+//
+// Usage:
+//
+//	activeAdults := dbkit.And[User](
+//	    dbkit.WhereColumn[User]("active", "=", true),
+//	    dbkit.WhereColumn[User]("age", ">=", 18),
+//	)
+func And[T any](specs ...Specification[T]) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, spec := range specs {
+				q = spec.Apply(q)
+			}
+			return q
+		})
+	})
+}
+
+// Or matches records satisfying at least one spec.
+// Only WHERE conditions are combined, ordering and limits still apply to the whole query.
+func Or[T any](specs ...Specification[T]) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, spec := range specs {
+				q = q.WhereGroup(" OR ", spec.Apply)
+			}
+			return q
+		})
+	})
+}
+
+// Not matches records that do not satisfy spec.
+func Not[T any](spec Specification[T]) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			// Bun drops the separator of a group's first condition, so NOT needs a predecessor
+			return q.Where("TRUE").WhereGroup(" AND NOT ", spec.Apply)
+		})
+	})
+}
+
+// ByID matches the record with the given primary key.
+func ByID[T any](id any) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("?TableAlias.id = ?", id)
+	})
+}
+
+// WhereColumn compares a column to a value, e.g. WhereColumn[User]("age", ">=", 18).
+// Unsupported operators make the query fail.
+func WhereColumn[T any](col, op string, val any) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		op := strings.ToUpper(strings.TrimSpace(op))
+		if !specOperators[op] {
+			return q.Err(fmt.Errorf("dbkit: unsupported operator %q in WhereColumn", op))
+		}
+		return q.Where("? "+op+" ?", bun.Ident(col), val)
+	})
+}
+
+// WhereIn matches records whose column is one of vals. An empty vals matches nothing.
+func WhereIn[T any](col string, vals []any) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		if len(vals) == 0 {
+			return q.Where("FALSE")
+		}
+		return q.Where("? IN (?)", bun.Ident(col), bun.In(vals))
+	})
+}
+
+// Limit restricts the number of returned records.
+func Limit[T any](n int) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Limit(n)
+	})
+}
+
+// OrderBy sorts records by a column.
+func OrderBy[T any](col string, desc bool) Specification[T] {
+	return SpecFunc[T](func(q *bun.SelectQuery) *bun.SelectQuery {
+		if desc {
+			return q.OrderExpr("? DESC", bun.Ident(col))
+		}
+		return q.OrderExpr("? ASC", bun.Ident(col))
+	})
+}
+
+// FindAllBySpec finds T's records matching spec. Returns an empty slice when nothing matches.
+//
+// Usage:
+//
+//	recent, err := dbkit.FindAllBySpec[User](ctx, db, dbkit.And[User](
+//	    dbkit.WhereColumn[User]("active", "=", true),
+//	    dbkit.OrderBy[User]("created_at", true),
+//	    dbkit.Limit[User](10),
+//	))
+func FindAllBySpec[T any](ctx context.Context, db IDB, spec Specification[T]) ([]T, error) {
+	models := []T{}
+	if err := spec.Apply(db.NewSelect().Model(&models)).Scan(ctx); err != nil {
+		return nil, wrapError(err, "FindAllBySpec")
+	}
+	return models, nil
+}
+
+// CountBySpec counts T's records matching spec, ignoring its limit.
+//
+// Usage:
+//
+//	count, err := dbkit.CountBySpec[User](ctx, db, dbkit.WhereColumn[User]("active", "=", true))
+func CountBySpec[T any](ctx context.Context, db IDB, spec Specification[T]) (int, error) {
+	count, err := spec.Apply(db.NewSelect().Model((*T)(nil))).Count(ctx)
+	if err != nil {
+		return 0, wrapError(err, "CountBySpec")
+	}
+	return count, nil
+}
@@ -0,0 +1,112 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func specSQL(t *testing.T, spec Specification[TestModel]) string {
+	t.Helper()
+	db, _ := newFakeDB(t)
+	var models []TestModel
+	sql := spec.Apply(db.NewSelect().Model(&models)).String()
+	_, where, _ := strings.Cut(sql, " FROM \"test_models\" AS \"tm\" ")
+	return where
+}
+
+func TestSpecification_SQL(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Specification[TestModel]
+		want string
+	}{
+		{"column", WhereColumn[TestModel]("age", ">=", 18), `WHERE ("age" >= 18)`},
+		{"in", WhereIn[TestModel]("name", []any{"a", "b"}), `WHERE ("name" IN ('a', 'b'))`},
+		{"empty in", WhereIn[TestModel]("name", nil), `WHERE (FALSE)`},
+		{"by id", ByID[TestModel]("x"), `WHERE ("tm".id = 'x')`},
+		{"order", OrderBy[TestModel]("created_at", true), `ORDER BY "created_at" DESC`},
+		{"limit", Limit[TestModel](5), `LIMIT 5`},
+		{
+			"or",
+			Or[TestModel](WhereColumn[TestModel]("age", "<", 10), WhereColumn[TestModel]("age", ">", 90)),
+			`WHERE ((("age" < 10)) OR (("age" > 90)))`,
+		},
+		{
+			"not",
+			Not[TestModel](WhereColumn[TestModel]("active", "=", true)),
+			`WHERE ((TRUE) AND NOT (("active" = TRUE)))`,
+		},
+		{
+			"and",
+			And[TestModel](WhereColumn[TestModel]("active", "=", true), Not[TestModel](WhereIn[TestModel]("name", []any{"a"}))),
+			`WHERE (("active" = TRUE) AND ((TRUE) AND NOT (("name" IN ('a')))))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := specSQL(t, tt.spec); got != tt.want {
+				t.Errorf("Unexpected SQL:\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereColumn_InvalidOperator(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	_, err := FindAllBySpec[TestModel](context.Background(), db, WhereColumn[TestModel]("age", "; DROP TABLE x; --", 1))
+	if err == nil || !strings.Contains(err.Error(), "unsupported operator") {
+		t.Errorf("Expected an unsupported operator error, got %v", err)
+	}
+}
+
+func TestFindAllBySpec(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	base := time.Now().Add(-time.Hour)
+	for i := range 6 {
+		m := &TestModel{
+			Name:      "User",
+			Email:     strings.Repeat("u", i+1) + "@example.com",
+			Age:       i,
+			Active:    i%2 == 0,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+		if _, err := db.NewInsert().Model(m).Exec(ctx); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	spec := And[TestModel](
+		WhereColumn[TestModel]("active", "=", true),
+		OrderBy[TestModel]("created_at", true),
+	)
+	rows, err := FindAllBySpec[TestModel](ctx, db, spec)
+	if err != nil {
+		t.Fatalf("FindAllBySpec failed: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 active records, got %d", len(rows))
+	}
+	for i, r := range rows {
+		if !r.Active {
+			t.Errorf("Row %d is not active", i)
+		}
+		if i > 0 && !r.CreatedAt.Before(rows[i-1].CreatedAt) {
+			t.Errorf("Expected descending created_at, row %d is %v after %v", i, r.CreatedAt, rows[i-1].CreatedAt)
+		}
+	}
+
+	count, err := CountBySpec[TestModel](ctx, db, Or[TestModel](
+		WhereColumn[TestModel]("age", "<", 2),
+		Not[TestModel](WhereColumn[TestModel]("active", "=", true)),
+	))
+	if err != nil || count != 4 { // ages 0, 1, 3, 5
+		t.Errorf("Expected 4 records, got %d, %v", count, err)
+	}
+}
@@ -0,0 +1,103 @@
+package dbkit
+
+import (
+	"context"
+)
+
+// TableStatistics holds size and row estimates for a table.
+// Sizes are in bytes, RowCount is PostgreSQL's live tuple estimate.
+type TableStatistics struct {
+	TableName string `bun:"table_name"`
+	RowCount  int64  `bun:"row_count"`
+	TotalSize int64  `bun:"total_size"` // Table, indexes and TOAST
+	TableSize int64  `bun:"table_size"`
+	IndexSize int64  `bun:"index_size"`
+	ToastSize int64  `bun:"toast_size"`
+}
+
+// IndexStatistics holds size and usage counters for an index
+type IndexStatistics struct {
+	IndexName     string `bun:"index_name"`
+	IndexSize     int64  `bun:"index_size"`
+	IndexScans    int64  `bun:"index_scans"`
+	TuplesFetched int64  `bun:"tuples_fetched"`
+	TuplesRead    int64  `bun:"tuples_read"`
+}
+
+// tableStatsQuery selects TableStatistics columns from pg_stat_user_tables as s.
+const tableStatsQuery = `
+SELECT
+    s.relname AS table_name,
+    s.n_live_tup AS row_count,
+    pg_total_relation_size(s.relid) AS total_size,
+    pg_relation_size(s.relid) AS table_size,
+    pg_indexes_size(s.relid) AS index_size,
+    COALESCE(pg_total_relation_size(NULLIF(c.reltoastrelid, 0)), 0) AS toast_size
+FROM pg_stat_user_tables s
+JOIN pg_class c ON c.oid = s.relid
+`
+
+// TableStats returns size and row statistics for a table, optionally schema-qualified.
+// Returns ErrNotFound if the table does not exist.
+//
+// Usage:
+//
+//	stats, err := dbkit.TableStats(ctx, db, "users")
+//	log.Printf("users: ~%d rows, %d bytes", stats.RowCount, stats.TotalSize)
+func TableStats(ctx context.Context, db IDB, tableName string) (*TableStatistics, error) {
+	var stats TableStatistics
+	err := db.NewRaw(tableStatsQuery+"WHERE s.relid = to_regclass(?)", tableName).Scan(ctx, &stats)
+	if err != nil {
+		return nil, wrapError(err, "TableStats")
+	}
+	return &stats, nil
+}
+
+// AllTableStats returns statistics for every user table, largest first.
+//
+// Usage:
+//
+//	tables, err := dbkit.AllTableStats(ctx, db)
+func AllTableStats(ctx context.Context, db IDB) ([]TableStatistics, error) {
+	stats := []TableStatistics{}
+	err := db.NewRaw(tableStatsQuery+"ORDER BY total_size DESC, table_name").Scan(ctx, &stats)
+	if err != nil {
+		return nil, wrapError(err, "AllTableStats")
+	}
+	return stats, nil
+}
+
+// IndexStats returns size and usage statistics for each index of a table.
+// Usage counters are cumulative since the last statistics reset.
+//
+// Usage:
+//
+//	indexes, err := dbkit.IndexStats(ctx, db, "users")
+//	for _, idx := range indexes {
+//	    if dbkit.IsIndexUnused(idx) {
+//	        log.Printf("unused index %s (%d bytes)", idx.IndexName, idx.IndexSize)
+//	    }
+//	}
+func IndexStats(ctx context.Context, db IDB, tableName string) ([]IndexStatistics, error) {
+	stats := []IndexStatistics{}
+	err := db.NewRaw(`
+        SELECT
+            indexrelname AS index_name,
+            pg_relation_size(indexrelid) AS index_size,
+            idx_scan AS index_scans,
+            idx_tup_fetch AS tuples_fetched,
+            idx_tup_read AS tuples_read
+        FROM pg_stat_user_indexes
+        WHERE relid = to_regclass(?)
+        ORDER BY indexrelname
+    `, tableName).Scan(ctx, &stats)
+	if err != nil {
+		return nil, wrapError(err, "IndexStats")
+	}
+	return stats, nil
+}
+
+// IsIndexUnused reports whether an index has never been scanned
+func IsIndexUnused(stats IndexStatistics) bool {
+	return stats.IndexScans == 0
+}
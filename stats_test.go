@@ -0,0 +1,91 @@
+package dbkit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsIndexUnused(t *testing.T) {
+	if !IsIndexUnused(IndexStatistics{IndexName: "idx", IndexSize: 8192}) {
+		t.Error("Expected an index without scans to be unused")
+	}
+	if IsIndexUnused(IndexStatistics{IndexName: "idx", IndexScans: 1}) {
+		t.Error("Expected a scanned index to be used")
+	}
+}
+
+func TestTableStats(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	models := insertTestModels(t, db, 20)
+	if _, err := db.ExecContext(ctx, "ANALYZE test_models"); err != nil {
+		t.Fatalf("ANALYZE failed: %v", err)
+	}
+
+	stats, err := TableStats(ctx, db, "test_models")
+	if err != nil {
+		t.Fatalf("TableStats failed: %v", err)
+	}
+	if stats.TableName != "test_models" || stats.RowCount != 20 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if stats.TableSize <= 0 || stats.IndexSize <= 0 || stats.TotalSize < stats.TableSize+stats.IndexSize {
+		t.Errorf("Unexpected sizes %+v", stats)
+	}
+
+	if _, err := TableStats(ctx, db, "missing_table"); !IsNotFound(err) {
+		t.Errorf("Expected ErrNotFound for a missing table, got %v", err)
+	}
+
+	all, err := AllTableStats(ctx, db)
+	if err != nil {
+		t.Fatalf("AllTableStats failed: %v", err)
+	}
+	found := false
+	for _, s := range all {
+		found = found || s.TableName == "test_models"
+	}
+	if !found {
+		t.Errorf("Expected test_models in %+v", all)
+	}
+
+	// Primary key lookups scan the pkey index, the table is small enough for a seq scan otherwise
+	err = db.Transaction(ctx, func(tx *Tx) error {
+		if _, err := tx.ExecContext(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+			return err
+		}
+		for _, m := range models[:5] {
+			var found TestModel
+			if err := tx.NewSelect().Model(&found).Where("id = ?", m.ID).Scan(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Lookups failed: %v", err)
+	}
+
+	// Statistics are flushed asynchronously
+	var pkey IndexStatistics
+	ok := waitFor(t, 5*time.Second, func() bool {
+		indexes, err := IndexStats(ctx, db, "test_models")
+		if err != nil {
+			t.Fatalf("IndexStats failed: %v", err)
+		}
+		for _, idx := range indexes {
+			if idx.IndexName == "test_models_pkey" {
+				pkey = idx
+			}
+		}
+		return pkey.IndexScans > 0
+	})
+	if !ok {
+		t.Errorf("Expected index scans on test_models_pkey, got %+v", pkey)
+	}
+	if pkey.IndexSize <= 0 || IsIndexUnused(pkey) {
+		t.Errorf("Unexpected pkey stats %+v", pkey)
+	}
+}
@@ -0,0 +1,118 @@
+package dbkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// TemporalModel tracks the period during which a version of a record was current.
+// Every version of a record shares its id, so include valid_from in the primary key.
+// ValidTo is nil for the current version.
+//
+// Usage:
+//
+//	type Price struct {
+//	    bun.BaseModel `bun:"table:prices,alias:p"`
+//	    ID            int64 `bun:"id,pk"`
+//	    dbkit.TemporalModel
+//	    Amount int64 `bun:"amount,notnull"`
+//	}
+type TemporalModel struct {
+	ValidFrom time.Time  `bun:"valid_from,pk,notnull"`
+	ValidTo   *time.Time `bun:"valid_to"`
+}
+
+// IsCurrent reports whether this is the current version.
+func (m *TemporalModel) IsCurrent() bool {
+	return m.ValidTo == nil
+}
+
+// InsertTemporal inserts model as a new current version, valid from NOW().
+//
+// Usage:
+//
+//	err := dbkit.InsertTemporal(ctx, db, &Price{ID: 1, Amount: 100})
+func InsertTemporal[T any](ctx context.Context, db IDB, model *T) error {
+	_, err := db.NewInsert().Model(model).
+		Value("valid_from", "NOW()").
+		Value("valid_to", "NULL").
+		Returning("*").
+		Exec(ctx)
+	return wrapError(err, "InsertTemporal")
+}
+
+// CloseTemporal ends the current version of the record with the given id at NOW().
+// The closed version is kept as history; the record has no current version until a new one is inserted.
+// Returns a NotFound error if the record has no current version.
+//
+// Usage:
+//
+//	err := dbkit.CloseTemporal[Price](ctx, db, 1)
+func CloseTemporal[T any](ctx context.Context, db IDB, id any) error {
+	res, err := db.NewUpdate().Model((*T)(nil)).
+		Set("valid_to = NOW()").
+		Where("id = ?", id).
+		Where("valid_to IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "CloseTemporal")
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return &Error{Code: CodeNotFound, Message: "no current version found", Op: "CloseTemporal"}
+	}
+	return nil
+}
+
+// UpdateTemporal closes the current version of model's record and inserts model as the new one.
+// Both run in one transaction, so the new version starts exactly when the previous one ends.
+//
+// Usage:
+//
+//	price.Amount = 120
+//	err := dbkit.UpdateTemporal(ctx, db, price.ID, &price)
+func UpdateTemporal[T any](ctx context.Context, db IDB, id any, model *T) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := CloseTemporal[T](ctx, tx, id); err != nil {
+			return err
+		}
+		return InsertTemporal(ctx, tx, model)
+	})
+}
+
+// FindAt returns the version of the record with the given id that was current at asOf.
+// Returns a NotFound error if the record did not exist at that time.
+//
+// Usage:
+//
+//	price, err := dbkit.FindAt[Price](ctx, db, 1, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+func FindAt[T any](ctx context.Context, db IDB, id any, asOf time.Time) (*T, error) {
+	var model T
+	err := db.NewSelect().Model(&model).
+		Where("id = ?", id).
+		Where("valid_from <= ?", asOf).
+		Where("(valid_to IS NULL OR valid_to > ?)", asOf).
+		Scan(ctx)
+	if err != nil {
+		return nil, wrapError(err, "FindAt")
+	}
+	return &model, nil
+}
+
+// FindHistory returns every version of the record with the given id, oldest first.
+//
+// Usage:
+//
+//	versions, err := dbkit.FindHistory[Price](ctx, db, 1)
+func FindHistory[T any](ctx context.Context, db IDB, id any) ([]T, error) {
+	var versions []T
+	err := db.NewSelect().Model(&versions).
+		Where("id = ?", id).
+		OrderExpr("valid_from ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, wrapError(err, "FindHistory")
+	}
+	return versions, nil
+}
@@ -0,0 +1,118 @@
+package dbkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TestTemporalPrice struct {
+	bun.BaseModel `bun:"table:test_temporal_prices,alias:ttp"`
+	ID            int64 `bun:"id,pk"`
+	TemporalModel
+	Amount int64 `bun:"amount,notnull"`
+}
+
+func createTemporalTable(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewCreateTable().Model((*TestTemporalPrice)(nil)).IfNotExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to create temporal table: %v", err)
+	}
+	_, _ = db.NewDelete().Model((*TestTemporalPrice)(nil)).Where("1=1").Exec(ctx)
+	return ctx
+}
+
+// dbNow returns the database clock, so points in time compare with NOW() without skew.
+func dbNow(t *testing.T, db *DBKit) time.Time {
+	t.Helper()
+	var now time.Time
+	if err := db.NewRaw("SELECT clock_timestamp()").Scan(context.Background(), &now); err != nil {
+		t.Fatalf("Failed to read database clock: %v", err)
+	}
+	return now
+}
+
+func TestTemporal_FindAt(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTemporalTable(t, db)
+
+	before := dbNow(t, db)
+	if err := InsertTemporal(ctx, db, &TestTemporalPrice{ID: 1, Amount: 100}); err != nil {
+		t.Fatalf("InsertTemporal failed: %v", err)
+	}
+	between := dbNow(t, db)
+
+	if err := CloseTemporal[TestTemporalPrice](ctx, db, 1); err != nil {
+		t.Fatalf("CloseTemporal failed: %v", err)
+	}
+	updated := &TestTemporalPrice{ID: 1, Amount: 120}
+	if err := InsertTemporal(ctx, db, updated); err != nil {
+		t.Fatalf("InsertTemporal failed: %v", err)
+	}
+	if !updated.IsCurrent() || updated.ValidFrom.IsZero() {
+		t.Errorf("Expected the inserted version to be current, got %+v", updated.TemporalModel)
+	}
+	after := dbNow(t, db)
+
+	if _, err := FindAt[TestTemporalPrice](ctx, db, 1, before); !IsNotFound(err) {
+		t.Errorf("Expected NotFound before the first insert, got %v", err)
+	}
+
+	old, err := FindAt[TestTemporalPrice](ctx, db, 1, between)
+	if err != nil {
+		t.Fatalf("FindAt failed: %v", err)
+	}
+	if old.Amount != 100 || old.IsCurrent() {
+		t.Errorf("Expected the closed first version, got %+v", old)
+	}
+
+	current, err := FindAt[TestTemporalPrice](ctx, db, 1, after)
+	if err != nil {
+		t.Fatalf("FindAt failed: %v", err)
+	}
+	if current.Amount != 120 || !current.IsCurrent() {
+		t.Errorf("Expected the current version, got %+v", current)
+	}
+}
+
+func TestTemporal_UpdateTemporal(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTemporalTable(t, db)
+
+	if err := InsertTemporal(ctx, db, &TestTemporalPrice{ID: 2, Amount: 10}); err != nil {
+		t.Fatalf("InsertTemporal failed: %v", err)
+	}
+	if err := UpdateTemporal(ctx, db, 2, &TestTemporalPrice{ID: 2, Amount: 20}); err != nil {
+		t.Fatalf("UpdateTemporal failed: %v", err)
+	}
+
+	versions, err := FindHistory[TestTemporalPrice](ctx, db, 2)
+	if err != nil {
+		t.Fatalf("FindHistory failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(versions))
+	}
+	if versions[0].ValidTo == nil || !versions[0].ValidTo.Equal(versions[1].ValidFrom) {
+		t.Errorf("Expected the new version to start when the old one ended, got %+v", versions)
+	}
+	if versions[1].Amount != 20 || !versions[1].IsCurrent() {
+		t.Errorf("Expected the updated version to be current, got %+v", versions[1])
+	}
+}
+
+func TestTemporal_CloseWithoutCurrent(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := createTemporalTable(t, db)
+
+	if err := CloseTemporal[TestTemporalPrice](ctx, db, 99); !IsNotFound(err) {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
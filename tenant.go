@@ -37,6 +37,25 @@ func WithTenant(ctx context.Context, tenantID string) context.Context {
 	return context.WithValue(ctx, TenantContextKey{}, tenantID)
 }
 
+type adminBypassKey struct{}
+
+// WithAdminBypass returns a context under which TenantIsolation doesn't filter by tenant,
+// for admin tools working across tenants. Never derive it from request input.
+//
+// Usage:
+//
+//	ctx = dbkit.WithAdminBypass(ctx)
+//	ti.Select(ctx).Model(&users).Scan(ctx) // Users of every tenant
+func WithAdminBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminBypassKey{}, true)
+}
+
+// isAdminBypass reports whether ctx was returned by WithAdminBypass
+func isAdminBypass(ctx context.Context) bool {
+	bypass, _ := ctx.Value(adminBypassKey{}).(bool)
+	return bypass
+}
+
 // GetTenant extracts tenant ID from the context.
 // Returns empty string if not found.
 //
@@ -146,6 +165,103 @@ func (m *TenantModel) SetTenantID(tenantID string) {
 	m.TenantID = tenantID
 }
 
+// TenantFindByID fetches a record by primary key within the tenant from context.
+// A record of another tenant is reported as not found, so callers can't probe for IDs.
+// Returns ErrNoTenant if the context has no tenant.
+//
+// Usage:
+//
+//	user, err := dbkit.TenantFindByID[User](ctx, db, userID)
+func TenantFindByID[T any](ctx context.Context, db IDB, id any) (*T, error) {
+	tenantID, err := RequireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var model T
+	err = db.NewSelect().
+		Model(&model).
+		Where("id = ?", id).
+		Where("tenant_id = ?", tenantID).
+		Scan(ctx)
+	if err != nil {
+		return nil, wrapError(err, "TenantFindByID")
+	}
+	return &model, nil
+}
+
+// TenantCreate sets the tenant from context on model and inserts it, see Create.
+// Returns ErrNoTenant if the context has no tenant.
+//
+// Usage:
+//
+//	err := dbkit.TenantCreate(ctx, db, &user)
+func TenantCreate[T any](ctx context.Context, db IDB, model *T) error {
+	if err := SetTenantID(ctx, model); err != nil {
+		return err
+	}
+	return Create(ctx, db, model)
+}
+
+// TenantUpdate updates a record by primary key if it belongs to the tenant from context.
+// The tenant ID of model is reset to the context tenant, so records can't be moved to
+// another tenant. Returns a not found error for records of other tenants.
+//
+// Usage:
+//
+//	err := dbkit.TenantUpdate(ctx, db, &user)
+func TenantUpdate[T any](ctx context.Context, db IDB, model *T) error {
+	tenantID, err := RequireTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if err := validateModel(ctx, model, "TenantUpdate"); err != nil {
+		return err
+	}
+	if err := SetTenantID(ctx, model); err != nil {
+		return err
+	}
+
+	result, err := db.NewUpdate().
+		Model(model).
+		WherePK().
+		Where("tenant_id = ?", tenantID).
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "TenantUpdate")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "TenantUpdate"}
+	}
+	return nil
+}
+
+// TenantDelete deletes a record by primary key if it belongs to the tenant from context
+// (soft delete for soft-deletable models). Returns a not found error for records of other tenants.
+//
+// Usage:
+//
+//	err := dbkit.TenantDelete[User](ctx, db, userID)
+func TenantDelete[T any](ctx context.Context, db IDB, id any) error {
+	tenantID, err := RequireTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.NewDelete().
+		Model((*T)(nil)).
+		Where("id = ?", id).
+		Where("tenant_id = ?", tenantID).
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "TenantDelete")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "record not found", Op: "TenantDelete"}
+	}
+	return nil
+}
+
 // TenantHook is a Bun query hook that automatically applies tenant filtering.
 type TenantHook struct {
 	// Column is the tenant ID column name (default: "tenant_id")
@@ -173,6 +289,18 @@ type Tenant struct {
 	TimestampedModel
 }
 
+// TenantsDeletedAtMigration adds a deleted_at column to the tenants table, which
+// TenantManager.Delete needs to soft delete tenants. Without it, only purging deletes work.
+//
+// Usage:
+//
+//	_, err := db.Migrate(ctx, append([]dbkit.Migration{dbkit.TenantsDeletedAtMigration}, migrations...))
+var TenantsDeletedAtMigration = Migration{
+	ID:          "dbkit_tenants_deleted_at",
+	Description: "Add deleted_at to tenants for soft deletes",
+	SQL:         "ALTER TABLE tenants ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ",
+}
+
 // TenantConfig configures multi-tenancy behavior.
 type TenantConfig struct {
 	// Column is the tenant ID column name
@@ -225,7 +353,7 @@ func NewTenantIsolation(db bun.IDB, config TenantConfig) *TenantIsolation {
 func (ti *TenantIsolation) Select(ctx context.Context) *bun.SelectQuery {
 	q := ti.db.NewSelect()
 	tenantID := GetTenant(ctx)
-	if tenantID != "" && ti.config.EnforceOnSelect {
+	if tenantID != "" && ti.config.EnforceOnSelect && !isAdminBypass(ctx) {
 		q = q.Where(ti.config.Column+" = ?", tenantID)
 	}
 	return q
@@ -245,7 +373,7 @@ func (ti *TenantIsolation) Insert(ctx context.Context) *bun.InsertQuery {
 func (ti *TenantIsolation) Update(ctx context.Context) *bun.UpdateQuery {
 	q := ti.db.NewUpdate()
 	tenantID := GetTenant(ctx)
-	if tenantID != "" && ti.config.EnforceOnUpdate {
+	if tenantID != "" && ti.config.EnforceOnUpdate && !isAdminBypass(ctx) {
 		q = q.Where(ti.config.Column+" = ?", tenantID)
 	}
 	return q
@@ -259,7 +387,7 @@ func (ti *TenantIsolation) Update(ctx context.Context) *bun.UpdateQuery {
 func (ti *TenantIsolation) Delete(ctx context.Context) *bun.DeleteQuery {
 	q := ti.db.NewDelete()
 	tenantID := GetTenant(ctx)
-	if tenantID != "" && ti.config.EnforceOnDelete {
+	if tenantID != "" && ti.config.EnforceOnDelete && !isAdminBypass(ctx) {
 		q = q.Where(ti.config.Column+" = ?", tenantID)
 	}
 	return q
@@ -273,6 +401,12 @@ func ValidateTenant(ctx context.Context, db bun.IDB) error {
 		return ErrNoTenant
 	}
 
+	_, err := loadActiveTenant(ctx, db, tenantID, "ValidateTenant")
+	return err
+}
+
+// loadActiveTenant fetches an active tenant, reporting missing and inactive tenants alike
+func loadActiveTenant(ctx context.Context, db bun.IDB, tenantID, op string) (*Tenant, error) {
 	var tenant Tenant
 	err := db.NewSelect().
 		Model(&tenant).
@@ -282,15 +416,15 @@ func ValidateTenant(ctx context.Context, db bun.IDB) error {
 
 	if err != nil {
 		if IsNotFound(err) {
-			return &Error{
+			return nil, &Error{
 				Code:    CodeNotFound,
 				Message: "tenant not found or inactive",
-				Op:      "ValidateTenant",
+				Op:      op,
 				Cause:   ErrNoTenant,
 			}
 		}
-		return wrapError(err, "ValidateTenant")
+		return nil, wrapError(err, op)
 	}
 
-	return nil
+	return &tenant, nil
 }
@@ -0,0 +1,130 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TenantCache keeps active tenants in memory so validating the tenant of each request
+// doesn't query the tenants table. Missing and inactive tenants are not cached.
+type TenantCache struct {
+	db    IDB
+	cache sync.Map // tenant ID -> tenantCacheEntry
+	TTL   time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+type tenantCacheEntry struct {
+	tenant  Tenant
+	expires time.Time
+}
+
+// NewTenantCache creates a tenant cache keeping tenants for ttl (default: 1m) and starts
+// a goroutine evicting expired entries. Call Close to stop it.
+//
+// Usage:
+//
+//	tenants := dbkit.NewTenantCache(db, 5*time.Minute)
+//	defer tenants.Close()
+//
+//	if err := tenants.Validate(ctx); err != nil {
+//	    http.Error(w, "unknown tenant", http.StatusForbidden)
+//	}
+func NewTenantCache(db IDB, ttl time.Duration) *TenantCache {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	tc := &TenantCache{
+		db:   db,
+		TTL:  ttl,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go tc.run()
+	return tc
+}
+
+// Validate is ValidateTenant served from the cache when possible.
+func (tc *TenantCache) Validate(ctx context.Context) error {
+	_, err := tc.get(ctx, "TenantCache.Validate")
+	return err
+}
+
+// GetCachedTenant returns the active tenant from context, from the cache when possible.
+// Returns ErrNoTenant if the context has no tenant.
+//
+// Usage:
+//
+//	tenant, err := dbkit.GetCachedTenant(ctx, tenants)
+func GetCachedTenant(ctx context.Context, tc *TenantCache) (*Tenant, error) {
+	return tc.get(ctx, "GetCachedTenant")
+}
+
+// Invalidate removes a tenant from the cache, e.g. after suspending it.
+func (tc *TenantCache) Invalidate(tenantID string) {
+	tc.cache.Delete(tenantID)
+}
+
+// InvalidateAll empties the cache.
+func (tc *TenantCache) InvalidateAll() {
+	tc.cache.Clear()
+}
+
+// Close stops the eviction goroutine.
+func (tc *TenantCache) Close() {
+	tc.stopOnce.Do(func() { close(tc.stop) })
+	<-tc.done
+}
+
+// get returns a copy of the cached tenant, loading it on a miss
+func (tc *TenantCache) get(ctx context.Context, op string) (*Tenant, error) {
+	tenantID, err := RequireTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := tc.cache.Load(tenantID); ok {
+		entry := v.(tenantCacheEntry)
+		if time.Now().Before(entry.expires) {
+			tenant := entry.tenant
+			return &tenant, nil
+		}
+		tc.cache.CompareAndDelete(tenantID, v)
+	}
+
+	tenant, err := loadActiveTenant(ctx, tc.db, tenantID, op)
+	if err != nil {
+		return nil, err
+	}
+	tc.cache.Store(tenantID, tenantCacheEntry{tenant: *tenant, expires: time.Now().Add(tc.TTL)})
+	return tenant, nil
+}
+
+// run evicts expired entries every TTL
+func (tc *TenantCache) run() {
+	defer close(tc.done)
+
+	ticker := time.NewTicker(tc.TTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tc.stop:
+			return
+		case now := <-ticker.C:
+			tc.evictExpired(now)
+		}
+	}
+}
+
+func (tc *TenantCache) evictExpired(now time.Time) {
+	tc.cache.Range(func(key, v any) bool {
+		if !now.Before(v.(tenantCacheEntry).expires) {
+			tc.cache.CompareAndDelete(key, v)
+		}
+		return true
+	})
+}
@@ -0,0 +1,121 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTenantCache_NoTenant(t *testing.T) {
+	db, _ := newFakeDB(t)
+	tc := NewTenantCache(db, time.Minute)
+	defer tc.Close()
+
+	if err := tc.Validate(context.Background()); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant, got %v", err)
+	}
+}
+
+func TestTenantCache_MissesAreNotCached(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+	tc := NewTenantCache(db, time.Minute)
+	defer tc.Close()
+
+	ctx := WithTenant(context.Background(), "missing")
+	for range 2 {
+		if err := tc.Validate(ctx); !IsNotFound(err) {
+			t.Errorf("Expected not found, got %v", err)
+		}
+	}
+	if hook.Count() != 2 {
+		t.Errorf("Expected 2 queries, got %d", hook.Count())
+	}
+}
+
+func TestTenantCache_HitAndInvalidate(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+	tc := NewTenantCache(db, time.Minute)
+	defer tc.Close()
+
+	tc.cache.Store("t1", tenantCacheEntry{tenant: Tenant{ID: "t1", Name: "Acme"}, expires: time.Now().Add(time.Minute)})
+	ctx := WithTenant(context.Background(), "t1")
+
+	tenant, err := GetCachedTenant(ctx, tc)
+	if err != nil || tenant.Name != "Acme" {
+		t.Fatalf("Expected cached tenant, got %v, %v", tenant, err)
+	}
+	tenant.Name = "Changed"
+	if again, _ := GetCachedTenant(ctx, tc); again.Name != "Acme" {
+		t.Errorf("Expected callers to get a copy, got %s", again.Name)
+	}
+	if hook.Count() != 0 {
+		t.Errorf("Expected no query on a hit, got %d", hook.Count())
+	}
+
+	tc.Invalidate("t1")
+	if err := tc.Validate(ctx); !IsNotFound(err) {
+		t.Errorf("Expected a database lookup after Invalidate, got %v", err)
+	}
+	if hook.Count() != 1 {
+		t.Errorf("Expected 1 query after Invalidate, got %d", hook.Count())
+	}
+}
+
+func TestTenantCache_Eviction(t *testing.T) {
+	db, _ := newFakeDB(t)
+	tc := NewTenantCache(db, time.Minute)
+	defer tc.Close()
+
+	now := time.Now()
+	tc.cache.Store("old", tenantCacheEntry{expires: now.Add(-time.Second)})
+	tc.cache.Store("new", tenantCacheEntry{expires: now.Add(time.Minute)})
+	tc.evictExpired(now)
+
+	if _, ok := tc.cache.Load("old"); ok {
+		t.Error("Expected expired entry to be evicted")
+	}
+	if _, ok := tc.cache.Load("new"); !ok {
+		t.Error("Expected live entry to be kept")
+	}
+
+	tc.InvalidateAll()
+	if _, ok := tc.cache.Load("new"); ok {
+		t.Error("Expected InvalidateAll to empty the cache")
+	}
+}
+
+func TestTenantCache_Validate(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTenantsTable(t, db)
+	tenant, err := NewTenantManager(db, nil, TenantManagerConfig{}).Create(ctx, "Acme", "acme")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+	tc := NewTenantCache(db, time.Minute)
+	defer tc.Close()
+
+	tenantCtx := WithTenant(ctx, tenant.ID)
+	for range 2 {
+		if err := tc.Validate(tenantCtx); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+	}
+	if hook.Count() != 1 {
+		t.Errorf("Expected the database to be hit once, got %d", hook.Count())
+	}
+
+	cached, err := GetCachedTenant(tenantCtx, tc)
+	if err != nil || cached.Subdomain != "acme" {
+		t.Errorf("Expected full tenant data, got %v, %v", cached, err)
+	}
+}
@@ -0,0 +1,247 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// TenantManagerConfig configures a TenantManager
+type TenantManagerConfig struct {
+	// SchemaIsolation creates a PostgreSQL schema per tenant, see TenantSchemaName
+	SchemaIsolation bool
+
+	// DeleteCheck is called before a tenant is deleted, e.g. to refuse tenants with
+	// active subscriptions. A non-nil error aborts the deletion as a validation error.
+	DeleteCheck func(ctx context.Context, tenant *Tenant) error
+}
+
+// TenantManager handles the lifecycle of the rows in the tenants table.
+type TenantManager struct {
+	db         *DBKit
+	migrations []Migration
+	config     TenantManagerConfig
+}
+
+// NewTenantManager creates a tenant manager. Under schema isolation, the migrations run in the
+// schema of each created tenant. Otherwise they run once, with Migrate, on the first Create.
+//
+// Usage:
+//
+//	tm := dbkit.NewTenantManager(db, tenantMigrations, dbkit.TenantManagerConfig{
+//	    SchemaIsolation: true,
+//	    DeleteCheck: func(ctx context.Context, t *dbkit.Tenant) error {
+//	        return billing.RequireNoActiveSubscriptions(ctx, t.ID)
+//	    },
+//	})
+func NewTenantManager(db *DBKit, migrations []Migration, config TenantManagerConfig) *TenantManager {
+	return &TenantManager{
+		db:         db,
+		migrations: migrations,
+		config:     config,
+	}
+}
+
+// TenantSchemaName returns the schema of a tenant under schema isolation: "tenant_" and the ID
+// with dashes replaced by underscores.
+func TenantSchemaName(tenantID string) string {
+	return "tenant_" + strings.ReplaceAll(tenantID, "-", "_")
+}
+
+// Create inserts an active tenant. Under schema isolation, it also creates the tenant schema and
+// runs the migrations in it, in the same transaction, recording them in the schema's own
+// _dbkit_migrations table; nothing is kept if a step fails. Without schema isolation the
+// migrations are shared: Migrate applies them once, before the tenant is inserted.
+//
+// Usage:
+//
+//	tenant, err := tm.Create(ctx, "Acme", "acme")
+func (tm *TenantManager) Create(ctx context.Context, name, subdomain string) (*Tenant, error) {
+	if !tm.config.SchemaIsolation && len(tm.migrations) > 0 {
+		if _, err := tm.db.Migrate(ctx, tm.migrations); err != nil {
+			return nil, err
+		}
+	}
+
+	tenant := &Tenant{Name: name, Subdomain: subdomain, Active: true, Metadata: "{}"}
+	err := tm.db.Transaction(ctx, func(tx *Tx) error {
+		if _, err := tx.NewInsert().Model(tenant).Returning("*").Exec(ctx); err != nil {
+			return wrapError(err, "TenantManager.Create")
+		}
+		if !tm.config.SchemaIsolation {
+			return nil
+		}
+
+		schema := TenantSchemaName(tenant.ID)
+		if _, err := tx.NewRaw("CREATE SCHEMA ?", bun.Ident(schema)).Exec(ctx); err != nil {
+			return wrapError(err, "TenantManager.Create")
+		}
+		if _, err := tx.NewRaw("SELECT set_config('search_path', ?, true)", schema+", public").Exec(ctx); err != nil {
+			return wrapError(err, "TenantManager.Create")
+		}
+		if _, err := tx.ExecContext(ctx, migrationsTable); err != nil {
+			return wrapError(err, "TenantManager.Create")
+		}
+		for _, m := range tm.migrations {
+			if err := tm.applyMigration(ctx, tx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// applyMigration runs a tenant migration and records it in the tenant schema
+func (tm *TenantManager) applyMigration(ctx context.Context, tx *Tx, m Migration) error {
+	start := time.Now()
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "tenant migration " + m.ID + " failed: " + err.Error(),
+			Op:      "TenantManager.Create",
+			Query:   truncateSQL(m.SQL, 200),
+			Cause:   err,
+		}
+	}
+
+	labels, err := migrationLabels(m.Labels)
+	if err != nil {
+		return wrapError(err, "TenantManager.Create")
+	}
+	_, err = tx.NewRaw(`
+        INSERT INTO _dbkit_migrations (id, description, checksum, duration_ms, labels, author)
+        VALUES (?, ?, ?, ?, ?::jsonb, NULLIF(?, ''))
+    `, m.ID, m.Description, checksumSQL(m.SQL), time.Since(start).Milliseconds(), labels, m.Author).Exec(ctx)
+	return wrapError(err, "TenantManager.Create")
+}
+
+// Suspend deactivates a tenant, ValidateTenant then rejects it.
+//
+// Usage:
+//
+//	err := tm.Suspend(ctx, tenantID)
+func (tm *TenantManager) Suspend(ctx context.Context, tenantID string) error {
+	return tm.setActive(ctx, tenantID, false, "TenantManager.Suspend")
+}
+
+// Activate reactivates a suspended tenant.
+//
+// Usage:
+//
+//	err := tm.Activate(ctx, tenantID)
+func (tm *TenantManager) Activate(ctx context.Context, tenantID string) error {
+	return tm.setActive(ctx, tenantID, true, "TenantManager.Activate")
+}
+
+func (tm *TenantManager) setActive(ctx context.Context, tenantID string, active bool, op string) error {
+	q := tm.db.NewUpdate().
+		Model((*Tenant)(nil)).
+		Set("active = ?", active).
+		Set("updated_at = NOW()").
+		Where("id = ?", tenantID)
+	if active {
+		// Soft deleted tenants stay inactive
+		softDeletable, err := tm.softDeletable(ctx, op)
+		if err != nil {
+			return err
+		}
+		if softDeletable {
+			q = q.Where("deleted_at IS NULL")
+		}
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return wrapError(err, op)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "tenant not found", Op: op}
+	}
+	return nil
+}
+
+// Delete soft deletes a tenant, or with purgeData removes its row and, under schema
+// isolation, drops its schema with every table in it. The configured DeleteCheck runs first.
+// A soft deleted tenant is deactivated and keeps its data. Soft deletes need the deleted_at
+// column of TenantsDeletedAtMigration and fail with an unsupported error without it.
+//
+// Usage:
+//
+//	err := tm.Delete(ctx, tenantID, false)
+func (tm *TenantManager) Delete(ctx context.Context, tenantID string, purgeData bool) error {
+	tenant := new(Tenant)
+	if err := tm.db.NewSelect().Model(tenant).Where("id = ?", tenantID).Scan(ctx); err != nil {
+		return wrapError(err, "TenantManager.Delete")
+	}
+	if tm.config.DeleteCheck != nil {
+		if err := tm.config.DeleteCheck(ctx, tenant); err != nil {
+			return deletionRejected(err, "TenantManager.Delete")
+		}
+	}
+
+	if !purgeData {
+		return tm.softDelete(ctx, tenant.ID)
+	}
+
+	return tm.db.Transaction(ctx, func(tx *Tx) error {
+		if tm.config.SchemaIsolation {
+			if _, err := tx.NewRaw("DROP SCHEMA IF EXISTS ? CASCADE", bun.Ident(TenantSchemaName(tenant.ID))).Exec(ctx); err != nil {
+				return wrapError(err, "TenantManager.Delete")
+			}
+		}
+		_, err := tx.NewDelete().Model(tenant).WherePK().ForceDelete().Exec(ctx)
+		return wrapError(err, "TenantManager.Delete")
+	})
+}
+
+// softDelete marks a tenant deleted and deactivates it
+func (tm *TenantManager) softDelete(ctx context.Context, tenantID string) error {
+	softDeletable, err := tm.softDeletable(ctx, "TenantManager.Delete")
+	if err != nil {
+		return err
+	}
+	if !softDeletable {
+		return &Error{
+			Code:    CodeUnsupported,
+			Message: "soft deleting tenants needs the deleted_at column of TenantsDeletedAtMigration",
+			Op:      "TenantManager.Delete",
+		}
+	}
+
+	result, err := tm.db.NewUpdate().
+		Model((*Tenant)(nil)).
+		Set("deleted_at = NOW()").
+		Set("active = ?", false).
+		Set("updated_at = NOW()").
+		Where("id = ?", tenantID).
+		Where("deleted_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return wrapError(err, "TenantManager.Delete")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &Error{Code: CodeNotFound, Message: "tenant not found", Op: "TenantManager.Delete"}
+	}
+	return nil
+}
+
+// softDeletable reports whether the tenants table has the deleted_at column of TenantsDeletedAtMigration
+func (tm *TenantManager) softDeletable(ctx context.Context, op string) (bool, error) {
+	var exists bool
+	err := tm.db.NewRaw(`
+        SELECT EXISTS (
+            SELECT 1 FROM pg_attribute
+            WHERE attrelid = to_regclass('tenants') AND attname = 'deleted_at' AND NOT attisdropped
+        )
+    `).Scan(ctx, &exists)
+	if err != nil {
+		return false, wrapError(err, op)
+	}
+	return exists, nil
+}
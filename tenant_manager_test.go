@@ -0,0 +1,208 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTenantSchemaName(t *testing.T) {
+	got := TenantSchemaName("6f1c2b4e-0d7a-4c1e-9a53-2b8f0e1d7c90")
+	if got != "tenant_6f1c2b4e_0d7a_4c1e_9a53_2b8f0e1d7c90" {
+		t.Errorf("Unexpected schema name %s", got)
+	}
+}
+
+func createTenantsTable(t *testing.T, db *DBKit) context.Context {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := db.NewDropTable().Model((*Tenant)(nil)).IfExists().Cascade().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop tenants table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*Tenant)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create tenants table: %v", err)
+	}
+	t.Cleanup(func() {
+		db.NewDropTable().Model((*Tenant)(nil)).IfExists().Cascade().Exec(ctx)
+	})
+	return ctx
+}
+
+func TestTenantManager_SuspendActivate(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTenantsTable(t, db)
+	tm := NewTenantManager(db, nil, TenantManagerConfig{})
+
+	tenant, err := tm.Create(ctx, "Acme", "acme")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	tenantCtx := WithTenant(ctx, tenant.ID)
+	if err := ValidateTenant(tenantCtx, db); err != nil {
+		t.Fatalf("Expected new tenant to be valid, got %v", err)
+	}
+
+	if err := tm.Suspend(ctx, tenant.ID); err != nil {
+		t.Fatalf("Suspend failed: %v", err)
+	}
+	if err := ValidateTenant(tenantCtx, db); !IsNotFound(err) {
+		t.Errorf("Expected suspended tenant to be rejected, got %v", err)
+	}
+
+	if err := tm.Activate(ctx, tenant.ID); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	if err := ValidateTenant(tenantCtx, db); err != nil {
+		t.Errorf("Expected reactivated tenant to be valid, got %v", err)
+	}
+
+	if err := tm.Suspend(ctx, "00000000-0000-0000-0000-000000000000"); !IsNotFound(err) {
+		t.Errorf("Expected not found for unknown tenant, got %v", err)
+	}
+}
+
+func TestTenantManager_Delete(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTenantsTable(t, db)
+	errSubscribed := errors.New("tenant has active subscriptions")
+	subscribed := true
+	tm := NewTenantManager(db, nil, TenantManagerConfig{
+		DeleteCheck: func(ctx context.Context, tenant *Tenant) error {
+			if subscribed {
+				return errSubscribed
+			}
+			return nil
+		},
+	})
+
+	tenant, err := tm.Create(ctx, "Acme", "acme")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	err = tm.Delete(ctx, tenant.ID, false)
+	if !IsValidation(err) || !errors.Is(err, errSubscribed) {
+		t.Fatalf("Expected the delete check to refuse, got %v", err)
+	}
+
+	// Soft deletes are opt-in through TenantsDeletedAtMigration
+	subscribed = false
+	if err := tm.Delete(ctx, tenant.ID, false); !IsUnsupported(err) {
+		t.Fatalf("Expected soft delete without deleted_at to be unsupported, got %v", err)
+	}
+	if _, err := db.ExecContext(ctx, TenantsDeletedAtMigration.SQL); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	if err := tm.Delete(ctx, tenant.ID, false); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := ValidateTenant(WithTenant(ctx, tenant.ID), db); !IsNotFound(err) {
+		t.Errorf("Expected deleted tenant to be rejected, got %v", err)
+	}
+	if err := tm.Activate(ctx, tenant.ID); !IsNotFound(err) {
+		t.Errorf("Expected deleted tenant to stay inactive, got %v", err)
+	}
+	if err := tm.Delete(ctx, tenant.ID, false); !IsNotFound(err) {
+		t.Errorf("Expected not found for a deleted tenant, got %v", err)
+	}
+	total, _ := db.NewSelect().Model((*Tenant)(nil)).Count(ctx)
+	if total != 1 {
+		t.Errorf("Expected the tenant row to be kept, got %d rows", total)
+	}
+}
+
+func TestTenantManager_SchemaIsolation(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTenantsTable(t, db)
+	tm := NewTenantManager(db, []Migration{
+		{ID: "001", SQL: "CREATE TABLE notes (id SERIAL PRIMARY KEY, body TEXT)"},
+	}, TenantManagerConfig{SchemaIsolation: true})
+
+	tenant, err := tm.Create(ctx, "Acme", "acme")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	schema := TenantSchemaName(tenant.ID)
+
+	var tables []string
+	err = db.NewRaw("SELECT table_name FROM information_schema.tables WHERE table_schema = ? ORDER BY table_name", schema).Scan(ctx, &tables)
+	if err != nil {
+		t.Fatalf("Failed to list tenant tables: %v", err)
+	}
+	if len(tables) != 2 || tables[0] != "_dbkit_migrations" || tables[1] != "notes" {
+		t.Errorf("Expected migrated tenant schema, got %v", tables)
+	}
+
+	if err := tm.Delete(ctx, tenant.ID, true); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	exists, err := db.NewSelect().TableExpr("information_schema.schemata").Where("schema_name = ?", schema).Exists(ctx)
+	if err != nil || exists {
+		t.Errorf("Expected schema to be dropped, exists %v (%v)", exists, err)
+	}
+	total, _ := db.NewSelect().Model((*Tenant)(nil)).Count(ctx)
+	if total != 0 {
+		t.Errorf("Expected the tenant row to be removed, got %d rows", total)
+	}
+}
+
+func TestTenantsDeletedAtMigration(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTenantsTable(t, db)
+	_, _ = db.ExecContext(ctx, "DELETE FROM _dbkit_migrations WHERE id = ?", TenantsDeletedAtMigration.ID)
+
+	// Applying it twice must not fail, e.g. on a table that already has the column
+	for range 2 {
+		if _, err := db.ExecContext(ctx, TenantsDeletedAtMigration.SQL); err != nil {
+			t.Fatalf("Migration failed: %v", err)
+		}
+	}
+	if _, err := db.Migrate(ctx, []Migration{TenantsDeletedAtMigration}); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	tenant, err := NewTenantManager(db, nil, TenantManagerConfig{}).Create(ctx, "Acme", "acme")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := ValidateTenant(WithTenant(ctx, tenant.ID), db); err != nil {
+		t.Errorf("Expected ValidateTenant to succeed after the migration, got %v", err)
+	}
+	if err := NewTenantManager(db, nil, TenantManagerConfig{}).Delete(ctx, tenant.ID, false); err != nil {
+		t.Errorf("Expected soft delete to work after the migration, got %v", err)
+	}
+}
+
+func TestTenantManager_SharedMigrationsRunOnce(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTenantsTable(t, db)
+	_, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS shared_notes")
+	_, _ = db.ExecContext(ctx, "DELETE FROM _dbkit_migrations WHERE id = 'shared_notes_001'")
+	t.Cleanup(func() {
+		_, _ = db.ExecContext(ctx, "DROP TABLE IF EXISTS shared_notes")
+		_, _ = db.ExecContext(ctx, "DELETE FROM _dbkit_migrations WHERE id = 'shared_notes_001'")
+	})
+
+	// CREATE TABLE without IF NOT EXISTS fails if it runs for the second tenant
+	tm := NewTenantManager(db, []Migration{
+		{ID: "shared_notes_001", SQL: "CREATE TABLE shared_notes (id SERIAL PRIMARY KEY, tenant_id UUID)"},
+	}, TenantManagerConfig{})
+
+	for _, subdomain := range []string{"acme", "globex"} {
+		if _, err := tm.Create(ctx, subdomain, subdomain); err != nil {
+			t.Fatalf("Create %s failed: %v", subdomain, err)
+		}
+	}
+}
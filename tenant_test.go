@@ -2,7 +2,11 @@ package dbkit
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/uptrace/bun"
 )
 
 func TestWithTenant(t *testing.T) {
@@ -157,3 +161,94 @@ func TestTenant_Model(t *testing.T) {
 		t.Errorf("Expected Name 'Test Tenant', got %s", tenant.Name)
 	}
 }
+
+type tenantNote struct {
+	bun.BaseModel `bun:"table:test_tenant_notes,alias:tn"`
+	ID            string `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	Body          string `bun:"body,notnull"`
+	TenantModel
+}
+
+func TestTenantHelpers_NoTenant(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	if _, err := TenantFindByID[tenantNote](ctx, db, "id"); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("TenantFindByID: expected ErrNoTenant, got %v", err)
+	}
+	if err := TenantCreate(ctx, db, &tenantNote{}); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("TenantCreate: expected ErrNoTenant, got %v", err)
+	}
+	if err := TenantUpdate(ctx, db, &tenantNote{ID: "id"}); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("TenantUpdate: expected ErrNoTenant, got %v", err)
+	}
+	if err := TenantDelete[tenantNote](ctx, db, "id"); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("TenantDelete: expected ErrNoTenant, got %v", err)
+	}
+}
+
+func TestTenantUpdate_KeepsTenant(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	note := &tenantNote{ID: "id", TenantModel: TenantModel{TenantID: "tenant-b"}}
+	err := TenantUpdate(WithTenant(context.Background(), "tenant-a"), db, note)
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found from the fake database, got %v", err)
+	}
+	if note.TenantID != "tenant-a" {
+		t.Errorf("Expected tenant reset to tenant-a, got %s", note.TenantID)
+	}
+	if !strings.Contains(hook.last, `"tenant_id" = 'tenant-a'`) || !strings.Contains(hook.last, `(tenant_id = 'tenant-a')`) {
+		t.Errorf("Expected update scoped to tenant-a, got %s", hook.last)
+	}
+}
+
+func TestTenantCRUD_Isolation(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.NewDropTable().Model((*tenantNote)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*tenantNote)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*tenantNote)(nil)).IfExists().Exec(ctx)
+
+	ctxA := WithTenant(ctx, "tenant-a")
+	ctxB := WithTenant(ctx, "tenant-b")
+
+	note := &tenantNote{Body: "secret"}
+	if err := TenantCreate(ctxA, db, note); err != nil {
+		t.Fatalf("TenantCreate failed: %v", err)
+	}
+	if note.TenantID != "tenant-a" {
+		t.Errorf("Expected tenant-a, got %s", note.TenantID)
+	}
+
+	if _, err := TenantFindByID[tenantNote](ctxA, db, note.ID); err != nil {
+		t.Errorf("Expected owner to find the note, got %v", err)
+	}
+
+	// Another tenant sees records of tenant A as missing
+	if _, err := TenantFindByID[tenantNote](ctxB, db, note.ID); !IsNotFound(err) {
+		t.Errorf("Expected not found for tenant B, got %v", err)
+	}
+	if err := TenantUpdate(ctxB, db, &tenantNote{ID: note.ID, Body: "stolen"}); !IsNotFound(err) {
+		t.Errorf("Expected not found updating as tenant B, got %v", err)
+	}
+	if err := TenantDelete[tenantNote](ctxB, db, note.ID); !IsNotFound(err) {
+		t.Errorf("Expected not found deleting as tenant B, got %v", err)
+	}
+
+	found, err := TenantFindByID[tenantNote](ctxA, db, note.ID)
+	if err != nil || found.Body != "secret" {
+		t.Fatalf("Expected note untouched, got %v, %v", found, err)
+	}
+	if err := TenantDelete[tenantNote](ctxA, db, note.ID); err != nil {
+		t.Errorf("Expected owner to delete the note, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// TransferRecords moves the records of T matching queryFn from one tenant to another, in a
+// single UPDATE. It ignores the tenant in context, so restrict it to admin tools.
+// Returns the number of records moved.
+//
+// Usage:
+//
+//	moved, err := dbkit.TransferRecords[Project](ctx, db, oldTenantID, newTenantID, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+//	    return q.Where("owner_id = ?", userID)
+//	})
+func TransferRecords[T any](ctx context.Context, db *DBKit, fromTenantID, toTenantID string, queryFn func(*bun.UpdateQuery) *bun.UpdateQuery) (int64, error) {
+	if err := validateTransfer(fromTenantID, toTenantID, "TransferRecords"); err != nil {
+		return 0, err
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	q := db.NewUpdate().
+		Model((*T)(nil)).
+		Set("tenant_id = ?", toTenantID).
+		Where("tenant_id = ?", fromTenantID)
+	if _, ok := table.FieldMap["updated_at"]; ok {
+		q = q.Set("updated_at = NOW()")
+	}
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "TransferRecords")
+	}
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+// CopyRecords copies the records of T matching queryFn from one tenant to another with
+// INSERT ... SELECT. Copies get new primary keys: generated ones (autoincrement, identity or
+// a column default) are left to the database, UUID ones without a default are set to
+// gen_random_uuid(), and other primary keys are rejected with a validation error. It ignores
+// the tenant in context, so restrict it to admin tools. Returns the number of records copied.
+//
+// Usage:
+//
+//	copied, err := dbkit.CopyRecords[Template](ctx, db, demoTenantID, newTenantID, nil)
+func CopyRecords[T any](ctx context.Context, db *DBKit, fromTenantID, toTenantID string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) (int64, error) {
+	if err := validateTransfer(fromTenantID, toTenantID, "CopyRecords"); err != nil {
+		return 0, err
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeFor[T]())
+	var columns []bun.Safe
+	sel := db.NewSelect().Model((*T)(nil))
+	for _, field := range table.Fields {
+		switch {
+		case field.IsPK && (field.AutoIncrement || field.Identity || field.SQLDefault != ""):
+			continue
+		case field.IsPK && strings.EqualFold(field.CreateTableSQLType, "uuid"):
+			sel = sel.ColumnExpr("gen_random_uuid()")
+		case field.IsPK:
+			return 0, &Error{
+				Code:    CodeValidation,
+				Message: fmt.Sprintf("cannot generate a new %s primary key, give it a default", field.CreateTableSQLType),
+				Op:      "CopyRecords",
+				Table:   table.Name,
+				Column:  field.Name,
+			}
+		case field.Name == "tenant_id":
+			sel = sel.ColumnExpr("?", toTenantID)
+		default:
+			sel = sel.ColumnExpr("?", field.SQLName)
+		}
+		columns = append(columns, field.SQLName)
+	}
+	sel = sel.Where("tenant_id = ?", fromTenantID)
+	if queryFn != nil {
+		sel = queryFn(sel)
+	}
+
+	result, err := db.NewRaw("INSERT INTO ? (?) ?", table.SQLName, bun.In(columns), sel).Exec(ctx)
+	if err != nil {
+		return 0, wrapError(err, "CopyRecords")
+	}
+	rows, _ := result.RowsAffected()
+	return rows, nil
+}
+
+func validateTransfer(fromTenantID, toTenantID, op string) error {
+	if fromTenantID == "" || toTenantID == "" {
+		return &Error{Code: CodeValidation, Message: "source and target tenants are required", Op: op, Cause: ErrNoTenant}
+	}
+	if fromTenantID == toTenantID {
+		return &Error{Code: CodeValidation, Message: "source and target tenants are the same", Op: op}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/uptrace/bun"
+)
+
+func TestTransferRecords_Validation(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	if _, err := TransferRecords[tenantNote](ctx, db, "", "b", nil); !IsValidation(err) {
+		t.Errorf("Expected validation error for a missing tenant, got %v", err)
+	}
+	if _, err := CopyRecords[tenantNote](ctx, db, "a", "a", nil); !IsValidation(err) {
+		t.Errorf("Expected validation error for the same tenant, got %v", err)
+	}
+}
+
+func TestCopyRecords_SQL(t *testing.T) {
+	db, _ := newFakeDB(t)
+	hook := &queryRecorderHook{}
+	db.AddQueryHook(hook)
+
+	if _, err := CopyRecords[tenantNote](WithTenant(context.Background(), "other"), db, "a", "b", nil); err != nil {
+		t.Fatalf("CopyRecords failed: %v", err)
+	}
+	want := `INSERT INTO "test_tenant_notes" ("body", "tenant_id") SELECT "body", 'b' FROM "test_tenant_notes" AS "tn" WHERE (tenant_id = 'a')`
+	if hook.last != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", hook.last, want)
+	}
+
+	type uuidNote struct {
+		bun.BaseModel `bun:"table:uuid_notes"`
+		ID            string `bun:"id,pk,type:uuid"`
+		TenantModel
+	}
+	if _, err := CopyRecords[uuidNote](context.Background(), db, "a", "b", nil); err != nil {
+		t.Fatalf("CopyRecords failed: %v", err)
+	}
+	want = `INSERT INTO "uuid_notes" ("id", "tenant_id") SELECT gen_random_uuid(), 'b' FROM "uuid_notes" AS "uuid_note" WHERE (tenant_id = 'a')`
+	if hook.last != want {
+		t.Errorf("Unexpected SQL:\n got %s\nwant %s", hook.last, want)
+	}
+
+	type codeNote struct {
+		bun.BaseModel `bun:"table:code_notes"`
+		Code          string `bun:"code,pk"`
+		TenantModel
+	}
+	if _, err := CopyRecords[codeNote](context.Background(), db, "a", "b", nil); !IsValidation(err) {
+		t.Errorf("Expected a validation error for a primary key that can't be generated, got %v", err)
+	}
+}
+
+func TestTenantIsolation_AdminBypass(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ti := NewTenantIsolation(db, DefaultTenantConfig())
+	ctx := WithTenant(context.Background(), "tenant-a")
+
+	if sql := ti.Select(ctx).Model((*tenantNote)(nil)).String(); !strings.Contains(sql, "tenant_id = 'tenant-a'") {
+		t.Errorf("Expected tenant filter, got %s", sql)
+	}
+	if sql := ti.Select(WithAdminBypass(ctx)).Model((*tenantNote)(nil)).String(); strings.Contains(sql, "WHERE") {
+		t.Errorf("Expected no tenant filter with admin bypass, got %s", sql)
+	}
+}
+
+func TestTransferAndCopyRecords(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.NewDropTable().Model((*tenantNote)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Failed to drop table: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*tenantNote)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	defer db.NewDropTable().Model((*tenantNote)(nil)).IfExists().Exec(ctx)
+
+	notes := make([]tenantNote, 4)
+	for i := range notes {
+		notes[i] = tenantNote{Body: "note", TenantModel: TenantModel{TenantID: "tenant-a"}}
+	}
+	notes[3].Body = "keep"
+	if _, err := db.NewInsert().Model(&notes).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	moved, err := TransferRecords[tenantNote](ctx, db, "tenant-a", "tenant-b", func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("body = ?", "note")
+	})
+	if err != nil || moved != 3 {
+		t.Fatalf("Expected 3 records transferred, got %d, %v", moved, err)
+	}
+	for _, n := range notes[:3] {
+		found, err := TenantFindByID[tenantNote](WithTenant(ctx, "tenant-b"), db, n.ID)
+		if err != nil || found.TenantID != "tenant-b" {
+			t.Errorf("Expected %s to belong to tenant-b, got %v, %v", n.ID, found, err)
+		}
+	}
+	if left, _ := Count[tenantNote](ctx, db, TenantScope(WithTenant(ctx, "tenant-a"))); left != 1 {
+		t.Errorf("Expected 1 record left in tenant-a, got %d", left)
+	}
+
+	copied, err := CopyRecords[tenantNote](ctx, db, "tenant-b", "tenant-c", nil)
+	if err != nil || copied != 3 {
+		t.Fatalf("Expected 3 records copied, got %d, %v", copied, err)
+	}
+	var copies []tenantNote
+	if err := db.NewSelect().Model(&copies).Where("tenant_id = ?", "tenant-c").Scan(ctx); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	for _, c := range copies {
+		for _, n := range notes {
+			if c.ID == n.ID {
+				t.Errorf("Expected copies to get new IDs, %s was reused", c.ID)
+			}
+		}
+	}
+}
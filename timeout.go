@@ -0,0 +1,52 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MustWithinTimeout runs fn and returns its error, or a timeout error if fn takes longer than timeout
+// or ctx reaches its deadline first. A canceled ctx returns context.Canceled as is. fn is not
+// interrupted and its result is discarded after a timeout; queries it runs should use a context
+// from hooks.WithQueryTimeout to stop them as well.
+//
+// Usage:
+//
+//	err := dbkit.MustWithinTimeout(ctx, 200*time.Millisecond, func() error {
+//	    return db.NewSelect().Model(&user).Where("id = ?", id).Scan(ctx)
+//	})
+//	if dbkit.IsTimeout(err) {
+//	    // Serve from cache
+//	}
+func MustWithinTimeout(ctx context.Context, timeout time.Duration, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return &Error{
+			Code:    CodeTimeout,
+			Message: fmt.Sprintf("operation did not finish within %s", timeout),
+			Op:      "MustWithinTimeout",
+		}
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ctx.Err()
+		}
+		return &Error{
+			Code:    CodeTimeout,
+			Message: "context deadline exceeded before the operation finished",
+			Op:      "MustWithinTimeout",
+			Cause:   ctx.Err(),
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := hooks.WithQueryTimeout(context.Background(), 10*time.Millisecond)
+	if _, err := db.ExecContext(ctx, "SELECT pg_sleep(0.001)"); err != nil {
+		t.Fatalf("Expected a fast query to succeed, got %v", err)
+	}
+
+	start := time.Now()
+	_, err := db.ExecContext(ctx, "SELECT pg_sleep(1)")
+	err = WrapError(err, "Exec")
+	var dbErr *Error
+	if !errors.As(err, &dbErr) || dbErr.Code != CodeTimeout {
+		t.Fatalf("Expected CodeTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the query to stop at its timeout, took %s", elapsed)
+	}
+
+	// The timeout applies per query, not to the whole context
+	if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+		t.Errorf("Expected the next query to get its own deadline, got %v", err)
+	}
+}
+
+func TestQueryTimeoutHook(t *testing.T) {
+	hook := hooks.NewQueryTimeoutHook()
+	ctx := hooks.WithQueryTimeout(context.Background(), time.Minute)
+	db, _ := newFakeDB(t)
+
+	event := &bun.QueryEvent{IQuery: db.NewSelect().TableExpr("t"), Query: "SELECT * FROM t"}
+	queryCtx := hook.BeforeQuery(ctx, event)
+	if deadline, ok := queryCtx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Fatalf("Expected a deadline within a minute, got %v, %v", deadline, ok)
+	}
+	event.Result = driver.RowsAffected(1)
+	hook.AfterQuery(queryCtx, event)
+	if !errors.Is(queryCtx.Err(), context.Canceled) {
+		t.Errorf("Expected AfterQuery to cancel the query context, got %v", queryCtx.Err())
+	}
+
+	// SelectQuery.Rows returns its rows open, after AfterQuery
+	rows := &bun.QueryEvent{IQuery: db.NewSelect().TableExpr("t"), Query: "SELECT * FROM t"}
+	rowsCtx := hook.BeforeQuery(ctx, rows)
+	hook.AfterQuery(rowsCtx, rows)
+	if rowsCtx.Err() != nil {
+		t.Errorf("Expected the context of open rows to stay open, got %v", rowsCtx.Err())
+	}
+
+	begin := &bun.QueryEvent{Query: "BEGIN"}
+	if _, ok := hook.BeforeQuery(ctx, begin).Deadline(); ok {
+		t.Error("Expected BEGIN to keep the transaction context")
+	}
+
+	// Rows of raw queries are read after AfterQuery
+	raw := &bun.QueryEvent{Query: "SELECT 1"}
+	rawCtx := hook.BeforeQuery(ctx, raw)
+	hook.AfterQuery(rawCtx, raw)
+	if rawCtx.Err() != nil {
+		t.Errorf("Expected raw query context to stay open, got %v", rawCtx.Err())
+	}
+
+	if _, ok := hook.BeforeQuery(context.Background(), event).Deadline(); ok {
+		t.Error("Expected no deadline without WithQueryTimeout")
+	}
+}
+
+func TestMustWithinTimeout(t *testing.T) {
+	ctx := context.Background()
+	errFn := errors.New("fn failed")
+
+	if err := MustWithinTimeout(ctx, time.Second, func() error { return errFn }); !errors.Is(err, errFn) {
+		t.Errorf("Expected the error of fn, got %v", err)
+	}
+
+	err := MustWithinTimeout(ctx, 10*time.Millisecond, func() error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+	if !IsTimeout(err) {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = MustWithinTimeout(canceled, time.Second, func() error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+	if IsTimeout(err) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation to be passed through, got %v", err)
+	}
+
+	expired, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err = MustWithinTimeout(expired, time.Second, func() error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+	if !IsTimeout(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout error caused by the deadline, got %v", err)
+	}
+}
+
+func TestWrapError_Deadline(t *testing.T) {
+	if err := WrapError(context.DeadlineExceeded, "Find"); !IsTimeout(err) {
+		t.Errorf("Expected context.DeadlineExceeded to be a timeout, got %v", err)
+	}
+}
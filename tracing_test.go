@@ -0,0 +1,203 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fernandezvara/dbkit/hooks"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// startedSpan records the name and start attributes of a span.
+type startedSpan struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// recordingTracer is a noop tracer that remembers the spans it starts.
+type recordingTracer struct {
+	noop.Tracer
+	mu    sync.Mutex
+	spans []startedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	r.mu.Lock()
+	r.spans = append(r.spans, startedSpan{name: name, attrs: config.Attributes()})
+	r.mu.Unlock()
+	return r.Tracer.Start(ctx, name, opts...)
+}
+
+func TestTracingHook_SpanAttributesFromContext(t *testing.T) {
+	tracer := &recordingTracer{}
+	cfg := DefaultConfig("fake").WithTracing(tracer)
+	db, err := newDBKit(cfg, &fakeConnector{})
+	if err != nil {
+		t.Fatalf("newDBKit failed: %v", err)
+	}
+	defer db.Close()
+
+	ctx := hooks.WithSpanAttributes(context.Background(), attribute.String("tenant.id", "acme"))
+	ctx = hooks.WithSpanAttributes(ctx, attribute.Int("user.id", 42))
+	ctx = hooks.WithDBName(ctx, "orders")
+
+	if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "db.select" {
+		t.Errorf("Expected span name db.select, got %q", span.name)
+	}
+
+	got := attribute.NewSet(span.attrs...)
+	for _, want := range []attribute.KeyValue{
+		attribute.String("tenant.id", "acme"),
+		attribute.Int("user.id", 42),
+		attribute.String("db.name", "orders"),
+	} {
+		if v, ok := got.Value(want.Key); !ok || v != want.Value {
+			t.Errorf("Expected attribute %s=%v, got %v", want.Key, want.Value.Emit(), span.attrs)
+		}
+	}
+}
+
+func TestTracingHook_SpanNameFormatter(t *testing.T) {
+	tracer := &recordingTracer{}
+	hook := hooks.NewTracingHook(tracer, hooks.TracingHookOptions{
+		SpanNameFormatter: func(event *bun.QueryEvent) string {
+			return "orders." + hooks.OperationType(event.Query)
+		},
+	})
+
+	event := &bun.QueryEvent{Query: "INSERT INTO orders DEFAULT VALUES"}
+	hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+
+	if len(tracer.spans) != 1 || tracer.spans[0].name != "orders.insert" {
+		t.Errorf("Expected span orders.insert, got %+v", tracer.spans)
+	}
+	if len(tracer.spans[0].attrs) != 0 {
+		t.Errorf("Expected no context attributes, got %v", tracer.spans[0].attrs)
+	}
+}
+
+// recordedSpan is a recording noop span that keeps the attributes set after start.
+type recordedSpan struct {
+	noop.Span
+	attrs []attribute.KeyValue
+}
+
+func (s *recordedSpan) IsRecording() bool { return true }
+
+func (s *recordedSpan) SetAttributes(kv ...attribute.KeyValue) { s.attrs = append(s.attrs, kv...) }
+
+// spanRecordingTracer starts recordedSpans.
+type spanRecordingTracer struct {
+	noop.Tracer
+	spans []*recordedSpan
+}
+
+func (r *spanRecordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	span := &recordedSpan{attrs: config.Attributes()}
+	r.spans = append(r.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func baggageContext(t *testing.T, pairs ...string) context.Context {
+	t.Helper()
+	var members []baggage.Member
+	for i := 0; i < len(pairs); i += 2 {
+		m, err := baggage.NewMember(pairs[i], pairs[i+1])
+		if err != nil {
+			t.Fatalf("NewMember failed: %v", err)
+		}
+		members = append(members, m)
+	}
+	bag, err := baggage.New(members...)
+	if err != nil {
+		t.Fatalf("baggage.New failed: %v", err)
+	}
+	return baggage.ContextWithBaggage(context.Background(), bag)
+}
+
+func TestTracingHook_Baggage(t *testing.T) {
+	ctx := baggageContext(t, "user_id", "42", "tenant_id", "acme", "session", "s1")
+	event := &bun.QueryEvent{Query: "SELECT 1"}
+
+	tracer := &spanRecordingTracer{}
+	hook := hooks.NewTracingHook(tracer, hooks.TracingHookOptions{})
+	hook.AfterQuery(hook.BeforeQuery(ctx, event), event)
+
+	got := attribute.NewSet(tracer.spans[0].attrs...)
+	for key, want := range map[string]string{"baggage.user_id": "42", "baggage.tenant_id": "acme", "baggage.session": "s1"} {
+		if v, ok := got.Value(attribute.Key(key)); !ok || v.AsString() != want {
+			t.Errorf("Expected %s=%s, got %v", key, want, tracer.spans[0].attrs)
+		}
+	}
+
+	tracer = &spanRecordingTracer{}
+	hook = hooks.NewTracingHook(tracer, hooks.TracingHookOptions{BaggageKeys: []string{"tenant_id", "missing"}})
+	hook.AfterQuery(hook.BeforeQuery(ctx, event), event)
+
+	got = attribute.NewSet(tracer.spans[0].attrs...)
+	if v, ok := got.Value("baggage.tenant_id"); !ok || v.AsString() != "acme" {
+		t.Errorf("Expected whitelisted baggage.tenant_id, got %v", tracer.spans[0].attrs)
+	}
+	for _, key := range []attribute.Key{"baggage.user_id", "baggage.session", "baggage.missing"} {
+		if got.HasValue(key) {
+			t.Errorf("Expected %s to be filtered out, got %v", key, tracer.spans[0].attrs)
+		}
+	}
+}
+
+func TestTracingHook_QueryOptions(t *testing.T) {
+	long := "SELECT " + strings.Repeat("1, ", 400) + "1"
+	event := &bun.QueryEvent{Query: long, QueryArgs: []any{42, "alice"}}
+
+	tracer := &spanRecordingTracer{}
+	hook := hooks.NewTracingHook(tracer, hooks.TracingHookOptions{})
+	hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+
+	got := attribute.NewSet(tracer.spans[0].attrs...)
+	if v, _ := got.Value("db.statement"); len(v.AsString()) != 503 {
+		t.Errorf("Expected the default 500 character limit, got %d", len(v.AsString()))
+	}
+	if got.HasValue("db.statement.parameters") {
+		t.Error("Expected no parameters unless enabled")
+	}
+
+	tracer = &spanRecordingTracer{}
+	hook = hooks.NewTracingHook(tracer, hooks.TracingHookOptions{MaxQueryLength: 20, RecordQueryParameters: true})
+	hook.AfterQuery(hook.BeforeQuery(context.Background(), event), event)
+
+	got = attribute.NewSet(tracer.spans[0].attrs...)
+	if v, _ := got.Value("db.statement"); v.AsString() != long[:20]+"..." {
+		t.Errorf("Expected a 20 character statement, got %q", v.AsString())
+	}
+	if v, _ := got.Value("db.statement.parameters"); fmt.Sprint(v.AsStringSlice()) != "[42 alice]" {
+		t.Errorf("Expected recorded parameters, got %v", v.AsStringSlice())
+	}
+}
+
+func TestTracingHook_NilAndNoopTracers(t *testing.T) {
+	ctx := baggageContext(t, "user_id", "42")
+	event := &bun.QueryEvent{Query: "SELECT 1", QueryArgs: []any{1}}
+	opts := hooks.TracingHookOptions{RecordQueryParameters: true}
+
+	for _, tracer := range []trace.Tracer{nil, noop.NewTracerProvider().Tracer("test")} {
+		hook := hooks.NewTracingHook(tracer, opts)
+		hook.AfterQuery(hook.BeforeQuery(ctx, event), event)
+	}
+}
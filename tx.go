@@ -4,17 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"sync/atomic"
 
 	"github.com/uptrace/bun"
+
+	"github.com/fernandezvara/dbkit/hooks"
 )
 
 // Tx wraps bun.Tx with additional functionality
 type Tx struct {
 	bun.Tx
 	db           *DBKit
+	ctx          context.Context
 	savepointID  int64
-	savepointSeq *int64 // Shared across nested transactions
+	savepointSeq *int64     // Shared across nested transactions
+	tracker      *txTracker // Shared across nested transactions
 }
 
 // Ensure Tx implements IDB
@@ -24,6 +29,7 @@ var _ IDB = (*Tx)(nil)
 type TxOptions struct {
 	Isolation sql.IsolationLevel
 	ReadOnly  bool
+	Retry     *RetryPolicy // Retry the whole transaction on transient errors (nil = policy of the context, see WithRetryPolicy)
 }
 
 // DefaultTxOptions returns default transaction options
@@ -50,7 +56,10 @@ func SerializableTxOptions() TxOptions {
 	}
 }
 
-// TxFunc is a function executed within a transaction
+// TxFunc is a function executed within a transaction.
+// It should run its queries with tx.Context() rather than a context captured from
+// the caller: inside a retried transaction, only tx.Context() stops nested
+// operations from retrying on their own.
 type TxFunc func(tx *Tx) error
 
 // Transaction executes fn within a transaction with automatic commit/rollback
@@ -58,9 +67,20 @@ func (db *DBKit) Transaction(ctx context.Context, fn TxFunc) error {
 	return db.TransactionWithOptions(ctx, DefaultTxOptions(), fn)
 }
 
-// TransactionWithOptions executes fn within a transaction with custom options
+// TransactionWithOptions executes fn within a transaction with custom options.
+// With a retry policy, fn runs again in a new transaction after a transient error.
 func (db *DBKit) TransactionWithOptions(ctx context.Context, opts TxOptions, fn TxFunc) error {
-	bunTx, err := db.BeginTx(ctx, &sql.TxOptions{
+	if policy, ok := txRetryPolicy(ctx, opts); ok {
+		return RetryableOperation(ctx, policy, func(ctx context.Context) error {
+			return db.transaction(ctx, opts, fn)
+		})
+	}
+	return db.transaction(ctx, opts, fn)
+}
+
+// transaction runs fn in a single transaction
+func (db *DBKit) transaction(ctx context.Context, opts TxOptions, fn TxFunc) error {
+	bunTx, err := db.BeginTx(hooks.WithIsolationLevel(ctx, isolationLabel(opts.Isolation)), &sql.TxOptions{
 		Isolation: opts.Isolation,
 		ReadOnly:  opts.ReadOnly,
 	})
@@ -72,7 +92,9 @@ func (db *DBKit) TransactionWithOptions(ctx context.Context, opts TxOptions, fn
 	tx := &Tx{
 		Tx:           bunTx,
 		db:           db,
+		ctx:          ctx,
 		savepointSeq: &seq,
+		tracker:      &txTracker{},
 	}
 
 	defer func() {
@@ -96,11 +118,30 @@ func (db *DBKit) TransactionWithOptions(ctx context.Context, opts TxOptions, fn
 	return nil
 }
 
+// isolationLabel returns level as a metric label, e.g. "read_committed"
+func isolationLabel(level sql.IsolationLevel) string {
+	return strings.ReplaceAll(strings.ToLower(level.String()), " ", "_")
+}
+
 // ReadOnlyTransaction executes fn within a read-only transaction
 func (db *DBKit) ReadOnlyTransaction(ctx context.Context, fn TxFunc) error {
 	return db.TransactionWithOptions(ctx, ReadOnlyTxOptions(), fn)
 }
 
+// SerializableTransaction executes fn within a serializable transaction.
+// Serialization failures are expected at this level: set a policy with WithRetryPolicy,
+// or use TransactionWithOptions with SerializableTxOptions and Retry set.
+//
+// Usage:
+//
+//	ctx = dbkit.WithRetryPolicy(ctx, dbkit.DefaultRetryPolicy())
+//	err := db.SerializableTransaction(ctx, func(tx *dbkit.Tx) error {
+//	    return transfer(tx.Context(), tx, from, to, amount)
+//	})
+func (db *DBKit) SerializableTransaction(ctx context.Context, fn TxFunc) error {
+	return db.TransactionWithOptions(ctx, SerializableTxOptions(), fn)
+}
+
 // Begin starts a new transaction (manual control)
 func (db *DBKit) Begin(ctx context.Context) (*Tx, error) {
 	return db.BeginWithOptions(ctx, DefaultTxOptions())
@@ -108,7 +149,7 @@ func (db *DBKit) Begin(ctx context.Context) (*Tx, error) {
 
 // BeginWithOptions starts a new transaction with custom options
 func (db *DBKit) BeginWithOptions(ctx context.Context, opts TxOptions) (*Tx, error) {
-	bunTx, err := db.BeginTx(ctx, &sql.TxOptions{
+	bunTx, err := db.BeginTx(hooks.WithIsolationLevel(ctx, isolationLabel(opts.Isolation)), &sql.TxOptions{
 		Isolation: opts.Isolation,
 		ReadOnly:  opts.ReadOnly,
 	})
@@ -120,7 +161,9 @@ func (db *DBKit) BeginWithOptions(ctx context.Context, opts TxOptions) (*Tx, err
 	return &Tx{
 		Tx:           bunTx,
 		db:           db,
+		ctx:          ctx,
 		savepointSeq: &seq,
+		tracker:      &txTracker{},
 	}, nil
 }
 
@@ -158,11 +201,16 @@ func (tx *Tx) Transaction(ctx context.Context, fn TxFunc) error {
 	nestedTx := &Tx{
 		Tx:           tx.Tx,
 		db:           tx.db,
+		ctx:          ctx,
 		savepointID:  id,
 		savepointSeq: tx.savepointSeq,
+		tracker:      tx.tracker,
 	}
 
+	tracked := tx.tracker.len()
 	if err := fn(nestedTx); err != nil {
+		// Models tracked in the savepoint were rolled back with it
+		tx.tracker.truncate(tracked)
 		// Rollback to savepoint
 		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rbErr != nil {
 			return fmt.Errorf("dbkit: savepoint rollback failed: %v (original error: %w)", rbErr, err)
@@ -196,6 +244,19 @@ func (tx *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
 	return wrapError(err, "ReleaseSavepoint")
 }
 
+// Context returns the context the transaction was started with. Inside a retried
+// transaction it marks the current attempt, so operations run with it are not
+// retried again.
+//
+// Usage:
+//
+//	err := db.SerializableTransaction(ctx, func(tx *dbkit.Tx) error {
+//	    return dbkit.Create(tx.Context(), tx, &transfer)
+//	})
+func (tx *Tx) Context() context.Context {
+	return tx.ctx
+}
+
 // DB returns the parent database
 func (tx *Tx) DBKit() *DBKit {
 	return tx.db
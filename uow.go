@@ -0,0 +1,94 @@
+package dbkit
+
+import (
+	"context"
+	"sync"
+)
+
+// UnitOfWork is a transaction that collects domain events and dispatches them only after commit.
+// Events are lost if dispatching fails after a successful commit; use an outbox table written
+// in the transaction when delivery must be guaranteed.
+type UnitOfWork struct {
+	tx         *Tx
+	dispatcher func(events []any) error
+
+	mu     sync.Mutex
+	events []any
+}
+
+// BeginUnitOfWork starts a transaction whose registered events are passed to dispatcher after commit.
+//
+// Usage:
+//
+//	uow, err := db.BeginUnitOfWork(ctx, bus.Publish)
+//	if err != nil {
+//	    return err
+//	}
+//	defer uow.Rollback()
+//
+//	if err := dbkit.Create(ctx, uow.Tx(), &order); err != nil {
+//	    return err
+//	}
+//	uow.Register(OrderPlaced{OrderID: order.ID})
+//	return uow.Commit(ctx)
+func (db *DBKit) BeginUnitOfWork(ctx context.Context, dispatcher func(events []any) error) (*UnitOfWork, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, wrapError(err, "BeginUnitOfWork")
+	}
+	return &UnitOfWork{tx: tx, dispatcher: dispatcher}, nil
+}
+
+// Tx returns the transaction for queries within the unit of work
+func (uow *UnitOfWork) Tx() *Tx {
+	return uow.tx
+}
+
+// Register records an event to dispatch after commit
+func (uow *UnitOfWork) Register(event any) {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+	uow.events = append(uow.events, event)
+}
+
+// Events returns the events registered so far
+func (uow *UnitOfWork) Events() []any {
+	uow.mu.Lock()
+	defer uow.mu.Unlock()
+	return append([]any(nil), uow.events...)
+}
+
+// Commit commits the transaction, then dispatches the registered events.
+// A dispatch error is returned after the data has been committed.
+func (uow *UnitOfWork) Commit(ctx context.Context) error {
+	if err := uow.tx.Commit(); err != nil {
+		return err
+	}
+
+	uow.mu.Lock()
+	events := uow.events
+	uow.events = nil
+	uow.mu.Unlock()
+
+	if len(events) == 0 || uow.dispatcher == nil {
+		return nil
+	}
+	if err := uow.dispatcher(events); err != nil {
+		return &Error{
+			Code:    CodeUnknown,
+			Message: "transaction committed but event dispatch failed: " + err.Error(),
+			Op:      "UnitOfWork.Commit",
+			Cause:   err,
+		}
+	}
+	return nil
+}
+
+// Rollback aborts the transaction and discards the registered events.
+// It is safe to call after Commit, e.g. in a defer.
+func (uow *UnitOfWork) Rollback() error {
+	uow.mu.Lock()
+	uow.events = nil
+	uow.mu.Unlock()
+	return uow.tx.Rollback()
+}
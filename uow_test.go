@@ -0,0 +1,134 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type orderPlaced struct {
+	ID string
+}
+
+func TestUnitOfWork_DispatchAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	var dispatched []any
+	uow, err := db.BeginUnitOfWork(ctx, func(events []any) error {
+		dispatched = append(dispatched, events...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("BeginUnitOfWork failed: %v", err)
+	}
+	defer uow.Rollback()
+
+	uow.Register(orderPlaced{ID: "1"})
+	uow.Register(orderPlaced{ID: "2"})
+	if len(dispatched) != 0 {
+		t.Fatal("Expected no dispatch before commit")
+	}
+	if len(uow.Events()) != 2 {
+		t.Errorf("Expected 2 registered events, got %d", len(uow.Events()))
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if len(dispatched) != 2 || dispatched[0].(orderPlaced).ID != "1" {
+		t.Errorf("Expected both events in order, got %v", dispatched)
+	}
+
+	// A second commit fails and dispatches nothing
+	if err := uow.Commit(ctx); err == nil {
+		t.Error("Expected an error committing twice")
+	}
+	if len(dispatched) != 2 {
+		t.Errorf("Expected no further dispatch, got %v", dispatched)
+	}
+}
+
+func TestUnitOfWork_NoDispatchAfterRollback(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	calls := 0
+	uow, err := db.BeginUnitOfWork(ctx, func(events []any) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("BeginUnitOfWork failed: %v", err)
+	}
+
+	uow.Register(orderPlaced{ID: "1"})
+	if err := uow.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if err := uow.Commit(ctx); err == nil {
+		t.Error("Expected commit after rollback to fail")
+	}
+	if calls != 0 {
+		t.Errorf("Expected no dispatch after rollback, got %d", calls)
+	}
+}
+
+func TestUnitOfWork_DispatchError(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+
+	errBus := errors.New("bus unavailable")
+	uow, _ := db.BeginUnitOfWork(ctx, func(events []any) error { return errBus })
+	uow.Register(orderPlaced{ID: "1"})
+
+	err := uow.Commit(ctx)
+	if !errors.Is(err, errBus) {
+		t.Errorf("Expected the dispatch error, got %v", err)
+	}
+}
+
+func TestUnitOfWork_Integration(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	var dispatched []any
+	dispatcher := func(events []any) error {
+		dispatched = append(dispatched, events...)
+		return nil
+	}
+
+	uow, err := db.BeginUnitOfWork(ctx, dispatcher)
+	if err != nil {
+		t.Fatalf("BeginUnitOfWork failed: %v", err)
+	}
+	model := &TestModel{Name: "Committed", Email: "committed@example.com"}
+	if err := Create(ctx, uow.Tx(), model); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	uow.Register(orderPlaced{ID: model.ID})
+	if err := uow.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if exists, _ := ExistsByID[TestModel](ctx, db, model.ID); !exists || len(dispatched) != 1 {
+		t.Errorf("Expected committed row and one event, got exists=%v events=%v", exists, dispatched)
+	}
+
+	uow, err = db.BeginUnitOfWork(ctx, dispatcher)
+	if err != nil {
+		t.Fatalf("BeginUnitOfWork failed: %v", err)
+	}
+	rolledBack := &TestModel{Name: "Rolled back", Email: "rolledback@example.com"}
+	if err := Create(ctx, uow.Tx(), rolledBack); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	uow.Register(orderPlaced{ID: rolledBack.ID})
+	if err := uow.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if exists, _ := ExistsByID[TestModel](ctx, db, rolledBack.ID); exists || len(dispatched) != 1 {
+		t.Errorf("Expected no row and no new event, got exists=%v events=%v", exists, dispatched)
+	}
+}
@@ -0,0 +1,104 @@
+package dbkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// updatedAtFunction is the trigger function shared by every updated_at trigger
+const updatedAtFunction = "dbkit_set_updated_at"
+
+// UpdatedAtTriggerSQL returns DDL for a BEFORE UPDATE trigger that sets updated_at to NOW() on every
+// updated row of tableName, including updates that bypass the Bun model hooks. The DDL can be run again.
+//
+// Usage:
+//
+//	migration := dbkit.Migration{ID: "006", Description: "Track order updates", SQL: dbkit.UpdatedAtTriggerSQL("orders")}
+func UpdatedAtTriggerSQL(tableName string) string {
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION %[1]s() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS %[2]s ON %[3]s;
+CREATE TRIGGER %[2]s BEFORE UPDATE ON %[3]s
+FOR EACH ROW EXECUTE FUNCTION %[1]s();`,
+		updatedAtFunction, quoteIdent(updatedAtTriggerName(tableName)), quoteIdent(tableName))
+}
+
+// quoteIdent quotes a possibly schema-qualified identifier, as bun.Ident does
+func quoteIdent(name string) string {
+	return string(dialect.AppendIdent(nil, name, '"'))
+}
+
+// CreateUpdatedAtTrigger installs the trigger of UpdatedAtTriggerSQL on tableName, replacing an existing one.
+//
+// Usage:
+//
+//	err := dbkit.CreateUpdatedAtTrigger(ctx, db, "orders")
+func CreateUpdatedAtTrigger(ctx context.Context, db IDB, tableName string) error {
+	if _, err := db.ExecContext(ctx, UpdatedAtTriggerSQL(tableName)); err != nil {
+		return wrapError(err, "CreateUpdatedAtTrigger")
+	}
+	return nil
+}
+
+// DropUpdatedAtTrigger removes the updated_at trigger of tableName, if any.
+// The shared trigger function is kept, as other tables may use it.
+func DropUpdatedAtTrigger(ctx context.Context, db IDB, tableName string) error {
+	_, err := db.NewRaw("DROP TRIGGER IF EXISTS ? ON ?", bun.Ident(updatedAtTriggerName(tableName)), bun.Ident(tableName)).Exec(ctx)
+	if err != nil {
+		return wrapError(err, "DropUpdatedAtTrigger")
+	}
+	return nil
+}
+
+// EnsureUpdatedAtTrigger creates the updated_at trigger of tableName unless it already exists.
+//
+// Usage:
+//
+//	for _, table := range []string{"users", "orders"} {
+//	    if err := dbkit.EnsureUpdatedAtTrigger(ctx, db, table); err != nil {
+//	        return err
+//	    }
+//	}
+func EnsureUpdatedAtTrigger(ctx context.Context, db IDB, tableName string) error {
+	exists, err := updatedAtTriggerExists(ctx, db, tableName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return CreateUpdatedAtTrigger(ctx, db, tableName)
+}
+
+// updatedAtTriggerExists looks the trigger of tableName up in information_schema.triggers.
+// Unqualified table names are looked up in the current schema.
+func updatedAtTriggerExists(ctx context.Context, db IDB, tableName string) (bool, error) {
+	schemaName, table := "", tableName
+	if i := strings.LastIndex(tableName, "."); i >= 0 {
+		schemaName, table = tableName[:i], tableName[i+1:]
+	}
+
+	var triggers []string
+	err := db.NewRaw(`
+        SELECT trigger_name FROM information_schema.triggers
+        WHERE trigger_name = ? AND event_object_table = ?
+          AND event_object_schema = COALESCE(NULLIF(?, ''), current_schema())
+    `, updatedAtTriggerName(tableName), table, schemaName).Scan(ctx, &triggers)
+	if err != nil {
+		return false, wrapError(err, "EnsureUpdatedAtTrigger")
+	}
+	return len(triggers) > 0, nil
+}
+
+// updatedAtTriggerName returns the trigger name of tableName, trigger names are never schema-qualified
+func updatedAtTriggerName(tableName string) string {
+	return tableName[strings.LastIndex(tableName, ".")+1:] + "_updated_at_trigger"
+}
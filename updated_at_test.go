@@ -0,0 +1,88 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdatedAtTriggerSQL(t *testing.T) {
+	ddl := UpdatedAtTriggerSQL("billing.invoices")
+
+	for _, want := range []string{
+		"CREATE OR REPLACE FUNCTION dbkit_set_updated_at() RETURNS trigger",
+		"NEW.updated_at = NOW();",
+		`DROP TRIGGER IF EXISTS "invoices_updated_at_trigger" ON "billing"."invoices";`,
+		`CREATE TRIGGER "invoices_updated_at_trigger" BEFORE UPDATE ON "billing"."invoices"`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("Expected DDL to contain %q, got:\n%s", want, ddl)
+		}
+	}
+
+	// Table names are quoted, never interpolated as SQL
+	ddl = UpdatedAtTriggerSQL(`orders; DROP TABLE users; --`)
+	if !strings.Contains(ddl, `ON "orders; DROP TABLE users; --";`) {
+		t.Errorf("Expected the table name to be quoted, got:\n%s", ddl)
+	}
+}
+
+func TestAutoMigrate_UpdatedAtTriggerDryRun(t *testing.T) {
+	db, _ := newFakeDB(t)
+	ctx := context.Background()
+
+	stmts, err := db.AutoMigrateWithOptions(ctx, AutoMigrateOptions{DryRun: true, AddUpdatedAtTriggers: true},
+		(*TestModel)(nil), (*Tenant)(nil))
+	if err != nil {
+		t.Fatalf("AutoMigrateWithOptions failed: %v", err)
+	}
+	if len(stmts) != 4 || stmts[1] != UpdatedAtTriggerSQL("public.test_models") || stmts[3] != UpdatedAtTriggerSQL("public.tenants") {
+		t.Errorf("Expected a trigger after each CREATE TABLE, got %v", stmts)
+	}
+
+	stmts, err = db.AutoMigrateWithOptions(ctx, AutoMigrateOptions{DryRun: true}, (*TestModel)(nil))
+	if err != nil || len(stmts) != 1 {
+		t.Errorf("Expected no trigger by default, got %v, %v", stmts, err)
+	}
+}
+
+func TestUpdatedAtTrigger(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	past := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	model := TestModel{Name: "Alice", Email: "alice@example.com", UpdatedAt: past}
+	if _, err := db.NewInsert().Model(&model).Exec(ctx); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	if err := EnsureUpdatedAtTrigger(ctx, db, "test_models"); err != nil {
+		t.Fatalf("EnsureUpdatedAtTrigger failed: %v", err)
+	}
+	defer func() { _ = DropUpdatedAtTrigger(ctx, db, "test_models") }()
+	if err := EnsureUpdatedAtTrigger(ctx, db, "test_models"); err != nil {
+		t.Fatalf("EnsureUpdatedAtTrigger with an existing trigger failed: %v", err)
+	}
+
+	// Raw update, no model hooks involved
+	if _, err := db.NewUpdate().Table("test_models").Set("name = ?", "Bob").Where("id = ?", model.ID).Exec(ctx); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	found, err := FindByID[TestModel](ctx, db, model.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if !found.UpdatedAt.After(past) {
+		t.Errorf("Expected the trigger to update updated_at, got %v", found.UpdatedAt)
+	}
+
+	if err := DropUpdatedAtTrigger(ctx, db, "test_models"); err != nil {
+		t.Fatalf("DropUpdatedAtTrigger failed: %v", err)
+	}
+	exists, err := updatedAtTriggerExists(ctx, db, "test_models")
+	if err != nil || exists {
+		t.Errorf("Expected the trigger to be dropped, got %v, %v", exists, err)
+	}
+}
@@ -0,0 +1,120 @@
+package dbkit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// uuidV7State makes IDs generated within the same millisecond increase,
+// using the 12 bit rand_a field as a counter (RFC 9562, method 3).
+var uuidV7State struct {
+	mu     sync.Mutex
+	lastMs int64
+	seq    uint16
+}
+
+// NewUUIDv7 returns a time-ordered UUID version 7. IDs from this process sort in
+// generation order, IDs from different processes sort by millisecond.
+//
+// Usage:
+//
+//	id, err := dbkit.NewUUIDv7()
+func NewUUIDv7() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	ms, seq := nextUUIDv7Time(binary.BigEndian.Uint16(b[6:8]))
+
+	// 48 bit big-endian millisecond timestamp
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8) // version 7
+	b[7] = byte(seq)
+	b[8] = 0x80 | b[8]&0x3f // variant 10
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:]), nil
+}
+
+// nextUUIDv7Time returns the timestamp and counter for the next ID.
+// A new millisecond starts the counter at a random value in its lower half.
+func nextUUIDv7Time(random uint16) (int64, uint16) {
+	uuidV7State.mu.Lock()
+	defer uuidV7State.mu.Unlock()
+
+	ms := time.Now().UnixMilli()
+	if ms > uuidV7State.lastMs {
+		uuidV7State.lastMs = ms
+		uuidV7State.seq = random & 0x7ff
+	} else {
+		// Same millisecond or clock moved backwards: keep increasing
+		uuidV7State.seq++
+		if uuidV7State.seq > 0xfff {
+			uuidV7State.lastMs++
+			uuidV7State.seq = random & 0x7ff
+		}
+	}
+	return uuidV7State.lastMs, uuidV7State.seq
+}
+
+// UUIDv7BaseModel is BaseModel with time-ordered UUID v7 IDs generated in Go on insert,
+// which keeps primary key indexes compact on insertion-heavy tables.
+//
+// Usage:
+//
+//	type Event struct {
+//	    bun.BaseModel `bun:"table:events,alias:e"`
+//	    dbkit.UUIDv7BaseModel
+//	    Type string `bun:"type,notnull"`
+//	}
+type UUIDv7BaseModel struct {
+	ID        string    `bun:"id,pk,type:uuid,default:gen_random_uuid()"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// BeforeAppendModel is a Bun hook that sets a UUID v7 ID and timestamps on insert.
+var _ bun.BeforeAppendModelHook = (*UUIDv7BaseModel)(nil)
+
+func (m *UUIDv7BaseModel) BeforeAppendModel(ctx context.Context, query schema.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		if m.ID == "" {
+			id, err := NewUUIDv7()
+			if err != nil {
+				return err
+			}
+			m.ID = id
+		}
+		now := time.Now()
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.UpdatedAt = now
+	case *bun.UpdateQuery:
+		m.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package dbkit
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+type TestEvent struct {
+	bun.BaseModel `bun:"table:test_events,alias:te"`
+	UUIDv7BaseModel
+	Type string `bun:"type,notnull"`
+}
+
+func TestNewUUIDv7_Format(t *testing.T) {
+	id, err := NewUUIDv7()
+	if err != nil {
+		t.Fatalf("NewUUIDv7 failed: %v", err)
+	}
+	if !uuidV7Pattern.MatchString(id) {
+		t.Errorf("Expected a version 7 UUID, got %s", id)
+	}
+}
+
+func TestNewUUIDv7_Sorted(t *testing.T) {
+	ids := make([]string, 1000)
+	seen := make(map[string]bool, len(ids))
+	for i := range ids {
+		id, err := NewUUIDv7()
+		if err != nil {
+			t.Fatalf("NewUUIDv7 failed: %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate ID %s", id)
+		}
+		seen[id] = true
+		ids[i] = id
+	}
+	if !slices.IsSorted(ids) {
+		t.Error("Expected IDs to be lexicographically sorted in generation order")
+	}
+}
+
+func TestNewUUIDv7_Timestamp(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id, _ := NewUUIDv7()
+	after := time.Now().UnixMilli()
+
+	var ms int64
+	for _, c := range id[:8] + id[9:13] {
+		ms = ms<<4 | int64(hexValue(byte(c)))
+	}
+	// The counter may push the timestamp ahead by a millisecond on overflow
+	if ms < before || ms > after+1 {
+		t.Errorf("Expected timestamp in [%d, %d], got %d", before, after, ms)
+	}
+}
+
+func hexValue(c byte) byte {
+	if c >= 'a' {
+		return c - 'a' + 10
+	}
+	return c - '0'
+}
+
+func TestUUIDv7BaseModel_BeforeAppendModel(t *testing.T) {
+	model := &UUIDv7BaseModel{}
+	if err := model.BeforeAppendModel(context.Background(), &bun.InsertQuery{}); err != nil {
+		t.Fatalf("BeforeAppendModel failed: %v", err)
+	}
+	if !uuidV7Pattern.MatchString(model.ID) || model.CreatedAt.IsZero() {
+		t.Errorf("Expected ID and timestamps to be set, got %+v", model)
+	}
+
+	preset := &UUIDv7BaseModel{ID: "00000000-0000-0000-0000-000000000001"}
+	_ = preset.BeforeAppendModel(context.Background(), &bun.InsertQuery{})
+	if preset.ID != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("Expected a preset ID to be kept, got %s", preset.ID)
+	}
+}
+
+func TestUUIDv7BaseModel_Insert(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.NewDropTable().Model((*TestEvent)(nil)).IfExists().Exec(ctx); err != nil {
+		t.Fatalf("Drop failed: %v", err)
+	}
+	if _, err := db.NewCreateTable().Model((*TestEvent)(nil)).Exec(ctx); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var ids []string
+	for range 3 {
+		e := &TestEvent{Type: "signup"}
+		if _, err := db.NewInsert().Model(e).Exec(ctx); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		ids = append(ids, e.ID)
+	}
+
+	var ordered []string
+	if err := db.NewSelect().Model((*TestEvent)(nil)).Column("id").Order("id ASC").Scan(ctx, &ordered); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if !slices.Equal(ids, ordered) {
+		t.Errorf("Expected insertion order %v, got %v", ids, ordered)
+	}
+}
@@ -0,0 +1,83 @@
+package dbkit
+
+import (
+	"context"
+	"strings"
+)
+
+// Validatable is implemented by models that check their own state.
+// Create, CreateMany, Update and UpdateColumns call Validate before executing the query.
+//
+// Usage:
+//
+//	func (u *User) Validate() error {
+//	    var errs dbkit.ValidationErrors
+//	    if u.Email == "" {
+//	        errs = append(errs, dbkit.ValidationError{Field: "email", Message: "is required"})
+//	    }
+//	    return errs.Err()
+//	}
+type Validatable interface {
+	Validate() error
+}
+
+// ValidationError describes an invalid model field
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors lists every invalid field of a model
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap makes each ValidationError reachable with errors.As
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Err returns e as an error, or nil if it is empty
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+type skipValidationKey struct{}
+
+// SkipValidation returns a context under which models are not validated,
+// e.g. for migrations or seed scripts that load trusted data.
+//
+// Usage:
+//
+//	err := dbkit.CreateMany(dbkit.SkipValidation(ctx), db, fixtures)
+func SkipValidation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipValidationKey{}, true)
+}
+
+// validateModel runs model's Validate unless validation is skipped in ctx.
+func validateModel(ctx context.Context, model any, op string) error {
+	if skip, _ := ctx.Value(skipValidationKey{}).(bool); skip {
+		return nil
+	}
+	if v, ok := model.(Validatable); ok {
+		return wrapError(v.Validate(), op)
+	}
+	return nil
+}
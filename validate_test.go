@@ -0,0 +1,104 @@
+package dbkit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func (m *TestModel) Validate() error {
+	var errs ValidationErrors
+	if m.Name == "" {
+		errs = append(errs, ValidationError{Field: "name", Message: "is required"})
+	}
+	if m.Age < 0 {
+		errs = append(errs, ValidationError{Field: "age", Message: "must not be negative"})
+	}
+	return errs.Err()
+}
+
+func TestValidationErrors(t *testing.T) {
+	if (ValidationErrors{}).Err() != nil {
+		t.Error("Expected nil for no validation errors")
+	}
+
+	err := ValidationErrors{{Field: "name", Message: "is required"}, {Field: "age", Message: "must not be negative"}}.Err()
+	if err.Error() != "name: is required; age: must not be negative" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	var fieldErr ValidationError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "name" {
+		t.Errorf("Expected the first ValidationError via errors.As, got %+v", fieldErr)
+	}
+}
+
+func TestCreate_Validation(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newFakeDB(t)
+	hook := &queryCountHook{}
+	db.AddQueryHook(hook)
+
+	err := Create(ctx, db, &TestModel{Email: "noname@example.com"})
+	var fieldErr ValidationError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "name" {
+		t.Fatalf("Expected a ValidationError for name, got %v", err)
+	}
+	if !IsValidation(err) || HTTPStatus(err) != http.StatusUnprocessableEntity {
+		t.Errorf("Expected a validation error mapped to 422, got %v", err)
+	}
+	if col, _ := GetColumn(err); col != "name" {
+		t.Errorf("Expected column name, got %q", col)
+	}
+
+	if err := Update(ctx, db, &TestModel{ID: "id", Name: "n", Age: -1}); !IsValidation(err) {
+		t.Errorf("Expected Update to validate, got %v", err)
+	}
+	if err := UpdateColumns(ctx, db, &TestModel{ID: "id"}, "age"); !IsValidation(err) {
+		t.Errorf("Expected UpdateColumns to validate, got %v", err)
+	}
+	if err := CreateMany(ctx, db, []TestModel{{Name: "ok"}, {}}); !IsValidation(err) {
+		t.Errorf("Expected CreateMany to validate every model, got %v", err)
+	}
+	if hook.Count() != 0 {
+		t.Errorf("Expected no queries for invalid models, got %d", hook.Count())
+	}
+
+	// SkipValidation runs the query even for invalid models
+	_ = Update(SkipValidation(ctx), db, &TestModel{ID: "id"})
+	if hook.Count() != 1 {
+		t.Errorf("Expected the query to run with SkipValidation, got %d queries", hook.Count())
+	}
+}
+
+func TestCreate(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+
+	model := &TestModel{Name: "Valid", Email: "valid@example.com"}
+	if err := Create(ctx, db, model); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if model.ID == "" {
+		t.Error("Expected the ID to be returned")
+	}
+
+	model.Name = ""
+	if err := UpdateColumns(ctx, db, model, "name"); !IsValidation(err) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	found, _ := FindByID[TestModel](ctx, db, model.ID)
+	if found == nil || found.Name != "Valid" {
+		t.Errorf("Expected the record to be unchanged, got %+v", found)
+	}
+
+	if err := Create(ctx, db, &TestModel{Email: "empty@example.com"}); !IsValidation(err) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if count, _ := Count[TestModel](ctx, db, nil); count != 1 {
+		t.Errorf("Expected the invalid model not to be inserted, got %d rows", count)
+	}
+}
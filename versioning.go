@@ -127,6 +127,7 @@ func IsConflict(err error) bool {
 
 // RetryOnConflict executes a function and retries on optimistic locking conflicts.
 // The function should reload the model and retry the operation.
+// Each retry uses the retry budget of ctx, if any (see WithRetryBudget).
 //
 // Usage:
 //
@@ -138,8 +139,14 @@ func IsConflict(err error) bool {
 //	    return dbkit.UpdateWithVersion(ctx, db, &account, account.Version)
 //	})
 func RetryOnConflict(ctx context.Context, maxRetries int, fn func() error) error {
+	budget := RetryBudgetFromContext(ctx)
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
+		if i > 0 {
+			if err := budget.Take("RetryOnConflict"); err != nil {
+				return err
+			}
+		}
 		err := fn()
 		if err == nil {
 			return nil
@@ -0,0 +1,104 @@
+package dbkit
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// windowColumn is the alias of the window function value.
+// Bun skips columns starting with an underscore when scanning models.
+const windowColumn = "_wf_value"
+
+// WindowedResult is a row together with the value of a window function
+type WindowedResult[T any, W any] struct {
+	Model  T
+	Window W
+}
+
+// Ranked is a row with its ROW_NUMBER(), RANK() or DENSE_RANK()
+type Ranked[T any] = WindowedResult[T, int64]
+
+// Lagged is a row with its LAG() or LEAD() value, nil for the first or last row of a partition
+type Lagged[T any, V any] = WindowedResult[T, *V]
+
+// ScanWithWindow selects T's records together with windowExpr.
+// windowExpr is a SQL expression and must not contain user input.
+// Its value can be referenced in queryFn as _wf_value, e.g. for ordering.
+//
+// Usage:
+//
+//	ranked, err := dbkit.ScanWithWindow[User, int64](ctx, db, "ROW_NUMBER() OVER (PARTITION BY country ORDER BY score DESC)",
+//	    func(q *bun.SelectQuery) *bun.SelectQuery {
+//	        return q.OrderExpr("country, _wf_value")
+//	    })
+func ScanWithWindow[T any, W any](ctx context.Context, db IDB, windowExpr string, queryFn func(*bun.SelectQuery) *bun.SelectQuery) ([]WindowedResult[T, W], error) {
+	q := db.NewSelect().
+		Model((*T)(nil)).
+		ColumnExpr("?TableAlias.*").
+		ColumnExpr(windowExpr + " AS " + windowColumn)
+	if queryFn != nil {
+		q = queryFn(q)
+	}
+
+	model := &windowModel[T, W]{
+		table:   db.Dialect().Tables().Get(reflect.TypeFor[T]()),
+		results: []WindowedResult[T, W]{},
+	}
+	if err := q.Scan(ctx, model); err != nil {
+		return nil, wrapError(err, "ScanWithWindow")
+	}
+	return model.results, nil
+}
+
+// windowModel scans the window column into Window and every other column into Model
+type windowModel[T any, W any] struct {
+	table   *schema.Table
+	results []WindowedResult[T, W]
+}
+
+func (m *windowModel[T, W]) Value() any {
+	return &m.results
+}
+
+func (m *windowModel[T, W]) ScanRows(ctx context.Context, rows *sql.Rows) (int, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	for rows.Next() {
+		var result WindowedResult[T, W]
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i, col := range columns {
+			if col == windowColumn {
+				dest[i] = &result.Window
+			} else {
+				dest[i] = &values[i]
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+
+		strct := reflect.ValueOf(&result.Model).Elem()
+		for i, col := range columns {
+			field := m.table.LookupField(col)
+			if col == windowColumn || field == nil {
+				continue
+			}
+			if err := field.ScanValue(strct, values[i]); err != nil {
+				return 0, err
+			}
+		}
+		m.results = append(m.results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return len(m.results), nil
+}
@@ -0,0 +1,86 @@
+package dbkit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+func TestScanWithWindow(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	models := make([]TestModel, 6)
+	for i := range models {
+		models[i] = TestModel{
+			Name:      fmt.Sprintf("User %d", i),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			Age:       i,
+			Active:    i%2 == 0,
+			CreatedAt: base.Add(time.Duration(len(models)-i) * time.Minute), // newest first
+		}
+	}
+	if _, err := BatchInsert(ctx, db, models, 100); err != nil {
+		t.Fatalf("BatchInsert failed: %v", err)
+	}
+
+	ranked, err := ScanWithWindow[TestModel, int64](ctx, db, "ROW_NUMBER() OVER (PARTITION BY active ORDER BY created_at)",
+		func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.OrderExpr("active DESC, _wf_value ASC")
+		})
+	if err != nil {
+		t.Fatalf("ScanWithWindow failed: %v", err)
+	}
+	if len(ranked) != 6 {
+		t.Fatalf("Expected 6 rows, got %d", len(ranked))
+	}
+
+	// Oldest first within each partition: active ages 4, 2, 0 then inactive ages 5, 3, 1
+	wantAges := []int{4, 2, 0, 5, 3, 1}
+	for i, r := range ranked {
+		if r.Model.Age != wantAges[i] || r.Window != int64(i%3+1) {
+			t.Errorf("Row %d: expected age %d rank %d, got age %d rank %d", i, wantAges[i], i%3+1, r.Model.Age, r.Window)
+		}
+		if r.Model.ID == "" || r.Model.CreatedAt.IsZero() {
+			t.Errorf("Row %d: model not fully scanned: %+v", i, r.Model)
+		}
+	}
+}
+
+func TestScanWithWindow_Lagged(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+
+	ctx := createTable(t, db)
+	insertTestModels(t, db, 3)
+
+	var lagged []Lagged[TestModel, int]
+	lagged, err := ScanWithWindow[TestModel, *int](ctx, db, "LAG(age) OVER (ORDER BY age)", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Order("age ASC")
+	})
+	if err != nil {
+		t.Fatalf("ScanWithWindow failed: %v", err)
+	}
+	if len(lagged) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(lagged))
+	}
+	if lagged[0].Window != nil {
+		t.Errorf("Expected nil LAG for the first row, got %d", *lagged[0].Window)
+	}
+	for i := 1; i < len(lagged); i++ {
+		if lagged[i].Window == nil || *lagged[i].Window != lagged[i-1].Model.Age {
+			t.Errorf("Row %d: expected LAG %d, got %v", i, lagged[i-1].Model.Age, lagged[i].Window)
+		}
+	}
+
+	empty, err := ScanWithWindow[TestModel, int64](ctx, db, "RANK() OVER (ORDER BY age)", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("age > ?", 100)
+	})
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected an empty slice, got %v, %v", empty, err)
+	}
+}